@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/blend/go-sdk/configutil"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/diagnostics"
+	"github.com/blend/go-sdk/email"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/fileutil"
+	"github.com/blend/go-sdk/jobkit"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/slack"
+	"github.com/blend/go-sdk/stats"
+)
+
+// watchRetryDelay is how long to wait before watching the config again if the watch fails, e.g. if the file was replaced.
+const watchRetryDelay = 5 * time.Second
+
+// jobLoader creates jobs from configs and loads them into a job manager, and reloads the
+// jobs from a config file when it changes.
+type jobLoader struct {
+	Log         logger.Log
+	Jobs        *cron.JobManager
+	Reloads     *jobkit.ReloadHistory
+	MaxLogBytes int
+
+	EmailClient email.Sender
+	SlackClient slack.Sender
+	StatsClient stats.Collector
+	ErrorClient diagnostics.Notifier
+
+	// loaded are the configs of the jobs loaded from the config file, by name.
+	loaded map[string]jobConfig
+}
+
+// Create creates a job from a config with the loader's clients.
+func (jl *jobLoader) Create(cfg jobConfig) (*jobkit.Job, error) {
+	job, err := createJobFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	job.WithLogger(jl.Log).
+		WithMaxLogBytes(jl.MaxLogBytes).
+		WithEmailClient(jl.EmailClient).
+		WithSlackClient(jl.SlackClient).
+		WithStatsClient(jl.StatsClient).
+		WithErrorClient(jl.ErrorClient)
+	return job, nil
+}
+
+// Load loads jobs from configs. Jobs loaded from the config file are tracked so they can be reloaded.
+func (jl *jobLoader) Load(configs []jobConfig, fromConfigFile bool) error {
+	for _, jobCfg := range configs {
+		job, err := jl.Create(jobCfg)
+		if err != nil {
+			return err
+		}
+		jl.Log.Infof("loading job `%s` with schedule `%s`", jobCfg.Name, jobCfg.ScheduleOrDefault())
+		if err := jl.Jobs.LoadJobs(job); err != nil {
+			return err
+		}
+		if fromConfigFile {
+			if jl.loaded == nil {
+				jl.loaded = make(map[string]jobConfig)
+			}
+			jl.loaded[jobCfg.Name] = jobCfg
+		}
+	}
+	return nil
+}
+
+// Watch watches a config file, and reloads the jobs from it when it changes.
+// It blocks, and should be called in a goroutine.
+func (jl *jobLoader) Watch(path string) {
+	jl.Log.Infof("watching `%s` for job changes", path)
+	jl.watch(context.Background(), path, fileutil.DefaultWatchInterval, watchRetryDelay)
+}
+
+// watch watches a config file until the context is done, watching it again after a delay if the watch fails.
+// The file can change while it isn't watched, e.g. if it was replaced, so the jobs are reloaded from it
+// each time the watch is established again.
+func (jl *jobLoader) watch(ctx context.Context, path string, interval, retryDelay time.Duration) {
+	var rewatch bool
+	for {
+		if rewatch {
+			if _, err := os.Stat(path); err == nil {
+				jl.ReloadFile(path)
+				rewatch = false
+			}
+		}
+		err := fileutil.WatchContext(ctx, path, interval, func(f *os.File) error {
+			f.Close()
+			jl.ReloadFile(path)
+			return nil
+		})
+		if err == nil {
+			return
+		}
+		logger.MaybeError(jl.Log, err)
+		rewatch = true
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retryDelay):
+		}
+	}
+}
+
+// ReloadFile reloads the jobs from a config file, recording and logging the result.
+func (jl *jobLoader) ReloadFile(path string) jobkit.ReloadResult {
+	var cfg config
+	var result jobkit.ReloadResult
+	if _, err := configutil.Read(&cfg, configutil.OptPaths(path)); err != nil {
+		result = jobkit.ReloadResult{Err: errString(err)}
+	} else {
+		result = jl.Reload(cfg.Jobs)
+	}
+	result.Source = path
+	if jl.Reloads != nil {
+		jl.Reloads.Add(result)
+	}
+	if result.Err != "" {
+		jl.Log.Errorf("%s: %s", path, result.String())
+	} else {
+		jl.Log.Infof("%s: %s", path, result.String())
+	}
+	return result
+}
+
+// Reload diffs the jobs in a config against the jobs loaded from the config file, and adds, updates and
+// removes jobs in the job manager to match. Running invocations of updated and removed jobs are not interrupted.
+// If any of the jobs in the config are invalid, no changes are made.
+func (jl *jobLoader) Reload(configs []jobConfig) (result jobkit.ReloadResult) {
+	next := make(map[string]jobConfig)
+	for _, jobCfg := range configs {
+		if jobCfg.Name == "" {
+			result.Err = "job name unset"
+			return
+		}
+		if _, ok := next[jobCfg.Name]; ok {
+			result.Err = "duplicate job: " + jobCfg.Name
+			return
+		}
+		next[jobCfg.Name] = jobCfg
+	}
+
+	var added, updated []cron.Job
+	for _, name := range sortedNames(next) {
+		jobCfg := next[name]
+		previous, ok := jl.loaded[name]
+		if ok && reflect.DeepEqual(previous, jobCfg) {
+			continue
+		}
+		if !ok && jl.Jobs.HasJob(name) {
+			result.Err = "job already loaded: " + name
+			return
+		}
+		job, err := jl.Create(jobCfg)
+		if err != nil {
+			result.Err = errString(err)
+			return
+		}
+		if ok {
+			updated = append(updated, job)
+			result.Updated = append(result.Updated, name)
+		} else {
+			added = append(added, job)
+			result.Added = append(result.Added, name)
+		}
+	}
+	for _, name := range sortedNames(jl.loaded) {
+		if _, ok := next[name]; !ok {
+			result.Removed = append(result.Removed, name)
+		}
+	}
+
+	// apply the changes, tracking what was applied if one of the steps fails.
+	if len(result.Removed) > 0 {
+		if err := jl.Jobs.UnloadJobs(result.Removed...); err != nil {
+			return jobkit.ReloadResult{Err: errString(err)}
+		}
+		for _, name := range result.Removed {
+			delete(jl.loaded, name)
+		}
+	}
+	if len(updated) > 0 {
+		if err := jl.Jobs.UpdateJobs(updated...); err != nil {
+			return jobkit.ReloadResult{Removed: result.Removed, Err: errString(err)}
+		}
+		for _, name := range result.Updated {
+			jl.loaded[name] = next[name]
+		}
+	}
+	if len(added) > 0 {
+		if err := jl.Jobs.LoadJobs(added...); err != nil {
+			return jobkit.ReloadResult{Removed: result.Removed, Updated: result.Updated, Err: errString(err)}
+		}
+	}
+	jl.loaded = next
+	return
+}
+
+func sortedNames(configs map[string]jobConfig) []string {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// errString returns an error's class and message.
+func errString(err error) string {
+	if message := ex.ErrMessage(err); message != "" {
+		return ex.ErrClass(err) + ": " + message
+	}
+	return ex.ErrClass(err)
+}
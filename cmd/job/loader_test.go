@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/jobkit"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/ref"
+)
+
+func testJobConfig(name, schedule string) jobConfig {
+	return jobConfig{
+		Exec:      []string{"echo", name},
+		JobConfig: jobkit.JobConfig{Name: name, Schedule: schedule},
+	}
+}
+
+func TestJobLoaderReload(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := cron.New()
+	loader := &jobLoader{Log: logger.None(), Jobs: jobs}
+	assert.Nil(loader.Load([]jobConfig{
+		testJobConfig("one", "0 0 * * * *"),
+		testJobConfig("two", "0 0 * * * *"),
+	}, true))
+	assert.Nil(loader.Load([]jobConfig{testJobConfig("default", "")}, false))
+	assert.Nil(jobs.StartAsync())
+	defer jobs.Stop()
+
+	one, err := jobs.Job("one")
+	assert.Nil(err)
+
+	updated := testJobConfig("one", "0 30 * * * *")
+	updated.Timeout = time.Minute
+	result := loader.Reload([]jobConfig{updated, testJobConfig("three", "")})
+	assert.Empty(result.Err)
+	assert.Equal([]string{"three"}, result.Added)
+	assert.Equal([]string{"one"}, result.Updated)
+	assert.Equal([]string{"two"}, result.Removed)
+
+	reloaded, err := jobs.Job("one")
+	assert.Nil(err)
+	assert.True(one == reloaded)
+	assert.Equal(time.Minute, reloaded.TimeoutProvider())
+	assert.True(jobs.HasJob("three"))
+	assert.False(jobs.HasJob("two"))
+	assert.True(jobs.HasJob("default"), "jobs not from the config file should not be removed")
+
+	result = loader.Reload([]jobConfig{updated, testJobConfig("three", "")})
+	assert.Empty(result.Err)
+	assert.False(result.Changed())
+}
+
+func TestJobLoaderReloadInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	jobs := cron.New()
+	loader := &jobLoader{Log: logger.None(), Jobs: jobs}
+	assert.Nil(loader.Load([]jobConfig{testJobConfig("one", "")}, true))
+	assert.Nil(loader.Load([]jobConfig{testJobConfig("default", "")}, false))
+
+	result := loader.Reload([]jobConfig{testJobConfig("two", "not a schedule")})
+	assert.NotEmpty(result.Err)
+	assert.True(jobs.HasJob("one"), "invalid configs should not change the loaded jobs")
+	assert.False(jobs.HasJob("two"))
+
+	result = loader.Reload([]jobConfig{testJobConfig("two", ""), testJobConfig("two", "")})
+	assert.Equal("duplicate job: two", result.Err)
+
+	result = loader.Reload([]jobConfig{testJobConfig("default", "")})
+	assert.Equal("job already loaded: default", result.Err)
+	assert.True(jobs.HasJob("one"))
+}
+
+func TestJobLoaderWatchReplaced(t *testing.T) {
+	assert := assert.New(t)
+
+	// the flags are read when the config is resolved, and are only set up by the command.
+	flagBind, flagDefaultJobName, flagDefaultJobDiscardOutput = ref.String(""), ref.String(""), ref.Bool(false)
+
+	path := filepath.Join(t.TempDir(), "jobs.yml")
+	assert.Nil(os.WriteFile(path, []byte("jobs:\n- name: one\n  exec: [echo, one]\n"), 0644))
+
+	jobs := cron.New()
+	loader := &jobLoader{Log: logger.None(), Jobs: jobs}
+	assert.Empty(loader.ReloadFile(path).Err)
+	assert.True(jobs.HasJob("one"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		loader.watch(ctx, path, 5*time.Millisecond, 5*time.Millisecond)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// the file is replaced while it isn't watched, so the change is picked up when it is watched again.
+	assert.Nil(os.Remove(path))
+	time.Sleep(50 * time.Millisecond)
+	assert.Nil(os.WriteFile(path, []byte("jobs:\n- name: two\n  exec: [echo, two]\n"), 0644))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !jobs.HasJob("two") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.True(jobs.HasJob("two"))
+	assert.False(jobs.HasJob("one"))
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -29,6 +30,9 @@ import (
 	"github.com/blend/go-sdk/stringutil"
 )
 
+// parameterEnvPrefix is the prefix of the environment variables job parameters are passed to commands with.
+const parameterEnvPrefix = "JOB_PARAM_"
+
 var (
 	flagBind                    *string
 	flagConfigPath              *string
@@ -79,7 +83,7 @@ func command() *cobra.Command {
 	return &cobra.Command{
 		Use:   "job",
 		Short: "Job runs a command on a schedule, and tracks limited job history in memory.",
-		Long:  "Job runs a command on a schedule, and tracks limited job history in memory.",
+		Long:  "Job runs a command on a schedule, and tracks limited job history in memory, or on disk if `cron.historyPath` is set.",
 		Example: `
 # echo 'hello world' with the default schedule
 job -- echo 'hello world'
@@ -93,7 +97,8 @@ job -n echo --schedule='*/30 * * * *' -- echo 'hello world'
 # use a config
 job -c config.yml'
 
-# where the config can specify multiple jobs.
+# where the config can specify multiple jobs, which are added, updated
+# and removed without a restart when the config file changes.
 """
 jobs:
 - name: echo
@@ -147,11 +152,7 @@ func run(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if defaultJobCfg != nil {
-		cfg.Jobs = append(cfg.Jobs, *defaultJobCfg)
-	}
-
-	if len(cfg.Jobs) == 0 {
+	if len(cfg.Jobs) == 0 && defaultJobCfg == nil {
 		return ex.New("must supply a command to run with `--exec=...` or `-- command`), or provide a jobs config file")
 	}
 
@@ -181,16 +182,28 @@ func run(cmd *cobra.Command, args []string) error {
 		log.Infof("adding airbrake notifications")
 	}
 
-	jobs := cron.New(cron.OptConfig(cfg.Config.Cron), cron.OptLog(log))
+	jobs := cron.New(cron.OptConfig(cfg.Config.Cron), cron.OptLog(log), cron.OptHistoryStore(cfg.Config.Cron.HistoryStore()), cron.OptConcurrencyLimiter(cfg.Config.Cron.ConcurrencyLimiter()))
+	if cfg.Config.Cron.HistoryPath != "" {
+		log.Infof("persisting job history to `%s`", cfg.Config.Cron.HistoryPath)
+	}
 
-	for _, jobCfg := range cfg.Jobs {
-		job, err := createJobFromConfig(jobCfg)
-		if err != nil {
+	loader := &jobLoader{
+		Log:         log,
+		Jobs:        jobs,
+		Reloads:     jobkit.NewReloadHistory(0),
+		MaxLogBytes: cfg.Config.MaxLogBytesOrDefault(),
+		EmailClient: emailClient,
+		SlackClient: slackClient,
+		StatsClient: statsClient,
+		ErrorClient: errorClient,
+	}
+	if err := loader.Load(cfg.Jobs, true); err != nil {
+		return err
+	}
+	if defaultJobCfg != nil {
+		if err := loader.Load([]jobConfig{*defaultJobCfg}, false); err != nil {
 			return err
 		}
-		job.WithLogger(log).WithEmailClient(emailClient).WithSlackClient(slackClient).WithStatsClient(statsClient).WithErrorClient(errorClient)
-		log.Infof("loading job `%s` with schedule `%s`", jobCfg.Name, jobCfg.ScheduleOrDefault())
-		jobs.LoadJobs(job)
 	}
 
 	hosted := []graceful.Graceful{jobs}
@@ -198,10 +211,16 @@ func run(cmd *cobra.Command, args []string) error {
 	if !*flagDisableServer {
 		ws := jobkit.NewManagementServer(jobs, cfg.Config)
 		ws.Log = log.SubContext("management server")
+		jobkit.AddReloadRoutes(ws, cfg.Config, loader.Reloads)
 		hosted = append(hosted, ws)
 	} else {
 		log.Infof("management server disabled")
 	}
+
+	// jobs are reloaded when the config changes; other settings require a restart.
+	if *flagConfigPath != "" {
+		go loader.Watch(*flagConfigPath)
+	}
 	return graceful.Shutdown(hosted...)
 }
 
@@ -222,18 +241,22 @@ func createJobFromConfig(cfg jobConfig) (*jobkit.Job, error) {
 		return nil, ex.New("job exec and command unset", ex.OptMessagef("job: %s", cfg.Name))
 	}
 	action := func(ctx context.Context) error {
+		cmd, err := sh.CmdContext(ctx, cfg.Exec[0], cfg.Exec[1:]...)
+		if err != nil {
+			return err
+		}
+		cmd.Env = append(cmd.Env, parameterEnv(cron.GetParameters(ctx))...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		cmd.Stdin = os.Stdin
 		if cfg.DiscardOutput == nil || (cfg.DiscardOutput != nil && !*cfg.DiscardOutput) {
 			if jis := jobkit.GetJobInvocationState(ctx); jis != nil {
-				cmd, err := sh.CmdContext(ctx, cfg.Exec[0], cfg.Exec[1:]...)
-				if err != nil {
-					return err
-				}
 				cmd.Stdout = io.MultiWriter(jis.Output, os.Stdout)
 				cmd.Stderr = io.MultiWriter(jis.ErrorOutput, os.Stderr)
-				return ex.New(cmd.Run())
+				cmd.Stdin = nil
 			}
 		}
-		return sh.ForkContext(ctx, cfg.Exec[0], cfg.Exec[1:]...)
+		return ex.New(cmd.Run())
 	}
 
 	job, err := jobkit.NewJob(cfg.JobConfig, action)
@@ -245,3 +268,12 @@ func createJobFromConfig(cfg jobConfig) (*jobkit.Job, error) {
 	}
 	return job, nil
 }
+
+// parameterEnv returns the environment variables for the parameters of an invocation,
+// e.g. a parameter `batchSize` is passed as `JOB_PARAM_BATCHSIZE`.
+func parameterEnv(parameters cron.Parameters) (env []string) {
+	for _, name := range parameters.Names() {
+		env = append(env, fmt.Sprintf("%s%s=%s", parameterEnvPrefix, strings.ToUpper(name), parameters.String(name)))
+	}
+	return
+}
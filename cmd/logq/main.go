@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/selector"
+)
+
+// linker metadata block
+// this block must be present
+// it is used by goreleaser
+var (
+	version = "dev"
+	commit  = "none"
+	date    = "unknown"
+)
+
+var flags = flag.String("flags", "", "A comma separated list of flags to show, e.g. `error,fatal` (defaults to all flags)")
+var since = flag.String("since", "", "Only show events at or after a time, as RFC3339 or a duration ago, e.g. `2h`")
+var until = flag.String("until", "", "Only show events before a time, as RFC3339 or a duration ago, e.g. `30m`")
+var labels = flag.String("labels", "", "A selector to match against event labels, e.g. `env=prod,region in (us-east-1)`")
+var annotations = flag.String("annotations", "", "A selector to match against event annotations")
+var scope = flag.String("scope", "", "Only show events in a sub-context path or its children, e.g. `db > migrations`")
+var follow = flag.Bool("f", false, "Follow the given files as they are appended to")
+var pollInterval = flag.Duration("poll", 250*time.Millisecond, "The interval to check followed files for new output")
+var passthrough = flag.Bool("passthrough", false, "Write lines that are not json events as is instead of skipping them")
+var noColor = flag.Bool("no-color", false, "Disable colorized output")
+var hideTimestamp = flag.Bool("hide-timestamp", false, "Hide timestamps in output")
+var hideFields = flag.Bool("hide-fields", false, "Hide sub-context fields in output")
+var printVersion = flag.Bool("version", false, "Print the version and exit")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "logq queries the json output of a logger and re-renders matching events as text.\n\n")
+		fmt.Fprintf(os.Stderr, "usage: logq [options] [file ...]\n\n")
+		fmt.Fprintf(os.Stderr, "Files ending in .gz, or starting with the gzip header, are decompressed. If no files are given, or a file is `-`, stdin is read.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *printVersion {
+		fmt.Printf("logq %s (%s %s)\n", version, commit, date)
+		os.Exit(0)
+	}
+
+	q, err := newQuery()
+	if err != nil {
+		fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt)
+	go func() {
+		<-quit
+		cancel()
+	}()
+
+	output := &syncWriter{Writer: bufio.NewWriter(os.Stdout)}
+	defer output.Flush()
+
+	paths := flag.Args()
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+
+	if !*follow {
+		for _, path := range paths {
+			if err := q.File(ctx, output, path); err != nil {
+				output.Flush()
+				fatal(err)
+			}
+		}
+		return
+	}
+
+	// in follow mode, each input is read concurrently and events are written as they arrive.
+	output.AutoFlush = true
+	errors := make(chan error, len(paths))
+	wg := sync.WaitGroup{}
+	for _, path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			if err := q.Follow(ctx, output, path); err != nil {
+				errors <- err
+			}
+		}(path)
+	}
+	wg.Wait()
+	close(errors)
+	for err := range errors {
+		fatal(err)
+	}
+}
+
+func newQuery() (*query, error) {
+	q := &query{
+		Formatter: logger.NewTextOutputFormatter(),
+	}
+	if *noColor {
+		q.Formatter.NoColor = true
+	}
+	if *hideTimestamp {
+		q.Formatter.HideTimestamp = true
+	}
+	if *hideFields {
+		q.Formatter.HideFields = true
+	}
+
+	if *flags != "" {
+		q.Flags = logger.NewFlags(strings.Split(*flags, ",")...)
+	}
+
+	var err error
+	now := time.Now().UTC()
+	if q.Since, err = parseTime(*since, now); err != nil {
+		return nil, err
+	}
+	if q.Until, err = parseTime(*until, now); err != nil {
+		return nil, err
+	}
+	if *labels != "" {
+		if q.Labels, err = selector.Parse(*labels); err != nil {
+			return nil, err
+		}
+	}
+	if *annotations != "" {
+		if q.Annotations, err = selector.Parse(*annotations); err != nil {
+			return nil, err
+		}
+	}
+	if *scope != "" {
+		q.Scope = logger.ParseScopeName(*scope)
+	}
+	return q, nil
+}
+
+// parseTime parses a time as RFC3339, or as a duration before now.
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if ago, err := time.ParseDuration(value); err == nil {
+		return now.Add(-ago), nil
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q; must be RFC3339 or a duration", value)
+	}
+	return parsed, nil
+}
+
+// query filters json events.
+type query struct {
+	Formatter   *logger.TextOutputFormatter
+	Flags       *logger.Flags
+	Since       time.Time
+	Until       time.Time
+	Labels      selector.Selector
+	Annotations selector.Selector
+	Scope       []string
+}
+
+// Matches returns if an event matches the query.
+func (q query) Matches(je *logger.JSONEvent) bool {
+	if q.Flags != nil && !q.Flags.IsEnabled(je.Event.GetFlag()) {
+		return false
+	}
+	timestamp := je.Event.GetTimestamp()
+	if !q.Since.IsZero() && timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !timestamp.Before(q.Until) {
+		return false
+	}
+	if q.Labels != nil && !q.Labels.Matches(je.GetLabels()) {
+		return false
+	}
+	if q.Annotations != nil && !q.Annotations.Matches(je.GetAnnotations()) {
+		return false
+	}
+	if len(q.Scope) > 0 {
+		if len(je.Path) < len(q.Scope) {
+			return false
+		}
+		for index := range q.Scope {
+			if je.Path[index] != q.Scope[index] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Line writes a line of input to the output if it matches the query.
+func (q query) Line(ctx context.Context, output io.Writer, line []byte) error {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+	je, err := logger.ParseJSONEvent(line)
+	if err != nil {
+		if *passthrough {
+			_, err = fmt.Fprintf(output, "%s\n", line)
+			return err
+		}
+		return nil
+	}
+	if !q.Matches(je) {
+		return nil
+	}
+	return q.Formatter.WriteFormat(je.Context(ctx), output, je.Event)
+}
+
+// File writes the matching events in a file, or stdin if the path is `-`.
+func (q query) File(ctx context.Context, output io.Writer, path string) error {
+	input, closer, err := open(path)
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	reader := bufio.NewReader(input)
+	for {
+		if ctx.Err() != nil {
+			return nil
+		}
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			if writeErr := q.Line(ctx, output, line); writeErr != nil {
+				return writeErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Follow writes the matching events in a file, and then polls the file for new events
+// until the context is cancelled. If the file is truncated it is read from the start.
+func (q query) Follow(ctx context.Context, output io.Writer, path string) error {
+	if path == "-" {
+		return q.File(ctx, output, path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { file.Close() }()
+	if isGzip(path, bufio.NewReader(file)) {
+		return fmt.Errorf("%s: cannot follow a compressed file", path)
+	}
+	if _, err = file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	var offset int64
+	var partial []byte
+	reader := bufio.NewReader(file)
+	ticker := time.NewTicker(*pollInterval)
+	defer ticker.Stop()
+	for {
+		line, err := reader.ReadBytes('\n')
+		offset += int64(len(line))
+		if err == nil {
+			if writeErr := q.Line(ctx, output, append(partial, line...)); writeErr != nil {
+				return writeErr
+			}
+			partial = nil
+			continue
+		}
+		if err != io.EOF {
+			return err
+		}
+		// hold on to incomplete lines until the rest is written.
+		partial = append(partial, line...)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			// the file may be mid-rotation; try again on the next tick.
+			continue
+		}
+		current, err := file.Stat()
+		if err != nil {
+			return err
+		}
+		if !os.SameFile(info, current) || info.Size() < offset {
+			reopened, err := os.Open(path)
+			if err != nil {
+				continue
+			}
+			file.Close()
+			file = reopened
+			offset = 0
+			partial = nil
+			reader.Reset(file)
+		}
+	}
+}
+
+// open opens a file, or stdin if the path is `-`, decompressing it if it is gzipped.
+func open(path string) (io.Reader, io.Closer, error) {
+	var file *os.File
+	if path == "-" {
+		file = os.Stdin
+	} else {
+		var err error
+		if file, err = os.Open(path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	buffered := bufio.NewReader(file)
+	if !isGzip(path, buffered) {
+		return buffered, file, nil
+	}
+	gz, err := gzip.NewReader(buffered)
+	if err != nil {
+		file.Close()
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return gz, file, nil
+}
+
+// isGzip returns if a file is gzipped by its extension or header.
+func isGzip(path string, reader *bufio.Reader) bool {
+	if strings.HasSuffix(path, ".gz") {
+		return true
+	}
+	header, _ := reader.Peek(2)
+	return len(header) == 2 && header[0] == 0x1f && header[1] == 0x8b
+}
+
+// syncWriter serializes writes from followed files.
+type syncWriter struct {
+	sync.Mutex
+	*bufio.Writer
+	AutoFlush bool
+}
+
+func (sw *syncWriter) Write(contents []byte) (int, error) {
+	sw.Lock()
+	defer sw.Unlock()
+	written, err := sw.Writer.Write(contents)
+	if err != nil {
+		return written, err
+	}
+	if sw.AutoFlush {
+		err = sw.Writer.Flush()
+	}
+	return written, err
+}
+
+func (sw *syncWriter) Flush() error {
+	sw.Lock()
+	defer sw.Unlock()
+	return sw.Writer.Flush()
+}
+
+func fatal(err error) {
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "logq: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/selector"
+)
+
+const (
+	infoLine  = `{"flag":"info","_timestamp":"2019-01-02T03:00:00Z","message":"starting up","labels":{"env":"prod"},"_path":["db","migrations"]}`
+	errorLine = `{"flag":"error","_timestamp":"2019-01-02T04:00:00Z","err":"connection refused","annotations":{"team":"data"},"_path":["db"]}`
+	debugLine = `{"flag":"debug","_timestamp":"2019-01-02T05:00:00Z","message":"polling"}`
+)
+
+func newTestQuery() *query {
+	formatter := logger.NewTextOutputFormatter()
+	formatter.NoColor = true
+	formatter.HideTimestamp = true
+	return &query{Formatter: formatter}
+}
+
+func parseEvent(t *testing.T, line string) *logger.JSONEvent {
+	je, err := logger.ParseJSONEvent([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return je
+}
+
+func TestQueryMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	info, errorEvent, debug := parseEvent(t, infoLine), parseEvent(t, errorLine), parseEvent(t, debugLine)
+
+	q := newTestQuery()
+	assert.True(q.Matches(info))
+	assert.True(q.Matches(errorEvent))
+	assert.True(q.Matches(debug))
+
+	q = newTestQuery()
+	q.Flags = logger.NewFlags("error", "debug")
+	assert.False(q.Matches(info))
+	assert.True(q.Matches(errorEvent))
+	assert.True(q.Matches(debug))
+
+	q = newTestQuery()
+	q.Since = time.Date(2019, 01, 02, 04, 00, 00, 00, time.UTC)
+	q.Until = time.Date(2019, 01, 02, 05, 00, 00, 00, time.UTC)
+	assert.False(q.Matches(info))
+	assert.True(q.Matches(errorEvent), "since should be inclusive")
+	assert.False(q.Matches(debug), "until should be exclusive")
+
+	var err error
+	q = newTestQuery()
+	q.Labels, err = selector.Parse("env=prod")
+	assert.Nil(err)
+	assert.True(q.Matches(info))
+	assert.False(q.Matches(errorEvent))
+
+	q = newTestQuery()
+	q.Annotations, err = selector.Parse("team=data")
+	assert.Nil(err)
+	assert.False(q.Matches(info))
+	assert.True(q.Matches(errorEvent))
+
+	q = newTestQuery()
+	q.Scope = logger.ParseScopeName("db")
+	assert.True(q.Matches(info), "children of the scope should match")
+	assert.True(q.Matches(errorEvent))
+	assert.False(q.Matches(debug))
+
+	q.Scope = logger.ParseScopeName("db > migrations")
+	assert.True(q.Matches(info))
+	assert.False(q.Matches(errorEvent), "parents of the scope should not match")
+}
+
+func TestParseTime(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2019, 01, 02, 03, 04, 05, 00, time.UTC)
+
+	parsed, err := parseTime("", now)
+	assert.Nil(err)
+	assert.True(parsed.IsZero())
+
+	parsed, err = parseTime("2h", now)
+	assert.Nil(err)
+	assert.Equal(now.Add(-2*time.Hour), parsed)
+
+	parsed, err = parseTime("2019-01-01T00:00:00Z", now)
+	assert.Nil(err)
+	assert.Equal(time.Date(2019, 01, 01, 00, 00, 00, 00, time.UTC), parsed)
+
+	parsed, err = parseTime("2019-01-01T00:00:00.5-05:00", now)
+	assert.Nil(err)
+	assert.Equal(time.Date(2019, 01, 01, 05, 00, 00, 5e8, time.UTC), parsed.UTC())
+
+	_, err = parseTime("yesterday", now)
+	assert.NotNil(err)
+}
+
+func TestQueryFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "logq")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	contents := strings.Join([]string{infoLine, "not json", "", errorLine, debugLine}, "\n") + "\n"
+	plain := filepath.Join(dir, "app.log")
+	assert.Nil(ioutil.WriteFile(plain, []byte(contents), 0600))
+
+	compressed := new(bytes.Buffer)
+	gz := gzip.NewWriter(compressed)
+	_, err = gz.Write([]byte(contents))
+	assert.Nil(err)
+	assert.Nil(gz.Close())
+	// without the extension, so it is detected by its header.
+	zipped := filepath.Join(dir, "app.log.1")
+	assert.Nil(ioutil.WriteFile(zipped, compressed.Bytes(), 0600))
+
+	q := newTestQuery()
+	q.Flags = logger.NewFlags("info", "error")
+	for _, path := range []string{plain, zipped} {
+		output := new(bytes.Buffer)
+		assert.Nil(q.File(context.Background(), output, path))
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		assert.Len(lines, 2, path)
+		assert.Contains(lines[0], "starting up")
+		assert.Contains(lines[1], "connection refused")
+		assert.NotContains(output.String(), "not json")
+	}
+
+	*passthrough = true
+	defer func() { *passthrough = false }()
+	output := new(bytes.Buffer)
+	assert.Nil(q.File(context.Background(), output, plain))
+	assert.Contains(output.String(), "not json\n")
+
+	assert.NotNil(q.File(context.Background(), new(bytes.Buffer), filepath.Join(dir, "missing.log")))
+}
+
+func TestQueryFollow(t *testing.T) {
+	assert := assert.New(t)
+
+	previous := *pollInterval
+	*pollInterval = time.Millisecond
+	defer func() { *pollInterval = previous }()
+
+	dir, err := ioutil.TempDir("", "logq")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "app.log")
+	assert.Nil(ioutil.WriteFile(path, []byte(infoLine+"\n"), 0600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	output := &lockedBuffer{}
+	done := make(chan error, 1)
+	go func() { done <- newTestQuery().Follow(ctx, output, path) }()
+
+	waitFor := func(expected string) {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if strings.Contains(output.String(), expected) {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		assert.FailNow("timed out waiting for: " + expected)
+	}
+	waitFor("starting up")
+
+	// lines written in pieces are written once they are complete.
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	assert.Nil(err)
+	_, err = file.WriteString(errorLine[:20])
+	assert.Nil(err)
+	time.Sleep(10 * time.Millisecond)
+	_, err = file.WriteString(errorLine[20:] + "\n")
+	assert.Nil(err)
+	assert.Nil(file.Close())
+	waitFor("connection refused")
+
+	// truncated files are read from the start.
+	assert.Nil(ioutil.WriteFile(path, []byte(debugLine+"\n"), 0600))
+	waitFor("polling")
+
+	// rotated files are reopened.
+	assert.Nil(os.Rename(path, path+".1"))
+	assert.Nil(ioutil.WriteFile(path, []byte(`{"flag":"info","message":"rotated"}`+"\n"), 0600))
+	waitFor("rotated")
+
+	cancel()
+	select {
+	case err = <-done:
+		assert.Nil(err)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("follow did not return after the context was cancelled")
+	}
+	assert.Equal(1, strings.Count(output.String(), "connection refused"))
+
+	assert.NotNil(newTestQuery().Follow(context.Background(), new(bytes.Buffer), filepath.Join(dir, "missing.log")))
+}
+
+// lockedBuffer is a buffer that can be read while it is written to.
+type lockedBuffer struct {
+	sync.Mutex
+	bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(contents []byte) (int, error) {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.Buffer.Write(contents)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.Buffer.String()
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/secrets"
+	"github.com/blend/go-sdk/yaml"
+)
+
+// output formats.
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+var (
+	flagAddr  *string
+	flagToken *string
+	flagMount *string
+)
+
+func command() *cobra.Command {
+	return &cobra.Command{
+		Use:   "secrets",
+		Short: "Secrets lists, reads, writes, exports, diffs and syncs vault kv secrets.",
+		Long:  "Secrets lists, reads, writes, exports, diffs and syncs vault kv secrets, detecting if a path is in a kv1 or kv2 backend. It reads `VAULT_ADDR`, `VAULT_TOKEN` and the vault auth method variables from the environment.",
+		Example: `
+# list the keys and subfolders at a path
+secrets list secret/dev/app
+
+# read a secret, or a single field of it
+secrets get secret/dev/app/db
+secrets get secret/dev/app/db -f password
+
+# write fields of a secret, keeping the other fields
+secrets put secret/dev/app/db username=app password=hunter2
+
+# export a subtree as yaml, and import it elsewhere
+secrets export secret/dev/app -o yaml > app.yml
+secrets import secret/stage/app app.yml
+
+# diff two subtrees, optionally in another vault
+secrets diff secret/dev/app secret/prod/app
+secrets diff secret/app secret/app --target-addr https://vault.prod:8200 --target-token $PROD_TOKEN
+
+# sync a subtree to another path, showing what would change first
+secrets sync secret/dev/app secret/stage/app --dry-run
+`,
+	}
+}
+
+func main() {
+	cmd := command()
+	flagAddr = cmd.PersistentFlags().String("addr", "", "The vault address (defaults to `VAULT_ADDR`).")
+	flagToken = cmd.PersistentFlags().String("token", "", "The vault token (defaults to `VAULT_TOKEN`).")
+	flagMount = cmd.PersistentFlags().String("mount", "", "The kv mount used to detect the kv version (defaults to `/secret`).")
+
+	cmd.AddCommand(
+		listCommand(),
+		getCommand(),
+		putCommand(),
+		exportCommand(),
+		importCommand(),
+		diffCommand(),
+		syncCommand(),
+	)
+	if err := cmd.Execute(); err != nil {
+		logger.FatalExit(err)
+	}
+}
+
+func fatalExit(action func(*cobra.Command, []string) error) func(*cobra.Command, []string) {
+	return func(parent *cobra.Command, args []string) {
+		if err := action(parent, args); err != nil {
+			logger.FatalExit(err)
+		}
+	}
+}
+
+// newClient returns a vault client from the environment, with the flags overriding the address and token.
+func newClient(addr, token string) (*secrets.VaultClient, error) {
+	cfg, err := secrets.NewConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if addr != "" {
+		cfg.Addr = addr
+	}
+	if token != "" {
+		cfg.Token = token
+	}
+	if *flagMount != "" {
+		cfg.Mount = *flagMount
+	}
+	client, err := secrets.New(secrets.OptConfig(cfg))
+	if err != nil {
+		return nil, err
+	}
+	if client.GetToken() == "" && client.Auth != nil {
+		if _, err := client.Login(context.Background()); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+func listCommand() *cobra.Command {
+	var recursive *bool
+	list := &cobra.Command{
+		Use:   "list [path]",
+		Short: "List the keys and subfolders at a path; subfolders end with a slash.",
+		Args:  cobra.ExactArgs(1),
+	}
+	recursive = list.Flags().BoolP("recursive", "r", false, "If the keys of subfolders should be listed.")
+	list.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		client, err := newClient(*flagAddr, *flagToken)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		if *recursive {
+			secretsTree, err := readTree(ctx, client, args[0])
+			if err != nil {
+				return err
+			}
+			for _, key := range secretsTree.Keys() {
+				fmt.Println(key)
+			}
+			return nil
+		}
+		keys, err := client.List(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Println(key)
+		}
+		return nil
+	})
+	return list
+}
+
+func getCommand() *cobra.Command {
+	var field, output *string
+	get := &cobra.Command{
+		Use:   "get [key]",
+		Short: "Get a secret, or a single field of it.",
+		Args:  cobra.ExactArgs(1),
+	}
+	field = get.Flags().StringP("field", "f", "", "A single field to print the value of.")
+	output = get.Flags().StringP("output", "o", formatText, "The output format, one of `text`, `json` or `yaml`.")
+	get.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		client, err := newClient(*flagAddr, *flagToken)
+		if err != nil {
+			return err
+		}
+		values, err := client.Get(context.Background(), args[0])
+		if err != nil {
+			return err
+		}
+		if *field != "" {
+			value, ok := values[*field]
+			if !ok {
+				return ex.New(secrets.ErrNotFound, ex.OptMessagef("key: %s; field: %s", args[0], *field))
+			}
+			fmt.Println(value)
+			return nil
+		}
+		if *output == formatText {
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("%s=%s\n", key, values[key])
+			}
+			return nil
+		}
+		return write(os.Stdout, *output, values)
+	})
+	return get
+}
+
+func putCommand() *cobra.Command {
+	var replace *bool
+	put := &cobra.Command{
+		Use:   "put [key] [field=value...]",
+		Short: "Put fields of a secret; other existing fields are kept unless --replace is set.",
+		Args:  cobra.MinimumNArgs(2),
+	}
+	replace = put.Flags().Bool("replace", false, "If the secret should be replaced with only the given fields.")
+	put.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		client, err := newClient(*flagAddr, *flagToken)
+		if err != nil {
+			return err
+		}
+		ctx := context.Background()
+		values := make(secrets.Values)
+		if !*replace {
+			existing, err := client.Get(ctx, args[0])
+			if err != nil && !ex.Is(err, secrets.ErrNotFound) {
+				return err
+			}
+			for field, value := range existing {
+				values[field] = value
+			}
+		}
+		for _, pair := range args[1:] {
+			index := strings.Index(pair, "=")
+			if index < 1 {
+				return ex.New("invalid field; should be of the form `field=value`", ex.OptMessage(pair))
+			}
+			values[pair[:index]] = pair[index+1:]
+		}
+		return client.Put(ctx, args[0], values)
+	})
+	return put
+}
+
+func exportCommand() *cobra.Command {
+	var output *string
+	var jsonValues *bool
+	export := &cobra.Command{
+		Use:   "export [path]",
+		Short: "Export the secrets under a path, keyed by their path relative to it.",
+		Args:  cobra.ExactArgs(1),
+	}
+	output = export.Flags().StringP("output", "o", formatJSON, "The output format, one of `json` or `yaml`.")
+	jsonValues = export.Flags().Bool("json-values", false, "If values written as json, e.g. with `WriteInto`, should be decoded.")
+	export.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		client, err := newClient(*flagAddr, *flagToken)
+		if err != nil {
+			return err
+		}
+		secretsTree, err := readTree(context.Background(), client, args[0])
+		if err != nil {
+			return err
+		}
+		return write(os.Stdout, *output, exportTree(secretsTree, *jsonValues))
+	})
+	return export
+}
+
+func importCommand() *cobra.Command {
+	var dryRun, jsonValues *bool
+	importCmd := &cobra.Command{
+		Use:   "import [path] [file]",
+		Short: "Import exported secrets under a path from a json or yaml file ('-' reads from stdin).",
+		Args:  cobra.ExactArgs(2),
+	}
+	dryRun = importCmd.Flags().Bool("dry-run", false, "If the secrets that would be written should only be printed.")
+	jsonValues = importCmd.Flags().Bool("json-values", false, "If values should be written as json, e.g. to be read with `ReadInto`.")
+	importCmd.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		contents, err := readContents(args[1])
+		if err != nil {
+			return err
+		}
+		var document map[string]map[string]interface{}
+		if err := yaml.Unmarshal(contents, &document); err != nil {
+			return ex.New(err)
+		}
+		source, err := importTree(document, *jsonValues)
+		if err != nil {
+			return err
+		}
+		client, err := newClient(*flagAddr, *flagToken)
+		if err != nil {
+			return err
+		}
+		result, err := syncTrees(context.Background(), client, args[0], source, nil, false, *dryRun)
+		printSyncResult(os.Stdout, args[0], result, *dryRun)
+		return err
+	})
+	return importCmd
+}
+
+func diffCommand() *cobra.Command {
+	var targetAddr, targetToken *string
+	var showValues *bool
+	diff := &cobra.Command{
+		Use:   "diff [source] [target]",
+		Short: "Diff the secrets under two paths, showing the added (+), removed (-) and changed (~) fields of the target.",
+		Args:  cobra.ExactArgs(2),
+	}
+	targetAddr = diff.Flags().String("target-addr", "", "The vault address of the target, if it is in another vault.")
+	targetToken = diff.Flags().String("target-token", "", "The vault token for the target, if it is in another vault.")
+	showValues = diff.Flags().Bool("show-values", false, "If values should be shown instead of masked.")
+	diff.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		source, target, _, err := readSourceAndTarget(args[0], args[1], *targetAddr, *targetToken)
+		if err != nil {
+			return err
+		}
+		printChanges(os.Stdout, diffTrees(source, target), *showValues)
+		return nil
+	})
+	return diff
+}
+
+func syncCommand() *cobra.Command {
+	var targetAddr, targetToken *string
+	var dryRun, deleteMissing *bool
+	sync := &cobra.Command{
+		Use:   "sync [source] [target]",
+		Short: "Sync the secrets under a source path to a target path, writing the secrets that are missing or differ.",
+		Args:  cobra.ExactArgs(2),
+	}
+	targetAddr = sync.Flags().String("target-addr", "", "The vault address of the target, if it is in another vault.")
+	targetToken = sync.Flags().String("target-token", "", "The vault token for the target, if it is in another vault.")
+	dryRun = sync.Flags().Bool("dry-run", false, "If the changes should only be printed.")
+	deleteMissing = sync.Flags().Bool("delete", false, "If target secrets that are not in the source should be deleted.")
+	sync.Run = fatalExit(func(_ *cobra.Command, args []string) error {
+		source, target, targetClient, err := readSourceAndTarget(args[0], args[1], *targetAddr, *targetToken)
+		if err != nil {
+			return err
+		}
+		if *dryRun {
+			printChanges(os.Stdout, diffTrees(target, source), false)
+		}
+		result, err := syncTrees(context.Background(), targetClient, args[1], source, target, *deleteMissing, *dryRun)
+		printSyncResult(os.Stdout, args[1], result, *dryRun)
+		return err
+	})
+	return sync
+}
+
+// readSourceAndTarget reads the source and target trees, and returns the client for the target.
+// A missing target is read as empty.
+func readSourceAndTarget(sourcePath, targetPath, targetAddr, targetToken string) (source, target tree, targetClient *secrets.VaultClient, err error) {
+	ctx := context.Background()
+	var sourceClient *secrets.VaultClient
+	sourceClient, err = newClient(*flagAddr, *flagToken)
+	if err != nil {
+		return
+	}
+	targetClient = sourceClient
+	if targetAddr != "" || targetToken != "" {
+		targetClient, err = newClient(stringOr(targetAddr, *flagAddr), stringOr(targetToken, *flagToken))
+		if err != nil {
+			return
+		}
+	}
+	source, err = readTree(ctx, sourceClient, sourcePath)
+	if err != nil {
+		return
+	}
+	target, err = readTree(ctx, targetClient, targetPath)
+	if ex.Is(err, secrets.ErrNotFound) {
+		target, err = tree{}, nil
+	}
+	return
+}
+
+func printSyncResult(w io.Writer, root string, result syncResult, dryRun bool) {
+	put, deleted := "put", "deleted"
+	if dryRun {
+		put, deleted = "would put", "would delete"
+	}
+	for _, key := range result.Put {
+		fmt.Fprintf(w, "%s %s\n", put, strings.TrimSuffix(root+"/"+key, "/"))
+	}
+	for _, key := range result.Deleted {
+		fmt.Fprintf(w, "%s %s\n", deleted, strings.TrimSuffix(root+"/"+key, "/"))
+	}
+}
+
+// write writes an object in a given format.
+func write(w io.Writer, format string, obj interface{}) error {
+	switch format {
+	case formatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return ex.New(encoder.Encode(obj))
+	case formatYAML:
+		return ex.New(yaml.NewEncoder(w).Encode(obj))
+	default:
+		return ex.New("invalid output format; should be one of `json` or `yaml`", ex.OptMessage(format))
+	}
+}
+
+func readContents(path string) ([]byte, error) {
+	if strings.TrimSpace(path) == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(path)
+}
+
+func stringOr(value, defaultValue string) string {
+	if value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+)
+
+// tree is a subtree of secrets keyed by their path relative to the subtree root.
+// If the root is itself a secret, it is keyed by the empty string.
+type tree map[string]secrets.Values
+
+// Keys returns the sorted secret keys.
+func (t tree) Keys() []string {
+	keys := make(map[string]bool, len(t))
+	for key := range t {
+		keys[key] = true
+	}
+	return sortedKeys(keys)
+}
+
+// readTree reads the secrets under a path, recursing into subfolders.
+// If the path has no children it is read as a single secret.
+func readTree(ctx context.Context, client secrets.Client, root string) (tree, error) {
+	output := make(tree)
+	if err := readTreeInto(ctx, client, root, "", output); err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		values, err := client.Get(ctx, root)
+		if err != nil {
+			return nil, err
+		}
+		output[""] = values
+	}
+	return output, nil
+}
+
+func readTreeInto(ctx context.Context, client secrets.Client, root, prefix string, output tree) error {
+	keys, err := client.List(ctx, filepath.Join(root, prefix))
+	if err != nil && !ex.Is(err, secrets.ErrNotFound) {
+		return err
+	}
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			if err := readTreeInto(ctx, client, root, filepath.Join(prefix, key), output); err != nil {
+				return err
+			}
+			continue
+		}
+		relative := filepath.Join(prefix, key)
+		values, err := client.Get(ctx, filepath.Join(root, relative))
+		if err != nil {
+			return err
+		}
+		output[relative] = values
+	}
+	return nil
+}
+
+// change kinds.
+const (
+	changeAdded   = "+"
+	changeRemoved = "-"
+	changeChanged = "~"
+)
+
+// change is a difference in a field of a secret between two trees.
+type change struct {
+	Kind  string
+	Key   string
+	Field string
+	From  string
+	To    string
+}
+
+// diffTrees returns the field changes that turn one tree into another, sorted by key and field.
+func diffTrees(from, to tree) (changes []change) {
+	keys := make(map[string]bool)
+	for key := range from {
+		keys[key] = true
+	}
+	for key := range to {
+		keys[key] = true
+	}
+	for _, key := range sortedKeys(keys) {
+		fields := make(map[string]bool)
+		for field := range from[key] {
+			fields[field] = true
+		}
+		for field := range to[key] {
+			fields[field] = true
+		}
+		for _, field := range sortedKeys(fields) {
+			fromValue, inFrom := from[key][field]
+			toValue, inTo := to[key][field]
+			switch {
+			case !inFrom:
+				changes = append(changes, change{Kind: changeAdded, Key: key, Field: field, To: toValue})
+			case !inTo:
+				changes = append(changes, change{Kind: changeRemoved, Key: key, Field: field, From: fromValue})
+			case fromValue != toValue:
+				changes = append(changes, change{Kind: changeChanged, Key: key, Field: field, From: fromValue, To: toValue})
+			}
+		}
+	}
+	return
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mask hides a secret value, keeping only its length.
+func mask(value string) string {
+	return fmt.Sprintf("****(%d)", len(value))
+}
+
+// printChanges writes changes one per line, masking values unless told otherwise.
+func printChanges(w io.Writer, changes []change, showValues bool) {
+	format := mask
+	if showValues {
+		format = func(value string) string { return value }
+	}
+	for _, c := range changes {
+		name := strings.TrimPrefix(c.Key+"#"+c.Field, "#")
+		switch c.Kind {
+		case changeAdded:
+			fmt.Fprintf(w, "%s %s = %s\n", c.Kind, name, format(c.To))
+		case changeRemoved:
+			fmt.Fprintf(w, "%s %s\n", c.Kind, name)
+		default:
+			fmt.Fprintf(w, "%s %s: %s -> %s\n", c.Kind, name, format(c.From), format(c.To))
+		}
+	}
+}
+
+// syncResult is the secrets a sync put and deleted.
+type syncResult struct {
+	Put     []string
+	Deleted []string
+}
+
+// syncTrees makes the secrets under a target root match a source tree, putting the secrets that are missing
+// or differ, and optionally deleting the secrets that are not in the source.
+// With dry run, it only returns what it would put and delete.
+func syncTrees(ctx context.Context, client secrets.Client, targetRoot string, source, target tree, deleteMissing, dryRun bool) (result syncResult, err error) {
+	for _, key := range source.Keys() {
+		if existing, ok := target[key]; ok && reflect.DeepEqual(existing, source[key]) {
+			continue
+		}
+		result.Put = append(result.Put, key)
+		if !dryRun {
+			if err = client.Put(ctx, filepath.Join(targetRoot, key), source[key]); err != nil {
+				return
+			}
+		}
+	}
+	if !deleteMissing {
+		return
+	}
+	for _, key := range target.Keys() {
+		if _, ok := source[key]; ok {
+			continue
+		}
+		result.Deleted = append(result.Deleted, key)
+		if !dryRun {
+			if err = client.Delete(ctx, filepath.Join(targetRoot, key)); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// exportTree returns a tree as a document for export.
+// With json values, the values written with `secrets.DecomposeJSON`, i.e. by `WriteInto`, are decoded
+// so the export holds their structured values instead of json strings.
+func exportTree(t tree, jsonValues bool) map[string]map[string]interface{} {
+	output := make(map[string]map[string]interface{}, len(t))
+	for key, values := range t {
+		fields := make(map[string]interface{}, len(values))
+		for field, value := range values {
+			var decoded interface{}
+			if jsonValues && json.Unmarshal([]byte(value), &decoded) == nil {
+				fields[field] = decoded
+				continue
+			}
+			fields[field] = value
+		}
+		output[key] = fields
+	}
+	return output
+}
+
+// importTree returns a tree from an exported document.
+// Non string values are encoded as json; with json values, every value is encoded as json, as `secrets.DecomposeJSON`
+// does, so the secrets can be read with `secrets.RestoreJSON`, i.e. by `ReadInto`.
+func importTree(document map[string]map[string]interface{}, jsonValues bool) (tree, error) {
+	output := make(tree, len(document))
+	for key, fields := range document {
+		values := make(secrets.Values, len(fields))
+		for field, value := range fields {
+			if typed, ok := value.(string); ok && !jsonValues {
+				values[field] = typed
+				continue
+			}
+			contents, err := json.Marshal(normalize(value))
+			if err != nil {
+				return nil, err
+			}
+			values[field] = string(contents)
+		}
+		output[key] = values
+	}
+	return output, nil
+}
+
+// normalize converts the maps yaml decodes nested values into to maps with string keys, so they can be encoded as json.
+func normalize(value interface{}) interface{} {
+	switch typed := value.(type) {
+	case map[interface{}]interface{}:
+		output := make(map[string]interface{}, len(typed))
+		for key, elem := range typed {
+			output[fmt.Sprint(key)] = normalize(elem)
+		}
+		return output
+	case map[string]interface{}:
+		output := make(map[string]interface{}, len(typed))
+		for key, elem := range typed {
+			output[key] = normalize(elem)
+		}
+		return output
+	case []interface{}:
+		output := make([]interface{}, len(typed))
+		for index, elem := range typed {
+			output[index] = normalize(elem)
+		}
+		return output
+	default:
+		return value
+	}
+}
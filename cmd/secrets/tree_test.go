@@ -0,0 +1,131 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/secrets"
+	"github.com/blend/go-sdk/yaml"
+)
+
+func TestReadTree(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	client := secrets.NewMockClient()
+	client.SecretValues["secret/dev/app/db"] = secrets.Values{"password": "hunter2"}
+	client.SecretValues["secret/dev/app/services/email"] = secrets.Values{"apiKey": "abcd"}
+	client.SecretValues["secret/dev/other"] = secrets.Values{"foo": "bar"}
+
+	secretsTree, err := readTree(todo, client, "secret/dev/app")
+	assert.Nil(err)
+	assert.Equal([]string{"db", "services/email"}, secretsTree.Keys())
+	assert.Equal("hunter2", secretsTree["db"]["password"])
+
+	single, err := readTree(todo, client, "secret/dev/app/db")
+	assert.Nil(err)
+	assert.Equal([]string{""}, single.Keys(), "a secret path should be read as a single secret")
+
+	_, err = readTree(todo, client, "secret/dev/missing")
+	assert.NotNil(err)
+}
+
+func TestDiffTrees(t *testing.T) {
+	assert := assert.New(t)
+
+	from := tree{
+		"db":    {"username": "app", "password": "hunter2", "host": "db.dev"},
+		"email": {"apiKey": "abcd"},
+	}
+	to := tree{
+		"db":    {"username": "app", "password": "correct horse", "port": "5432"},
+		"queue": {"url": "amqp://queue"},
+	}
+	changes := diffTrees(from, to)
+	assert.Equal([]change{
+		{Kind: changeRemoved, Key: "db", Field: "host", From: "db.dev"},
+		{Kind: changeChanged, Key: "db", Field: "password", From: "hunter2", To: "correct horse"},
+		{Kind: changeAdded, Key: "db", Field: "port", To: "5432"},
+		{Kind: changeRemoved, Key: "email", Field: "apiKey", From: "abcd"},
+		{Kind: changeAdded, Key: "queue", Field: "url", To: "amqp://queue"},
+	}, changes)
+	assert.Empty(diffTrees(from, from))
+
+	output := new(bytes.Buffer)
+	printChanges(output, changes, false)
+	assert.Contains(output.String(), "~ db#password: ****(7) -> ****(13)\n")
+	assert.Contains(output.String(), "- db#host\n")
+	assert.NotContains(output.String(), "hunter2", "values should be masked")
+	assert.NotContains(output.String(), "db.dev", "removed values should not be shown")
+
+	output.Reset()
+	printChanges(output, changes, true)
+	assert.Contains(output.String(), "~ db#password: hunter2 -> correct horse\n")
+}
+
+func TestSyncTrees(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	client := secrets.NewMockClient()
+	client.SecretValues["secret/stage/app/db"] = secrets.Values{"password": "old"}
+	client.SecretValues["secret/stage/app/email"] = secrets.Values{"apiKey": "abcd"}
+	client.SecretValues["secret/stage/app/legacy"] = secrets.Values{"foo": "bar"}
+
+	source := tree{
+		"db":    {"password": "new"},
+		"email": {"apiKey": "abcd"},
+		"queue": {"url": "amqp://queue"},
+	}
+	target, err := readTree(todo, client, "secret/stage/app")
+	assert.Nil(err)
+
+	result, err := syncTrees(todo, client, "secret/stage/app", source, target, true, true)
+	assert.Nil(err)
+	assert.Equal([]string{"db", "queue"}, result.Put)
+	assert.Equal([]string{"legacy"}, result.Deleted)
+	assert.Equal("old", client.SecretValues["secret/stage/app/db"]["password"], "dry runs should not write")
+	assert.Len(client.SecretValues, 3)
+
+	result, err = syncTrees(todo, client, "secret/stage/app", source, target, false, false)
+	assert.Nil(err)
+	assert.Equal([]string{"db", "queue"}, result.Put)
+	assert.Empty(result.Deleted)
+	assert.Equal("new", client.SecretValues["secret/stage/app/db"]["password"])
+	assert.Equal("amqp://queue", client.SecretValues["secret/stage/app/queue"]["url"])
+	assert.NotNil(client.SecretValues["secret/stage/app/legacy"])
+}
+
+func TestExportImportTree(t *testing.T) {
+	assert := assert.New(t)
+
+	type dbConfig struct {
+		Username string   `secret:"username"`
+		Port     int      `secret:"port"`
+		Hosts    []string `secret:"hosts"`
+	}
+	values, err := secrets.DecomposeJSON(dbConfig{Username: "app", Port: 5432, Hosts: []string{"a", "b"}})
+	assert.Nil(err)
+	original := tree{"db": values}
+
+	exported := exportTree(original, true)
+	assert.Equal("app", exported["db"]["username"])
+	assert.Equal(5432.0, exported["db"]["port"])
+
+	contents, err := yaml.Marshal(exported)
+	assert.Nil(err)
+	var document map[string]map[string]interface{}
+	assert.Nil(yaml.Unmarshal(contents, &document))
+
+	imported, err := importTree(document, true)
+	assert.Nil(err)
+	var restored dbConfig
+	assert.Nil(secrets.RestoreJSON(imported["db"], &restored))
+	assert.Equal(dbConfig{Username: "app", Port: 5432, Hosts: []string{"a", "b"}}, restored)
+
+	plain, err := importTree(exportTree(tree{"email": {"apiKey": "abcd"}}, false), false)
+	assert.Nil(err)
+	assert.Equal("abcd", plain["email"]["apiKey"])
+}
@@ -4,4 +4,10 @@ package configutil
 type ConfigOptions struct {
 	Resolver func(interface{}) error
 	Paths    []string
+	// OverlayNames are the names of overlays read next to the config file, e.g. `prod` for `config.prod.yml`.
+	OverlayNames []string
+	// Overlays are the paths of files deep merged over the config file in order.
+	Overlays []string
+	// Provenance, if set, records where the final value of each field came from.
+	Provenance Provenance
 }
@@ -0,0 +1,6 @@
+package configutil
+
+// ConfigValidator is a type that can be validated.
+type ConfigValidator interface {
+	Validate() error
+}
@@ -12,12 +12,19 @@ var (
 	_ IntSource      = (*Env)(nil)
 	_ Float64Source  = (*Env)(nil)
 	_ DurationSource = (*Env)(nil)
+
+	_ ProvenanceSource = (*Env)(nil)
 )
 
 // Env is a value provider where the string represents the environment variable name.
 // It can be used with *any* config.Set___ type.
 type Env string
 
+// Provenance returns the provenance of values read from the environment variable, e.g. `env:<name>`.
+func (e Env) Provenance() string {
+	return ProvenanceEnv + string(e)
+}
+
 // String returns a given environment variable as a string.
 func (e Env) String() (*string, error) {
 	key := string(e)
@@ -12,6 +12,19 @@ const (
 
 	// ErrInvalidConfigExtension is a common error.
 	ErrInvalidConfigExtension = ex.Class("config extension invalid")
+
+	// ErrInvalidSecretRef is returned for secret references that aren't of the form `vault://<key>#<field>`.
+	ErrInvalidSecretRef = ex.Class("config secret reference invalid")
+
+	// ErrSecretRefNotFound is returned when the field a secret reference references does not exist.
+	ErrSecretRefNotFound = ex.Class("config secret reference not found")
+
+	// ErrSecretNotFound is the class of the errors secrets clients return for secrets that don't exist.
+	// It matches `secrets.ErrNotFound`.
+	ErrSecretNotFound = ex.Class("secrets; not found")
+
+	// ErrConfigRefNotPointer is returned when a config to watch isn't a pointer, so new instances of it can't be read.
+	ErrConfigRefNotPointer = ex.Class("config reference must be a pointer")
 )
 
 // IsIgnored returns if we should ignore the config read error.
@@ -45,3 +58,23 @@ func IsConfigPathUnset(err error) bool {
 func IsInvalidConfigExtension(err error) bool {
 	return ex.Is(err, ErrInvalidConfigExtension)
 }
+
+// IsInvalidSecretRef returns if an error is an ErrInvalidSecretRef.
+func IsInvalidSecretRef(err error) bool {
+	return ex.Is(err, ErrInvalidSecretRef)
+}
+
+// IsSecretRefNotFound returns if an error is an ErrSecretRefNotFound.
+func IsSecretRefNotFound(err error) bool {
+	return ex.Is(err, ErrSecretRefNotFound)
+}
+
+// IsSecretNotFound returns if an error is an ErrSecretNotFound.
+func IsSecretNotFound(err error) bool {
+	return ex.Is(err, ErrSecretNotFound)
+}
+
+// IsConfigRefNotPointer returns if an error is an ErrConfigRefNotPointer.
+func IsConfigRefNotPointer(err error) bool {
+	return ex.Is(err, ErrConfigRefNotPointer)
+}
@@ -0,0 +1,66 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ StringSource   = (*File)(nil)
+	_ StringsSource  = (*File)(nil)
+	_ BoolSource     = (*File)(nil)
+	_ IntSource      = (*File)(nil)
+	_ Float64Source  = (*File)(nil)
+	_ DurationSource = (*File)(nil)
+)
+
+// File is a value provider where the string represents the path of a file that holds the value,
+// e.g. a key of a kubernetes secret mounted as a volume.
+// Trailing newlines are trimmed from the value. If the file does not exist, the value is not present.
+// It can be used with *any* config.Set___ type.
+type File string
+
+// String returns the contents of the file as a string.
+func (f File) String() (*string, error) {
+	contents, err := ioutil.ReadFile(string(f))
+	if IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	value := strings.TrimRight(string(contents), "\r\n")
+	return &value, nil
+}
+
+// Strings returns the contents of the file as csv strings.
+func (f File) Strings() ([]string, error) {
+	value, err := f.String()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return strings.Split(*value, ","), nil
+}
+
+// Bool returns the contents of the file as a bool.
+func (f File) Bool() (*bool, error) {
+	return Parse(f).Bool()
+}
+
+// Int returns the contents of the file as an int.
+func (f File) Int() (*int, error) {
+	return Parse(f).Int()
+}
+
+// Float64 returns the contents of the file as a float64.
+func (f File) Float64() (*float64, error) {
+	return Parse(f).Float64()
+}
+
+// Duration returns the contents of the file as a time.Duration.
+func (f File) Duration() (*time.Duration, error) {
+	return Parse(f).Duration()
+}
@@ -0,0 +1,46 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestFile(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "password"), []byte("hunter2\n"), 0600))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "hosts"), []byte("a,b\n"), 0600))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "timeout"), []byte("5s"), 0600))
+	assert.Nil(ioutil.WriteFile(filepath.Join(dir, "enabled"), []byte("true"), 0600))
+
+	password := "default"
+	assert.Nil(SetString(&password, File(filepath.Join(dir, "password")), String("fallback")))
+	assert.Equal("hunter2", password)
+
+	assert.Nil(SetString(&password, File(filepath.Join(dir, "missing")), String("fallback")))
+	assert.Equal("fallback", password)
+
+	var hosts []string
+	assert.Nil(SetStrings(&hosts, File(filepath.Join(dir, "hosts"))))
+	assert.Equal([]string{"a", "b"}, hosts)
+
+	var timeout time.Duration
+	assert.Nil(SetDuration(&timeout, File(filepath.Join(dir, "timeout"))))
+	assert.Equal(5*time.Second, timeout)
+
+	var enabled *bool
+	assert.Nil(SetBool(&enabled, File(filepath.Join(dir, "enabled"))))
+	assert.True(*enabled)
+
+	var port int
+	assert.NotNil(SetInt(&port, File(filepath.Join(dir, "password"))))
+}
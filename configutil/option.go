@@ -1,5 +1,7 @@
 package configutil
 
+import "github.com/blend/go-sdk/env"
+
 // Option is a modification of config options.
 type Option func(*ConfigOptions) error
 
@@ -34,3 +36,36 @@ func OptResolver(resolver func(interface{}) error) Option {
 		return nil
 	}
 }
+
+// OptOverlays adds files that are deep merged over the config file in order; files that don't exist are skipped.
+// Fields set by an overlay replace the values of earlier files, and fields it doesn't set are kept.
+func OptOverlays(paths ...string) Option {
+	return func(co *ConfigOptions) error {
+		co.Overlays = append(co.Overlays, paths...)
+		return nil
+	}
+}
+
+// OptEnvOverlays adds overlays next to the config file for a service environment and for local overrides,
+// e.g. for `config.yml`, `config.prod.yml` and then `config.local.yml`, which should not be committed.
+// If the service environment is unset it defaults to `SERVICE_ENV`.
+func OptEnvOverlays(serviceEnv string) Option {
+	return func(co *ConfigOptions) error {
+		if serviceEnv == "" {
+			serviceEnv = env.Env().ServiceEnv()
+		}
+		if serviceEnv != "" {
+			co.OverlayNames = append(co.OverlayNames, serviceEnv)
+		}
+		co.OverlayNames = append(co.OverlayNames, OverlayLocal)
+		return nil
+	}
+}
+
+// OptProvenance sets a provenance to record where the final value of each field came from.
+func OptProvenance(provenance Provenance) Option {
+	return func(co *ConfigOptions) error {
+		co.Provenance = provenance
+		return nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 var (
+	_ BoolSource     = (*Parser)(nil)
 	_ IntSource      = (*Parser)(nil)
 	_ Float64Source  = (*Parser)(nil)
 	_ DurationSource = (*Parser)(nil)
@@ -23,6 +24,22 @@ type Parser struct {
 	Source StringSource
 }
 
+// Bool returns the bool value.
+func (p Parser) Bool() (*bool, error) {
+	value, err := p.Source.String()
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseBool(*value)
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	return &parsed, nil
+}
+
 // Int returns the int value.
 func (p Parser) Int() (*int, error) {
 	value, err := p.Source.String()
@@ -0,0 +1,355 @@
+package configutil
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/yaml"
+)
+
+// Provenance source prefixes and sources.
+const (
+	ProvenanceFile    = "file:"
+	ProvenanceEnv     = "env:"
+	ProvenanceResolve = "resolve"
+)
+
+// maxProvenanceDepth bounds how deep configs are walked, so self referencing configs terminate.
+const maxProvenanceDepth = 32
+
+// Provenance records where the final value of each field of a config came from.
+//
+// It is keyed by the path of the field's yaml keys, e.g. `web.bindAddr`, and the sources are `file:<path>` for
+// config files and overlays, the source's own provenance, e.g. `env:<name>`, for values set in `Resolve()` by the
+// `Set___` functions from a `ProvenanceSource` such as `Env`, and `resolve` for other values `Resolve()` changes.
+// Fields that are never set are not included.
+type Provenance map[string]string
+
+// ProvenanceSource is a value source that reports where its values come from, e.g. `env:<name>`.
+// Fields set from it by the `Set___` functions while a config is resolved are attributed to it.
+type ProvenanceSource interface {
+	Provenance() string
+}
+
+// Fields returns the sorted field paths.
+func (p Provenance) Fields() []string {
+	fields := make([]string, 0, len(p))
+	for field := range p {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// String returns the provenance with a field per line, e.g. `web.bindAddr: file:config.prod.yml`.
+func (p Provenance) String() string {
+	lines := make([]string, 0, len(p))
+	for _, field := range p.Fields() {
+		lines = append(lines, field+": "+p[field])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trackFile records the fields set by a config file.
+func (p Provenance) trackFile(path string, contents []byte, ref Any) error {
+	var document interface{}
+	// json is also valid yaml, so either format is read into a generic document.
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return ex.New(err)
+	}
+	isJSON := strings.ToLower(filepath.Ext(path)) == ExtensionJSON
+	for _, field := range documentFields(document, reflect.TypeOf(ref), "", isJSON, 0) {
+		p[field] = ProvenanceFile + path
+	}
+	return nil
+}
+
+// trackResolve starts recording the fields set while a config is resolved, and returns a function that
+// stops recording and attributes the fields resolving changed that no source was recorded for to `resolve`.
+func (p Provenance) trackResolve(ref Any) func() {
+	tracker := &resolveTracker{
+		provenance: p,
+		addresses:  make(map[fieldAddress]string),
+		set:        make(map[string]bool),
+	}
+	before := make(map[string]interface{})
+	flattenFields(reflect.ValueOf(ref), "", before, tracker.addresses, 0)
+
+	resolvingMu.Lock()
+	resolving[tracker] = struct{}{}
+	resolvingMu.Unlock()
+
+	return func() {
+		resolvingMu.Lock()
+		delete(resolving, tracker)
+		resolvingMu.Unlock()
+
+		after := make(map[string]interface{})
+		flattenFields(reflect.ValueOf(ref), "", after, nil, 0)
+		for field, value := range after {
+			if tracker.set[field] {
+				continue
+			}
+			if previous, ok := before[field]; ok && reflect.DeepEqual(previous, value) {
+				continue
+			}
+			if isZero(value) {
+				continue
+			}
+			p[field] = ProvenanceResolve
+		}
+	}
+}
+
+var (
+	resolvingMu sync.Mutex
+	// resolving are the trackers of the configs being resolved.
+	resolving = make(map[*resolveTracker]struct{})
+)
+
+// resolveTracker records the sources of the fields of a config set while it is resolved.
+type resolveTracker struct {
+	provenance Provenance
+	// addresses are the paths of the fields of the config by address.
+	addresses map[fieldAddress]string
+	// set are the fields a source was recorded for.
+	set map[string]bool
+}
+
+// fieldAddress is the address and type of a field; the type tells apart a struct and its first field.
+type fieldAddress struct {
+	Pointer uintptr
+	Type    reflect.Type
+}
+
+// trackSet records the source of a value a `Set___` function is about to set to a destination, if the destination
+// is a field of a config being resolved. Sources without a provenance are only recorded if they change the value,
+// so sources that pass the existing value through, e.g. `String(cfg.Name)`, keep its provenance.
+func trackSet(destination, source, value interface{}) {
+	resolvingMu.Lock()
+	defer resolvingMu.Unlock()
+	if len(resolving) == 0 {
+		return
+	}
+	pointer := reflect.ValueOf(destination)
+	address := fieldAddress{Pointer: pointer.Pointer(), Type: pointer.Type().Elem()}
+	for tracker := range resolving {
+		field, ok := tracker.addresses[address]
+		if !ok {
+			continue
+		}
+		if typed, ok := source.(ProvenanceSource); ok {
+			tracker.provenance[field] = typed.Provenance()
+			tracker.set[field] = true
+		} else if !reflect.DeepEqual(pointer.Elem().Interface(), value) {
+			tracker.provenance[field] = ProvenanceResolve
+			tracker.set[field] = true
+		}
+	}
+}
+
+// documentFields returns the paths of the fields a generic yaml or json document sets in a config type.
+func documentFields(document interface{}, t reflect.Type, prefix string, isJSON bool, depth int) (fields []string) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	values, isMap := documentMap(document)
+	if t == nil || !isMap || depth > maxProvenanceDepth || isLeafType(t) {
+		if prefix != "" {
+			fields = append(fields, prefix)
+		}
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		for key, value := range values {
+			name, fieldType, ok := findField(t, key, isJSON)
+			if !ok {
+				continue
+			}
+			fields = append(fields, documentFields(value, fieldType, joinField(prefix, name), isJSON, depth+1)...)
+		}
+	case reflect.Map:
+		for key, value := range values {
+			fields = append(fields, documentFields(value, t.Elem(), joinField(prefix, key), isJSON, depth+1)...)
+		}
+	default:
+		if prefix != "" {
+			fields = append(fields, prefix)
+		}
+	}
+	return
+}
+
+// findField returns the yaml key and type of the field of a struct a document key sets, looking in inlined structs.
+// Yaml keys match exactly, json keys match the json name case insensitively, as the decoders do.
+func findField(t reflect.Type, key string, isJSON bool) (name string, fieldType reflect.Type, ok bool) {
+	for x := 0; x < t.NumField(); x++ {
+		field := t.Field(x)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+		if isInline(field) {
+			inlineType := field.Type
+			for inlineType.Kind() == reflect.Ptr {
+				inlineType = inlineType.Elem()
+			}
+			if inlineType.Kind() == reflect.Struct {
+				if name, fieldType, ok = findField(inlineType, key, isJSON); ok {
+					return
+				}
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if isJSON {
+			jsonName := tagName(field, "json")
+			if jsonName == "-" {
+				continue
+			}
+			if jsonName == "" {
+				jsonName = field.Name
+			}
+			if !strings.EqualFold(jsonName, key) {
+				continue
+			}
+		} else if yamlName(field) != key {
+			continue
+		}
+		return fieldName(field), field.Type, true
+	}
+	return
+}
+
+// flattenFields records the values of the fields of a config by path, and the paths of its fields by address.
+func flattenFields(value reflect.Value, prefix string, output map[string]interface{}, addresses map[fieldAddress]string, depth int) {
+	if !value.IsValid() || depth > maxProvenanceDepth {
+		return
+	}
+	if addresses != nil && prefix != "" && value.CanAddr() {
+		addresses[fieldAddress{Pointer: value.Addr().Pointer(), Type: value.Type()}] = prefix
+	}
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			if prefix != "" {
+				output[prefix] = nil
+			}
+			return
+		}
+		value = value.Elem()
+	}
+	switch {
+	case value.Kind() == reflect.Struct && !isLeafType(value.Type()):
+		for x := 0; x < value.NumField(); x++ {
+			field := value.Type().Field(x)
+			if field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if isInline(field) {
+				flattenFields(value.Field(x), prefix, output, addresses, depth+1)
+				continue
+			}
+			if field.PkgPath != "" || yamlName(field) == "-" {
+				continue
+			}
+			flattenFields(value.Field(x), joinField(prefix, fieldName(field)), output, addresses, depth+1)
+		}
+	case value.Kind() == reflect.Map && value.Type().Key().Kind() == reflect.String && !isLeafType(value.Type().Elem()):
+		iter := value.MapRange()
+		for iter.Next() {
+			flattenFields(iter.Value(), joinField(prefix, iter.Key().String()), output, addresses, depth+1)
+		}
+	default:
+		if prefix != "" && value.CanInterface() {
+			output[prefix] = value.Interface()
+		}
+	}
+}
+
+// documentMap returns a generic document as a map with string keys, if it is a map.
+func documentMap(document interface{}) (map[string]interface{}, bool) {
+	switch typed := document.(type) {
+	case map[string]interface{}:
+		return typed, true
+	case map[interface{}]interface{}:
+		output := make(map[string]interface{}, len(typed))
+		for key, value := range typed {
+			output[fmt.Sprint(key)] = value
+		}
+		return output, true
+	default:
+		return nil, false
+	}
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	yamlUnmarshalerType = reflect.TypeOf((*yaml.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isLeafType returns if a type is decoded as a whole, i.e. it isn't a struct or map, or it decodes itself.
+func isLeafType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct && t.Kind() != reflect.Map {
+		return true
+	}
+	pointer := reflect.PtrTo(t)
+	return pointer.Implements(jsonUnmarshalerType) || pointer.Implements(yamlUnmarshalerType) || pointer.Implements(textUnmarshalerType)
+}
+
+// isInline returns if a field's fields are read as fields of its parent.
+func isInline(field reflect.StructField) bool {
+	if strings.Contains(field.Tag.Get("yaml"), ",inline") {
+		return true
+	}
+	return field.Anonymous && tagName(field, "yaml") == "" && tagName(field, "json") == ""
+}
+
+// fieldName returns the name a field is tracked by, its yaml key, or its json name if it has no yaml tag.
+func fieldName(field reflect.StructField) string {
+	if name := tagName(field, "yaml"); name != "" {
+		return name
+	}
+	if name := tagName(field, "json"); name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// yamlName returns the key yaml reads a field from.
+func yamlName(field reflect.StructField) string {
+	if name := tagName(field, "yaml"); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+func tagName(field reflect.StructField, tag string) string {
+	return strings.Split(field.Tag.Get(tag), ",")[0]
+}
+
+func joinField(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
+
+func isZero(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	return reflect.DeepEqual(value, reflect.Zero(reflect.TypeOf(value)).Interface())
+}
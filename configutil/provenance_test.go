@@ -0,0 +1,205 @@
+package configutil
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+)
+
+type layeredWebConfig struct {
+	BindAddr string   `yaml:"bindAddr"`
+	Hosts    []string `yaml:"hosts"`
+}
+
+type LayeredBaseConfig struct {
+	Name string `yaml:"name"`
+}
+
+type layeredConfig struct {
+	LayeredBaseConfig `yaml:",inline"`
+	Environment       string            `yaml:"env"`
+	Web               layeredWebConfig  `yaml:"web"`
+	Labels            map[string]string `yaml:"labels"`
+	Debug             bool              `yaml:"debug"`
+}
+
+func (lc *layeredConfig) Resolve() error {
+	if err := SetString(&lc.Environment, Env("SERVICE_ENV"), String(lc.Environment)); err != nil {
+		return err
+	}
+	return SetString(&lc.Web.BindAddr, Env("BIND_ADDR"), String(lc.Web.BindAddr), String(":8080"))
+}
+
+func writeLayers(assert *assert.Assertions, dir string, files map[string]string) {
+	for name, contents := range files {
+		assert.Nil(ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600))
+	}
+}
+
+func TestReadOverlays(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+	env.SetEnv(env.Vars{})
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	writeLayers(assert, dir, map[string]string{
+		"config.yml":       "name: app\nweb:\n  bindAddr: :5000\n  hosts: [a, b]\nlabels:\n  team: core\n  tier: web\n",
+		"config.prod.yml":  "web:\n  hosts: [c]\nlabels:\n  tier: api\n",
+		"config.local.yml": "debug: true\n",
+	})
+	path := filepath.Join(dir, "config.yml")
+
+	var cfg layeredConfig
+	readPath, err := Read(&cfg, OptPaths(path), OptEnvOverlays("prod"))
+	assert.Nil(err)
+	assert.Equal(path, readPath)
+	assert.Equal("app", cfg.Name)
+	assert.Equal(":5000", cfg.Web.BindAddr)
+	assert.Equal([]string{"c"}, cfg.Web.Hosts)
+	assert.Equal(map[string]string{"team": "core", "tier": "api"}, cfg.Labels)
+	assert.True(cfg.Debug)
+}
+
+func TestReadOverlaysMissing(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+	env.SetEnv(env.Vars{})
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	writeLayers(assert, dir, map[string]string{
+		"config.yml":      "name: app\n",
+		"override.yml":    "web:\n  bindAddr: :9000\n",
+		"config.beta.yml": "name: beta\n",
+	})
+
+	var cfg layeredConfig
+	_, err = Read(&cfg,
+		OptPaths(filepath.Join(dir, "config.yml")),
+		OptEnvOverlays("prod"),
+		OptOverlays(filepath.Join(dir, "override.yml"), filepath.Join(dir, "missing.yml")),
+	)
+	assert.Nil(err)
+	assert.Equal("app", cfg.Name)
+	assert.Equal(":9000", cfg.Web.BindAddr)
+}
+
+func TestReadOverlaysInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	writeLayers(assert, dir, map[string]string{
+		"config.yml":      "name: app\n",
+		"config.prod.yml": "web: [\n",
+	})
+
+	var cfg layeredConfig
+	_, err = Read(&cfg, OptPaths(filepath.Join(dir, "config.yml")), OptEnvOverlays("prod"))
+	assert.NotNil(err)
+	assert.False(IsNotExist(err))
+}
+
+func TestOverlayPath(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("_config/config.prod.yml", OverlayPath("_config/config.yml", "prod"))
+	assert.Equal("config.local.json", OverlayPath("config.json", OverlayLocal))
+	assert.Equal("config.local", OverlayPath("config", OverlayLocal))
+}
+
+func TestReadProvenance(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+	env.SetEnv(env.Vars{"SERVICE_ENV": "prod"})
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	writeLayers(assert, dir, map[string]string{
+		"config.yml":       "name: app\nweb:\n  hosts: [a, b]\nlabels:\n  team: core\n  tier: web\n",
+		"config.prod.yml":  "web:\n  hosts: [c]\nlabels:\n  tier: api\n",
+		"config.local.yml": "debug: true\n",
+	})
+	base := filepath.Join(dir, "config.yml")
+	prod := filepath.Join(dir, "config.prod.yml")
+	local := filepath.Join(dir, "config.local.yml")
+
+	var cfg layeredConfig
+	provenance := Provenance{}
+	_, err = Read(&cfg, OptPaths(base), OptEnvOverlays(""), OptProvenance(provenance))
+	assert.Nil(err)
+
+	assert.Equal(Provenance{
+		"name":         ProvenanceFile + base,
+		"env":          ProvenanceEnv + "SERVICE_ENV",
+		"web.bindAddr": ProvenanceResolve,
+		"web.hosts":    ProvenanceFile + prod,
+		"labels.team":  ProvenanceFile + base,
+		"labels.tier":  ProvenanceFile + prod,
+		"debug":        ProvenanceFile + local,
+	}, provenance)
+	assert.Equal([]string{"debug", "env", "labels.team", "labels.tier", "name", "web.bindAddr", "web.hosts"}, provenance.Fields())
+}
+
+func TestReadProvenanceEnvSameAsFile(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+	env.SetEnv(env.Vars{"BIND_ADDR": ":5000"})
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	writeLayers(assert, dir, map[string]string{
+		"config.yml": "env: dev\nweb:\n  bindAddr: :5000\n",
+	})
+	path := filepath.Join(dir, "config.yml")
+
+	// the env var sets the value even though the file has the same value, and the file value passed through is kept.
+	var cfg layeredConfig
+	provenance := Provenance{}
+	_, err = Read(&cfg, OptPaths(path), OptProvenance(provenance))
+	assert.Nil(err)
+	assert.Equal(Provenance{
+		"env":          ProvenanceFile + path,
+		"web.bindAddr": ProvenanceEnv + "BIND_ADDR",
+	}, provenance)
+}
+
+func TestProvenanceTrackFileJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	type jsonConfig struct {
+		Name   string   `json:"name"`
+		Hosts  []string `json:"hosts"`
+		Nested struct {
+			Port    int `json:"port"`
+			Ignored int `json:"-"`
+		} `json:"nested"`
+	}
+
+	provenance := Provenance{}
+	assert.Nil(provenance.trackFile("config.json", []byte(`{"NAME": "app", "nested": {"port": 80, "ignored": 1}, "unknown": true}`), &jsonConfig{}))
+	assert.Equal(Provenance{
+		"name":        ProvenanceFile + "config.json",
+		"nested.port": ProvenanceFile + "config.json",
+	}, provenance)
+}
+
+func TestProvenanceString(t *testing.T) {
+	assert := assert.New(t)
+
+	provenance := Provenance{
+		"web.bindAddr": ProvenanceFile + "config.prod.yml",
+		"env":          ProvenanceEnv + "SERVICE_ENV",
+	}
+	assert.Equal("env: env:SERVICE_ENV\nweb.bindAddr: file:config.prod.yml", provenance.String())
+}
@@ -1,9 +1,10 @@
 package configutil
 
 import (
+	"bytes"
 	"encoding/json"
 	"io"
-	"os"
+	"io/ioutil"
 	"path/filepath"
 	"strings"
 
@@ -21,6 +22,9 @@ const (
 	ExtensionYAML = ".yaml"
 	// ExtensionYML is a file extension.
 	ExtensionYML = ".yml"
+
+	// OverlayLocal is the name of the local overrides overlay, e.g. `config.local.yml`.
+	OverlayLocal = "local"
 )
 
 var (
@@ -43,37 +47,58 @@ var (
 // Read reads a config from optional path(s).
 // Paths will be tested from a standard set of defaults (ex. config.yml)
 // and optionally a csv named in the `CONFIG_PATH` environment variable.
+//
+// The first path that exists is read, then any overlays (see `OptEnvOverlays` and `OptOverlays`)
+// are deep merged over it in order, before the config is resolved.
 func Read(ref Any, options ...Option) (path string, err error) {
 	var configOptions ConfigOptions
 	configOptions, err = createConfigOptions(options...)
 	if err != nil {
 		return
 	}
+	path, _, err = read(ref, configOptions)
+	return
+}
 
+// read reads a config, returning the path of the config file and the paths of the files that were read.
+func read(ref Any, configOptions ConfigOptions) (path string, files []string, err error) {
 	// for each of the paths
 	// if the path doesn't exist, continue, read the path that is found.
-	var f *os.File
 	for _, path = range configOptions.Paths {
 		if path == "" {
 			continue
 		}
-
-		f, err = os.Open(path)
+		err = readFile(path, ref, configOptions.Provenance)
 		if IsNotExist(err) {
 			continue
 		}
-		if err != nil {
-			err = ex.New(err)
-			break
-		}
-		defer f.Close()
-		err = deserialize(filepath.Ext(path), f, ref)
 		break
 	}
 	if err != nil && !IsNotExist(err) {
 		return
 	}
 
+	var found string
+	if err == nil && path != "" {
+		found = path
+		files = append(files, path)
+	}
+	for _, overlay := range overlayPaths(found, configOptions) {
+		overlayErr := readFile(overlay, ref, configOptions.Provenance)
+		if IsNotExist(overlayErr) {
+			continue
+		}
+		if overlayErr != nil {
+			err = overlayErr
+			return
+		}
+		files = append(files, overlay)
+	}
+
+	if configOptions.Provenance != nil {
+		defer configOptions.Provenance.trackResolve(ref)()
+	}
+
 	if typed, ok := ref.(ConfigResolver); ok {
 		if resolveErr := typed.Resolve(); resolveErr != nil {
 			err = resolveErr
@@ -90,6 +115,38 @@ func Read(ref Any, options ...Option) (path string, err error) {
 	return
 }
 
+// OverlayPath returns the path of a named overlay next to a config file, e.g. `config.prod.yml` for `config.yml` and `prod`.
+func OverlayPath(path, name string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + name + ext
+}
+
+// overlayPaths returns the paths of the overlays read over a config file, whether or not they exist.
+// Named overlays are only read next to a config file that was found.
+func overlayPaths(path string, configOptions ConfigOptions) (overlays []string) {
+	if path != "" {
+		for _, name := range configOptions.OverlayNames {
+			overlays = append(overlays, OverlayPath(path, name))
+		}
+	}
+	return append(overlays, configOptions.Overlays...)
+}
+
+// readFile deserializes a config file into a config, recording the fields it sets if provenance is tracked.
+func readFile(path string, ref Any, provenance Provenance) error {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ex.New(err)
+	}
+	if err := deserialize(filepath.Ext(path), bytes.NewReader(contents), ref); err != nil {
+		return err
+	}
+	if provenance != nil {
+		return provenance.trackFile(path, contents, ref)
+	}
+	return nil
+}
+
 func createConfigOptions(options ...Option) (configOptions ConfigOptions, err error) {
 	configOptions.Paths = DefaultPaths
 	if env.Env().Has(EnvVarConfigPath) {
@@ -0,0 +1,86 @@
+package configutil
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/webutil"
+)
+
+// SecretGetter gets secrets, e.g. a `secrets.Client`.
+//
+// It is declared here so configutil does not depend on the secrets package and its dependencies.
+type SecretGetter interface {
+	Get(ctx context.Context, key string, options ...webutil.RequestOption) (map[string]string, error)
+}
+
+var (
+	_ StringSource   = (*SecretSource)(nil)
+	_ StringsSource  = (*SecretSource)(nil)
+	_ BoolSource     = (*SecretSource)(nil)
+	_ IntSource      = (*SecretSource)(nil)
+	_ Float64Source  = (*SecretSource)(nil)
+	_ DurationSource = (*SecretSource)(nil)
+)
+
+// Secret returns a value provider that reads a field of a secret from a secret getter, e.g. a secrets client.
+// It can be used with *any* config.Set___ type.
+//
+// Each source reads the secret when it is resolved, so use a `secrets.CachedClient` to read
+// several fields of the same secret with one request.
+func Secret(client SecretGetter, key, field string) SecretSource {
+	return SecretSource{Client: client, Key: key, Field: field}
+}
+
+// SecretSource is a value provider for a field of a secret.
+// If the secret or the field does not exist, the value is not present.
+type SecretSource struct {
+	Client SecretGetter
+	Key    string
+	Field  string
+}
+
+// String returns the secret field as a string.
+func (s SecretSource) String() (*string, error) {
+	values, err := s.Client.Get(context.Background(), s.Key)
+	if IsSecretNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := values[s.Field]; ok {
+		return &value, nil
+	}
+	return nil, nil
+}
+
+// Strings returns the secret field as csv strings.
+func (s SecretSource) Strings() ([]string, error) {
+	value, err := s.String()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	return strings.Split(*value, ","), nil
+}
+
+// Bool returns the secret field as a bool.
+func (s SecretSource) Bool() (*bool, error) {
+	return Parse(s).Bool()
+}
+
+// Int returns the secret field as an int.
+func (s SecretSource) Int() (*int, error) {
+	return Parse(s).Int()
+}
+
+// Float64 returns the secret field as a float64.
+func (s SecretSource) Float64() (*float64, error) {
+	return Parse(s).Float64()
+}
+
+// Duration returns the secret field as a time.Duration.
+func (s SecretSource) Duration() (*time.Duration, error) {
+	return Parse(s).Duration()
+}
@@ -0,0 +1,169 @@
+package configutil
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// SecretRefPrefix is the prefix of secret references in config values, e.g. `vault://secret/data/app#password`.
+const SecretRefPrefix = "vault://"
+
+// IsSecretRef returns if a value is a secret reference.
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretRefPrefix)
+}
+
+// ParseSecretRef parses a secret reference of the form `vault://<key>#<field>` into the secret key and field.
+func ParseSecretRef(ref string) (key, field string, err error) {
+	if !IsSecretRef(ref) {
+		err = ex.New(ErrInvalidSecretRef, ex.OptMessagef("ref: %s", ref))
+		return
+	}
+	index := strings.LastIndex(ref, "#")
+	if index < 0 {
+		err = ex.New(ErrInvalidSecretRef, ex.OptMessagef("ref: %s; missing field", ref))
+		return
+	}
+	key, field = strings.TrimPrefix(ref[:index], SecretRefPrefix), ref[index+1:]
+	if key == "" || field == "" {
+		err = ex.New(ErrInvalidSecretRef, ex.OptMessagef("ref: %s", ref))
+	}
+	return
+}
+
+// SecretRef returns a value provider that reads the secret field a value references if it is a secret reference,
+// and returns the value as is otherwise. It is typically used in `Resolve()` with a value read from a config file.
+//
+//	func (c *Config) Resolve() error {
+//		return configutil.SetString(&c.DB.Password, configutil.Env("DB_PASSWORD"), configutil.SecretRef(client, c.DB.Password))
+//	}
+//
+// Unlike `Secret`, it returns an error if the referenced secret or field does not exist.
+func SecretRef(client SecretGetter, value string) StringSource {
+	return StringFunc(func() (*string, error) {
+		if !IsSecretRef(value) {
+			return String(value).String()
+		}
+		resolved, err := newSecretRefResolver(client).Resolve(value)
+		if err != nil {
+			return nil, err
+		}
+		return &resolved, nil
+	})
+}
+
+// ResolveSecretRefs replaces the secret references in the string fields of an object with the secret fields they
+// reference. It walks nested structs, pointers, slices and maps, and reads each referenced secret once.
+//
+// It is not called by `Read`, which doesn't have a client to read secrets with; configs with secret references
+// must call it themselves, typically at the end of `Resolve()`, so references set from any source are resolved.
+//
+//	func (c *Config) Resolve() error {
+//		// ... set the other fields ...
+//		return configutil.ResolveSecretRefs(client, c)
+//	}
+func ResolveSecretRefs(client SecretGetter, obj interface{}) error {
+	return newSecretRefResolver(client).Walk(reflect.ValueOf(obj))
+}
+
+func newSecretRefResolver(client SecretGetter) *secretRefResolver {
+	return &secretRefResolver{Client: client, Secrets: make(map[string]map[string]string), Visited: make(map[uintptr]bool)}
+}
+
+// secretRefResolver resolves secret references, caching the secrets it reads.
+type secretRefResolver struct {
+	Client  SecretGetter
+	Secrets map[string]map[string]string
+	// Visited are the pointers already walked, so cycles terminate.
+	Visited map[uintptr]bool
+}
+
+// Resolve returns the secret field a reference references.
+func (srr *secretRefResolver) Resolve(ref string) (string, error) {
+	key, field, err := ParseSecretRef(ref)
+	if err != nil {
+		return "", err
+	}
+	values, ok := srr.Secrets[key]
+	if !ok {
+		values, err = srr.Client.Get(context.Background(), key)
+		if err != nil {
+			return "", err
+		}
+		srr.Secrets[key] = values
+	}
+	value, ok := values[field]
+	if !ok {
+		return "", ex.New(ErrSecretRefNotFound, ex.OptMessagef("ref: %s", ref))
+	}
+	return value, nil
+}
+
+// Walk resolves the secret references in a value, which must be settable to be changed.
+func (srr *secretRefResolver) Walk(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		if value.Kind() == reflect.Interface && value.Elem().Kind() != reflect.Ptr {
+			// values held by interfaces can't be set in place, so resolve a copy and set it back.
+			if !value.CanSet() {
+				return nil
+			}
+			elem := reflect.New(value.Elem().Type()).Elem()
+			elem.Set(value.Elem())
+			if err := srr.Walk(elem); err != nil {
+				return err
+			}
+			value.Set(elem)
+			return nil
+		}
+		if value.Kind() == reflect.Ptr {
+			if srr.Visited[value.Pointer()] {
+				return nil
+			}
+			srr.Visited[value.Pointer()] = true
+		}
+		return srr.Walk(value.Elem())
+	case reflect.Struct:
+		for x := 0; x < value.NumField(); x++ {
+			if value.Type().Field(x).PkgPath != "" {
+				continue
+			}
+			if err := srr.Walk(value.Field(x)); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for x := 0; x < value.Len(); x++ {
+			if err := srr.Walk(value.Index(x)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := value.MapRange()
+		for iter.Next() {
+			// map values aren't addressable, so resolve a copy and set it back.
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := srr.Walk(elem); err != nil {
+				return err
+			}
+			value.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		if !value.CanSet() || !IsSecretRef(value.String()) {
+			return nil
+		}
+		resolved, err := srr.Resolve(value.String())
+		if err != nil {
+			return err
+		}
+		value.SetString(resolved)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package configutil
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// assert secrets clients are secret getters.
+var (
+	_ SecretGetter = (secrets.Client)(nil)
+	_ SecretGetter = (*secrets.CachedClient)(nil)
+)
+
+// notFoundGetter is a secret getter that returns the secrets client not found error for every key.
+type notFoundGetter struct{}
+
+func (notFoundGetter) Get(_ context.Context, key string, _ ...webutil.RequestOption) (map[string]string, error) {
+	return nil, ex.New(secrets.ErrNotFound, ex.OptMessagef("key: %s", key))
+}
+
+func TestSecret(t *testing.T) {
+	assert := assert.New(t)
+
+	client := secrets.NewMockClient()
+	assert.Nil(client.Put(context.TODO(), "secret/data/app/db", secrets.Values{"password": "hunter2", "port": "5432"}))
+
+	password := "default"
+	assert.Nil(SetString(&password, Env("CONFIGUTIL_TEST_UNSET"), Secret(client, "secret/data/app/db", "password")))
+	assert.Equal("hunter2", password)
+
+	assert.Nil(SetString(&password, Secret(client, "secret/data/app/db", "missing"), String("fallback")))
+	assert.Equal("fallback", password)
+
+	var port int
+	assert.Nil(SetInt(&port, Secret(client, "secret/data/app/db", "port")))
+	assert.Equal(5432, port)
+
+	assert.NotNil(SetString(&password, Secret(client, "secret/data/missing", "password")), "errors other than not found should be returned")
+
+	assert.Nil(SetString(&password, Secret(notFoundGetter{}, "secret/data/missing", "password"), String("not found")))
+	assert.Equal("not found", password, "missing secrets should not be present")
+}
+
+func TestParseSecretRef(t *testing.T) {
+	assert := assert.New(t)
+
+	key, field, err := ParseSecretRef("vault://secret/data/app/db#password")
+	assert.Nil(err)
+	assert.Equal("secret/data/app/db", key)
+	assert.Equal("password", field)
+
+	for _, ref := range []string{"secret/data/app/db#password", "vault://secret/data/app/db", "vault://#password", "vault://secret/data/app/db#"} {
+		_, _, err = ParseSecretRef(ref)
+		assert.True(IsInvalidSecretRef(err), ref)
+	}
+}
+
+type secretRefConfig struct {
+	Name string `yaml:"name"`
+	DB   struct {
+		Password string `yaml:"password"`
+	} `yaml:"db"`
+	Tokens  []string          `yaml:"tokens"`
+	Labels  map[string]string `yaml:"labels"`
+	Extra   Vars              `yaml:"extra"`
+	Nested  *secretRefConfig  `yaml:"nested"`
+	private string
+
+	client secrets.Client
+}
+
+func (src *secretRefConfig) Resolve() error {
+	return ResolveSecretRefs(src.client, src)
+}
+
+func TestResolveSecretRefs(t *testing.T) {
+	assert := assert.New(t)
+
+	client := secrets.NewMockClient()
+	assert.Nil(client.Put(context.TODO(), "secret/data/app/db", secrets.Values{"password": "hunter2"}))
+	assert.Nil(client.Put(context.TODO(), "secret/data/app/tokens", secrets.Values{"api": "token", "label": "value"}))
+
+	contents := `name: app
+db:
+  password: vault://secret/data/app/db#password
+tokens:
+- vault://secret/data/app/tokens#api
+- literal
+labels:
+  team: vault://secret/data/app/tokens#label
+extra:
+  key: vault://secret/data/app/tokens#api
+nested:
+  name: vault://secret/data/app/db#password
+`
+	cfg := secretRefConfig{client: client}
+	assert.Nil(deserialize(ExtensionYAML, bytes.NewBufferString(contents), &cfg))
+	assert.Equal("vault://secret/data/app/db#password", cfg.DB.Password, "references should not be resolved until resolve is called")
+
+	cfg.private = "vault://secret/data/app/db#password"
+	assert.Nil(cfg.Resolve())
+	assert.Equal("app", cfg.Name)
+	assert.Equal("hunter2", cfg.DB.Password)
+	assert.Equal([]string{"token", "literal"}, cfg.Tokens)
+	assert.Equal("value", cfg.Labels["team"])
+	assert.Equal("token", cfg.Extra["key"])
+	assert.Equal("hunter2", cfg.Nested.Name)
+	assert.Equal("vault://secret/data/app/db#password", cfg.private, "unexported fields should be skipped")
+
+	cfg.Nested.Nested = &cfg
+	cfg.Nested.Name = "vault://secret/data/app/tokens#api"
+	assert.Nil(ResolveSecretRefs(client, &cfg), "cycles should be walked once")
+	assert.Equal("token", cfg.Nested.Name)
+
+	cfg.DB.Password = "vault://secret/data/app/db#username"
+	assert.True(IsSecretRefNotFound(ResolveSecretRefs(client, &cfg)))
+}
+
+func TestSecretRef(t *testing.T) {
+	assert := assert.New(t)
+
+	client := secrets.NewMockClient()
+	assert.Nil(client.Put(context.TODO(), "secret/data/app/db", secrets.Values{"password": "hunter2"}))
+
+	var password string
+	assert.Nil(SetString(&password, SecretRef(client, "vault://secret/data/app/db#password")))
+	assert.Equal("hunter2", password)
+
+	assert.Nil(SetString(&password, SecretRef(client, "literal")))
+	assert.Equal("literal", password)
+
+	assert.Nil(SetString(&password, SecretRef(client, ""), String("fallback")))
+	assert.Equal("fallback", password)
+
+	assert.NotNil(SetString(&password, SecretRef(client, "vault://secret/data/app/db#missing")))
+}
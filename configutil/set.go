@@ -12,6 +12,7 @@ func SetString(destination *string, sources ...StringSource) error {
 			return err
 		}
 		if value != nil {
+			trackSet(destination, source, *value)
 			*destination = *value
 			return nil
 		}
@@ -29,6 +30,7 @@ func SetStrings(destination *[]string, sources ...StringsSource) error {
 			return err
 		}
 		if value != nil {
+			trackSet(destination, source, value)
 			*destination = value
 			return nil
 		}
@@ -46,6 +48,7 @@ func SetBool(destination **bool, sources ...BoolSource) error {
 			return err
 		}
 		if value != nil {
+			trackSet(destination, source, value)
 			*destination = value
 			return nil
 		}
@@ -63,6 +66,7 @@ func SetInt(destination *int, sources ...IntSource) error {
 			return err
 		}
 		if value != nil {
+			trackSet(destination, source, *value)
 			*destination = *value
 			return nil
 		}
@@ -80,6 +84,7 @@ func SetFloat64(destination *float64, sources ...Float64Source) error {
 			return err
 		}
 		if value != nil {
+			trackSet(destination, source, *value)
 			*destination = *value
 			return nil
 		}
@@ -97,6 +102,7 @@ func SetDuration(destination *time.Duration, sources ...DurationSource) error {
 			return err
 		}
 		if value != nil {
+			trackSet(destination, source, *value)
 			*destination = *value
 			return nil
 		}
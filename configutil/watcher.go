@@ -0,0 +1,259 @@
+package configutil
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/fileutil"
+	"github.com/blend/go-sdk/logger"
+)
+
+/*
+NewWatcher reads a config like `Read`, and returns a watcher that reloads it when the files it was read from change.
+
+On a change, the config files are deserialized and resolved into a new instance of the config type, which is then
+validated if it is a `ConfigValidator`. Only if that succeeds is the new config made current and delivered to the
+subscribers; otherwise the error is logged and the current config is kept. The config passed in is never modified
+after it is first read.
+
+The files watched are the config file that was first read, and every overlay that could be read over it, so
+overlays created later, e.g. a `config.local.yml`, are picked up.
+
+Example:
+
+	var cfg config
+	watcher, err := configutil.NewWatcher(&cfg, configutil.OptEnvOverlays(""))
+	if err != nil {
+		return err
+	}
+	watcher.Subscribe(func(next configutil.Any) {
+		app.SetTunables(next.(*config).Tunables)
+	})
+	go watcher.Start()
+	defer watcher.Stop()
+*/
+func NewWatcher(ref Any, options ...Option) (*Watcher, error) {
+	refType := reflect.TypeOf(ref)
+	if refType == nil || refType.Kind() != reflect.Ptr {
+		return nil, ex.New(ErrConfigRefNotPointer)
+	}
+	configOptions, err := createConfigOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+	path, files, err := read(ref, configOptions)
+	if err != nil {
+		return nil, err
+	}
+	if typed, ok := ref.(ConfigValidator); ok {
+		if err := typed.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if len(files) > 0 && files[0] == path {
+		files = append([]string{path}, overlayPaths(path, configOptions)...)
+	} else {
+		files = overlayPaths("", configOptions)
+	}
+	modTimes := make(map[string]time.Time, len(files))
+	for _, file := range files {
+		if stat, err := os.Stat(file); err == nil {
+			modTimes[file] = stat.ModTime()
+		}
+	}
+	return &Watcher{
+		Latch:      async.NewLatch(),
+		Options:    options,
+		Interval:   fileutil.DefaultWatchInterval,
+		configType: refType.Elem(),
+		current:    ref,
+		files:      files,
+		modTimes:   modTimes,
+		provenance: configOptions.Provenance,
+	}, nil
+}
+
+// Subscriber is called with each new config a watcher reloads.
+type Subscriber func(Any)
+
+// Watcher is a background worker that reloads a config when its files change.
+type Watcher struct {
+	*async.Latch
+	// Options are the options the config is read with.
+	Options []Option
+	// Interval is the interval the config files are polled on for changes.
+	Interval time.Duration
+	Log      logger.Log
+
+	configType reflect.Type
+	files      []string
+	modTimes   map[string]time.Time
+	reloadMu   sync.Mutex
+
+	mu          sync.Mutex
+	current     Any
+	provenance  Provenance
+	subscribers []Subscriber
+}
+
+// IntervalOrDefault returns the poll interval or a default.
+func (w *Watcher) IntervalOrDefault() time.Duration {
+	if w.Interval > 0 {
+		return w.Interval
+	}
+	return fileutil.DefaultWatchInterval
+}
+
+// Subscribe adds a subscriber that is called with each new config.
+func (w *Watcher) Subscribe(subscriber Subscriber) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, subscriber)
+}
+
+// Current returns the current config.
+func (w *Watcher) Current() Any {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.current
+}
+
+// Provenance returns the provenance of the current config, if the config is read with `OptProvenance`.
+// Each reload records a new provenance, so the provenance passed to `OptProvenance` is that of the first config.
+func (w *Watcher) Provenance() Provenance {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.provenance
+}
+
+// Files returns the paths of the watched files, including overlays that don't exist yet.
+func (w *Watcher) Files() []string {
+	return w.files
+}
+
+/*
+Start starts the watcher, which polls the config files for changes on the interval.
+It will return an ErrCannotStart if the watcher is already started.
+
+This call will block.
+*/
+func (w *Watcher) Start() error {
+	if !w.CanStart() {
+		return ex.New(async.ErrCannotStart)
+	}
+	w.Starting()
+	stopping := w.NotifyStopping()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	for _, path := range w.files {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			w.watch(ctx, path)
+		}(path)
+	}
+	w.Started()
+
+	<-stopping
+	cancel()
+	wg.Wait()
+	w.Stopped()
+	return nil
+}
+
+// Stop stops the watcher.
+func (w *Watcher) Stop() error {
+	if !w.CanStop() {
+		return ex.New(async.ErrCannotStop)
+	}
+	w.Stopping()
+	<-w.NotifyStopped()
+	return nil
+}
+
+// Reload reads the config into a new instance and validates it, and if that succeeds, makes it the current config
+// and calls the subscribers with it. Otherwise the error is returned and the current config is kept.
+func (w *Watcher) Reload() error {
+	w.reloadMu.Lock()
+	defer w.reloadMu.Unlock()
+
+	configOptions, err := createConfigOptions(w.Options...)
+	if err != nil {
+		return err
+	}
+	// each config gets its own provenance, so the provenance of the current config doesn't change under readers.
+	if configOptions.Provenance != nil {
+		configOptions.Provenance = Provenance{}
+	}
+
+	next := reflect.New(w.configType).Interface()
+	_, files, err := read(next, configOptions)
+	if err != nil {
+		return err
+	}
+	if typed, ok := next.(ConfigValidator); ok {
+		if err := typed.Validate(); err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	w.current, w.provenance = next, configOptions.Provenance
+	subscribers := append([]Subscriber(nil), w.subscribers...)
+	w.mu.Unlock()
+
+	logger.MaybeInfof(w.Log, "config reloaded from: %s", strings.Join(files, ", "))
+	for _, subscriber := range subscribers {
+		subscriber(next)
+	}
+	return nil
+}
+
+// watch reloads the config when a file changes until the context is done, including changes made since the config
+// was first read. If the file doesn't exist yet, or goes missing, e.g. while it is replaced, it is watched again once it exists.
+func (w *Watcher) watch(ctx context.Context, path string) {
+	lastMod := w.modTimes[path]
+	var missing bool
+	for {
+		if stat, err := os.Stat(path); err == nil {
+			missing = false
+			if !stat.ModTime().Equal(lastMod) {
+				lastMod = stat.ModTime()
+				logger.MaybeError(w.Log, w.Reload())
+			}
+			err = fileutil.WatchContext(ctx, path, w.IntervalOrDefault(), func(f *os.File) error {
+				defer f.Close()
+				if stat, err := f.Stat(); err == nil {
+					lastMod = stat.ModTime()
+				}
+				logger.MaybeError(w.Log, w.Reload())
+				return nil
+			})
+			if err == nil {
+				return
+			}
+			if !IsNotExist(err) {
+				logger.MaybeError(w.Log, err)
+			}
+		} else if !missing {
+			missing = true
+			// overlays that don't exist yet are expected; only report files that went missing.
+			if !lastMod.IsZero() {
+				logger.MaybeError(w.Log, ex.New(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.IntervalOrDefault()):
+		}
+	}
+}
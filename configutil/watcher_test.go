@@ -0,0 +1,206 @@
+package configutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/fileutil"
+)
+
+type watchedConfig struct {
+	Name    string `yaml:"name"`
+	Workers int    `yaml:"workers"`
+}
+
+func (wc *watchedConfig) Resolve() error {
+	if wc.Name == "" {
+		wc.Name = "default"
+	}
+	return nil
+}
+
+func (wc watchedConfig) Validate() error {
+	if wc.Workers < 0 {
+		return fmt.Errorf("workers must be positive")
+	}
+	return nil
+}
+
+// writeWatched writes a watched file, moving its modification time forward so the change is seen.
+func writeWatched(assert *assert.Assertions, path, contents string) {
+	assert.Nil(ioutil.WriteFile(path, []byte(contents), 0600))
+	modTime := time.Now().Add(time.Second)
+	if stat, err := os.Stat(path); err == nil && !stat.ModTime().Before(modTime) {
+		modTime = stat.ModTime().Add(time.Second)
+	}
+	assert.Nil(os.Chtimes(path, modTime, modTime))
+}
+
+func TestWatcher(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yml")
+	prod := filepath.Join(dir, "config.prod.yml")
+	local := filepath.Join(dir, "config.local.yml")
+	writeLayers(assert, dir, map[string]string{
+		"config.yml":       "name: app\nworkers: 2\n",
+		"config.local.yml": "workers: 4\n",
+	})
+
+	var cfg watchedConfig
+	watcher, err := NewWatcher(&cfg, OptPaths(path), OptEnvOverlays("prod"))
+	assert.Nil(err)
+	assert.Equal([]string{path, prod, local}, watcher.Files(), "overlays that don't exist yet should be watched")
+	assert.Equal(4, cfg.Workers)
+	assert.True(watcher.Current() == &cfg)
+	watcher.Interval = 10 * time.Millisecond
+
+	reloaded := make(chan *watchedConfig, 4)
+	watcher.Subscribe(func(next Any) {
+		reloaded <- next.(*watchedConfig)
+	})
+
+	// changes made before the watcher is started are also seen.
+	writeWatched(assert, local, "workers: 8\n")
+	started := watcher.NotifyStarted()
+	go watcher.Start()
+	<-started
+	defer watcher.Stop()
+
+	var next *watchedConfig
+	select {
+	case next = <-reloaded:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("config was not reloaded")
+	}
+	assert.Equal("app", next.Name)
+	assert.Equal(8, next.Workers)
+	assert.True(watcher.Current() == next)
+	assert.Equal(4, cfg.Workers)
+
+	// invalid configs are not delivered, and the current config is kept.
+	writeWatched(assert, local, "workers: -1\n")
+	writeWatched(assert, path, "name: [\n")
+	select {
+	case invalid := <-reloaded:
+		assert.FailNow(fmt.Sprintf("invalid config was reloaded: %#v", invalid))
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.True(watcher.Current() == next)
+
+	writeWatched(assert, path, "name: fixed\n")
+	writeWatched(assert, local, "workers: 1\n")
+	assert.True(waitForReload(reloaded, func(wc *watchedConfig) bool {
+		return wc.Name == "fixed" && wc.Workers == 1
+	}))
+
+	// overlays created after the config is first read are picked up.
+	writeWatched(assert, prod, "name: prod\nworkers: 3\n")
+	assert.True(waitForReload(reloaded, func(wc *watchedConfig) bool {
+		return wc.Name == "prod" && wc.Workers == 1
+	}))
+	assert.Nil(watcher.Reload())
+}
+
+func waitForReload(reloaded chan *watchedConfig, predicate func(*watchedConfig) bool) bool {
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case next := <-reloaded:
+			if predicate(next) {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
+
+func TestWatcherReloadInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yml")
+	writeLayers(assert, dir, map[string]string{"config.yml": "workers: 2\n"})
+
+	var cfg watchedConfig
+	watcher, err := NewWatcher(&cfg, OptPaths(path))
+	assert.Nil(err)
+	var called bool
+	watcher.Subscribe(func(Any) { called = true })
+
+	writeLayers(assert, dir, map[string]string{"config.yml": "workers: -2\n"})
+	assert.NotNil(watcher.Reload())
+	assert.Nil(os.Remove(path))
+	assert.True(IsNotExist(watcher.Reload()))
+	assert.False(called)
+	assert.True(watcher.Current() == &cfg)
+	assert.Equal("default", watcher.Current().(*watchedConfig).Name)
+
+	writeLayers(assert, dir, map[string]string{"config.yml": "workers: 3\n"})
+	assert.Nil(watcher.Reload())
+	assert.True(called)
+	assert.Equal(3, watcher.Current().(*watchedConfig).Workers)
+}
+
+func TestWatcherProvenance(t *testing.T) {
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yml")
+	writeLayers(assert, dir, map[string]string{"config.yml": "workers: 2\n"})
+
+	var cfg watchedConfig
+	provenance := Provenance{}
+	watcher, err := NewWatcher(&cfg, OptPaths(path), OptProvenance(provenance))
+	assert.Nil(err)
+	assert.Equal(Provenance{"workers": ProvenanceFile + path, "name": ProvenanceResolve}, watcher.Provenance())
+
+	writeLayers(assert, dir, map[string]string{"config.yml": "name: app\n"})
+	assert.Nil(watcher.Reload())
+	assert.Equal(Provenance{"name": ProvenanceFile + path}, watcher.Provenance())
+	assert.Equal(Provenance{"workers": ProvenanceFile + path, "name": ProvenanceResolve}, provenance)
+}
+
+func TestWatcherIntervalOrDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	var watcher Watcher
+	assert.Equal(fileutil.DefaultWatchInterval, watcher.IntervalOrDefault())
+	watcher.Interval = -time.Second
+	assert.Equal(fileutil.DefaultWatchInterval, watcher.IntervalOrDefault())
+	watcher.Interval = time.Second
+	assert.Equal(time.Second, watcher.IntervalOrDefault())
+}
+
+func TestNewWatcherErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewWatcher(watchedConfig{}, OptPaths("testdata/config.yml"))
+	assert.True(IsConfigRefNotPointer(err))
+
+	dir, err := ioutil.TempDir("", "configutil")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	writeLayers(assert, dir, map[string]string{"config.yml": "workers: -1\n"})
+
+	var cfg watchedConfig
+	_, err = NewWatcher(&cfg, OptPaths(filepath.Join(dir, "config.yml")))
+	assert.NotNil(err)
+	_, err = NewWatcher(&cfg, OptPaths(filepath.Join(dir, "missing.yml")))
+	assert.True(IsNotExist(err))
+	assert.False(IsConfigRefNotPointer(ex.New("other")))
+}
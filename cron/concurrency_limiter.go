@@ -0,0 +1,190 @@
+package cron
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// NewConcurrencyLimiter returns a new concurrency limiter.
+func NewConcurrencyLimiter() *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		Groups:       map[string]int{},
+		groupRunning: map[string]int{},
+	}
+}
+
+// ConcurrencyLimiter limits the number of job invocations that run at once, both
+// overall and within named concurrency groups.
+//
+// Invocations that cannot run wait in a queue, highest priority first and then in the order
+// they were queued. An invocation that is only waiting on its group's limit does not hold up
+// invocations in other groups behind it.
+type ConcurrencyLimiter struct {
+	sync.Mutex
+
+	// MaxConcurrent is the maximum number of invocations that run at once; zero is unlimited.
+	MaxConcurrent int
+	// Groups are the maximum number of invocations that run at once for each named group.
+	// Groups that are not set are unlimited.
+	Groups map[string]int
+
+	running      int
+	groupRunning map[string]int
+	queue        []*concurrencyWaiter
+	sequence     uint64
+}
+
+// SetGroup sets the maximum concurrent invocations for a group.
+func (cl *ConcurrencyLimiter) SetGroup(group string, maxConcurrent int) {
+	cl.Lock()
+	defer cl.Unlock()
+	if cl.Groups == nil {
+		cl.Groups = map[string]int{}
+	}
+	cl.Groups[group] = maxConcurrent
+	cl.dispatchUnsafe()
+}
+
+// Acquire waits until an invocation in a group (which can be empty) can run, and returns a function
+// that must be called when it finishes, along with how long it waited.
+// If the context is done before it can run, `ErrJobCancelled` is returned.
+func (cl *ConcurrencyLimiter) Acquire(ctx context.Context, group string, priority int) (release func(), waited time.Duration, err error) {
+	cl.Lock()
+	cl.sequence++
+	waiter := &concurrencyWaiter{
+		Group:    group,
+		Priority: priority,
+		Sequence: cl.sequence,
+		Queued:   Now(),
+		Ready:    make(chan struct{}),
+	}
+	cl.queue = append(cl.queue, waiter)
+	cl.dispatchUnsafe()
+	cl.Unlock()
+
+	release = func() { cl.release(group) }
+	select {
+	case <-waiter.Ready:
+		return release, Since(waiter.Queued), nil
+	case <-ctx.Done():
+	}
+
+	cl.Lock()
+	defer cl.Unlock()
+	if waiter.Granted {
+		// it was granted while the context finished; give it back.
+		cl.releaseUnsafe(group)
+	} else {
+		cl.removeUnsafe(waiter)
+	}
+	return nil, Since(waiter.Queued), ex.New(ErrJobCancelled, ex.OptMessage("cancelled while queued"))
+}
+
+// Status returns the number of running and queued invocations, overall and by group.
+func (cl *ConcurrencyLimiter) Status() ConcurrencyStatus {
+	cl.Lock()
+	defer cl.Unlock()
+
+	status := ConcurrencyStatus{
+		MaxConcurrent: cl.MaxConcurrent,
+		Running:       cl.running,
+		Queued:        len(cl.queue),
+		Groups:        map[string]ConcurrencyGroupStatus{},
+	}
+	for group, maxConcurrent := range cl.Groups {
+		status.Groups[group] = ConcurrencyGroupStatus{MaxConcurrent: maxConcurrent, Running: cl.groupRunning[group]}
+	}
+	for _, waiter := range cl.queue {
+		if waiter.Group == "" {
+			continue
+		}
+		groupStatus := status.Groups[waiter.Group]
+		groupStatus.Queued++
+		status.Groups[waiter.Group] = groupStatus
+	}
+	return status
+}
+
+// ConcurrencyStatus is the status of a concurrency limiter.
+type ConcurrencyStatus struct {
+	MaxConcurrent int                               `json:"maxConcurrent"`
+	Running       int                               `json:"running"`
+	Queued        int                               `json:"queued"`
+	Groups        map[string]ConcurrencyGroupStatus `json:"groups,omitempty"`
+}
+
+// ConcurrencyGroupStatus is the status of a concurrency group.
+type ConcurrencyGroupStatus struct {
+	MaxConcurrent int `json:"maxConcurrent"`
+	Running       int `json:"running"`
+	Queued        int `json:"queued"`
+}
+
+func (cl *ConcurrencyLimiter) release(group string) {
+	cl.Lock()
+	defer cl.Unlock()
+	cl.releaseUnsafe(group)
+}
+
+func (cl *ConcurrencyLimiter) releaseUnsafe(group string) {
+	cl.running--
+	if group != "" {
+		cl.groupRunning[group]--
+	}
+	cl.dispatchUnsafe()
+}
+
+// dispatchUnsafe grants queued waiters in order while there is capacity.
+func (cl *ConcurrencyLimiter) dispatchUnsafe() {
+	if cl.groupRunning == nil {
+		cl.groupRunning = map[string]int{}
+	}
+	sort.SliceStable(cl.queue, func(i, j int) bool {
+		if cl.queue[i].Priority != cl.queue[j].Priority {
+			return cl.queue[i].Priority > cl.queue[j].Priority
+		}
+		return cl.queue[i].Sequence < cl.queue[j].Sequence
+	})
+
+	var waiting []*concurrencyWaiter
+	for index, waiter := range cl.queue {
+		if cl.MaxConcurrent > 0 && cl.running >= cl.MaxConcurrent {
+			waiting = append(waiting, cl.queue[index:]...)
+			break
+		}
+		if maxConcurrent, ok := cl.Groups[waiter.Group]; ok && waiter.Group != "" && maxConcurrent > 0 && cl.groupRunning[waiter.Group] >= maxConcurrent {
+			waiting = append(waiting, waiter)
+			continue
+		}
+		cl.running++
+		if waiter.Group != "" {
+			cl.groupRunning[waiter.Group]++
+		}
+		waiter.Granted = true
+		close(waiter.Ready)
+	}
+	cl.queue = waiting
+}
+
+func (cl *ConcurrencyLimiter) removeUnsafe(waiter *concurrencyWaiter) {
+	for index := range cl.queue {
+		if cl.queue[index] == waiter {
+			cl.queue = append(cl.queue[:index], cl.queue[index+1:]...)
+			return
+		}
+	}
+}
+
+// concurrencyWaiter is a queued invocation.
+type concurrencyWaiter struct {
+	Group    string
+	Priority int
+	Sequence uint64
+	Queued   time.Time
+	Ready    chan struct{}
+	Granted  bool
+}
@@ -0,0 +1,235 @@
+package cron
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/env"
+)
+
+type acquired struct {
+	Name    string
+	Release func()
+}
+
+func acquireAsync(cl *ConcurrencyLimiter, name, group string, priority int, output chan acquired) {
+	go func() {
+		release, _, err := cl.Acquire(context.Background(), group, priority)
+		if err == nil {
+			output <- acquired{Name: name, Release: release}
+		}
+	}()
+}
+
+// waitQueued waits for a number of invocations to be queued.
+func waitQueued(cl *ConcurrencyLimiter, queued int) {
+	for cl.Status().Queued != queued {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConcurrencyLimiterPriority(t *testing.T) {
+	assert := assert.New(t)
+
+	cl := NewConcurrencyLimiter()
+	cl.MaxConcurrent = 1
+
+	release, waited, err := cl.Acquire(context.Background(), "", 0)
+	assert.Nil(err)
+	assert.True(waited < time.Second)
+
+	granted := make(chan acquired, 3)
+	acquireAsync(cl, "low", "", 0, granted)
+	waitQueued(cl, 1)
+	acquireAsync(cl, "fifo", "", 0, granted)
+	waitQueued(cl, 2)
+	acquireAsync(cl, "high", "", 10, granted)
+	waitQueued(cl, 3)
+
+	status := cl.Status()
+	assert.Equal(1, status.Running)
+	assert.Equal(3, status.Queued)
+
+	release()
+	for _, expected := range []string{"high", "low", "fifo"} {
+		next := <-granted
+		assert.Equal(expected, next.Name)
+		next.Release()
+	}
+	assert.Zero(cl.Status().Running)
+}
+
+func TestConcurrencyLimiterGroups(t *testing.T) {
+	assert := assert.New(t)
+
+	cl := NewConcurrencyLimiter()
+	cl.SetGroup("db", 1)
+
+	release, _, err := cl.Acquire(context.Background(), "db", 0)
+	assert.Nil(err)
+
+	granted := make(chan acquired, 2)
+	acquireAsync(cl, "db", "db", 0, granted)
+	waitQueued(cl, 1)
+
+	// invocations in other groups are not held up by the queued one.
+	acquireAsync(cl, "other", "", 0, granted)
+	next := <-granted
+	assert.Equal("other", next.Name)
+	next.Release()
+
+	status := cl.Status()
+	assert.Equal(1, status.Groups["db"].MaxConcurrent)
+	assert.Equal(1, status.Groups["db"].Running)
+	assert.Equal(1, status.Groups["db"].Queued)
+
+	release()
+	next = <-granted
+	assert.Equal("db", next.Name)
+	next.Release()
+
+	status = cl.Status()
+	assert.Zero(status.Running)
+	assert.Zero(status.Groups["db"].Running)
+}
+
+func TestConcurrencyLimiterCancelled(t *testing.T) {
+	assert := assert.New(t)
+
+	cl := NewConcurrencyLimiter()
+	cl.MaxConcurrent = 1
+
+	release, _, err := cl.Acquire(context.Background(), "", 0)
+	assert.Nil(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errors := make(chan error)
+	go func() {
+		_, _, err := cl.Acquire(ctx, "", 0)
+		errors <- err
+	}()
+	waitQueued(cl, 1)
+	cancel()
+	assert.True(IsJobCancelled(<-errors))
+	assert.Zero(cl.Status().Queued)
+
+	release()
+	assert.Zero(cl.Status().Running)
+}
+
+func TestJobManagerMaxConcurrent(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan string, 2)
+	unblock := make(chan struct{})
+	blocking := func(name string) Job {
+		return NewJob(name, func(_ context.Context) error {
+			started <- name
+			<-unblock
+			return nil
+		})
+	}
+
+	jm := New(OptMaxConcurrent(1))
+	assert.Nil(jm.LoadJobs(blocking("first"), blocking("second")))
+
+	assert.Nil(jm.RunJob("first"))
+	assert.Equal("first", <-started)
+	assert.Nil(jm.RunJob("second"))
+	waitQueued(jm.ConcurrencyLimiter, 1)
+
+	status := jm.Status()
+	assert.NotNil(status.Concurrency)
+	assert.Equal(1, status.Concurrency.Running)
+	assert.Equal(1, status.Concurrency.Queued)
+
+	unblock <- struct{}{}
+	assert.Equal("second", <-started)
+	unblock <- struct{}{}
+
+	second, err := jm.Job("second")
+	assert.Nil(err)
+	for second.CurrentInvocation() != nil || second.LastInvocation() == nil {
+		time.Sleep(time.Millisecond)
+	}
+	assert.NotZero(second.LastInvocation().QueueWait)
+}
+
+func TestConfigConcurrencyLimiter(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(Config{}.ConcurrencyLimiter())
+
+	cl := Config{MaxConcurrent: 4, ConcurrencyGroups: map[string]int{"db": 2}}.ConcurrencyLimiter()
+	assert.NotNil(cl)
+	assert.Equal(4, cl.MaxConcurrent)
+	assert.Equal(2, cl.Groups["db"])
+}
+
+func TestConfigResolveMaxConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+	env.SetEnv(env.Vars{"CRON_MAX_CONCURRENT": "3"})
+
+	var cfg Config
+	assert.Nil(cfg.Resolve())
+	assert.Equal(3, cfg.MaxConcurrent)
+}
+
+func TestJobSchedulerConcurrencyTicks(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int32
+	cl := NewConcurrencyLimiter()
+	cl.MaxConcurrent = 1
+	js := NewJobScheduler(NewJob("ticks", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}), OptJobSchedulerConcurrencyLimiter(cl))
+
+	release, _, err := cl.Acquire(context.Background(), "", 0)
+	assert.Nil(err)
+
+	// a tick waits in the limiter, and later ticks aren't queued behind it.
+	done := make(chan *JobInvocation, 1)
+	go func() { done <- js.run(context.Background(), Now(), nil, true) }()
+	waitQueued(cl, 1)
+	assert.Nil(js.run(context.Background(), Now(), nil, true))
+	assert.Equal(1, cl.Status().Queued)
+
+	// cancelling the job drops the queued tick.
+	js.Cancel()
+	assert.Nil(<-done)
+	assert.Zero(cl.Status().Queued)
+	release()
+	assert.Zero(cl.Status().Running)
+	assert.Zero(atomic.LoadInt32(&runs))
+}
+
+func TestJobSchedulerConcurrencyTickLate(t *testing.T) {
+	assert := assert.New(t)
+
+	var runs int32
+	cl := NewConcurrencyLimiter()
+	cl.MaxConcurrent = 1
+	js := NewJobScheduler(NewJob("late", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	}, OptJobBuilderMisfirePolicy(MisfirePolicy{StartDeadline: 10 * time.Millisecond})), OptJobSchedulerConcurrencyLimiter(cl))
+
+	release, _, err := cl.Acquire(context.Background(), "", 0)
+	assert.Nil(err)
+	done := make(chan *JobInvocation, 1)
+	go func() { done <- js.run(context.Background(), Now(), nil, true) }()
+	waitQueued(cl, 1)
+	time.Sleep(20 * time.Millisecond)
+	release()
+
+	// the tick is past its start deadline once it can run, so it is skipped.
+	assert.Nil(<-done)
+	assert.Zero(atomic.LoadInt32(&runs))
+	assert.Zero(cl.Status().Running)
+}
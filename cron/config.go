@@ -6,17 +6,25 @@ import (
 	"github.com/blend/go-sdk/configutil"
 )
 
-// Config governs job history retention in memory.
+// Config governs job history retention in memory, and optionally on disk.
 type Config struct {
 	HistoryMaxCount int           `json:"historyMaxCount" yaml:"historyMaxCount" env:"CRON_HISTORY_MAX_COUNT"`
 	HistoryMaxAge   time.Duration `json:"historyMaxAge" yaml:"historyMaxAge" env:"CRON_HISTORY_MAX_AGE"`
+	// HistoryPath is an optional path to a file to persist history to with a `FileHistoryStore`.
+	HistoryPath string `json:"historyPath" yaml:"historyPath" env:"CRON_HISTORY_PATH"`
+	// MaxConcurrent optionally limits the number of job invocations that run at once.
+	MaxConcurrent int `json:"maxConcurrent" yaml:"maxConcurrent" env:"CRON_MAX_CONCURRENT"`
+	// ConcurrencyGroups optionally limit the number of invocations that run at once by concurrency group.
+	ConcurrencyGroups map[string]int `json:"concurrencyGroups" yaml:"concurrencyGroups"`
 }
 
 // Resolve adds extra resolution steps when reading the config.
-func (hc Config) Resolve() error {
+func (hc *Config) Resolve() error {
 	return configutil.AnyError(
 		configutil.SetInt(&hc.HistoryMaxCount, configutil.Int(hc.HistoryMaxCount), configutil.Parse(configutil.Env("CRON_HISTORY_MAX_COUNT")), configutil.Int(DefaultHistoryMaxCount)),
 		configutil.SetDuration(&hc.HistoryMaxAge, configutil.Duration(hc.HistoryMaxAge), configutil.Parse(configutil.Env("CRON_HISTORY_MAX_AGE")), configutil.Duration(DefaultHistoryMaxAge)),
+		configutil.SetString(&hc.HistoryPath, configutil.String(hc.HistoryPath), configutil.Env("CRON_HISTORY_PATH")),
+		configutil.SetInt(&hc.MaxConcurrent, configutil.Parse(configutil.Env("CRON_MAX_CONCURRENT")), configutil.Int(hc.MaxConcurrent)),
 	)
 }
 
@@ -35,3 +43,24 @@ func (hc Config) HistoryMaxAgeOrDefault() time.Duration {
 	}
 	return DefaultHistoryMaxAge
 }
+
+// HistoryStore returns a file history store if `HistoryPath` is set, or nil.
+func (hc Config) HistoryStore() HistoryStore {
+	if hc.HistoryPath != "" {
+		return NewFileHistoryStore(hc.HistoryPath)
+	}
+	return nil
+}
+
+// ConcurrencyLimiter returns a concurrency limiter if `MaxConcurrent` or `ConcurrencyGroups` are set, or nil.
+func (hc Config) ConcurrencyLimiter() *ConcurrencyLimiter {
+	if hc.MaxConcurrent <= 0 && len(hc.ConcurrencyGroups) == 0 {
+		return nil
+	}
+	cl := NewConcurrencyLimiter()
+	cl.MaxConcurrent = hc.MaxConcurrent
+	for group, maxConcurrent := range hc.ConcurrencyGroups {
+		cl.Groups[group] = maxConcurrent
+	}
+	return cl
+}
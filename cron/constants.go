@@ -15,6 +15,30 @@ const (
 	DefaultHistoryMaxAge   = 6 * time.Hour
 )
 
+// History store defaults
+const (
+	// DefaultHistoryFileMode is the file mode used to create history files.
+	DefaultHistoryFileMode = 0644
+	// MaxHistoryEntrySize is the maximum size of a serialized history entry in a history file.
+	MaxHistoryEntrySize = 64 << 20 // 64mb
+)
+
+// Lease defaults
+const (
+	// DefaultLeaseName is the name of the job manager lease.
+	DefaultLeaseName = "cron"
+	// DefaultLeaseTTL is the default lease ttl; leases are renewed every third of the ttl.
+	DefaultLeaseTTL = 30 * time.Second
+)
+
+// Misfire defaults
+const (
+	// DefaultMisfireMaxRuns is the default number of missed runs that are run in `MisfireModeRunAll`.
+	DefaultMisfireMaxRuns = 10
+	// MisfireMaxScan is the most times a schedule is evaluated when looking for missed runs.
+	MisfireMaxScan = 100000
+)
+
 const (
 	// DefaultHeartbeatInterval is the interval between schedule next run checks.
 	DefaultHeartbeatInterval = 50 * time.Millisecond
@@ -36,6 +60,8 @@ const (
 	FlagStarted = "cron.started"
 	// FlagFailed is an event flag.
 	FlagFailed = "cron.failed"
+	// FlagRetry is an event flag.
+	FlagRetry = "cron.retry"
 	// FlagCancelled is an event flag.
 	FlagCancelled = "cron.cancelled"
 	// FlagComplete is an event flag.
@@ -48,6 +74,14 @@ const (
 	FlagEnabled = "cron.enabled"
 	// FlagDisabled is an event flag.
 	FlagDisabled = "cron.disabled"
+	// FlagMisfireSkipped is an event flag.
+	FlagMisfireSkipped = "cron.misfire.skipped"
+	// FlagMisfireRun is an event flag.
+	FlagMisfireRun = "cron.misfire.run"
+	// FlagLeaderAcquired is an event flag.
+	FlagLeaderAcquired = "cron.leader.acquired"
+	// FlagLeaderLost is an event flag.
+	FlagLeaderLost = "cron.leader.lost"
 )
 
 // State is a job state.
@@ -71,4 +105,8 @@ const (
 	JobStatusCancelled JobStatus = "cancelled"
 	JobStatusFailed    JobStatus = "failed"
 	JobStatusComplete  JobStatus = "complete"
+	// JobStatusPending is the status of workflow steps that have not started.
+	JobStatusPending JobStatus = "pending"
+	// JobStatusSkipped is the status of workflow steps that did not run.
+	JobStatusSkipped JobStatus = "skipped"
 )
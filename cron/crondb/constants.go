@@ -0,0 +1,10 @@
+package crondb
+
+const (
+	// TableName is the name of the history table.
+	TableName = "cron_job_invocation_history"
+	// IndexJobNameStarted is the name of the index on job name and start time.
+	IndexJobNameStarted = "ix_cron_job_invocation_history_job_name_started"
+	// LeaseTableName is the name of the lease table.
+	LeaseTableName = "cron_lease"
+)
@@ -0,0 +1,207 @@
+package crondb
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+)
+
+var (
+	_ cron.HistoryStore = (*HistoryStore)(nil)
+)
+
+// New returns a new history store for a connection.
+func New(conn *db.Connection) *HistoryStore {
+	return &HistoryStore{Conn: conn}
+}
+
+// HistoryStore is a cron.HistoryStore backed by a postgres table.
+//
+// The table must be created before use with the migrations returned by `Migrations()`.
+type HistoryStore struct {
+	Conn *db.Connection
+}
+
+// Migrations returns the migrations that create the history table and its indexes.
+func (hs HistoryStore) Migrations() *migration.Suite {
+	return migration.New(
+		migration.Group(
+			migration.Step(
+				migration.TableNotExists(TableName),
+				migration.Statements(
+					fmt.Sprintf(`CREATE TABLE %s (
+						id varchar(255) not null primary key,
+						job_name varchar(255) not null,
+						started timestamp not null,
+						finished timestamp,
+						cancelled timestamp,
+						timeout timestamp,
+						elapsed bigint not null,
+						status varchar(32) not null,
+						err_class text,
+						err_message text,
+						state jsonb
+					);`, TableName),
+				),
+			),
+			migration.Step(
+				migration.ColumnNotExists(TableName, "attempt"),
+				migration.Statements(
+					fmt.Sprintf(`ALTER TABLE %s ADD COLUMN attempt int not null default 1;`, TableName),
+				),
+			),
+			migration.Step(
+				migration.ColumnNotExists(TableName, "scheduled"),
+				migration.Statements(
+					fmt.Sprintf(`ALTER TABLE %s ADD COLUMN scheduled timestamp;`, TableName),
+				),
+			),
+			migration.Step(
+				migration.ColumnNotExists(TableName, "queue_wait"),
+				migration.Statements(
+					fmt.Sprintf(`ALTER TABLE %s ADD COLUMN queue_wait bigint not null default 0;`, TableName),
+				),
+			),
+			migration.Step(
+				migration.ColumnNotExists(TableName, "parameters"),
+				migration.Statements(
+					fmt.Sprintf(`ALTER TABLE %s ADD COLUMN parameters jsonb;`, TableName),
+				),
+			),
+			migration.Step(
+				migration.IndexNotExists(TableName, IndexJobNameStarted),
+				migration.Statements(
+					fmt.Sprintf(`CREATE INDEX %s ON %s (job_name, started);`, IndexJobNameStarted, TableName),
+				),
+			),
+		),
+	)
+}
+
+// SaveHistory implements cron.HistoryStore.
+func (hs HistoryStore) SaveHistory(ctx context.Context, entry cron.HistoryEntry) error {
+	return hs.Conn.Invoke(db.OptContext(ctx)).Upsert(newHistoryRow(entry))
+}
+
+// QueryHistory implements cron.HistoryStore.
+func (hs HistoryStore) QueryHistory(ctx context.Context, query cron.HistoryQuery) ([]cron.HistoryEntry, error) {
+	var where []string
+	var args []interface{}
+	addArg := func(clause string, arg interface{}) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(clause, len(args)))
+	}
+	if query.JobName != "" {
+		addArg("job_name = $%d", query.JobName)
+	}
+	if len(query.Statuses) > 0 {
+		var placeholders []string
+		for _, status := range query.Statuses {
+			args = append(args, string(status))
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		where = append(where, fmt.Sprintf("status IN (%s)", strings.Join(placeholders, ", ")))
+	}
+	if !query.After.IsZero() {
+		addArg("started >= $%d", query.After)
+	}
+	if !query.Before.IsZero() {
+		addArg("started < $%d", query.Before)
+	}
+
+	statement := fmt.Sprintf("SELECT %s FROM %s", db.ColumnNamesCSV(historyRow{}), TableName)
+	if len(where) > 0 {
+		statement += " WHERE " + strings.Join(where, " AND ")
+	}
+	// select the most recent rows so the limit applies to them, and reverse them below.
+	statement += " ORDER BY started DESC"
+	if query.Limit > 0 {
+		statement += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	var rows []historyRow
+	if err := hs.Conn.Invoke(db.OptContext(ctx)).Query(statement, args...).OutMany(&rows); err != nil {
+		return nil, err
+	}
+	output := make([]cron.HistoryEntry, len(rows))
+	for index, row := range rows {
+		output[len(rows)-1-index] = row.HistoryEntry()
+	}
+	return output, nil
+}
+
+// Cull removes entries for invocations that started before a given time.
+func (hs HistoryStore) Cull(ctx context.Context, before time.Time) error {
+	return hs.Conn.Invoke(db.OptContext(ctx)).Exec(fmt.Sprintf("DELETE FROM %s WHERE started < $1", TableName), before)
+}
+
+func newHistoryRow(entry cron.HistoryEntry) *historyRow {
+	return &historyRow{
+		ID:         entry.ID,
+		JobName:    entry.JobName,
+		Scheduled:  entry.Scheduled,
+		QueueWait:  int64(entry.QueueWait),
+		Parameters: entry.Parameters,
+		Started:    entry.Started,
+		Finished:   entry.Finished,
+		Cancelled:  entry.Cancelled,
+		Timeout:    entry.Timeout,
+		Elapsed:    int64(entry.Elapsed),
+		Status:     string(entry.Status),
+		Attempt:    entry.Attempt,
+		ErrClass:   entry.ErrClass,
+		ErrMessage: entry.ErrMessage,
+		State:      entry.State,
+	}
+}
+
+// historyRow is the database mapped form of a history entry.
+type historyRow struct {
+	ID         string          `db:"id,pk"`
+	JobName    string          `db:"job_name"`
+	Scheduled  time.Time       `db:"scheduled"`
+	QueueWait  int64           `db:"queue_wait"`
+	Parameters cron.Parameters `db:"parameters,json"`
+	Started    time.Time       `db:"started"`
+	Finished   time.Time       `db:"finished"`
+	Cancelled  time.Time       `db:"cancelled"`
+	Timeout    time.Time       `db:"timeout"`
+	Elapsed    int64           `db:"elapsed"`
+	Status     string          `db:"status"`
+	Attempt    int             `db:"attempt"`
+	ErrClass   string          `db:"err_class"`
+	ErrMessage string          `db:"err_message"`
+	State      json.RawMessage `db:"state,json"`
+}
+
+// TableName returns the mapped table name.
+func (hr historyRow) TableName() string {
+	return TableName
+}
+
+// HistoryEntry returns the history entry for the row.
+func (hr historyRow) HistoryEntry() cron.HistoryEntry {
+	return cron.HistoryEntry{
+		ID:         hr.ID,
+		JobName:    hr.JobName,
+		Scheduled:  hr.Scheduled.UTC(),
+		QueueWait:  time.Duration(hr.QueueWait),
+		Parameters: hr.Parameters,
+		Started:    hr.Started.UTC(),
+		Finished:   hr.Finished.UTC(),
+		Cancelled:  hr.Cancelled.UTC(),
+		Timeout:    hr.Timeout.UTC(),
+		Elapsed:    time.Duration(hr.Elapsed),
+		Status:     cron.JobStatus(hr.Status),
+		Attempt:    hr.Attempt,
+		ErrClass:   hr.ErrClass,
+		ErrMessage: hr.ErrMessage,
+		State:      hr.State,
+	}
+}
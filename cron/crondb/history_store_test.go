@@ -0,0 +1,156 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/uuid"
+)
+
+func TestHistoryStoreMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	// the migrations are applied by TestMain, so applying them again should be a no-op.
+	assert.Nil(New(db.Default()).Migrations().Apply(context.Background(), db.Default()))
+
+	tx, err := db.Default().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	exists, err := migration.PredicateTableExists(db.Default(), tx, TableName)
+	assert.Nil(err)
+	assert.True(exists)
+	for _, column := range []string{"attempt", "scheduled", "queue_wait", "parameters", "state"} {
+		exists, err = migration.PredicateColumnExists(db.Default(), tx, TableName, column)
+		assert.Nil(err)
+		assert.True(exists, column)
+	}
+	exists, err = migration.PredicateIndexExists(db.Default(), tx, TableName, IndexJobNameStarted)
+	assert.Nil(err)
+	assert.True(exists)
+}
+
+func TestHistoryStoreSaveQuery(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	store := New(db.Default())
+	jobName := "test_" + uuid.V4().String()
+	defer cleanupHistory(db.Default(), jobName)
+
+	started := time.Now().UTC().Truncate(time.Millisecond).Add(-time.Hour)
+	statuses := []cron.JobStatus{cron.JobStatusComplete, cron.JobStatusFailed, cron.JobStatusComplete, cron.JobStatusCancelled}
+	for index, status := range statuses {
+		entry := cron.HistoryEntry{
+			ID:         uuid.V4().String(),
+			JobName:    jobName,
+			Scheduled:  started.Add(time.Duration(index) * time.Minute),
+			QueueWait:  time.Millisecond,
+			Parameters: cron.Parameters{"index": fmt.Sprint(index)},
+			Started:    started.Add(time.Duration(index) * time.Minute),
+			Finished:   started.Add(time.Duration(index)*time.Minute + time.Second),
+			Elapsed:    time.Second,
+			Status:     status,
+			Attempt:    1,
+		}
+		if status == cron.JobStatusFailed {
+			entry.ErrClass, entry.ErrMessage = "test failure", "message"
+		}
+		assert.Nil(store.SaveHistory(ctx, entry))
+	}
+
+	// other jobs are filtered out.
+	otherJobName := "test_" + uuid.V4().String()
+	defer cleanupHistory(db.Default(), otherJobName)
+	assert.Nil(store.SaveHistory(ctx, cron.HistoryEntry{ID: uuid.V4().String(), JobName: otherJobName, Started: started, Status: cron.JobStatusComplete}))
+
+	entries, err := store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName})
+	assert.Nil(err)
+	assert.Len(entries, 4)
+	// entries are returned oldest first.
+	assert.Equal("0", entries[0].Parameters["index"])
+	assert.Equal("3", entries[3].Parameters["index"])
+	assert.True(started.Equal(entries[0].Started))
+	assert.True(started.Equal(entries[0].Scheduled))
+	assert.Equal(time.Second, entries[0].Elapsed)
+	assert.Equal(time.Millisecond, entries[0].QueueWait)
+	assert.Equal(cron.JobStatusFailed, entries[1].Status)
+	assert.Equal("test failure", entries[1].ErrClass)
+	assert.Equal("message", entries[1].ErrMessage)
+
+	entries, err = store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName, Statuses: []cron.JobStatus{cron.JobStatusFailed, cron.JobStatusCancelled}})
+	assert.Nil(err)
+	assert.Len(entries, 2)
+	assert.Equal(cron.JobStatusFailed, entries[0].Status)
+	assert.Equal(cron.JobStatusCancelled, entries[1].Status)
+
+	entries, err = store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName, After: started.Add(time.Minute), Before: started.Add(3 * time.Minute)})
+	assert.Nil(err)
+	assert.Len(entries, 2)
+	assert.Equal("1", entries[0].Parameters["index"])
+	assert.Equal("2", entries[1].Parameters["index"])
+
+	// the limit applies to the most recent entries.
+	entries, err = store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName, Limit: 2})
+	assert.Nil(err)
+	assert.Len(entries, 2)
+	assert.Equal("2", entries[0].Parameters["index"])
+	assert.Equal("3", entries[1].Parameters["index"])
+
+	// saving an entry again updates it.
+	entries[1].Status = cron.JobStatusComplete
+	assert.Nil(store.SaveHistory(ctx, entries[1]))
+	entries, err = store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName, Statuses: []cron.JobStatus{cron.JobStatusCancelled}})
+	assert.Nil(err)
+	assert.Empty(entries)
+}
+
+func TestHistoryStoreState(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	store := New(db.Default())
+	jobName := "test_" + uuid.V4().String()
+	defer cleanupHistory(db.Default(), jobName)
+
+	assert.Nil(store.SaveHistory(ctx, cron.HistoryEntry{
+		ID:      uuid.V4().String(),
+		JobName: jobName,
+		Started: time.Now().UTC(),
+		Status:  cron.JobStatusComplete,
+		State:   []byte(`{"processed":3}`),
+	}))
+
+	entries, err := store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName})
+	assert.Nil(err)
+	assert.Len(entries, 1)
+	assert.Equal(`{"processed": 3}`, string(entries[0].State))
+}
+
+func TestHistoryStoreCull(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	store := New(db.Default())
+	jobName := "test_" + uuid.V4().String()
+	defer cleanupHistory(db.Default(), jobName)
+
+	now := time.Now().UTC()
+	assert.Nil(store.SaveHistory(ctx, cron.HistoryEntry{ID: uuid.V4().String(), JobName: jobName, Started: now.Add(-2 * time.Hour), Status: cron.JobStatusComplete}))
+	assert.Nil(store.SaveHistory(ctx, cron.HistoryEntry{ID: uuid.V4().String(), JobName: jobName, Started: now, Status: cron.JobStatusComplete}))
+
+	assert.Nil(store.Cull(ctx, now.Add(-time.Hour)))
+	entries, err := store.QueryHistory(ctx, cron.HistoryQuery{JobName: jobName})
+	assert.Nil(err)
+	assert.Len(entries, 1)
+}
+
+func cleanupHistory(conn *db.Connection, jobName string) error {
+	return conn.Exec(fmt.Sprintf("DELETE FROM %s WHERE job_name = $1", TableName), jobName)
+}
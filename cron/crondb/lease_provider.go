@@ -0,0 +1,105 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ cron.LeaseProvider = (*LeaseProvider)(nil)
+)
+
+// NewLeaseProvider returns a new lease provider for a connection.
+func NewLeaseProvider(conn *db.Connection) *LeaseProvider {
+	return &LeaseProvider{Conn: conn}
+}
+
+// LeaseProvider is a cron.LeaseProvider backed by a postgres table.
+//
+// Lease expiry is computed with the database clock, so replicas only need
+// their clocks to roughly agree with the database.
+// The table must be created before use with the migrations returned by `Migrations()`.
+type LeaseProvider struct {
+	Conn *db.Connection
+}
+
+// Migrations returns the migrations that create the lease table.
+func (lp LeaseProvider) Migrations() *migration.Suite {
+	return migration.New(
+		migration.Group(
+			migration.Step(
+				migration.TableNotExists(LeaseTableName),
+				migration.Statements(
+					fmt.Sprintf(`CREATE TABLE %s (
+						name varchar(255) not null primary key,
+						holder varchar(255) not null,
+						token bigint not null,
+						expires timestamp not null
+					);`, LeaseTableName),
+				),
+			),
+		),
+	)
+}
+
+// Acquire implements cron.LeaseProvider.
+//
+// The lease is upserted in a single statement; the update only applies if the lease
+// is already held by the holder or has expired, and the token is incremented if the holder changes.
+func (lp LeaseProvider) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*cron.Lease, error) {
+	statement := fmt.Sprintf(`INSERT INTO %[1]s (name, holder, token, expires)
+		VALUES ($1, $2, 1, (now() at time zone 'utc') + $3 * interval '1 millisecond')
+		ON CONFLICT (name) DO UPDATE SET
+			token = CASE WHEN %[1]s.holder = EXCLUDED.holder THEN %[1]s.token ELSE %[1]s.token + 1 END,
+			holder = EXCLUDED.holder,
+			expires = EXCLUDED.expires
+		WHERE %[1]s.holder = EXCLUDED.holder OR %[1]s.expires <= (now() at time zone 'utc')
+		RETURNING %[2]s`, LeaseTableName, db.ColumnNamesCSV(leaseRow{}))
+
+	var row leaseRow
+	if err := lp.Conn.Invoke(db.OptContext(ctx)).Query(statement, name, holder, ttl.Nanoseconds()/int64(time.Millisecond)).Out(&row); err != nil {
+		return nil, err
+	}
+	if row.Name == "" {
+		return nil, ex.New(cron.ErrLeaseHeld, ex.OptMessagef("lease: %s", name))
+	}
+	lease := row.Lease()
+	return &lease, nil
+}
+
+// Release implements cron.LeaseProvider.
+func (lp LeaseProvider) Release(ctx context.Context, lease cron.Lease) error {
+	return lp.Conn.Invoke(db.OptContext(ctx)).Exec(
+		fmt.Sprintf("UPDATE %s SET expires = (now() at time zone 'utc') WHERE name = $1 AND holder = $2 AND token = $3", LeaseTableName),
+		lease.Name, lease.Holder, lease.Token,
+	)
+}
+
+// leaseRow is the database mapped form of a lease.
+type leaseRow struct {
+	Name    string    `db:"name,pk"`
+	Holder  string    `db:"holder"`
+	Token   int64     `db:"token"`
+	Expires time.Time `db:"expires"`
+}
+
+// TableName returns the mapped table name.
+func (lr leaseRow) TableName() string {
+	return LeaseTableName
+}
+
+// Lease returns the lease for the row.
+func (lr leaseRow) Lease() cron.Lease {
+	return cron.Lease{
+		Name:    lr.Name,
+		Holder:  lr.Holder,
+		Token:   lr.Token,
+		Expires: lr.Expires.UTC(),
+	}
+}
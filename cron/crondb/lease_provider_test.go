@@ -0,0 +1,89 @@
+package crondb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/db/migration"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/uuid"
+)
+
+func TestLeaseProviderMigrations(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(NewLeaseProvider(db.Default()).Migrations().Apply(context.Background(), db.Default()))
+
+	tx, err := db.Default().Begin()
+	assert.Nil(err)
+	defer tx.Rollback()
+
+	exists, err := migration.PredicateTableExists(db.Default(), tx, LeaseTableName)
+	assert.Nil(err)
+	assert.True(exists)
+}
+
+func TestLeaseProviderAcquireRenewRelease(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	provider := NewLeaseProvider(db.Default())
+	name := "test_" + uuid.V4().String()
+	defer cleanupLease(db.Default(), name)
+
+	lease, err := provider.Acquire(ctx, name, "a", time.Minute)
+	assert.Nil(err)
+	assert.Equal(name, lease.Name)
+	assert.Equal("a", lease.Holder)
+	assert.Equal(1, lease.Token)
+	assert.False(lease.IsExpired(time.Now().UTC()))
+
+	// renewing the lease extends it and keeps the token.
+	renewed, err := provider.Acquire(ctx, name, "a", time.Hour)
+	assert.Nil(err)
+	assert.Equal(1, renewed.Token)
+	assert.True(renewed.Expires.After(lease.Expires))
+
+	// another holder can't acquire a lease that is held.
+	_, err = provider.Acquire(ctx, name, "b", time.Minute)
+	assert.True(ex.Is(err, cron.ErrLeaseHeld))
+
+	// a released lease can be acquired by another holder, which increments the token.
+	assert.Nil(provider.Release(ctx, *renewed))
+	acquired, err := provider.Acquire(ctx, name, "b", time.Minute)
+	assert.Nil(err)
+	assert.Equal("b", acquired.Holder)
+	assert.Equal(2, acquired.Token)
+
+	// releasing a lease that has changed holders does nothing.
+	assert.Nil(provider.Release(ctx, *renewed))
+	_, err = provider.Acquire(ctx, name, "a", time.Minute)
+	assert.True(ex.Is(err, cron.ErrLeaseHeld))
+}
+
+func TestLeaseProviderAcquireExpired(t *testing.T) {
+	assert := assert.New(t)
+	ctx := context.Background()
+
+	provider := NewLeaseProvider(db.Default())
+	name := "test_" + uuid.V4().String()
+	defer cleanupLease(db.Default(), name)
+
+	_, err := provider.Acquire(ctx, name, "a", time.Millisecond)
+	assert.Nil(err)
+	time.Sleep(50 * time.Millisecond)
+
+	acquired, err := provider.Acquire(ctx, name, "b", time.Minute)
+	assert.Nil(err)
+	assert.Equal("b", acquired.Holder)
+	assert.Equal(2, acquired.Token)
+}
+
+func cleanupLease(conn *db.Connection, name string) error {
+	return conn.Exec(fmt.Sprintf("DELETE FROM %s WHERE name = $1", LeaseTableName), name)
+}
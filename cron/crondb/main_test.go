@@ -0,0 +1,32 @@
+package crondb
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	// tests use postgres
+	_ "github.com/lib/pq"
+
+	"github.com/blend/go-sdk/db"
+	"github.com/blend/go-sdk/logger"
+)
+
+// TestMain is the testing entrypoint.
+// It connects to the database from the environment and creates the history and lease tables.
+func TestMain(m *testing.M) {
+	conn, err := db.New(db.OptConfigFromEnv())
+	if err != nil {
+		logger.FatalExit(err)
+	}
+	if err = db.OpenDefault(conn); err != nil {
+		logger.FatalExit(err)
+	}
+	if err = New(conn).Migrations().Apply(context.Background(), conn); err != nil {
+		logger.FatalExit(err)
+	}
+	if err = NewLeaseProvider(conn).Migrations().Apply(context.Background(), conn); err != nil {
+		logger.FatalExit(err)
+	}
+	os.Exit(m.Run())
+}
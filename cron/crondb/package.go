@@ -0,0 +1,2 @@
+// Package crondb contains database backed job invocation history stores and lease providers for cron.
+package crondb
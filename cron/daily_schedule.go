@@ -13,36 +13,63 @@ var (
 
 // WeeklyAtUTC returns a schedule that fires on every of the given days at the given time by hour, minute and second in UTC.
 func WeeklyAtUTC(hour, minute, second int, days ...time.Weekday) Schedule {
+	return WeeklyAt(hour, minute, second, time.UTC, days...)
+}
+
+// WeeklyAt returns a schedule that fires on every of the given days at the given time by hour, minute and second in a location.
+func WeeklyAt(hour, minute, second int, location *time.Location, days ...time.Weekday) Schedule {
 	dayOfWeekMask := uint(0)
 	for _, day := range days {
 		dayOfWeekMask = dayOfWeekMask | 1<<uint(day)
 	}
-
-	return &DailySchedule{DayOfWeekMask: dayOfWeekMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return &DailySchedule{DayOfWeekMask: dayOfWeekMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // DailyAtUTC returns a schedule that fires every day at the given hour, minute and second in UTC.
 func DailyAtUTC(hour, minute, second int) Schedule {
-	return &DailySchedule{DayOfWeekMask: AllDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return DailyAt(hour, minute, second, time.UTC)
+}
+
+// DailyAt returns a schedule that fires every day at the given hour, minute and second in a location.
+func DailyAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: AllDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // WeekdaysAtUTC returns a schedule that fires every week day at the given hour, minute and second in UTC>
 func WeekdaysAtUTC(hour, minute, second int) Schedule {
-	return &DailySchedule{DayOfWeekMask: WeekDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return WeekdaysAt(hour, minute, second, time.UTC)
+}
+
+// WeekdaysAt returns a schedule that fires every week day at the given hour, minute and second in a location.
+func WeekdaysAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: WeekDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // WeekendsAtUTC returns a schedule that fires every weekend day at the given hour, minut and second.
 func WeekendsAtUTC(hour, minute, second int) Schedule {
-	return &DailySchedule{DayOfWeekMask: WeekendDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC)}
+	return WeekendsAt(hour, minute, second, time.UTC)
+}
+
+// WeekendsAt returns a schedule that fires every weekend day at the given hour, minute and second in a location.
+func WeekendsAt(hour, minute, second int, location *time.Location) Schedule {
+	return &DailySchedule{DayOfWeekMask: WeekendDaysMask, TimeOfDayUTC: time.Date(0, 0, 0, hour, minute, second, 0, time.UTC), Location: location}
 }
 
 // DailySchedule is a schedule that fires every day that satisfies the DayOfWeekMask at the given TimeOfDayUTC.
+//
+// If Location is set, the hour, minute and second of TimeOfDayUTC are a wall clock time in that location,
+// and days are days in that location. See `Schedule` for how daylight saving time transitions are handled.
 type DailySchedule struct {
 	DayOfWeekMask uint
 	TimeOfDayUTC  time.Time
+	Location      *time.Location
 }
 
 func (ds DailySchedule) String() string {
+	timeOfDay := ds.TimeOfDayUTC.Format(time.RFC3339)
+	if ds.Location != nil && ds.Location != time.UTC {
+		timeOfDay = fmt.Sprintf("%s %s", ds.TimeOfDayUTC.Format("15:04:05"), ds.Location.String())
+	}
 	if ds.DayOfWeekMask > 0 {
 		var days []string
 		for _, d := range DaysOfWeek {
@@ -50,9 +77,9 @@ func (ds DailySchedule) String() string {
 				days = append(days, d.String())
 			}
 		}
-		return fmt.Sprintf("%s on %s each week", ds.TimeOfDayUTC.Format(time.RFC3339), strings.Join(days, ", "))
+		return fmt.Sprintf("%s on %s each week", timeOfDay, strings.Join(days, ", "))
 	}
-	return fmt.Sprintf("%s every day", ds.TimeOfDayUTC.Format(time.RFC3339))
+	return fmt.Sprintf("%s every day", timeOfDay)
 }
 
 func (ds DailySchedule) checkDayOfWeekMask(day time.Weekday) bool {
@@ -66,15 +93,16 @@ func (ds DailySchedule) Next(after time.Time) time.Time {
 	if after.IsZero() {
 		after = Now()
 	}
-
-	todayInstance := time.Date(after.Year(), after.Month(), after.Day(), ds.TimeOfDayUTC.Hour(), ds.TimeOfDayUTC.Minute(), ds.TimeOfDayUTC.Second(), 0, time.UTC)
+	local := inLocation(after, ds.Location)
 	for day := 0; day < 8; day++ {
-		next := todayInstance.AddDate(0, 0, day) //the first run here it should be adding nothing, i.e. returning todayInstance ...
-
-		if ds.checkDayOfWeekMask(next.Weekday()) && next.After(after) { //we're on a day ...
+		//the first run here it should be adding nothing, i.e. returning today's instance ...
+		wall := time.Date(local.Year(), local.Month(), local.Day()+day, ds.TimeOfDayUTC.Hour(), ds.TimeOfDayUTC.Minute(), ds.TimeOfDayUTC.Second(), 0, time.UTC)
+		if !ds.checkDayOfWeekMask(wall.Weekday()) {
+			continue
+		}
+		if next := wallTime(ds.Location, wall); next.After(after) { //we're on a day ...
 			return next
 		}
 	}
-
 	return Zero
 }
@@ -12,8 +12,29 @@ const (
 	// ErrJobNotFound is a common error.
 	ErrJobNotFound ex.Class = "job not found"
 
+	// ErrJobInWorkflow is returned when a job used by a workflow step is unloaded.
+	ErrJobInWorkflow ex.Class = "job used by a workflow"
+
 	// ErrJobCancelled is a common error.
 	ErrJobCancelled ex.Class = "job cancelled"
+
+	// ErrLeaseHeld is returned by lease providers when another holder has the lease.
+	ErrLeaseHeld ex.Class = "lease held by another holder"
+
+	// ErrLeaseLost is a common error.
+	ErrLeaseLost ex.Class = "lease lost"
+
+	// ErrWorkflowInvalid is returned when a workflow is loaded with invalid steps.
+	ErrWorkflowInvalid ex.Class = "workflow invalid"
+
+	// ErrWorkflowCycle is returned when a workflow is loaded with cyclic dependencies.
+	ErrWorkflowCycle ex.Class = "workflow has a dependency cycle"
+
+	// ErrWorkflowStepFailed is returned by workflow runs with failed steps.
+	ErrWorkflowStepFailed ex.Class = "workflow step failed"
+
+	// ErrParameterInvalid is returned when a job is run with invalid parameters.
+	ErrParameterInvalid ex.Class = "job parameter invalid"
 )
 
 // IsJobNotLoaded returns if the error is a job not loaded error.
@@ -31,7 +52,37 @@ func IsJobNotFound(err error) bool {
 	return ex.Is(err, ErrJobNotFound)
 }
 
+// IsJobInWorkflow returns if the error is a job used by a workflow error.
+func IsJobInWorkflow(err error) bool {
+	return ex.Is(err, ErrJobInWorkflow)
+}
+
 // IsJobCancelled returns if the error is a task not found error.
 func IsJobCancelled(err error) bool {
 	return ex.Is(err, ErrJobCancelled)
 }
+
+// IsLeaseHeld returns if the error is a lease held error.
+func IsLeaseHeld(err error) bool {
+	return ex.Is(err, ErrLeaseHeld)
+}
+
+// IsLeaseLost returns if the error is a lease lost error.
+func IsLeaseLost(err error) bool {
+	return ex.Is(err, ErrLeaseLost)
+}
+
+// IsWorkflowInvalid returns if the error is a workflow invalid error.
+func IsWorkflowInvalid(err error) bool {
+	return ex.Is(err, ErrWorkflowInvalid)
+}
+
+// IsWorkflowCycle returns if the error is a workflow cycle error.
+func IsWorkflowCycle(err error) bool {
+	return ex.Is(err, ErrWorkflowCycle)
+}
+
+// IsParameterInvalid returns if the error is a parameter invalid error.
+func IsParameterInvalid(err error) bool {
+	return ex.Is(err, ErrParameterInvalid)
+}
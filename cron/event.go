@@ -83,6 +83,26 @@ func OptEventElapsed(elapsed time.Duration) EventOption {
 	return func(e *Event) { e.Elapsed = elapsed }
 }
 
+// OptEventAttempt sets a field.
+func OptEventAttempt(attempt int) EventOption {
+	return func(e *Event) { e.Attempt = attempt }
+}
+
+// OptEventScheduled sets a field.
+func OptEventScheduled(scheduled time.Time) EventOption {
+	return func(e *Event) { e.Scheduled = scheduled }
+}
+
+// OptEventMissed sets a field.
+func OptEventMissed(missed int) EventOption {
+	return func(e *Event) { e.Missed = missed }
+}
+
+// OptEventLease sets a field.
+func OptEventLease(lease *Lease) EventOption {
+	return func(e *Event) { e.Lease = lease }
+}
+
 // Event is an event.
 type Event struct {
 	*logger.EventMeta
@@ -94,6 +114,10 @@ type Event struct {
 	JobInvocation string
 	Err           error
 	Elapsed       time.Duration
+	Attempt       int
+	Scheduled     time.Time
+	Missed        int
+	Lease         *Lease
 }
 
 // Complete returns if the event completed.
@@ -125,17 +149,46 @@ func (e Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 		io.WriteString(wr, fmt.Sprintf("[%s]", tf.Colorize(e.JobName, ansi.ColorBlue)))
 	}
 
+	if e.Attempt > 1 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, fmt.Sprintf("attempt %d", e.Attempt))
+	}
+
+	if !e.Scheduled.IsZero() {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, fmt.Sprintf("scheduled %s", e.Scheduled.Format(time.RFC3339)))
+	}
+
+	if e.Missed > 0 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, fmt.Sprintf("missed %d", e.Missed))
+	}
+
 	if e.Elapsed > 0 {
 		io.WriteString(wr, logger.Space)
 		io.WriteString(wr, fmt.Sprintf("(%v)", e.Elapsed))
 	}
+
+	if e.Lease != nil {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, fmt.Sprintf("holder=%s token=%d", e.Lease.Holder, e.Lease.Token))
+	}
+
+	if e.Err != nil && (e.JobInvocation == "" || e.GetFlag() == FlagRetry) {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, e.Err.Error())
+	}
 }
 
 // MarshalJSON implements json.Marshaler
 func (e Event) MarshalJSON() ([]byte, error) {
 	return json.Marshal(logger.MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
-		"jobName": e.JobName,
-		"err":     e.Err,
-		"elapsed": timeutil.Milliseconds(e.Elapsed),
+		"jobName":   e.JobName,
+		"err":       e.Err,
+		"elapsed":   timeutil.Milliseconds(e.Elapsed),
+		"attempt":   e.Attempt,
+		"scheduled": e.Scheduled,
+		"missed":    e.Missed,
+		"lease":     e.Lease,
 	}))
 }
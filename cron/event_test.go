@@ -1,9 +1,13 @@
 package cron
 
 import (
+	"bytes"
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
 )
 
 func TestNewEvent(t *testing.T) {
@@ -16,3 +20,19 @@ func TestNewEvent(t *testing.T) {
 	assert.True(e.IsEnabled())
 	assert.True(e.IsWritable())
 }
+
+func TestEventMisfire(t *testing.T) {
+	assert := assert.New(t)
+
+	scheduled := time.Date(2019, 03, 01, 12, 00, 00, 00, time.UTC)
+	e := NewEvent(FlagMisfireSkipped, "test_task", OptEventScheduled(scheduled), OptEventMissed(3))
+
+	buffer := new(bytes.Buffer)
+	e.WriteText(logger.NewTextOutputFormatter(logger.OptTextNoColor()), buffer)
+	assert.Equal("[test_task] scheduled 2019-03-01T12:00:00Z missed 3", buffer.String())
+
+	contents, err := json.Marshal(e)
+	assert.Nil(err)
+	assert.Contains(string(contents), `"missed":3`)
+	assert.Contains(string(contents), `"scheduled":"2019-03-01T12:00:00Z"`)
+}
@@ -0,0 +1,126 @@
+package cron
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ HistoryStore = (*FileHistoryStore)(nil)
+)
+
+// NewFileHistoryStore returns a new file history store.
+func NewFileHistoryStore(path string) *FileHistoryStore {
+	return &FileHistoryStore{
+		Path:     path,
+		FileMode: DefaultHistoryFileMode,
+	}
+}
+
+// FileHistoryStore is a history store that appends entries to a file as json lines.
+//
+// The file grows with every invocation; use `Cull` periodically to remove old entries.
+type FileHistoryStore struct {
+	sync.Mutex
+
+	Path     string
+	FileMode os.FileMode
+}
+
+// SaveHistory implements HistoryStore.
+func (fhs *FileHistoryStore) SaveHistory(_ context.Context, entry HistoryEntry) error {
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return ex.New(err)
+	}
+
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	file, err := os.OpenFile(fhs.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, fhs.FileMode)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer file.Close()
+	if _, err = file.Write(append(contents, '\n')); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+// QueryHistory implements HistoryStore.
+func (fhs *FileHistoryStore) QueryHistory(_ context.Context, query HistoryQuery) ([]HistoryEntry, error) {
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	entries, err := fhs.readUnsafe()
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(entries), nil
+}
+
+// Cull removes entries for invocations that started before a given time.
+func (fhs *FileHistoryStore) Cull(_ context.Context, before time.Time) error {
+	fhs.Lock()
+	defer fhs.Unlock()
+
+	entries, err := fhs.readUnsafe()
+	if err != nil {
+		return err
+	}
+
+	tempPath := fhs.Path + ".tmp"
+	file, err := os.OpenFile(tempPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, fhs.FileMode)
+	if err != nil {
+		return ex.New(err)
+	}
+	encoder := json.NewEncoder(file)
+	for _, entry := range entries {
+		if entry.Started.Before(before) {
+			continue
+		}
+		if err = encoder.Encode(entry); err != nil {
+			file.Close()
+			return ex.New(err)
+		}
+	}
+	if err = file.Close(); err != nil {
+		return ex.New(err)
+	}
+	return ex.New(os.Rename(tempPath, fhs.Path))
+}
+
+// readUnsafe reads every entry in the file.
+// Lines that cannot be parsed (e.g. a partial line from a crash) are skipped.
+func (fhs *FileHistoryStore) readUnsafe() ([]HistoryEntry, error) {
+	file, err := os.Open(fhs.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	defer file.Close()
+
+	var entries []HistoryEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, MaxHistoryEntrySize)
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, ex.New(err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,86 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestFileHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "cron-history")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	store := NewFileHistoryStore(filepath.Join(tempDir, "history.jsonl"))
+
+	entries, err := store.QueryHistory(context.Background(), HistoryQuery{})
+	assert.Nil(err)
+	assert.Empty(entries)
+
+	now := time.Now().UTC()
+	for index := 0; index < 5; index++ {
+		assert.Nil(store.SaveHistory(context.Background(), HistoryEntry{
+			ID:      fmt.Sprint(index),
+			JobName: "foo",
+			Status:  JobStatusComplete,
+			Started: now.Add(time.Duration(index-5) * time.Hour),
+		}))
+	}
+
+	entries, err = store.QueryHistory(context.Background(), HistoryQuery{JobName: "foo", Limit: 3})
+	assert.Nil(err)
+	assert.Len(entries, 3)
+	assert.Equal("2", entries[0].ID)
+
+	assert.Nil(store.Cull(context.Background(), now.Add(-2*time.Hour)))
+	entries, err = store.QueryHistory(context.Background(), HistoryQuery{})
+	assert.Nil(err)
+	assert.Len(entries, 2)
+	assert.Equal("3", entries[0].ID)
+}
+
+func TestJobManagerHistoryStore(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "cron-history")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+	store := NewFileHistoryStore(filepath.Join(tempDir, "history.jsonl"))
+
+	jm := New(OptHistoryStore(store))
+	assert.Nil(jm.LoadJobs(NewJob("foo", func(_ context.Context) error { return fmt.Errorf("this is only a test") })))
+	js, err := jm.Job("foo")
+	assert.Nil(err)
+	js.Run()
+
+	entries, err := store.QueryHistory(context.Background(), HistoryQuery{JobName: "foo"})
+	assert.Nil(err)
+	assert.Len(entries, 1)
+	assert.Equal(JobStatusFailed, entries[0].Status)
+	assert.Equal("this is only a test", entries[0].ErrClass)
+
+	// a new manager restores the history from the store on start.
+	restarted := New(OptHistoryStore(store))
+	assert.Nil(restarted.LoadJobs(NewJob("foo", noop)))
+	assert.Nil(restarted.StartAsync())
+	defer restarted.Stop()
+
+	restored, err := restarted.Job("foo")
+	assert.Nil(err)
+	assert.Len(restored.History, 1)
+	assert.NotNil(restored.Last)
+	assert.Equal(entries[0].ID, restored.Last.ID)
+
+	history, err := restarted.QueryHistory(context.Background(), HistoryQuery{Statuses: []JobStatus{JobStatusFailed}})
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.NotNil(history[0].Err)
+}
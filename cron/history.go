@@ -0,0 +1,177 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// HistoryStore persists job invocation history so it survives restarts.
+//
+// Every finished invocation is saved, and the history for each job is restored
+// from the store when the job manager starts.
+type HistoryStore interface {
+	SaveHistory(context.Context, HistoryEntry) error
+	QueryHistory(context.Context, HistoryQuery) ([]HistoryEntry, error)
+}
+
+// HistoryStateProvider is an optional interface for jobs that set a `State` on their invocations.
+// It returns a new value that persisted state will be deserialized into when history is restored.
+// If a job does not implement it, restored invocations will have the raw json state.
+type HistoryStateProvider interface {
+	HistoryState() interface{}
+}
+
+// NewHistoryEntry returns a new history entry for a job invocation.
+func NewHistoryEntry(ji JobInvocation) (entry HistoryEntry, err error) {
+	entry = HistoryEntry{
+		ID:         ji.ID,
+		JobName:    ji.JobName,
+		Scheduled:  ji.Scheduled,
+		QueueWait:  ji.QueueWait,
+		Parameters: ji.Parameters,
+		Started:    ji.Started,
+		Finished:   ji.Finished,
+		Cancelled:  ji.Cancelled,
+		Timeout:    ji.Timeout,
+		Elapsed:    ji.Elapsed,
+		Status:     ji.Status,
+		Attempt:    ji.Attempt,
+	}
+	if ji.Err != nil {
+		entry.ErrClass = ex.ErrClass(ji.Err)
+		entry.ErrMessage = ex.ErrMessage(ji.Err)
+	}
+	if ji.State != nil {
+		entry.State, err = json.Marshal(ji.State)
+		if err != nil {
+			err = ex.New(err)
+		}
+	}
+	return
+}
+
+// HistoryEntry is the persisted form of a job invocation.
+type HistoryEntry struct {
+	ID         string          `json:"id"`
+	JobName    string          `json:"jobName"`
+	Scheduled  time.Time       `json:"scheduled,omitempty"`
+	QueueWait  time.Duration   `json:"queueWait,omitempty"`
+	Parameters Parameters      `json:"parameters,omitempty"`
+	Started    time.Time       `json:"started"`
+	Finished   time.Time       `json:"finished,omitempty"`
+	Cancelled  time.Time       `json:"cancelled,omitempty"`
+	Timeout    time.Time       `json:"timeout,omitempty"`
+	Elapsed    time.Duration   `json:"elapsed"`
+	Status     JobStatus       `json:"status"`
+	Attempt    int             `json:"attempt,omitempty"`
+	ErrClass   string          `json:"errClass,omitempty"`
+	ErrMessage string          `json:"errMessage,omitempty"`
+	State      json.RawMessage `json:"state,omitempty"`
+}
+
+// Runtime returns the time the invocation ran for; the time it was scheduled for
+// if it was scheduled, otherwise the time it started.
+func (he HistoryEntry) Runtime() time.Time {
+	if !he.Scheduled.IsZero() {
+		return he.Scheduled
+	}
+	return he.Started
+}
+
+// JobInvocation returns the job invocation for the entry.
+// If state is not nil, the persisted state is deserialized into it and set as the invocation state,
+// otherwise the invocation state is set to the raw json state.
+func (he HistoryEntry) JobInvocation(state interface{}) (ji JobInvocation, err error) {
+	ji = JobInvocation{
+		ID:         he.ID,
+		JobName:    he.JobName,
+		Scheduled:  he.Scheduled,
+		QueueWait:  he.QueueWait,
+		Parameters: he.Parameters,
+		Started:    he.Started,
+		Finished:   he.Finished,
+		Cancelled:  he.Cancelled,
+		Timeout:    he.Timeout,
+		Elapsed:    he.Elapsed,
+		Status:     he.Status,
+		Attempt:    he.Attempt,
+	}
+	if he.ErrClass != "" {
+		ji.Err = &ex.Ex{Class: ex.Class(he.ErrClass), Message: he.ErrMessage}
+	}
+	if len(he.State) == 0 {
+		return
+	}
+	if state == nil {
+		ji.State = he.State
+		return
+	}
+	if err = json.Unmarshal(he.State, state); err != nil {
+		err = ex.New(err)
+		return
+	}
+	ji.State = state
+	return
+}
+
+// HistoryQuery is a query for persisted history.
+type HistoryQuery struct {
+	// JobName optionally restricts results to a single job.
+	JobName string
+	// Statuses optionally restricts results to a set of statuses.
+	Statuses []JobStatus
+	// After optionally restricts results to invocations started at or after a time.
+	After time.Time
+	// Before optionally restricts results to invocations started before a time.
+	Before time.Time
+	// Limit optionally restricts results to the most recent invocations.
+	Limit int
+}
+
+// Matches returns if an entry matches the query's filters.
+func (hq HistoryQuery) Matches(entry HistoryEntry) bool {
+	if hq.JobName != "" && entry.JobName != hq.JobName {
+		return false
+	}
+	if len(hq.Statuses) > 0 {
+		var found bool
+		for _, status := range hq.Statuses {
+			if entry.Status == status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !hq.After.IsZero() && entry.Started.Before(hq.After) {
+		return false
+	}
+	if !hq.Before.IsZero() && !entry.Started.Before(hq.Before) {
+		return false
+	}
+	return true
+}
+
+// Apply filters a list of entries, returning the matching entries ordered by start time ascending.
+// If a limit is set, only the most recent matching entries are returned.
+func (hq HistoryQuery) Apply(entries []HistoryEntry) []HistoryEntry {
+	var output []HistoryEntry
+	for _, entry := range entries {
+		if hq.Matches(entry) {
+			output = append(output, entry)
+		}
+	}
+	sort.SliceStable(output, func(i, j int) bool {
+		return output[i].Started.Before(output[j].Started)
+	})
+	if hq.Limit > 0 && len(output) > hq.Limit {
+		output = output[len(output)-hq.Limit:]
+	}
+	return output
+}
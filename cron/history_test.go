@@ -0,0 +1,78 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestHistoryEntryRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	ji := JobInvocation{
+		ID:       NewJobInvocationID(),
+		JobName:  "foo",
+		Started:  time.Date(2019, 01, 02, 03, 04, 05, 00, time.UTC),
+		Finished: time.Date(2019, 01, 02, 03, 04, 06, 00, time.UTC),
+		Elapsed:  time.Second,
+		Status:   JobStatusFailed,
+		Err:      ex.New(ErrJobCancelled, ex.OptMessage("test message")),
+		State:    map[string]string{"output": "hello"},
+	}
+
+	entry, err := NewHistoryEntry(ji)
+	assert.Nil(err)
+	assert.Equal(string(ErrJobCancelled), entry.ErrClass)
+	assert.Equal("test message", entry.ErrMessage)
+
+	restored, err := entry.JobInvocation(new(map[string]string))
+	assert.Nil(err)
+	assert.Equal(ji.ID, restored.ID)
+	assert.Equal(ji.Started, restored.Started)
+	assert.Equal(ji.Elapsed, restored.Elapsed)
+	assert.Equal(JobStatusFailed, restored.Status)
+	assert.True(IsJobCancelled(restored.Err))
+	assert.Equal("test message", ex.ErrMessage(restored.Err))
+	assert.Equal("hello", (*restored.State.(*map[string]string))["output"])
+
+	raw, err := entry.JobInvocation(nil)
+	assert.Nil(err)
+	assert.Equal(`{"output":"hello"}`, fmt.Sprintf("%s", raw.State))
+}
+
+func TestHistoryQueryApply(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Now().UTC()
+	entries := []HistoryEntry{
+		{ID: "4", JobName: "foo", Status: JobStatusComplete, Started: now.Add(-1 * time.Hour)},
+		{ID: "1", JobName: "foo", Status: JobStatusComplete, Started: now.Add(-4 * time.Hour)},
+		{ID: "2", JobName: "bar", Status: JobStatusFailed, Started: now.Add(-3 * time.Hour)},
+		{ID: "3", JobName: "foo", Status: JobStatusFailed, Started: now.Add(-2 * time.Hour)},
+	}
+
+	results := HistoryQuery{}.Apply(entries)
+	assert.Len(results, 4)
+	assert.Equal("1", results[0].ID)
+	assert.Equal("4", results[3].ID)
+
+	results = HistoryQuery{JobName: "foo"}.Apply(entries)
+	assert.Len(results, 3)
+
+	results = HistoryQuery{Statuses: []JobStatus{JobStatusFailed}}.Apply(entries)
+	assert.Len(results, 2)
+	assert.Equal("2", results[0].ID)
+
+	results = HistoryQuery{After: now.Add(-3 * time.Hour), Before: now.Add(-time.Hour)}.Apply(entries)
+	assert.Len(results, 2)
+	assert.Equal("2", results[0].ID)
+	assert.Equal("3", results[1].ID)
+
+	results = HistoryQuery{JobName: "foo", Limit: 2}.Apply(entries)
+	assert.Len(results, 2)
+	assert.Equal("3", results[0].ID)
+	assert.Equal("4", results[1].ID)
+}
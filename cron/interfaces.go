@@ -32,6 +32,48 @@ type TimeoutProvider interface {
 	Timeout() time.Duration
 }
 
+// RetryProvider is an optional interface that allows a job to retry failed invocations.
+type RetryProvider interface {
+	RetryPolicy() RetryPolicy
+}
+
+// MisfireProvider is an optional interface that governs scheduled runs that were missed.
+type MisfireProvider interface {
+	MisfirePolicy() MisfirePolicy
+}
+
+// ConcurrencyGroupProvider is an optional interface that puts a job's invocations in a
+// named group, whose concurrent invocations can be limited with `OptConcurrencyGroup`.
+type ConcurrencyGroupProvider interface {
+	ConcurrencyGroup() string
+}
+
+// PriorityProvider is an optional interface that orders a job's queued invocations
+// ahead of those with a lower priority. The default priority is zero.
+type PriorityProvider interface {
+	Priority() int
+}
+
+// ParametersProvider is an optional interface that declares the parameters a job's
+// invocations can be started with. Parameter values are available to the job with `GetParameters`.
+type ParametersProvider interface {
+	Parameters() []Parameter
+}
+
+// WaitOnCancellationProvider is an optional interface that makes a cancelled invocation wait for the job
+// to return, so the job can record its final state. Jobs that implement it must return promptly once
+// their context is cancelled.
+type WaitOnCancellationProvider interface {
+	WaitOnCancellation() bool
+}
+
+// InvocationStateProvider is an optional interface for jobs that set a `State` on their invocations.
+// The state is set before the invocation is current, so it can be read along with the invocation
+// as soon as it starts, and it is kept across the invocation's retries.
+type InvocationStateProvider interface {
+	InvocationState(*JobInvocation) interface{}
+}
+
 // StatusProvider is an interface that allows a task to report its status.
 type StatusProvider interface {
 	Status() string
@@ -78,6 +120,11 @@ type OnFailureReceiver interface {
 	OnFailure(context.Context)
 }
 
+// OnRetryReceiver is an interface that allows a job to be signaled when a failed attempt will be retried.
+type OnRetryReceiver interface {
+	OnRetry(context.Context)
+}
+
 // OnBrokenReceiver is an interface that allows a job to be signaled when it is a failure that followed
 // a previous success.
 type OnBrokenReceiver interface {
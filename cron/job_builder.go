@@ -10,6 +10,11 @@ var (
 	_ Job                            = (*JobBuilder)(nil)
 	_ ScheduleProvider               = (*JobBuilder)(nil)
 	_ TimeoutProvider                = (*JobBuilder)(nil)
+	_ RetryProvider                  = (*JobBuilder)(nil)
+	_ MisfireProvider                = (*JobBuilder)(nil)
+	_ ConcurrencyGroupProvider       = (*JobBuilder)(nil)
+	_ PriorityProvider               = (*JobBuilder)(nil)
+	_ ParametersProvider             = (*JobBuilder)(nil)
 	_ EnabledProvider                = (*JobBuilder)(nil)
 	_ ShouldWriteOutputProvider      = (*JobBuilder)(nil)
 	_ ShouldTriggerListenersProvider = (*JobBuilder)(nil)
@@ -17,6 +22,7 @@ var (
 	_ OnCancellationReceiver         = (*JobBuilder)(nil)
 	_ OnCompleteReceiver             = (*JobBuilder)(nil)
 	_ OnFailureReceiver              = (*JobBuilder)(nil)
+	_ OnRetryReceiver                = (*JobBuilder)(nil)
 	_ OnBrokenReceiver               = (*JobBuilder)(nil)
 	_ OnFixedReceiver                = (*JobBuilder)(nil)
 	_ OnEnabledReceiver              = (*JobBuilder)(nil)
@@ -48,6 +54,31 @@ func OptJobBuilderTimeout(d time.Duration) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.TimeoutProvider = func() time.Duration { return d } }
 }
 
+// OptJobBuilderRetryPolicy is a job builder sets the job builder retry policy provider.
+func OptJobBuilderRetryPolicy(policy RetryPolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.RetryPolicyProvider = func() RetryPolicy { return policy } }
+}
+
+// OptJobBuilderMisfirePolicy is a job builder sets the job builder misfire policy provider.
+func OptJobBuilderMisfirePolicy(policy MisfirePolicy) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.MisfirePolicyProvider = func() MisfirePolicy { return policy } }
+}
+
+// OptJobBuilderConcurrencyGroup is a job builder sets the job builder concurrency group provider.
+func OptJobBuilderConcurrencyGroup(group string) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.ConcurrencyGroupProvider = func() string { return group } }
+}
+
+// OptJobBuilderPriority is a job builder sets the job builder priority provider.
+func OptJobBuilderPriority(priority int) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.PriorityProvider = func() int { return priority } }
+}
+
+// OptJobBuilderParameters is a job builder sets the job builder parameters provider.
+func OptJobBuilderParameters(parameters ...Parameter) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.ParametersProvider = func() []Parameter { return parameters } }
+}
+
 // OptJobBuilderEnabledProvider is a job builder sets the job builder timeout provder.
 func OptJobBuilderEnabledProvider(provider func() bool) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.EnabledProvider = provider }
@@ -73,6 +104,11 @@ func OptJobBuilderOnFailure(handler func(*JobInvocation)) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.OnFailureHandler = handler }
 }
 
+// OptJobBuilderOnRetry is a job builder option implementation.
+func OptJobBuilderOnRetry(handler func(*JobInvocation)) JobBuilderOption {
+	return func(jb *JobBuilder) { jb.OnRetryHandler = handler }
+}
+
 // OptJobBuilderOnBroken is a job builder option implementation.
 func OptJobBuilderOnBroken(handler func(*JobInvocation)) JobBuilderOption {
 	return func(jb *JobBuilder) { jb.OnBrokenHandler = handler }
@@ -100,6 +136,11 @@ type JobBuilder struct {
 
 	ScheduleProvider               func() Schedule
 	TimeoutProvider                func() time.Duration
+	RetryPolicyProvider            func() RetryPolicy
+	MisfirePolicyProvider          func() MisfirePolicy
+	ConcurrencyGroupProvider       func() string
+	PriorityProvider               func() int
+	ParametersProvider             func() []Parameter
 	EnabledProvider                func() bool
 	ShouldTriggerListenersProvider func() bool
 	ShouldWriteOutputProvider      func() bool
@@ -108,6 +149,7 @@ type JobBuilder struct {
 	OnCancellationHandler func(*JobInvocation)
 	OnCompleteHandler     func(*JobInvocation)
 	OnFailureHandler      func(*JobInvocation)
+	OnRetryHandler        func(*JobInvocation)
 	OnBrokenHandler       func(*JobInvocation)
 	OnFixedHandler        func(*JobInvocation)
 	OnEnabledHandler      func(context.Context)
@@ -139,6 +181,46 @@ func (jb *JobBuilder) Timeout() (timeout time.Duration) {
 	return
 }
 
+// RetryPolicy returns the job retry policy.
+func (jb *JobBuilder) RetryPolicy() (policy RetryPolicy) {
+	if jb.RetryPolicyProvider != nil {
+		return jb.RetryPolicyProvider()
+	}
+	return
+}
+
+// MisfirePolicy returns the job misfire policy.
+func (jb *JobBuilder) MisfirePolicy() (policy MisfirePolicy) {
+	if jb.MisfirePolicyProvider != nil {
+		return jb.MisfirePolicyProvider()
+	}
+	return
+}
+
+// ConcurrencyGroup returns the job concurrency group.
+func (jb *JobBuilder) ConcurrencyGroup() (group string) {
+	if jb.ConcurrencyGroupProvider != nil {
+		return jb.ConcurrencyGroupProvider()
+	}
+	return
+}
+
+// Priority returns the job priority.
+func (jb *JobBuilder) Priority() (priority int) {
+	if jb.PriorityProvider != nil {
+		return jb.PriorityProvider()
+	}
+	return
+}
+
+// Parameters returns the job parameters.
+func (jb *JobBuilder) Parameters() (parameters []Parameter) {
+	if jb.ParametersProvider != nil {
+		return jb.ParametersProvider()
+	}
+	return
+}
+
 // Enabled returns if the job is enabled.
 func (jb *JobBuilder) Enabled() bool {
 	if jb.EnabledProvider != nil {
@@ -191,6 +273,13 @@ func (jb *JobBuilder) OnFailure(ctx context.Context) {
 	}
 }
 
+// OnRetry is a lifecycle hook.
+func (jb *JobBuilder) OnRetry(ctx context.Context) {
+	if jb.OnRetryHandler != nil {
+		jb.OnRetryHandler(GetJobInvocation(ctx))
+	}
+}
+
 // OnFixed is a lifecycle hook.
 func (jb *JobBuilder) OnFixed(ctx context.Context) {
 	if jb.OnFixedHandler != nil {
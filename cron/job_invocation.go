@@ -7,16 +7,32 @@ import (
 
 // JobInvocation is metadata for a job invocation (or instance of a job running).
 type JobInvocation struct {
-	ID        string             `json:"id"`
-	JobName   string             `json:"jobName"`
-	Started   time.Time          `json:"started"`
-	Finished  time.Time          `json:"finished,omitempty"`
-	Cancelled time.Time          `json:"cancelled,omitempty"`
-	Timeout   time.Time          `json:"timeout,omitempty"`
-	Err       error              `json:"err,omitempty"`
-	Elapsed   time.Duration      `json:"elapsed"`
-	Status    JobStatus          `json:"status"`
-	State     interface{}        `json:"state,omitempty"`
-	Context   context.Context    `json:"-"`
-	Cancel    context.CancelFunc `json:"-"`
+	ID        string    `json:"id"`
+	JobName   string    `json:"jobName"`
+	Scheduled time.Time `json:"scheduled,omitempty"`
+	// QueueWait is how long the invocation waited for the concurrency limiter before it started.
+	QueueWait time.Duration `json:"queueWait,omitempty"`
+	// Parameters are the parameter values the invocation was started with.
+	Parameters Parameters         `json:"parameters,omitempty"`
+	Started    time.Time          `json:"started"`
+	Finished   time.Time          `json:"finished,omitempty"`
+	Cancelled  time.Time          `json:"cancelled,omitempty"`
+	Timeout    time.Time          `json:"timeout,omitempty"`
+	Err        error              `json:"err,omitempty"`
+	Elapsed    time.Duration      `json:"elapsed"`
+	Status     JobStatus          `json:"status"`
+	Attempt    int                `json:"attempt"`
+	State      interface{}        `json:"state,omitempty"`
+	Lease      *Lease             `json:"lease,omitempty"`
+	Context    context.Context    `json:"-"`
+	Cancel     context.CancelFunc `json:"-"`
+}
+
+// Runtime returns the time the invocation ran for; the time it was scheduled for
+// if it was scheduled, otherwise the time it started.
+func (ji JobInvocation) Runtime() time.Time {
+	if !ji.Scheduled.IsZero() {
+		return ji.Scheduled
+	}
+	return ji.Started
 }
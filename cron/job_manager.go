@@ -3,6 +3,7 @@ package cron
 // NOTE: ALL TIMES ARE IN UTC. JUST USE UTC.
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -15,8 +16,9 @@ import (
 // New returns a new job manager.
 func New(options ...JobManagerOption) *JobManager {
 	jm := JobManager{
-		Latch: async.NewLatch(),
-		Jobs:  map[string]*JobScheduler{},
+		Latch:     async.NewLatch(),
+		Jobs:      map[string]*JobScheduler{},
+		Workflows: map[string]*Workflow{},
 	}
 	for _, option := range options {
 		option(&jm)
@@ -29,10 +31,16 @@ type JobManager struct {
 	sync.Mutex
 	*async.Latch
 
-	Config Config
-	Tracer Tracer
-	Log    logger.Log
-	Jobs   map[string]*JobScheduler
+	Config       Config
+	Tracer       Tracer
+	Log          logger.Log
+	HistoryStore HistoryStore
+	LeaseManager *LeaseManager
+	// ConcurrencyLimiter optionally limits how many invocations run at once.
+	// Workflow invocations are not limited, but the invocations of their steps are.
+	ConcurrencyLimiter *ConcurrencyLimiter
+	Jobs               map[string]*JobScheduler
+	Workflows          map[string]*Workflow
 }
 
 // --------------------------------------------------------------------------------
@@ -40,6 +48,7 @@ type JobManager struct {
 // --------------------------------------------------------------------------------
 
 // LoadJobs loads a variadic list of jobs.
+// If the job manager has already started, the jobs are started as they are loaded.
 func (jm *JobManager) LoadJobs(jobs ...Job) error {
 	jm.Lock()
 	defer jm.Unlock()
@@ -49,11 +58,110 @@ func (jm *JobManager) LoadJobs(jobs ...Job) error {
 		if _, hasJob := jm.Jobs[jobName]; hasJob {
 			return ex.New(ErrJobAlreadyLoaded, ex.OptMessagef("job: %s", job.Name()))
 		}
-		jm.Jobs[jobName] = NewJobScheduler(job,
-			OptJobSchedulerTracer(jm.Tracer),
-			OptJobSchedulerLog(jm.Log),
-			OptJobSchedulerConfig(jm.Config),
-		)
+		if err := validateJobParameters(job); err != nil {
+			return err
+		}
+		jm.Jobs[jobName] = jm.newJobScheduler(job)
+		if jm.IsStarted() {
+			jm.startJobScheduler(jm.Jobs[jobName])
+		}
+	}
+	return nil
+}
+
+// UpdateJobs replaces loaded jobs with updated versions of them by name, e.g. with new
+// schedules, timeouts or notification settings. The jobs keep their history and whether
+// they are disabled, and running invocations are not interrupted.
+func (jm *JobManager) UpdateJobs(jobs ...Job) error {
+	jm.Lock()
+	defer jm.Unlock()
+
+	for _, job := range jobs {
+		if _, hasJob := jm.Jobs[job.Name()]; !hasJob {
+			return ex.New(ErrJobNotLoaded, ex.OptMessagef("job: %s", job.Name()))
+		}
+		if err := validateJobParameters(job); err != nil {
+			return err
+		}
+	}
+	for _, job := range jobs {
+		js := jm.Jobs[job.Name()]
+		if js.CanStop() {
+			logger.MaybeError(jm.Log, js.Stop())
+		}
+		if err := js.Reload(job); err != nil {
+			return err
+		}
+		js.ConcurrencyLimiter = jm.concurrencyLimiterFor(job)
+		if jm.IsStarted() {
+			go js.Start()
+			<-js.NotifyStarted()
+		}
+	}
+	return nil
+}
+
+// UnloadJobs stops and removes a variadic list of jobs by name.
+// Running invocations are not cancelled, and finish in the background.
+// Jobs used by the steps of a loaded workflow cannot be unloaded.
+func (jm *JobManager) UnloadJobs(jobNames ...string) error {
+	jm.Lock()
+	defer jm.Unlock()
+
+	for _, jobName := range jobNames {
+		if _, hasJob := jm.Jobs[jobName]; !hasJob {
+			return ex.New(ErrJobNotLoaded, ex.OptMessagef("job: %s", jobName))
+		}
+		for _, workflow := range jm.Workflows {
+			if workflow.Name() == jobName || stringsContain(jobNames, workflow.Name()) {
+				continue
+			}
+			for _, step := range workflow.Steps {
+				if step.JobName == jobName {
+					return ex.New(ErrJobInWorkflow, ex.OptMessagef("job: %s, workflow: %s", jobName, workflow.Name()))
+				}
+			}
+		}
+	}
+	for _, jobName := range jobNames {
+		js := jm.Jobs[jobName]
+		if js.CanStop() {
+			logger.MaybeError(jm.Log, js.Stop())
+		}
+		delete(jm.Jobs, jobName)
+		delete(jm.Workflows, jobName)
+	}
+	return nil
+}
+
+// LoadWorkflows loads a variadic list of workflows.
+// The jobs for each workflow's steps must already be loaded, and the dependencies
+// between steps must be acyclic. Each workflow is loaded as a job by its name.
+func (jm *JobManager) LoadWorkflows(workflows ...*Workflow) error {
+	jm.Lock()
+	defer jm.Unlock()
+
+	for _, workflow := range workflows {
+		if err := workflow.Validate(); err != nil {
+			return err
+		}
+		if _, hasJob := jm.Jobs[workflow.Name()]; hasJob {
+			return ex.New(ErrJobAlreadyLoaded, ex.OptMessagef("job: %s", workflow.Name()))
+		}
+		jobs := make(map[string]*JobScheduler)
+		for _, step := range workflow.Steps {
+			job, hasJob := jm.Jobs[step.JobName]
+			if !hasJob {
+				return ex.New(ErrJobNotLoaded, ex.OptMessagef("workflow: %s, job: %s", workflow.Name(), step.JobName))
+			}
+			jobs[step.JobName] = job
+		}
+		workflow.jobs = jobs
+		jm.Jobs[workflow.Name()] = jm.newJobScheduler(workflow)
+		jm.Workflows[workflow.Name()] = workflow
+		if jm.IsStarted() {
+			jm.startJobScheduler(jm.Jobs[workflow.Name()])
+		}
 	}
 	return nil
 }
@@ -128,7 +236,7 @@ func (jm *JobManager) IsJobRunning(jobName string) (isRunning bool) {
 	defer jm.Unlock()
 
 	if job, ok := jm.Jobs[jobName]; ok {
-		isRunning = job.Current != nil
+		isRunning = job.CurrentInvocation() != nil
 	}
 	return
 }
@@ -161,6 +269,25 @@ func (jm *JobManager) RunJob(jobName string) error {
 	return nil
 }
 
+// RunJobWithParameters runs a job by jobName on demand with a given set of parameter values.
+// The values are validated against the job's declared parameters before the job is started,
+// and an `ErrParameterInvalid` error is returned if they are not valid.
+func (jm *JobManager) RunJobWithParameters(jobName string, values map[string]string) error {
+	jm.Lock()
+	defer jm.Unlock()
+
+	job, ok := jm.Jobs[jobName]
+	if !ok {
+		return ex.New(ErrJobNotLoaded, ex.OptMessagef("job: %s", jobName))
+	}
+	parameters, err := job.ValidateParameters(values)
+	if err != nil {
+		return err
+	}
+	go job.RunWithParameters(context.Background(), parameters)
+	return nil
+}
+
 // RunAllJobs runs every job that has been loaded in the JobManager at once.
 func (jm *JobManager) RunAllJobs() {
 	jm.Lock()
@@ -197,14 +324,80 @@ func (jm *JobManager) Status() *Status {
 	for _, job := range jm.Jobs {
 		status.Jobs = append(status.Jobs, job)
 
-		if job.Current != nil {
-			status.Running[job.Name] = append(status.Running[job.Name], job.Current)
+		if current := job.CurrentInvocation(); current != nil {
+			status.Running[job.Name] = append(status.Running[job.Name], current)
 		}
 	}
 	sort.Sort(JobSchedulersByJobNameAsc(status.Jobs))
+	for name, workflow := range jm.Workflows {
+		workflowStatus := WorkflowStatus{
+			Name:  name,
+			Steps: workflow.Steps,
+		}
+		if job, ok := jm.Jobs[name]; ok {
+			workflowStatus.Current = workflowRunOf(job.CurrentInvocation())
+			workflowStatus.Last = workflowRunOf(job.LastInvocation())
+		}
+		status.Workflows = append(status.Workflows, workflowStatus)
+	}
+	sort.Slice(status.Workflows, func(i, j int) bool {
+		return status.Workflows[i].Name < status.Workflows[j].Name
+	})
+	if jm.LeaseManager != nil {
+		status.Leader = jm.LeaseManager.Leadership()
+	}
+	if jm.ConcurrencyLimiter != nil {
+		concurrency := jm.ConcurrencyLimiter.Status()
+		status.Concurrency = &concurrency
+	}
 	return &status
 }
 
+// QueryHistory returns the history for loaded jobs matching a query.
+// If a history store is set, it is queried, otherwise the in-memory history of the jobs is used.
+func (jm *JobManager) QueryHistory(ctx context.Context, query HistoryQuery) ([]JobInvocation, error) {
+	jm.Lock()
+	defer jm.Unlock()
+
+	if jm.HistoryStore == nil {
+		var output []JobInvocation
+		for _, job := range jm.Jobs {
+			for _, ji := range job.InvocationHistory() {
+				if entry, err := NewHistoryEntry(ji); err == nil && query.Matches(entry) {
+					output = append(output, ji)
+				}
+			}
+		}
+		sort.SliceStable(output, func(i, j int) bool {
+			return output[i].Started.Before(output[j].Started)
+		})
+		if query.Limit > 0 && len(output) > query.Limit {
+			output = output[len(output)-query.Limit:]
+		}
+		return output, nil
+	}
+
+	entries, err := jm.HistoryStore.QueryHistory(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]JobInvocation, 0, len(entries))
+	for _, entry := range entries {
+		var state interface{}
+		if job, ok := jm.Jobs[entry.JobName]; ok {
+			if typed, ok := job.Job.(HistoryStateProvider); ok {
+				state = typed.HistoryState()
+			}
+		}
+		ji, err := entry.JobInvocation(state)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, ji)
+	}
+	return output, nil
+}
+
 //
 // Life Cycle
 //
@@ -225,12 +418,16 @@ func (jm *JobManager) StartAsync() error {
 		return fmt.Errorf("already started")
 	}
 	jm.Starting()
+	if jm.LeaseManager != nil {
+		jm.LeaseManager.Log = jm.Log
+		jm.LeaseManager.OnLost = jm.cancelRunningJobs
+		if jm.LeaseManager.Mode == LeaseModeManager {
+			go jm.LeaseManager.Start()
+			<-jm.LeaseManager.NotifyStarted()
+		}
+	}
 	for _, job := range jm.Jobs {
-		job.Log = jm.Log
-		job.Tracer = jm.Tracer
-		job.Config = jm.Config
-		go job.Start()
-		<-job.NotifyStarted()
+		jm.startJobScheduler(job)
 	}
 	jm.Started()
 	return nil
@@ -245,6 +442,92 @@ func (jm *JobManager) Stop() error {
 	for _, job := range jm.Jobs {
 		job.Stop()
 	}
+	if jm.LeaseManager != nil && jm.LeaseManager.Mode == LeaseModeManager {
+		logger.MaybeError(jm.Log, jm.LeaseManager.Stop())
+	}
 	jm.Stopped()
 	return nil
 }
+
+// startJobScheduler applies the manager's options to a job scheduler, restores its history and starts it.
+func (jm *JobManager) startJobScheduler(job *JobScheduler) {
+	job.Log = jm.Log
+	job.Tracer = jm.Tracer
+	job.Config = jm.Config
+	job.HistoryStore = jm.HistoryStore
+	job.LeaseManager = jm.LeaseManager
+	job.ConcurrencyLimiter = jm.concurrencyLimiterFor(job.Job)
+	logger.MaybeError(jm.Log, job.RestoreHistory(context.Background()))
+	go job.Start()
+	<-job.NotifyStarted()
+}
+
+// newJobScheduler returns a scheduler for a job with the manager's options.
+func (jm *JobManager) newJobScheduler(job Job) *JobScheduler {
+	return NewJobScheduler(job,
+		OptJobSchedulerTracer(jm.Tracer),
+		OptJobSchedulerLog(jm.Log),
+		OptJobSchedulerConfig(jm.Config),
+		OptJobSchedulerHistoryStore(jm.HistoryStore),
+		OptJobSchedulerLeaseManager(jm.LeaseManager),
+		OptJobSchedulerConcurrencyLimiter(jm.concurrencyLimiterFor(job)),
+	)
+}
+
+// concurrencyLimiter returns the concurrency limiter, creating it if it is not set.
+func (jm *JobManager) concurrencyLimiter() *ConcurrencyLimiter {
+	if jm.ConcurrencyLimiter == nil {
+		jm.ConcurrencyLimiter = NewConcurrencyLimiter()
+	}
+	return jm.ConcurrencyLimiter
+}
+
+// concurrencyLimiterFor returns the concurrency limiter for a job.
+// Workflows are not limited, as their runs would hold slots their steps need.
+func (jm *JobManager) concurrencyLimiterFor(job Job) *ConcurrencyLimiter {
+	if _, ok := job.(*Workflow); ok {
+		return nil
+	}
+	return jm.ConcurrencyLimiter
+}
+
+// validateJobParameters validates the parameters a job declares, if it declares any.
+func validateJobParameters(job Job) error {
+	if typed, ok := job.(ParametersProvider); ok {
+		if err := ValidateParameterDefinitions(typed.Parameters()); err != nil {
+			return ex.New(ErrParameterInvalid, ex.OptMessagef("job: %s, %s", job.Name(), ex.ErrMessage(err)))
+		}
+	}
+	return nil
+}
+
+// workflowRunOf returns a snapshot of the workflow run of an invocation, if it has one.
+func workflowRunOf(ji *JobInvocation) *WorkflowRun {
+	if ji == nil {
+		return nil
+	}
+	if typed, ok := ji.State.(*WorkflowRun); ok {
+		return typed.Snapshot()
+	}
+	return nil
+}
+
+// cancelRunningJobs cancels every running job; it is called when
+// the replica loses leadership so jobs stop running on more than one replica.
+func (jm *JobManager) cancelRunningJobs() {
+	jm.Lock()
+	defer jm.Unlock()
+	for _, job := range jm.Jobs {
+		job.Cancel()
+	}
+}
+
+// stringsContain returns if a list of strings contains a value.
+func stringsContain(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
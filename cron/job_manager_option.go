@@ -19,3 +19,32 @@ func OptLog(log logger.Log) JobManagerOption {
 func OptTracer(tracer Tracer) JobManagerOption {
 	return func(jm *JobManager) { jm.Tracer = tracer }
 }
+
+// OptHistoryStore sets the job manager history store, which persists
+// job invocation history across restarts.
+func OptHistoryStore(store HistoryStore) JobManagerOption {
+	return func(jm *JobManager) { jm.HistoryStore = store }
+}
+
+// OptConcurrencyLimiter sets the job manager concurrency limiter.
+func OptConcurrencyLimiter(cl *ConcurrencyLimiter) JobManagerOption {
+	return func(jm *JobManager) { jm.ConcurrencyLimiter = cl }
+}
+
+// OptMaxConcurrent limits the number of job invocations that run at once;
+// invocations over the limit wait in a queue until others finish.
+func OptMaxConcurrent(maxConcurrent int) JobManagerOption {
+	return func(jm *JobManager) { jm.concurrencyLimiter().MaxConcurrent = maxConcurrent }
+}
+
+// OptConcurrencyGroup limits the number of invocations that run at once for jobs in a named
+// concurrency group (see `ConcurrencyGroupProvider`).
+func OptConcurrencyGroup(group string, maxConcurrent int) JobManagerOption {
+	return func(jm *JobManager) { jm.concurrencyLimiter().SetGroup(group, maxConcurrent) }
+}
+
+// OptLeaseManager sets the job manager lease manager, which coordinates
+// which replica runs jobs when the job manager runs on more than one replica.
+func OptLeaseManager(lm *LeaseManager) JobManagerOption {
+	return func(jm *JobManager) { jm.LeaseManager = lm }
+}
@@ -459,3 +459,121 @@ func TestJobManagerEnableDisableJob(t *testing.T) {
 	assert.Nil(err)
 	assert.False(j.Disabled)
 }
+
+func TestJobManagerLoadJobsStarted(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.StartAsync())
+	defer jm.Stop()
+
+	assert.Nil(jm.LoadJobs(NewJob("test-started", noop, OptJobBuilderSchedule(Every(time.Hour)))))
+
+	job, err := jm.Job("test-started")
+	assert.Nil(err)
+	assert.True(job.IsStarted())
+	assert.True(job.Log == jm.Log)
+}
+
+func TestJobManagerUpdateJobs(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("test-update", noop, OptJobBuilderTimeout(time.Second))))
+	assert.Nil(jm.StartAsync())
+	defer jm.Stop()
+
+	job, err := jm.Job("test-update")
+	assert.Nil(err)
+	job.Run()
+	assert.Len(job.History, 1)
+	assert.Nil(jm.DisableJobs("test-update"))
+
+	didRun := make(chan struct{})
+	assert.Nil(jm.UpdateJobs(NewJob("test-update", func(_ context.Context) error {
+		close(didRun)
+		return nil
+	}, OptJobBuilderTimeout(time.Minute), OptJobBuilderSchedule(Every(time.Hour)))))
+
+	updated, err := jm.Job("test-update")
+	assert.Nil(err)
+	assert.True(updated == job, "the scheduler should be kept")
+	assert.Equal(time.Minute, updated.TimeoutProvider())
+	assert.NotNil(updated.Schedule)
+	assert.True(updated.Disabled)
+	assert.Len(updated.History, 1)
+	assert.True(updated.IsStarted())
+	// the next runtime is set once the restarted scheduler's loop starts.
+	var nextRuntime time.Time
+	for nextRuntime.IsZero() {
+		time.Sleep(time.Millisecond)
+		updated.Lock()
+		nextRuntime = updated.NextRuntime
+		updated.Unlock()
+	}
+
+	assert.Nil(jm.EnableJobs("test-update"))
+	updated.Run()
+	<-didRun
+
+	assert.True(IsJobNotLoaded(jm.UpdateJobs(NewJob("test-not-loaded", noop))))
+}
+
+func TestJobManagerUpdateJobsRunning(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	completed := make(chan string, 1)
+	assert.Nil(jm.LoadJobs(NewJob("test-running", func(_ context.Context) error {
+		close(started)
+		<-proceed
+		return nil
+	}, OptJobBuilderOnComplete(func(_ *JobInvocation) { completed <- "original" }))))
+	assert.Nil(jm.StartAsync())
+	defer jm.Stop()
+
+	job, err := jm.Job("test-running")
+	assert.Nil(err)
+	finished := make(chan *JobInvocation)
+	go func() { finished <- job.RunContext(context.Background()) }()
+	<-started
+
+	assert.Nil(jm.UpdateJobs(NewJob("test-running", noop,
+		OptJobBuilderTimeout(time.Minute),
+		OptJobBuilderOnComplete(func(_ *JobInvocation) { completed <- "updated" }),
+	)))
+	assert.True(jm.IsJobRunning("test-running"))
+	close(proceed)
+
+	ji := <-finished
+	assert.Equal(JobStatusComplete, ji.Status)
+	assert.Nil(ji.Err)
+	assert.Len(job.InvocationHistory(), 1)
+	// the running invocation finishes with the job it started with.
+	assert.Equal("original", <-completed)
+}
+
+func TestJobManagerUnloadJobs(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("test-0", noop), NewJob("test-1", noop), NewJob("test-2", noop)))
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("test-workflow", OptWorkflowStep("test-1"))))
+	assert.Nil(jm.StartAsync())
+	defer jm.Stop()
+
+	assert.Nil(jm.UnloadJobs("test-0"))
+	assert.False(jm.HasJob("test-0"))
+	assert.True(IsJobNotLoaded(jm.UnloadJobs("test-0")))
+
+	assert.True(IsJobInWorkflow(jm.UnloadJobs("test-1")))
+	assert.True(jm.HasJob("test-1"))
+
+	assert.Nil(jm.UnloadJobs("test-workflow", "test-1"))
+	assert.False(jm.HasJob("test-1"))
+	assert.False(jm.HasJob("test-workflow"))
+	assert.Empty(jm.Workflows)
+	assert.True(jm.HasJob("test-2"))
+}
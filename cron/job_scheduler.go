@@ -16,46 +16,8 @@ func NewJobScheduler(job Job, options ...JobSchedulerOption) *JobScheduler {
 	js := &JobScheduler{
 		Latch: async.NewLatch(),
 		Name:  job.Name(),
-		Job:   job,
-	}
-
-	if typed, ok := job.(DescriptionProvider); ok {
-		js.Description = typed.Description()
-	}
-
-	if typed, ok := job.(ScheduleProvider); ok {
-		js.Schedule = typed.Schedule()
-	}
-
-	if typed, ok := job.(TimeoutProvider); ok {
-		js.TimeoutProvider = typed.Timeout
-	} else {
-		js.TimeoutProvider = func() time.Duration { return 0 }
-	}
-
-	if typed, ok := job.(EnabledProvider); ok {
-		js.EnabledProvider = typed.Enabled
-	} else {
-		js.EnabledProvider = func() bool { return DefaultEnabled }
-	}
-
-	if typed, ok := job.(SerialProvider); ok {
-		js.SerialProvider = typed.Serial
-	} else {
-		js.SerialProvider = func() bool { return DefaultSerial }
-	}
-
-	if typed, ok := job.(ShouldTriggerListenersProvider); ok {
-		js.ShouldTriggerListenersProvider = typed.ShouldTriggerListeners
-	} else {
-		js.ShouldTriggerListenersProvider = func() bool { return DefaultShouldTriggerListeners }
-	}
-
-	if typed, ok := job.(ShouldWriteOutputProvider); ok {
-		js.ShouldWriteOutputProvider = typed.ShouldWriteOutput
-	} else {
-		js.ShouldWriteOutputProvider = func() bool { return DefaultShouldWriteOutput }
 	}
+	js.loadJob(job)
 
 	for _, option := range options {
 		option(js)
@@ -72,10 +34,16 @@ type JobScheduler struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Job         Job    `json:"-"`
+	// Parameters are the parameters invocations of the job can be started with.
+	Parameters []Parameter `json:"parameters,omitempty"`
 
-	Config Config     `json:"-"`
-	Tracer Tracer     `json:"-"`
-	Log    logger.Log `json:"-"`
+	Config       Config        `json:"-"`
+	Tracer       Tracer        `json:"-"`
+	Log          logger.Log    `json:"-"`
+	HistoryStore HistoryStore  `json:"-"`
+	LeaseManager *LeaseManager `json:"-"`
+	// ConcurrencyLimiter optionally limits how many invocations run at once across jobs.
+	ConcurrencyLimiter *ConcurrencyLimiter `json:"-"`
 
 	// Meta Fields
 	Disabled    bool            `json:"disabled"`
@@ -88,8 +56,20 @@ type JobScheduler struct {
 	EnabledProvider                func() bool          `json:"-"`
 	SerialProvider                 func() bool          `json:"-"`
 	TimeoutProvider                func() time.Duration `json:"-"`
+	RetryPolicyProvider            func() RetryPolicy   `json:"-"`
+	MisfirePolicyProvider          func() MisfirePolicy `json:"-"`
+	ConcurrencyGroupProvider       func() string        `json:"-"`
+	PriorityProvider               func() int           `json:"-"`
 	ShouldTriggerListenersProvider func() bool          `json:"-"`
 	ShouldWriteOutputProvider      func() bool          `json:"-"`
+	WaitOnCancellationProvider     func() bool          `json:"-"`
+
+	// queueCtx is cancelled to drop the invocations waiting in the concurrency limiter,
+	// when the scheduler is stopped or the job is cancelled.
+	queueCtx    context.Context
+	queueCancel context.CancelFunc
+	// pending is the number of invocations waiting in the concurrency limiter, or running after waiting in it.
+	pending int
 }
 
 // Start starts the scheduler.
@@ -108,6 +88,9 @@ func (js *JobScheduler) Stop() error {
 	if !js.Latch.CanStop() {
 		return fmt.Errorf("already stopped")
 	}
+	js.Lock()
+	js.cancelQueuedUnsafe()
+	js.Unlock()
 	js.Latch.Stopping()
 	<-js.Latch.NotifyStopped()
 	return nil
@@ -153,10 +136,14 @@ func (js *JobScheduler) Disable() {
 	}
 }
 
-// Cancel stops an execution in process.
+// Cancel stops an execution in process, and drops the invocations waiting in the concurrency limiter.
 func (js *JobScheduler) Cancel() {
-	if js.Current != nil {
-		js.Current.Cancel()
+	js.Lock()
+	current := js.Current
+	js.cancelQueuedUnsafe()
+	js.Unlock()
+	if current != nil {
+		current.Cancel()
 	}
 }
 
@@ -169,27 +156,49 @@ func (js *JobScheduler) RunLoop() {
 		js.Stopped()
 	}()
 
-	if js.Schedule != nil {
-		js.NextRuntime = js.Schedule.Next(js.NextRuntime)
-	}
-	if js.NextRuntime.IsZero() {
-		return
+	// the latch replaces its notification channels once they close, so
+	// hold on to the stopping channel rather than fetching it every loop.
+	stopping := js.NotifyStopping()
+
+	// the next runtime is read while the loop runs, so the loop works on a local copy of it.
+	js.Lock()
+	schedule, next := js.Schedule, js.NextRuntime
+	js.Unlock()
+
+	if schedule != nil {
+		// handle runs missed since the last run, e.g. while the process was down.
+		js.handleMisfires(js.lastRuntime(), Now(), stopping)
+		next = schedule.Next(next)
+		js.setNextRuntime(next)
 	}
 
 	for {
-		if js.NextRuntime.IsZero() {
+		if next.IsZero() {
 			return
 		}
-		runAt := time.After(js.NextRuntime.UTC().Sub(Now()))
+		runAt := time.After(next.UTC().Sub(Now()))
 		select {
 		case <-runAt:
-			if js.enabled() {
-				// start the job
-				go js.Run()
+			scheduled := next
+			if js.enabled() && js.isLeader() {
+				if now := Now(); js.MisfirePolicyProvider().IsLate(scheduled, now) {
+					js.onMisfireSkipped(scheduled, 1)
+				} else {
+					// start the job
+					go js.run(context.Background(), scheduled, nil, true)
+				}
 			}
 			// set up the next runtime.
-			js.NextRuntime = js.Schedule.Next(js.NextRuntime)
-		case <-js.NotifyStopping():
+			next = schedule.Next(scheduled)
+
+			// if the next runtime has already passed (e.g. the process was paused),
+			// handle the runs in between as misfires rather than firing them all at once.
+			if now := Now(); js.MisfirePolicyProvider().Mode != "" && !next.IsZero() && !next.After(now) {
+				js.handleMisfires(scheduled, now, stopping)
+				next = schedule.Next(now)
+			}
+			js.setNextRuntime(next)
+		case <-stopping:
 			return
 		}
 	}
@@ -199,35 +208,134 @@ func (js *JobScheduler) RunLoop() {
 // It checks if the job should be allowed to execute.
 // It blocks on the job execution to enforce or clear timeouts.
 func (js *JobScheduler) Run() {
+	js.RunContext(context.Background())
+}
+
+// RunContext forces the job to run with a parent context, so that cancelling the
+// parent cancels the invocation. It blocks on the job execution and returns the
+// finished invocation, or nil if the job was not allowed to execute.
+func (js *JobScheduler) RunContext(parent context.Context) *JobInvocation {
+	return js.run(parent, time.Time{}, nil, false)
+}
+
+// ValidateParameters validates parameter values for the job, returning the typed values.
+func (js *JobScheduler) ValidateParameters(values map[string]string) (Parameters, error) {
+	js.Lock()
+	parameters := js.Parameters
+	js.Unlock()
+	return ValidateParameters(parameters, values)
+}
+
+// RunWithParameters runs the job with a given set of parameter values, which should
+// have been returned by `ValidateParameters`. It blocks until the invocation finishes.
+func (js *JobScheduler) RunWithParameters(parent context.Context, parameters Parameters) *JobInvocation {
+	return js.run(parent, time.Time{}, parameters, false)
+}
+
+// run runs the job for a time it was scheduled for, which is zero for on demand runs.
+// Ticks are the runs the scheduler starts on schedule; they are not queued in the concurrency limiter behind
+// another invocation of the job, and are skipped if they are late by the time they can run.
+func (js *JobScheduler) run(parent context.Context, scheduled time.Time, parameters Parameters, tick bool) (output *JobInvocation) {
 	// check if the job can run
 	if !js.enabled() {
 		return
 	}
 
+	// the invocation uses the job as it is now throughout, even if the job is reloaded while it runs.
+	job := js.definition()
+
+	// invocations that are not given parameters (e.g. scheduled runs) use the defaults.
+	if parameters == nil {
+		var err error
+		if parameters, err = ValidateParameters(job.Parameters, nil); err != nil {
+			logger.MaybeError(js.Log, err)
+			return
+		}
+	}
+
+	// wait until the concurrency limiter lets the invocation run, if there is one.
+	var queueWait time.Duration
+	if js.ConcurrencyLimiter != nil {
+		queueCtx, ok := js.enqueue(tick)
+		if !ok {
+			return
+		}
+		defer js.dequeue()
+
+		// the wait ends if the parent is done, or the queued invocations are cancelled.
+		waitCtx, cancelWait := context.WithCancel(parent)
+		go func() {
+			select {
+			case <-queueCtx.Done():
+				cancelWait()
+			case <-waitCtx.Done():
+			}
+		}()
+		release, waited, err := js.ConcurrencyLimiter.Acquire(waitCtx, job.ConcurrencyGroupProvider(), job.PriorityProvider())
+		cancelWait()
+		if err != nil {
+			return
+		}
+		defer release()
+		queueWait = waited
+
+		// check again, as the job may have been disabled (or started, if it is serial) while it was queued.
+		if !js.enabled() {
+			return
+		}
+		if tick && js.MisfirePolicyProvider().IsLate(scheduled, Now()) {
+			js.onMisfireSkipped(scheduled, 1)
+			return
+		}
+	}
+
+	// acquire the lease for the invocation if required
+	lease, err := js.acquireLease()
+	if err != nil {
+		if !IsLeaseHeld(err) {
+			logger.MaybeError(js.Log, err)
+		}
+		return
+	}
+
 	// mark the start time
 	start := Now()
 
-	timeout := js.TimeoutProvider()
+	timeout := job.TimeoutProvider()
 
 	// create the root context.
-	ctx, cancel := js.createContextWithTimeout(timeout)
+	ctx, cancel := js.createContextWithTimeout(parent, timeout)
 
 	// create a job invocation, or a record of each
 	// individual execution of a job.
 	ji := JobInvocation{
-		ID:      NewJobInvocationID(),
-		JobName: js.Name,
-		Status:  JobStatusRunning,
-		Started: start,
-		Context: ctx,
-		Cancel:  cancel,
+		ID:         NewJobInvocationID(),
+		JobName:    js.Name,
+		Status:     JobStatusRunning,
+		Scheduled:  scheduled,
+		QueueWait:  queueWait,
+		Parameters: parameters,
+		Started:    start,
+		Context:    ctx,
+		Cancel:     cancel,
+		Lease:      lease,
 	}
 	if timeout > 0 {
 		ji.Timeout = start.Add(timeout)
 	}
+	if typed, ok := job.Job.(InvocationStateProvider); ok {
+		ji.State = typed.InvocationState(&ji)
+	}
 	js.setCurrent(&ji)
 
-	var err error
+	// renew the invocation lease while the job runs, cancelling the job if it is lost.
+	if lease != nil && js.LeaseManager.Mode == LeaseModeInvocation {
+		go js.LeaseManager.RenewInvocation(ctx, *lease, func(leaseErr error) {
+			logger.MaybeError(js.Log, leaseErr)
+			cancel()
+		})
+	}
+
 	var tf TraceFinisher
 	// load the job invocation into the context
 	ctx = WithJobInvocation(ctx, &ji)
@@ -246,22 +354,34 @@ func (js *JobScheduler) Run() {
 			tf.Finish(ctx)
 		}
 
+		// the invocation is finished under the lock, as it can be read while it is current.
+		js.Lock()
 		ji.Finished = Now()
 		ji.Elapsed = ji.Finished.Sub(ji.Started)
 		ji.Err = err
-
 		if err != nil && IsJobCancelled(err) {
 			ji.Cancelled = ji.Finished
-			js.onCancelled(ctx, &ji)
+			ji.Status = JobStatusCancelled
 		} else if ji.Err != nil {
-			js.onFailure(ctx, &ji)
+			ji.Status = JobStatusFailed
 		} else {
-			js.onComplete(ctx, &ji)
+			ji.Status = JobStatusComplete
+		}
+		js.Unlock()
+
+		switch ji.Status {
+		case JobStatusCancelled:
+			js.onCancelled(ctx, job, &ji)
+		case JobStatusFailed:
+			js.onFailure(ctx, job, &ji)
+		default:
+			js.onComplete(ctx, job, &ji)
 		}
 
 		js.addHistory(ji)
 		js.setCurrent(nil)
 		js.setLast(&ji)
+		output = &ji
 	}()
 
 	// if the tracer is set, create a trace context
@@ -269,23 +389,26 @@ func (js *JobScheduler) Run() {
 		ctx, tf = js.Tracer.Start(ctx)
 	}
 	// fire the on start event
-	js.onStart(ctx, &ji)
+	js.onStart(ctx, job, &ji)
 
-	// check if the job has been canceled
-	// or if it's finished.
-	select {
-	case <-ctx.Done():
-		err = ErrJobCancelled
-	case err = <-js.safeAsyncExec(ctx):
-	}
+	// run the job, retrying failed attempts per the retry policy.
+	err = js.execute(ctx, job, &ji)
+	return
 }
 
 //
 // exported utility methods
 //
 
-// GetInvocationByID returns an invocation by id.
+// GetInvocationByID returns a copy of an invocation by id, including the current invocation if it is running.
 func (js *JobScheduler) GetInvocationByID(id string) *JobInvocation {
+	js.Lock()
+	defer js.Unlock()
+
+	if current := js.Current; current != nil && current.ID == id {
+		copied := *current
+		return &copied
+	}
 	for _, ji := range js.History {
 		if ji.ID == id {
 			return &ji
@@ -294,20 +417,382 @@ func (js *JobScheduler) GetInvocationByID(id string) *JobInvocation {
 	return nil
 }
 
+// CurrentInvocation returns a copy of the running invocation, or nil if the job is not running.
+func (js *JobScheduler) CurrentInvocation() *JobInvocation {
+	js.Lock()
+	defer js.Unlock()
+	return copyInvocation(js.Current)
+}
+
+// LastInvocation returns a copy of the last finished invocation, or nil if the job has not run.
+func (js *JobScheduler) LastInvocation() *JobInvocation {
+	js.Lock()
+	defer js.Unlock()
+	return copyInvocation(js.Last)
+}
+
+// InvocationHistory returns a copy of the job's history, oldest first.
+func (js *JobScheduler) InvocationHistory() []JobInvocation {
+	js.Lock()
+	defer js.Unlock()
+	return append([]JobInvocation(nil), js.History...)
+}
+
+// Reload replaces the job the scheduler runs with an updated version of it, e.g. one
+// with a new schedule or timeout, keeping the scheduler's history and whether it is disabled.
+// The scheduler should be stopped while it is reloaded, and started again after;
+// a running invocation is not interrupted.
+func (js *JobScheduler) Reload(job Job) error {
+	if job.Name() != js.Name {
+		return ex.New("cannot reload a job with a different name", ex.OptMessagef("job: %s, reloaded as: %s", js.Name, job.Name()))
+	}
+	js.Lock()
+	defer js.Unlock()
+	js.loadJob(job)
+	// the next runtime is computed from the new schedule when the scheduler starts.
+	js.NextRuntime = time.Time{}
+	return nil
+}
+
+// RestoreHistory loads the job's history from the history store, if one is set,
+// bounded by the history max count and max age.
+func (js *JobScheduler) RestoreHistory(ctx context.Context) error {
+	if js.HistoryStore == nil {
+		return nil
+	}
+	query := HistoryQuery{
+		JobName: js.Name,
+		Limit:   js.Config.HistoryMaxCountOrDefault(),
+	}
+	if maxAge := js.Config.HistoryMaxAgeOrDefault(); maxAge > 0 {
+		query.After = Now().Add(-maxAge)
+	}
+	entries, err := js.HistoryStore.QueryHistory(ctx, query)
+	if err != nil {
+		return err
+	}
+	history, err := js.historyInvocations(entries)
+	if err != nil {
+		return err
+	}
+
+	js.Lock()
+	defer js.Unlock()
+	js.History = history
+	if len(history) > 0 {
+		js.Last = &history[len(history)-1]
+	}
+	return nil
+}
+
 //
 // utility functions
 //
 
+// jobDefinition is the job a scheduler runs and the providers it implements, as of when an
+// invocation starts, so reloading the job doesn't change the invocations that are already running.
+type jobDefinition struct {
+	Job                            Job
+	Parameters                     []Parameter
+	TimeoutProvider                func() time.Duration
+	RetryPolicyProvider            func() RetryPolicy
+	ConcurrencyGroupProvider       func() string
+	PriorityProvider               func() int
+	ShouldTriggerListenersProvider func() bool
+	ShouldWriteOutputProvider      func() bool
+	WaitOnCancellationProvider     func() bool
+}
+
+// definition returns the job and the providers it implements.
+func (js *JobScheduler) definition() jobDefinition {
+	js.Lock()
+	defer js.Unlock()
+	return jobDefinition{
+		Job:                            js.Job,
+		Parameters:                     js.Parameters,
+		TimeoutProvider:                js.TimeoutProvider,
+		RetryPolicyProvider:            js.RetryPolicyProvider,
+		ConcurrencyGroupProvider:       js.ConcurrencyGroupProvider,
+		PriorityProvider:               js.PriorityProvider,
+		ShouldTriggerListenersProvider: js.ShouldTriggerListenersProvider,
+		ShouldWriteOutputProvider:      js.ShouldWriteOutputProvider,
+		WaitOnCancellationProvider:     js.WaitOnCancellationProvider,
+	}
+}
+
+// loadJob sets the job and the providers it implements.
+func (js *JobScheduler) loadJob(job Job) {
+	js.Job = job
+	js.Description, js.Schedule, js.Parameters = "", nil, nil
+
+	if typed, ok := job.(DescriptionProvider); ok {
+		js.Description = typed.Description()
+	}
+
+	if typed, ok := job.(ScheduleProvider); ok {
+		js.Schedule = typed.Schedule()
+	}
+
+	if typed, ok := job.(TimeoutProvider); ok {
+		js.TimeoutProvider = typed.Timeout
+	} else {
+		js.TimeoutProvider = func() time.Duration { return 0 }
+	}
+
+	if typed, ok := job.(RetryProvider); ok {
+		js.RetryPolicyProvider = typed.RetryPolicy
+	} else {
+		js.RetryPolicyProvider = func() RetryPolicy { return RetryPolicy{} }
+	}
+
+	if typed, ok := job.(MisfireProvider); ok {
+		js.MisfirePolicyProvider = typed.MisfirePolicy
+	} else {
+		js.MisfirePolicyProvider = func() MisfirePolicy { return MisfirePolicy{} }
+	}
+
+	if typed, ok := job.(ConcurrencyGroupProvider); ok {
+		js.ConcurrencyGroupProvider = typed.ConcurrencyGroup
+	} else {
+		js.ConcurrencyGroupProvider = func() string { return "" }
+	}
+
+	if typed, ok := job.(PriorityProvider); ok {
+		js.PriorityProvider = typed.Priority
+	} else {
+		js.PriorityProvider = func() int { return 0 }
+	}
+
+	if typed, ok := job.(ParametersProvider); ok {
+		js.Parameters = typed.Parameters()
+	}
+
+	if typed, ok := job.(EnabledProvider); ok {
+		js.EnabledProvider = typed.Enabled
+	} else {
+		js.EnabledProvider = func() bool { return DefaultEnabled }
+	}
+
+	if typed, ok := job.(SerialProvider); ok {
+		js.SerialProvider = typed.Serial
+	} else {
+		js.SerialProvider = func() bool { return DefaultSerial }
+	}
+
+	if typed, ok := job.(ShouldTriggerListenersProvider); ok {
+		js.ShouldTriggerListenersProvider = typed.ShouldTriggerListeners
+	} else {
+		js.ShouldTriggerListenersProvider = func() bool { return DefaultShouldTriggerListeners }
+	}
+
+	if typed, ok := job.(ShouldWriteOutputProvider); ok {
+		js.ShouldWriteOutputProvider = typed.ShouldWriteOutput
+	} else {
+		js.ShouldWriteOutputProvider = func() bool { return DefaultShouldWriteOutput }
+	}
+
+	if typed, ok := job.(WaitOnCancellationProvider); ok {
+		js.WaitOnCancellationProvider = typed.WaitOnCancellation
+	} else {
+		js.WaitOnCancellationProvider = func() bool { return false }
+	}
+}
+
+// historyInvocations returns the job invocations for a list of history entries.
+func (js *JobScheduler) historyInvocations(entries []HistoryEntry) ([]JobInvocation, error) {
+	output := make([]JobInvocation, 0, len(entries))
+	for _, entry := range entries {
+		var state interface{}
+		if typed, ok := js.Job.(HistoryStateProvider); ok {
+			state = typed.HistoryState()
+		}
+		ji, err := entry.JobInvocation(state)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, ji)
+	}
+	return output, nil
+}
+
+// lastRuntime returns the time the job last ran for, from the history store if one is set, or zero if it never ran.
+// Scheduled invocations ran for the time they were scheduled for, and on demand invocations for the time they started.
+func (js *JobScheduler) lastRuntime() time.Time {
+	if js.HistoryStore != nil {
+		entries, err := js.HistoryStore.QueryHistory(context.Background(), HistoryQuery{JobName: js.Name, Limit: 1})
+		if err == nil && len(entries) > 0 {
+			return entries[0].Runtime()
+		}
+		logger.MaybeError(js.Log, err)
+	}
+	js.Lock()
+	defer js.Unlock()
+	if js.Last != nil {
+		return js.Last.Runtime()
+	}
+	return time.Time{}
+}
+
+// handleMisfires finds the runs missed after a last run and at or before now, and
+// skips or runs them according to the misfire policy.
+// Missed runs are run one at a time in the background until the scheduler stops.
+func (js *JobScheduler) handleMisfires(last, now time.Time, stopping <-chan struct{}) {
+	policy := js.MisfirePolicyProvider()
+	if policy.Mode == "" || !js.enabled() || !js.isLeader() {
+		return
+	}
+
+	var limit int
+	switch policy.Mode {
+	case MisfireModeRunOnce:
+		limit = 1
+	case MisfireModeRunAll:
+		limit = policy.MaxRunsOrDefault()
+	}
+	missed, total := MissedRuns(js.Schedule, last, now, limit)
+	if total == 0 {
+		return
+	}
+	runs, skipped := policy.Runs(missed, total, now)
+	if skipped > 0 {
+		js.onMisfireSkipped(time.Time{}, skipped)
+	}
+	if len(runs) == 0 {
+		return
+	}
+	go func() {
+		for _, scheduled := range runs {
+			select {
+			case <-stopping:
+				return
+			default:
+			}
+			js.onMisfireRun(scheduled)
+			js.run(context.Background(), scheduled, nil, false)
+		}
+	}()
+}
+
+// isLeader returns if the replica should run scheduled invocations.
+func (js *JobScheduler) isLeader() bool {
+	if js.LeaseManager == nil {
+		return true
+	}
+	return js.LeaseManager.IsLeader()
+}
+
+// acquireLease returns the lease an invocation runs under, if there is a lease manager.
+// In invocation mode, it acquires the lease for the job and returns `ErrLeaseHeld` if
+// another replica holds it. In manager mode, it returns the manager lease, or `ErrLeaseHeld`
+// if the replica isn't the leader.
+func (js *JobScheduler) acquireLease() (*Lease, error) {
+	if js.LeaseManager == nil {
+		return nil, nil
+	}
+	if js.LeaseManager.Mode == LeaseModeInvocation {
+		return js.LeaseManager.AcquireInvocation(context.Background(), js.Name)
+	}
+	lease := js.LeaseManager.Leadership()
+	if lease == nil {
+		return nil, ex.New(ErrLeaseHeld, ex.OptMessagef("lease: %s, not the leader", js.LeaseManager.Name))
+	}
+	return lease, nil
+}
+
+func (js *JobScheduler) setNextRuntime(next time.Time) {
+	js.Lock()
+	defer js.Unlock()
+	js.NextRuntime = next
+}
+
+// enqueue counts an invocation waiting in the concurrency limiter, and returns the context that cancels the wait.
+// Ticks are not queued while another invocation of the job is queued or running, so the queue doesn't grow
+// with every tick while the limiter is full.
+func (js *JobScheduler) enqueue(tick bool) (context.Context, bool) {
+	js.Lock()
+	defer js.Unlock()
+	if tick && (js.pending > 0 || js.Current != nil) {
+		return nil, false
+	}
+	js.pending++
+	if js.queueCtx == nil {
+		js.queueCtx, js.queueCancel = context.WithCancel(context.Background())
+	}
+	return js.queueCtx, true
+}
+
+// dequeue counts an invocation that waited in the concurrency limiter as finished.
+func (js *JobScheduler) dequeue() {
+	js.Lock()
+	js.pending--
+	js.Unlock()
+}
+
+// cancelQueuedUnsafe cancels the invocations waiting in the concurrency limiter;
+// invocations queued after it wait on a new context.
+func (js *JobScheduler) cancelQueuedUnsafe() {
+	if js.queueCancel != nil {
+		js.queueCancel()
+	}
+	js.queueCtx, js.queueCancel = nil, nil
+}
+
 func (js *JobScheduler) setCurrent(ji *JobInvocation) {
+	js.Lock()
+	defer js.Unlock()
 	js.Current = ji
 }
 
 func (js *JobScheduler) setLast(ji *JobInvocation) {
+	js.Lock()
+	defer js.Unlock()
 	js.Last = ji
 }
 
+func copyInvocation(ji *JobInvocation) *JobInvocation {
+	if ji == nil {
+		return nil
+	}
+	copied := *ji
+	return &copied
+}
+
+// execute runs attempts of an invocation until one succeeds, the invocation is
+// cancelled, or the retry policy gives up.
+func (js *JobScheduler) execute(ctx context.Context, job jobDefinition, ji *JobInvocation) (err error) {
+	policy := job.RetryPolicyProvider()
+	for {
+		js.Lock()
+		ji.Attempt++
+		js.Unlock()
+
+		// check if the job has been canceled
+		// or if it's finished.
+		errors := js.safeAsyncExec(ctx, job.Job)
+		select {
+		case <-ctx.Done():
+			if job.WaitOnCancellationProvider() {
+				<-errors
+			}
+			return ErrJobCancelled
+		case err = <-errors:
+		}
+
+		if ctx.Err() != nil || !policy.ShouldRetry(ji.Attempt, err) {
+			return
+		}
+		js.onRetry(ctx, job, ji, err)
+
+		select {
+		case <-ctx.Done():
+			return ErrJobCancelled
+		case <-time.After(policy.Delay(ji.Attempt)):
+		}
+	}
+}
+
 // safeAsyncExec runs a given job's body and recovers panics.
-func (js *JobScheduler) safeAsyncExec(ctx context.Context) chan error {
+func (js *JobScheduler) safeAsyncExec(ctx context.Context, job Job) chan error {
 	errors := make(chan error)
 	go func() {
 		defer func() {
@@ -315,111 +800,147 @@ func (js *JobScheduler) safeAsyncExec(ctx context.Context) chan error {
 				errors <- ex.New(r)
 			}
 		}()
-		errors <- js.Job.Execute(ctx)
+		errors <- job.Execute(ctx)
 	}()
 	return errors
 }
 
-func (js *JobScheduler) createContextWithTimeout(timeout time.Duration) (context.Context, context.CancelFunc) {
+func (js *JobScheduler) createContextWithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	if timeout > 0 {
-		return context.WithTimeout(context.Background(), timeout)
+		return context.WithTimeout(parent, timeout)
 	}
-	return context.WithCancel(context.Background())
+	return context.WithCancel(parent)
 }
 
 // enabled returns if a job can execute.
 func (js *JobScheduler) enabled() bool {
-	if js.Disabled {
+	js.Lock()
+	disabled, enabledProvider, serialProvider, current := js.Disabled, js.EnabledProvider, js.SerialProvider, js.Current
+	js.Unlock()
+
+	if disabled {
 		return false
 	}
 
-	if js.EnabledProvider != nil {
-		if !js.EnabledProvider() {
+	if enabledProvider != nil {
+		if !enabledProvider() {
 			return false
 		}
 	}
 
-	if js.SerialProvider != nil && js.SerialProvider() {
-		if js.Current != nil {
+	if serialProvider != nil && serialProvider() {
+		if current != nil {
 			return false
 		}
 	}
 	return true
 }
 
-func (js *JobScheduler) onStart(ctx context.Context, ji *JobInvocation) {
-	if js.Log != nil && js.ShouldTriggerListenersProvider() {
-		event := NewEvent(FlagStarted, ji.JobName, OptEventJobInvocation(ji.ID), OptEventWritable(js.ShouldWriteOutputProvider()))
+func (js *JobScheduler) onStart(ctx context.Context, job jobDefinition, ji *JobInvocation) {
+	if js.Log != nil && job.ShouldTriggerListenersProvider() {
+		event := NewEvent(FlagStarted, ji.JobName, OptEventJobInvocation(ji.ID), OptEventWritable(job.ShouldWriteOutputProvider()))
 		js.Log.Trigger(ctx, event)
 	}
-	if typed, ok := js.Job.(OnStartReceiver); ok {
+	if typed, ok := job.Job.(OnStartReceiver); ok {
 		typed.OnStart(ctx)
 	}
 }
 
-func (js *JobScheduler) onCancelled(ctx context.Context, ji *JobInvocation) {
-	ji.Status = JobStatusCancelled
+func (js *JobScheduler) onMisfireSkipped(scheduled time.Time, missed int) {
+	if js.Log != nil && js.ShouldTriggerListenersProvider() {
+		event := NewEvent(FlagMisfireSkipped, js.Name, OptEventScheduled(scheduled), OptEventMissed(missed), OptEventWritable(js.ShouldWriteOutputProvider()))
+		js.Log.Trigger(context.Background(), event)
+	}
+}
 
+func (js *JobScheduler) onMisfireRun(scheduled time.Time) {
 	if js.Log != nil && js.ShouldTriggerListenersProvider() {
-		event := NewEvent(FlagCancelled, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
+		event := NewEvent(FlagMisfireRun, js.Name, OptEventScheduled(scheduled), OptEventWritable(js.ShouldWriteOutputProvider()))
+		js.Log.Trigger(context.Background(), event)
+	}
+}
+
+func (js *JobScheduler) onRetry(ctx context.Context, job jobDefinition, ji *JobInvocation, err error) {
+	if js.Log != nil && job.ShouldTriggerListenersProvider() {
+		event := NewEvent(FlagRetry, ji.JobName, OptEventErr(err), OptEventJobInvocation(ji.ID), OptEventAttempt(ji.Attempt), OptEventWritable(job.ShouldWriteOutputProvider()))
 		js.Log.Trigger(ctx, event)
 	}
-	if typed, ok := js.Job.(OnCancellationReceiver); ok {
-		typed.OnCancellation(ctx)
+	if typed, ok := job.Job.(OnRetryReceiver); ok {
+		typed.OnRetry(ctx)
 	}
 }
 
-func (js *JobScheduler) onComplete(ctx context.Context, ji *JobInvocation) {
-	ji.Status = JobStatusComplete
+func (js *JobScheduler) onCancelled(ctx context.Context, job jobDefinition, ji *JobInvocation) {
+	if js.Log != nil && job.ShouldTriggerListenersProvider() {
+		event := NewEvent(FlagCancelled, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventAttempt(ji.Attempt), OptEventWritable(job.ShouldWriteOutputProvider()))
+		js.Log.Trigger(ctx, event)
+	}
+	if typed, ok := job.Job.(OnCancellationReceiver); ok {
+		typed.OnCancellation(ctx)
+	}
+}
 
-	if js.Log != nil && js.ShouldTriggerListenersProvider() {
-		event := NewEvent(FlagComplete, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
+func (js *JobScheduler) onComplete(ctx context.Context, job jobDefinition, ji *JobInvocation) {
+	if js.Log != nil && job.ShouldTriggerListenersProvider() {
+		event := NewEvent(FlagComplete, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventAttempt(ji.Attempt), OptEventWritable(job.ShouldWriteOutputProvider()))
 		js.Log.Trigger(ctx, event)
 	}
-	if typed, ok := js.Job.(OnCompleteReceiver); ok {
+	if typed, ok := job.Job.(OnCompleteReceiver); ok {
 		typed.OnComplete(ctx)
 	}
 
-	if js.Last != nil && js.Last.Err != nil {
+	if last := js.LastInvocation(); last != nil && last.Err != nil {
 		if js.Log != nil {
-			event := NewEvent(FlagFixed, ji.JobName, OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
+			event := NewEvent(FlagFixed, ji.JobName, OptEventElapsed(ji.Elapsed), OptEventWritable(job.ShouldWriteOutputProvider()))
 			js.Log.Trigger(ctx, event)
 		}
 
-		if typed, ok := js.Job.(OnFixedReceiver); ok {
+		if typed, ok := job.Job.(OnFixedReceiver); ok {
 			typed.OnFixed(ctx)
 		}
 	}
 }
 
-func (js *JobScheduler) onFailure(ctx context.Context, ji *JobInvocation) {
-	ji.Status = JobStatusFailed
-
-	if js.Log != nil && js.ShouldTriggerListenersProvider() {
-		event := NewEvent(FlagFailed, ji.JobName, OptEventErr(ji.Err), OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
+func (js *JobScheduler) onFailure(ctx context.Context, job jobDefinition, ji *JobInvocation) {
+	if js.Log != nil && job.ShouldTriggerListenersProvider() {
+		event := NewEvent(FlagFailed, ji.JobName, OptEventErr(ji.Err), OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventAttempt(ji.Attempt), OptEventWritable(job.ShouldWriteOutputProvider()))
 
 		js.Log.Trigger(ctx, event)
 	}
 	if ji.Err != nil {
 		logger.MaybeError(js.Log, ji.Err)
 	}
-	if typed, ok := js.Job.(OnFailureReceiver); ok {
+	if typed, ok := job.Job.(OnFailureReceiver); ok {
 		typed.OnFailure(ctx)
 	}
-	if js.Last != nil && js.Last.Err == nil {
+	if last := js.LastInvocation(); last != nil && last.Err == nil {
 		if js.Log != nil {
-			event := NewEvent(FlagBroken, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventWritable(js.ShouldWriteOutputProvider()))
+			event := NewEvent(FlagBroken, ji.JobName, OptEventJobInvocation(ji.ID), OptEventElapsed(ji.Elapsed), OptEventAttempt(ji.Attempt), OptEventWritable(job.ShouldWriteOutputProvider()))
 			js.Log.Trigger(ctx, event)
 		}
 
-		if typed, ok := js.Job.(OnBrokenReceiver); ok {
+		if typed, ok := job.Job.(OnBrokenReceiver); ok {
 			typed.OnBroken(ctx)
 		}
 	}
 }
 
 func (js *JobScheduler) addHistory(ji JobInvocation) {
+	js.Lock()
 	js.History = append(js.cullHistory(), ji)
+	js.Unlock()
+	if js.HistoryStore != nil {
+		// the invocation context is cancelled by the time history is added.
+		logger.MaybeError(js.Log, js.saveHistory(context.Background(), ji))
+	}
+}
+
+func (js *JobScheduler) saveHistory(ctx context.Context, ji JobInvocation) error {
+	entry, err := NewHistoryEntry(ji)
+	if err != nil {
+		return err
+	}
+	return js.HistoryStore.SaveHistory(ctx, entry)
 }
 
 func (js *JobScheduler) cullHistory() []JobInvocation {
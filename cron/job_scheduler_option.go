@@ -19,3 +19,18 @@ func OptJobSchedulerLog(log logger.Log) JobSchedulerOption {
 func OptJobSchedulerConfig(hc Config) JobSchedulerOption {
 	return func(js *JobScheduler) { js.Config = hc }
 }
+
+// OptJobSchedulerHistoryStore sets the job scheduler history store.
+func OptJobSchedulerHistoryStore(store HistoryStore) JobSchedulerOption {
+	return func(js *JobScheduler) { js.HistoryStore = store }
+}
+
+// OptJobSchedulerConcurrencyLimiter sets the job scheduler concurrency limiter.
+func OptJobSchedulerConcurrencyLimiter(cl *ConcurrencyLimiter) JobSchedulerOption {
+	return func(js *JobScheduler) { js.ConcurrencyLimiter = cl }
+}
+
+// OptJobSchedulerLeaseManager sets the job scheduler lease manager.
+func OptJobSchedulerLeaseManager(lm *LeaseManager) JobSchedulerOption {
+	return func(js *JobScheduler) { js.LeaseManager = lm }
+}
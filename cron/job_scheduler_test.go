@@ -96,3 +96,32 @@ func TestJobSchedulerEnableDisable(t *testing.T) {
 	assert.True(disabled)
 	assert.True(enabled)
 }
+
+// waitingJob is a job that waits on cancellation.
+type waitingJob struct {
+	*JobBuilder
+}
+
+func (wj waitingJob) WaitOnCancellation() bool { return true }
+
+func TestJobSchedulerWaitOnCancellation(t *testing.T) {
+	assert := assert.New(t)
+
+	var returned bool
+	started := make(chan struct{})
+	js := NewJobScheduler(waitingJob{NewJob("foo", func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		returned = true
+		return nil
+	})})
+
+	done := make(chan *JobInvocation)
+	go func() { done <- js.RunContext(context.Background()) }()
+	<-started
+	js.Cancel()
+	ji := <-done
+	assert.Equal(JobStatusCancelled, ji.Status)
+	assert.True(returned)
+}
@@ -0,0 +1,99 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// LeaseProvider acquires exclusive, expiring leases that are shared between job manager replicas.
+type LeaseProvider interface {
+	// Acquire acquires a named lease for a holder, or renews it if the holder already holds it.
+	// It returns `ErrLeaseHeld` if another holder has an unexpired lease.
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*Lease, error)
+	// Release releases a lease if it is still held by its holder.
+	Release(ctx context.Context, lease Lease) error
+}
+
+// Lease is an exclusive, expiring lease.
+type Lease struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
+	// Token is a fencing token that increases every time the lease changes holders.
+	// Work done under a lease can be rejected by downstream systems if it carries a token
+	// lower than one they have already seen.
+	Token   int64     `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// IsExpired returns if the lease has expired as of a given time.
+func (l Lease) IsExpired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// LeaseMode determines what leases are used for.
+type LeaseMode string
+
+// Lease modes.
+const (
+	// LeaseModeManager uses a single lease for the job manager, and only the replica
+	// holding it (the leader) runs scheduled jobs.
+	LeaseModeManager LeaseMode = "manager"
+	// LeaseModeInvocation uses a lease per job, and a replica must acquire it for each invocation.
+	LeaseModeInvocation LeaseMode = "invocation"
+)
+
+var (
+	_ LeaseProvider = (*MemoryLeaseProvider)(nil)
+)
+
+// NewMemoryLeaseProvider returns a new in-memory lease provider.
+func NewMemoryLeaseProvider() *MemoryLeaseProvider {
+	return &MemoryLeaseProvider{
+		Leases: make(map[string]*Lease),
+	}
+}
+
+// MemoryLeaseProvider is a lease provider for a single process, and is useful for testing.
+type MemoryLeaseProvider struct {
+	sync.Mutex
+	Leases map[string]*Lease
+}
+
+// Acquire implements LeaseProvider.
+func (mlp *MemoryLeaseProvider) Acquire(_ context.Context, name, holder string, ttl time.Duration) (*Lease, error) {
+	mlp.Lock()
+	defer mlp.Unlock()
+
+	now := Now()
+	existing, ok := mlp.Leases[name]
+	if ok && existing.Holder != holder && !existing.IsExpired(now) {
+		return nil, ex.New(ErrLeaseHeld, ex.OptMessagef("lease: %s, holder: %s", name, existing.Holder))
+	}
+
+	lease := &Lease{Name: name, Holder: holder, Token: 1, Expires: now.Add(ttl)}
+	if ok {
+		lease.Token = existing.Token
+		if existing.Holder != holder {
+			lease.Token++
+		}
+	}
+	mlp.Leases[name] = lease
+
+	output := *lease
+	return &output, nil
+}
+
+// Release implements LeaseProvider.
+func (mlp *MemoryLeaseProvider) Release(_ context.Context, lease Lease) error {
+	mlp.Lock()
+	defer mlp.Unlock()
+
+	if existing, ok := mlp.Leases[lease.Name]; ok && existing.Holder == lease.Holder {
+		// keep the token so it continues to increase for the next holder.
+		existing.Expires = time.Time{}
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// NewLeaseManager returns a new lease manager for a provider.
+func NewLeaseManager(provider LeaseProvider, options ...LeaseManagerOption) *LeaseManager {
+	lm := &LeaseManager{
+		Latch:    async.NewLatch(),
+		Provider: provider,
+		Mode:     LeaseModeManager,
+		Name:     DefaultLeaseName,
+		Holder:   DefaultLeaseHolder(),
+		TTL:      DefaultLeaseTTL,
+	}
+	for _, option := range options {
+		option(lm)
+	}
+	return lm
+}
+
+// LeaseManagerOption is an option for lease managers.
+type LeaseManagerOption func(*LeaseManager)
+
+// OptLeaseManagerMode sets the lease mode.
+func OptLeaseManagerMode(mode LeaseMode) LeaseManagerOption {
+	return func(lm *LeaseManager) { lm.Mode = mode }
+}
+
+// OptLeaseManagerName sets the name of the manager lease.
+func OptLeaseManagerName(name string) LeaseManagerOption {
+	return func(lm *LeaseManager) { lm.Name = name }
+}
+
+// OptLeaseManagerHolder sets the holder identity of the replica.
+func OptLeaseManagerHolder(holder string) LeaseManagerOption {
+	return func(lm *LeaseManager) { lm.Holder = holder }
+}
+
+// OptLeaseManagerTTL sets the lease ttl.
+func OptLeaseManagerTTL(ttl time.Duration) LeaseManagerOption {
+	return func(lm *LeaseManager) { lm.TTL = ttl }
+}
+
+// DefaultLeaseHolder returns a holder identity from the hostname, process id and a random identifier.
+func DefaultLeaseHolder() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s/%d/%s", hostname, os.Getpid(), NewJobInvocationID()[:8])
+}
+
+// LeaseManager acquires and renews leases for a job manager replica, so that
+// jobs run on only one replica at a time.
+//
+// In `LeaseModeManager` it competes for a single lease in the background; while it holds it,
+// the replica is the leader and runs jobs. Leases are renewed every third of the ttl, and as soon as
+// a renewal fails, or the lease expires before it is renewed, leadership is lost and running jobs
+// are cancelled (fenced).
+//
+// In `LeaseModeInvocation` a lease named for the job is acquired before every invocation, renewed
+// while it runs, and the invocation is cancelled if it is lost. The lease is held for at least the ttl
+// after the invocation starts, so replicas that fire the same scheduled run slightly later skip it.
+type LeaseManager struct {
+	sync.Mutex
+	*async.Latch
+
+	Provider LeaseProvider
+	Mode     LeaseMode
+	Name     string
+	Holder   string
+	TTL      time.Duration
+	Log      logger.Log
+	// OnLost is called when leadership is lost.
+	OnLost func()
+
+	lease  *Lease
+	expiry *time.Timer
+}
+
+// IsLeader returns if the replica should run scheduled jobs.
+// It is always true in invocation mode.
+func (lm *LeaseManager) IsLeader() bool {
+	if lm.Mode != LeaseModeManager {
+		return true
+	}
+	return lm.Leadership() != nil
+}
+
+// Leadership returns the manager lease if it is held and unexpired, or nil.
+func (lm *LeaseManager) Leadership() *Lease {
+	lm.Lock()
+	defer lm.Unlock()
+	if lm.lease == nil || lm.lease.IsExpired(Now()) {
+		return nil
+	}
+	output := *lm.lease
+	return &output
+}
+
+// Start competes for the manager lease until stopped.
+// This call blocks.
+func (lm *LeaseManager) Start() error {
+	if !lm.Latch.CanStart() {
+		return fmt.Errorf("already started")
+	}
+	lm.Latch.Starting()
+	// make the first attempt before signalling started, so that a replica that
+	// can lead does so before its jobs are scheduled (and check for missed runs).
+	lm.renewLeadership()
+	lm.Latch.Started()
+	defer lm.Latch.Stopped()
+
+	stopping := lm.Latch.NotifyStopping()
+	ticker := time.NewTicker(lm.renewInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			lm.renewLeadership()
+		case <-stopping:
+			lm.releaseLeadership()
+			return nil
+		}
+	}
+}
+
+// Stop stops competing for the manager lease, and releases it if it is held.
+func (lm *LeaseManager) Stop() error {
+	if !lm.Latch.CanStop() {
+		return fmt.Errorf("already stopped")
+	}
+	lm.Latch.Stopping()
+	<-lm.Latch.NotifyStopped()
+	return nil
+}
+
+// AcquireInvocation acquires the lease for an invocation of a job.
+func (lm *LeaseManager) AcquireInvocation(ctx context.Context, jobName string) (*Lease, error) {
+	return lm.Provider.Acquire(ctx, jobName, lm.Holder, lm.TTL)
+}
+
+// RenewInvocation renews an invocation lease until the context is done, calling
+// the lost handler if it cannot be renewed. When the context is done, the lease
+// is released if it was acquired at least a ttl ago.
+func (lm *LeaseManager) RenewInvocation(ctx context.Context, lease Lease, lost func(error)) {
+	acquired := Now()
+	ticker := time.NewTicker(lm.renewInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if Since(acquired) >= lm.TTL {
+				logger.MaybeError(lm.Log, lm.Provider.Release(context.Background(), lease))
+			}
+			return
+		case <-ticker.C:
+			renewed, err := lm.Provider.Acquire(ctx, lease.Name, lm.Holder, lm.TTL)
+			if err == nil && renewed.Token != lease.Token {
+				err = ex.New(ErrLeaseLost, ex.OptMessagef("lease: %s, token changed", lease.Name))
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				lost(err)
+				return
+			}
+		}
+	}
+}
+
+// renewLeadership acquires or renews the manager lease.
+// If it can't be renewed, for any reason, leadership is lost immediately, as the lease
+// can no longer be guaranteed to be held by the time the next renewal is attempted.
+func (lm *LeaseManager) renewLeadership() {
+	lease, err := lm.Provider.Acquire(context.Background(), lm.Name, lm.Holder, lm.TTL)
+
+	lm.Lock()
+	previous := lm.lease
+	lm.lease = lease
+	lm.armExpiryUnsafe(lease)
+	lm.Unlock()
+
+	if err != nil && !ex.Is(err, ErrLeaseHeld) {
+		logger.MaybeError(lm.Log, err)
+	}
+	if previous != nil && (lease == nil || lease.Token != previous.Token) {
+		lm.lost(previous, err)
+	}
+	if lease != nil && (previous == nil || lease.Token != previous.Token) {
+		lm.trigger(FlagLeaderAcquired, lease, nil)
+	}
+}
+
+// armExpiryUnsafe loses leadership when a lease expires, if it isn't renewed before then.
+func (lm *LeaseManager) armExpiryUnsafe(lease *Lease) {
+	if lm.expiry != nil {
+		lm.expiry.Stop()
+		lm.expiry = nil
+	}
+	if lease == nil {
+		return
+	}
+	lm.expiry = time.AfterFunc(lease.Expires.Sub(Now()), func() {
+		lm.expire(lease)
+	})
+}
+
+// expire loses leadership if a lease is still the current lease and has expired.
+func (lm *LeaseManager) expire(lease *Lease) {
+	lm.Lock()
+	if lm.lease == nil || lm.lease.Token != lease.Token || !lm.lease.IsExpired(Now()) {
+		lm.Unlock()
+		return
+	}
+	lm.lease = nil
+	lm.expiry = nil
+	lm.Unlock()
+
+	lm.lost(lease, ex.New(ErrLeaseLost, ex.OptMessagef("lease: %s, expired before it was renewed", lease.Name)))
+}
+
+// releaseLeadership releases the manager lease if it is held.
+func (lm *LeaseManager) releaseLeadership() {
+	lm.Lock()
+	lease := lm.lease
+	lm.lease = nil
+	lm.armExpiryUnsafe(nil)
+	lm.Unlock()
+
+	if lease != nil {
+		logger.MaybeError(lm.Log, lm.Provider.Release(context.Background(), *lease))
+		lm.trigger(FlagLeaderLost, lease, nil)
+	}
+}
+
+func (lm *LeaseManager) lost(lease *Lease, err error) {
+	lm.trigger(FlagLeaderLost, lease, err)
+	if lm.OnLost != nil {
+		lm.OnLost()
+	}
+}
+
+func (lm *LeaseManager) trigger(flag string, lease *Lease, err error) {
+	if lm.Log == nil {
+		return
+	}
+	lm.Log.Trigger(context.Background(), NewEvent(flag, lease.Name, OptEventErr(err), OptEventLease(lease)))
+}
+
+// renewInterval returns the interval leases are renewed at.
+func (lm *LeaseManager) renewInterval() time.Duration {
+	if interval := lm.TTL / 3; interval > 0 {
+		return interval
+	}
+	return DefaultLeaseTTL / 3
+}
@@ -0,0 +1,234 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLeaseManagerLeadership(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	a := NewLeaseManager(mlp, OptLeaseManagerHolder("a"), OptLeaseManagerTTL(time.Minute))
+	b := NewLeaseManager(mlp, OptLeaseManagerHolder("b"), OptLeaseManagerTTL(time.Minute))
+
+	a.renewLeadership()
+	b.renewLeadership()
+	assert.True(a.IsLeader())
+	assert.False(b.IsLeader())
+	assert.NotNil(a.Leadership())
+	assert.Equal("a", a.Leadership().Holder)
+	assert.Nil(b.Leadership())
+
+	// renewing keeps the same token.
+	token := a.Leadership().Token
+	a.renewLeadership()
+	assert.Equal(token, a.Leadership().Token)
+
+	// releasing hands leadership over on the next renewal.
+	a.releaseLeadership()
+	assert.False(a.IsLeader())
+	b.renewLeadership()
+	assert.True(b.IsLeader())
+	assert.True(b.Leadership().Token > token)
+}
+
+func TestLeaseManagerLeadershipLost(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	var lost int32
+	a := NewLeaseManager(mlp, OptLeaseManagerHolder("a"), OptLeaseManagerTTL(time.Minute))
+	a.OnLost = func() { atomic.AddInt32(&lost, 1) }
+	b := NewLeaseManager(mlp, OptLeaseManagerHolder("b"), OptLeaseManagerTTL(time.Minute))
+
+	a.renewLeadership()
+	assert.True(a.IsLeader())
+
+	// simulate a failover after the lease expired without being renewed.
+	mlp.Leases[DefaultLeaseName].Expires = Now().Add(-time.Second)
+	b.renewLeadership()
+	assert.True(b.IsLeader())
+
+	a.renewLeadership()
+	assert.False(a.IsLeader())
+	assert.Equal(int32(1), atomic.LoadInt32(&lost))
+}
+
+func TestLeaseManagerInvocationMode(t *testing.T) {
+	assert := assert.New(t)
+
+	lm := NewLeaseManager(NewMemoryLeaseProvider(), OptLeaseManagerMode(LeaseModeInvocation))
+	assert.True(lm.IsLeader())
+	assert.Nil(lm.Leadership())
+}
+
+func TestLeaseManagerRenewInvocationLost(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	lm := NewLeaseManager(mlp, OptLeaseManagerHolder("a"), OptLeaseManagerTTL(30*time.Millisecond))
+	lease, err := lm.AcquireInvocation(context.Background(), "test")
+	assert.Nil(err)
+
+	// another replica takes over the lease.
+	mlp.Leases["test"].Expires = Now().Add(-time.Second)
+	_, err = mlp.Acquire(context.Background(), "test", "b", time.Minute)
+	assert.Nil(err)
+
+	lost := make(chan error, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	go lm.RenewInvocation(ctx, *lease, func(err error) { lost <- err })
+
+	select {
+	case err = <-lost:
+		assert.True(IsLeaseHeld(err))
+	case <-ctx.Done():
+		assert.FailNow("should have lost the lease")
+	}
+}
+
+func TestJobManagerLeaseModeManager(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	var holdersLock sync.Mutex
+	holders := map[string]int{}
+	newManager := func(holder string) *JobManager {
+		jm := New(OptLeaseManager(NewLeaseManager(mlp, OptLeaseManagerHolder(holder))))
+		assert.Nil(jm.LoadJobs(NewJob("test", func(ctx context.Context) error {
+			if lease := GetJobInvocation(ctx).Lease; lease != nil {
+				holdersLock.Lock()
+				holders[lease.Holder]++
+				holdersLock.Unlock()
+			}
+			return nil
+		}, OptJobBuilderSchedule(Every(10*time.Millisecond)))))
+		return jm
+	}
+
+	a := newManager("a")
+	assert.Nil(a.StartAsync())
+	defer a.Stop()
+	b := newManager("b")
+	assert.Nil(b.StartAsync())
+	defer b.Stop()
+
+	time.Sleep(100 * time.Millisecond)
+	assert.NotNil(a.Status().Leader)
+	assert.Nil(b.Status().Leader)
+
+	holdersLock.Lock()
+	defer holdersLock.Unlock()
+	assert.Len(holders, 1)
+	assert.NotZero(holders["a"])
+}
+
+func TestJobSchedulerLeaseModeInvocation(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	var runs int32
+	job := NewJob("test", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+	a := NewJobScheduler(job, OptJobSchedulerLeaseManager(NewLeaseManager(mlp, OptLeaseManagerMode(LeaseModeInvocation), OptLeaseManagerHolder("a"))))
+	b := NewJobScheduler(job, OptJobSchedulerLeaseManager(NewLeaseManager(mlp, OptLeaseManagerMode(LeaseModeInvocation), OptLeaseManagerHolder("b"))))
+
+	a.Run()
+	b.Run()
+	assert.Equal(int32(1), atomic.LoadInt32(&runs))
+	assert.Len(a.History, 1)
+	assert.NotNil(a.History[0].Lease)
+	assert.Empty(b.History)
+}
+
+// failingLeaseProvider fails to acquire leases while it is set to fail.
+type failingLeaseProvider struct {
+	*MemoryLeaseProvider
+	Fail int32
+}
+
+func (flp *failingLeaseProvider) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (*Lease, error) {
+	if atomic.LoadInt32(&flp.Fail) == 1 {
+		return nil, fmt.Errorf("provider unavailable")
+	}
+	return flp.MemoryLeaseProvider.Acquire(ctx, name, holder, ttl)
+}
+
+func TestLeaseManagerRenewalFailed(t *testing.T) {
+	assert := assert.New(t)
+
+	flp := &failingLeaseProvider{MemoryLeaseProvider: NewMemoryLeaseProvider()}
+	var lost int32
+	a := NewLeaseManager(flp, OptLeaseManagerHolder("a"), OptLeaseManagerTTL(time.Minute))
+	a.OnLost = func() { atomic.AddInt32(&lost, 1) }
+
+	a.renewLeadership()
+	assert.True(a.IsLeader())
+
+	// a failed renewal loses leadership right away, even though the lease hasn't expired yet.
+	atomic.StoreInt32(&flp.Fail, 1)
+	a.renewLeadership()
+	assert.False(a.IsLeader())
+	assert.Equal(int32(1), atomic.LoadInt32(&lost))
+
+	atomic.StoreInt32(&flp.Fail, 0)
+	a.renewLeadership()
+	assert.True(a.IsLeader())
+	a.releaseLeadership()
+}
+
+func TestLeaseManagerLeadershipExpired(t *testing.T) {
+	assert := assert.New(t)
+
+	lost := make(chan struct{}, 1)
+	a := NewLeaseManager(NewMemoryLeaseProvider(), OptLeaseManagerHolder("a"), OptLeaseManagerTTL(20*time.Millisecond))
+	a.OnLost = func() { lost <- struct{}{} }
+
+	// leadership is lost when the lease expires without being renewed, without waiting for the next renewal.
+	a.renewLeadership()
+	assert.True(a.IsLeader())
+	select {
+	case <-lost:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("leadership should have been lost")
+	}
+	assert.False(a.IsLeader())
+}
+
+func TestJobSchedulerLeaseModeManagerNotLeader(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	leader := NewLeaseManager(mlp, OptLeaseManagerHolder("a"), OptLeaseManagerTTL(time.Minute))
+	leader.renewLeadership()
+	defer leader.releaseLeadership()
+	follower := NewLeaseManager(mlp, OptLeaseManagerHolder("b"), OptLeaseManagerTTL(time.Minute))
+	follower.renewLeadership()
+
+	var runs int32
+	job := NewJob("test", func(_ context.Context) error {
+		atomic.AddInt32(&runs, 1)
+		return nil
+	})
+
+	// on demand runs don't run on replicas that aren't the leader.
+	js := NewJobScheduler(job, OptJobSchedulerLeaseManager(follower))
+	assert.Nil(js.RunWithParameters(context.Background(), nil))
+	assert.Zero(atomic.LoadInt32(&runs))
+
+	js = NewJobScheduler(job, OptJobSchedulerLeaseManager(leader))
+	ji := js.RunWithParameters(context.Background(), nil)
+	assert.NotNil(ji)
+	assert.Equal(leader.Leadership().Token, ji.Lease.Token)
+	assert.Equal(int32(1), atomic.LoadInt32(&runs))
+}
@@ -0,0 +1,60 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestMemoryLeaseProvider(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	first, err := mlp.Acquire(context.Background(), "test", "a", time.Minute)
+	assert.Nil(err)
+	assert.Equal("a", first.Holder)
+	assert.Equal(int64(1), first.Token)
+	assert.False(first.IsExpired(Now()))
+
+	_, err = mlp.Acquire(context.Background(), "test", "b", time.Minute)
+	assert.True(IsLeaseHeld(err))
+
+	renewed, err := mlp.Acquire(context.Background(), "test", "a", time.Minute)
+	assert.Nil(err)
+	assert.Equal(first.Token, renewed.Token)
+
+	assert.Nil(mlp.Release(context.Background(), *renewed))
+
+	second, err := mlp.Acquire(context.Background(), "test", "b", time.Minute)
+	assert.Nil(err)
+	assert.Equal("b", second.Holder)
+	assert.Equal(int64(2), second.Token)
+}
+
+func TestMemoryLeaseProviderExpired(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	_, err := mlp.Acquire(context.Background(), "test", "a", time.Minute)
+	assert.Nil(err)
+	mlp.Leases["test"].Expires = Now().Add(-time.Second)
+
+	lease, err := mlp.Acquire(context.Background(), "test", "b", time.Minute)
+	assert.Nil(err)
+	assert.Equal("b", lease.Holder)
+	assert.Equal(int64(2), lease.Token)
+}
+
+func TestMemoryLeaseProviderReleaseOtherHolder(t *testing.T) {
+	assert := assert.New(t)
+
+	mlp := NewMemoryLeaseProvider()
+	lease, err := mlp.Acquire(context.Background(), "test", "a", time.Minute)
+	assert.Nil(err)
+
+	assert.Nil(mlp.Release(context.Background(), Lease{Name: "test", Holder: "b"}))
+	assert.False(mlp.Leases["test"].IsExpired(Now()))
+	assert.Equal(lease.Expires, mlp.Leases["test"].Expires)
+}
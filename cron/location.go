@@ -0,0 +1,58 @@
+package cron
+
+import "time"
+
+// wallTime returns the instant a wall clock time occurs in a location, in UTC.
+//
+// The wall clock time is given as a time in UTC with the wall clock fields, e.g. 02:30 in a location is
+// passed as 02:30 UTC. Daylight saving time transitions are resolved the way schedules expect:
+//
+// - If the wall clock time occurs twice because clocks are set back, the earlier instant is returned,
+// so schedules do not fire twice in the repeated hour.
+//
+// - If the wall clock time does not occur because clocks are set forward, it is shifted forward by the
+// length of the gap, e.g. 02:30 on a day clocks go from 02:00 to 03:00 returns 03:30, so schedules
+// in the skipped hour still fire once.
+//
+// A nil location is treated as UTC.
+func wallTime(location *time.Location, wall time.Time) time.Time {
+	if location == nil || location == time.UTC {
+		return wall
+	}
+
+	var matched, shifted time.Time
+	// an instant with a given wall clock time is within a day of that wall clock time in utc,
+	// so the offsets in effect around it are the only candidates.
+	for _, probe := range []time.Duration{-24 * time.Hour, 0, 24 * time.Hour} {
+		_, offset := wall.Add(probe).In(location).Zone()
+		candidate := wall.Add(-time.Duration(offset) * time.Second)
+		candidateWall := wallClock(candidate.In(location))
+		switch {
+		case candidateWall.Equal(wall):
+			if matched.IsZero() || candidate.Before(matched) {
+				matched = candidate
+			}
+		case candidateWall.After(wall):
+			if shifted.IsZero() || candidate.Before(shifted) {
+				shifted = candidate
+			}
+		}
+	}
+	if !matched.IsZero() {
+		return matched
+	}
+	return shifted
+}
+
+// wallClock returns the wall clock fields of a time as a time in UTC.
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
+// inLocation returns a time in a location, treating a nil location as UTC.
+func inLocation(t time.Time, location *time.Location) time.Time {
+	if location == nil {
+		return t.UTC()
+	}
+	return t.In(location)
+}
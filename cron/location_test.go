@@ -0,0 +1,48 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("cannot load location %s: %v", name, err)
+	}
+	return location
+}
+
+func TestWallTime(t *testing.T) {
+	assert := assert.New(t)
+
+	newYork := mustLoadLocation(t, "America/New_York")
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+
+	testCases := []struct {
+		Name     string
+		Location *time.Location
+		Wall     time.Time
+		Expected time.Time
+	}{
+		{Name: "nil location", Wall: time.Date(2019, 03, 10, 2, 30, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 2, 30, 0, 0, time.UTC)},
+		{Name: "utc", Location: time.UTC, Wall: time.Date(2019, 03, 10, 2, 30, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 2, 30, 0, 0, time.UTC)},
+		{Name: "new york standard time", Location: newYork, Wall: time.Date(2019, 01, 15, 9, 0, 0, 0, time.UTC), Expected: time.Date(2019, 01, 15, 14, 0, 0, 0, time.UTC)},
+		{Name: "new york daylight time", Location: newYork, Wall: time.Date(2019, 07, 15, 9, 0, 0, 0, time.UTC), Expected: time.Date(2019, 07, 15, 13, 0, 0, 0, time.UTC)},
+		{Name: "new york gap", Location: newYork, Wall: time.Date(2019, 03, 10, 2, 30, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 7, 30, 0, 0, time.UTC)},
+		{Name: "new york gap start", Location: newYork, Wall: time.Date(2019, 03, 10, 2, 0, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 7, 0, 0, 0, time.UTC)},
+		{Name: "new york after gap", Location: newYork, Wall: time.Date(2019, 03, 10, 3, 0, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 7, 0, 0, 0, time.UTC)},
+		{Name: "new york overlap", Location: newYork, Wall: time.Date(2019, 11, 03, 1, 30, 0, 0, time.UTC), Expected: time.Date(2019, 11, 03, 5, 30, 0, 0, time.UTC)},
+		{Name: "new york after overlap", Location: newYork, Wall: time.Date(2019, 11, 03, 2, 0, 0, 0, time.UTC), Expected: time.Date(2019, 11, 03, 7, 0, 0, 0, time.UTC)},
+		{Name: "berlin gap", Location: berlin, Wall: time.Date(2019, 03, 31, 2, 30, 0, 0, time.UTC), Expected: time.Date(2019, 03, 31, 1, 30, 0, 0, time.UTC)},
+		{Name: "berlin overlap", Location: berlin, Wall: time.Date(2019, 10, 27, 2, 30, 0, 0, time.UTC), Expected: time.Date(2019, 10, 27, 0, 30, 0, 0, time.UTC)},
+		{Name: "kolkata", Location: kolkata, Wall: time.Date(2019, 07, 15, 9, 0, 0, 0, time.UTC), Expected: time.Date(2019, 07, 15, 3, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(tc.Expected, wallTime(tc.Location, tc.Wall), tc.Name)
+	}
+}
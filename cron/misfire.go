@@ -0,0 +1,92 @@
+package cron
+
+import "time"
+
+// MisfireMode determines what happens to scheduled runs that were missed.
+type MisfireMode string
+
+// Misfire modes.
+const (
+	// MisfireModeSkip skips missed runs.
+	MisfireModeSkip MisfireMode = "skip"
+	// MisfireModeRunOnce runs a job once for all of its missed runs.
+	MisfireModeRunOnce MisfireMode = "runOnce"
+	// MisfireModeRunAll runs a job once for each missed run, up to the policy's `MaxRuns`.
+	MisfireModeRunAll MisfireMode = "runAll"
+)
+
+// MisfirePolicy governs scheduled runs that were missed, either because the job manager was
+// not running when they were due, or because the process was paused (e.g. a host was suspended).
+//
+// Missed runs are found by evaluating the job's schedule from its last run, which is read from
+// the history store if one is set, so runs missed while the process was down are only found if history
+// is persisted. Missed runs are only checked for if a mode is set, as evaluating the schedule
+// from the last run is not meaningful for stateful schedules like `Immediately()`.
+//
+// Each decision is surfaced as an event; `FlagMisfireSkipped` for skipped runs, and `FlagMisfireRun`
+// for each missed run that is run. Missed runs are run one at a time, in order, in the background.
+type MisfirePolicy struct {
+	// Mode is what happens to missed runs; if unset, missed runs are not checked for.
+	Mode MisfireMode
+	// MaxRuns bounds the number of missed runs in `MisfireModeRunAll`; only the most recent are run.
+	MaxRuns int
+	// StartDeadline is how late a run can start relative to the time it was scheduled for,
+	// after which it is skipped. It applies to missed runs and to runs that fire late.
+	StartDeadline time.Duration
+}
+
+// MaxRunsOrDefault returns the max runs or a default.
+func (mp MisfirePolicy) MaxRunsOrDefault() int {
+	if mp.MaxRuns > 0 {
+		return mp.MaxRuns
+	}
+	return DefaultMisfireMaxRuns
+}
+
+// IsLate returns if a run scheduled for a given time is past the start deadline as of now.
+func (mp MisfirePolicy) IsLate(scheduled, now time.Time) bool {
+	return mp.StartDeadline > 0 && now.Sub(scheduled) > mp.StartDeadline
+}
+
+// Runs returns the missed runs that should be run as of now, and the number of missed runs that should be skipped.
+func (mp MisfirePolicy) Runs(missed []time.Time, total int, now time.Time) (runs []time.Time, skipped int) {
+	switch mp.Mode {
+	case MisfireModeRunOnce:
+		if len(missed) > 0 {
+			missed = missed[len(missed)-1:]
+		}
+	case MisfireModeRunAll:
+		if maxRuns := mp.MaxRunsOrDefault(); len(missed) > maxRuns {
+			missed = missed[len(missed)-maxRuns:]
+		}
+	default:
+		return nil, total
+	}
+	for _, scheduled := range missed {
+		if !mp.IsLate(scheduled, now) {
+			runs = append(runs, scheduled)
+		}
+	}
+	skipped = total - len(runs)
+	return
+}
+
+// MissedRuns returns the times a schedule would have fired after a given last run, and at or before now.
+// Only the most recent `limit` times are returned, along with the total number of missed runs.
+// Schedules are evaluated at most `MisfireMaxScan` times, so the total is bounded by it.
+func MissedRuns(schedule Schedule, last, now time.Time, limit int) (missed []time.Time, total int) {
+	if schedule == nil || last.IsZero() {
+		return
+	}
+	for next := schedule.Next(last); !next.IsZero() && !next.After(now) && total < MisfireMaxScan; next = schedule.Next(next) {
+		total++
+		if limit <= 0 {
+			continue
+		}
+		missed = append(missed, next)
+		if len(missed) > limit {
+			missed = missed[1:]
+		}
+	}
+	return
+}
@@ -0,0 +1,169 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/logger"
+)
+
+func TestMissedRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	last := time.Date(2019, 03, 01, 12, 00, 00, 00, time.UTC)
+	now := last.Add(5*time.Hour + 30*time.Minute)
+
+	missed, total := MissedRuns(Every(time.Hour), last, now, 2)
+	assert.Equal(5, total)
+	assert.Len(missed, 2)
+	assert.Equal(last.Add(4*time.Hour), missed[0])
+	assert.Equal(last.Add(5*time.Hour), missed[1])
+
+	missed, total = MissedRuns(Every(time.Hour), last, now, 0)
+	assert.Equal(5, total)
+	assert.Empty(missed)
+
+	missed, total = MissedRuns(Every(time.Hour), last, last.Add(30*time.Minute), 2)
+	assert.Zero(total)
+	assert.Empty(missed)
+
+	_, total = MissedRuns(Every(time.Hour), time.Time{}, now, 2)
+	assert.Zero(total, "jobs that never ran have no missed runs")
+
+	_, total = MissedRuns(Every(time.Millisecond), last, last.Add(time.Hour), 1)
+	assert.Equal(MisfireMaxScan, total)
+}
+
+func TestMisfirePolicyRuns(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2019, 03, 01, 12, 00, 00, 00, time.UTC)
+	missed := []time.Time{now.Add(-3 * time.Hour), now.Add(-2 * time.Hour), now.Add(-time.Hour)}
+
+	runs, skipped := MisfirePolicy{Mode: MisfireModeSkip}.Runs(missed, 5, now)
+	assert.Empty(runs)
+	assert.Equal(5, skipped)
+
+	runs, skipped = MisfirePolicy{Mode: MisfireModeRunOnce}.Runs(missed, 5, now)
+	assert.Equal([]time.Time{now.Add(-time.Hour)}, runs)
+	assert.Equal(4, skipped)
+
+	runs, skipped = MisfirePolicy{Mode: MisfireModeRunOnce, StartDeadline: 30 * time.Minute}.Runs(missed, 5, now)
+	assert.Empty(runs)
+	assert.Equal(5, skipped)
+
+	runs, skipped = MisfirePolicy{Mode: MisfireModeRunAll, MaxRuns: 2}.Runs(missed, 5, now)
+	assert.Equal([]time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)}, runs)
+	assert.Equal(3, skipped)
+
+	runs, skipped = MisfirePolicy{Mode: MisfireModeRunAll, StartDeadline: 150 * time.Minute}.Runs(missed, 3, now)
+	assert.Equal([]time.Time{now.Add(-2 * time.Hour), now.Add(-time.Hour)}, runs)
+	assert.Equal(1, skipped)
+
+	assert.False(MisfirePolicy{}.IsLate(now.Add(-time.Hour), now))
+	assert.True(MisfirePolicy{StartDeadline: time.Minute}.IsLate(now.Add(-time.Hour), now))
+}
+
+func TestJobSchedulerMisfireRunAll(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	var scheduled []time.Time
+	done := make(chan struct{})
+	job := NewJob("test", func(ctx context.Context) error {
+		lock.Lock()
+		defer lock.Unlock()
+		scheduled = append(scheduled, GetJobInvocation(ctx).Scheduled)
+		if len(scheduled) == 2 {
+			close(done)
+		}
+		return nil
+	},
+		OptJobBuilderSchedule(Every(time.Hour)),
+		OptJobBuilderMisfirePolicy(MisfirePolicy{Mode: MisfireModeRunAll, MaxRuns: 2, StartDeadline: 3 * time.Hour}),
+	)
+
+	log := logger.All(logger.OptOutput(new(bytes.Buffer)))
+	events := make(chan *Event, 3)
+	listener := NewEventListener(func(_ context.Context, e *Event) { events <- e })
+	log.Listen(FlagMisfireSkipped, "test", listener)
+	log.Listen(FlagMisfireRun, "test", listener)
+
+	last := Now().Add(-4*time.Hour - 30*time.Minute)
+	js := NewJobScheduler(job, OptJobSchedulerLog(log))
+	js.Last = &JobInvocation{JobName: "test", Scheduled: last, Started: last}
+
+	go js.Start()
+	<-js.NotifyStarted()
+	<-done
+	assert.Nil(js.Stop())
+
+	lock.Lock()
+	defer lock.Unlock()
+	assert.Equal([]time.Time{last.Add(3 * time.Hour), last.Add(4 * time.Hour)}, scheduled)
+
+	// listeners are called asynchronously, so the events can arrive in any order.
+	var runs []time.Time
+	for x := 0; x < 3; x++ {
+		e := <-events
+		if e.GetFlag() == FlagMisfireSkipped {
+			assert.Equal(2, e.Missed)
+			continue
+		}
+		assert.Equal(FlagMisfireRun, e.GetFlag())
+		runs = append(runs, e.Scheduled)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Before(runs[j]) })
+	assert.Equal([]time.Time{last.Add(3 * time.Hour), last.Add(4 * time.Hour)}, runs)
+}
+
+func TestJobSchedulerMisfireSkip(t *testing.T) {
+	assert := assert.New(t)
+
+	var ran bool
+	job := NewJob("test", func(_ context.Context) error {
+		ran = true
+		return nil
+	},
+		OptJobBuilderSchedule(Every(time.Hour)),
+		OptJobBuilderMisfirePolicy(MisfirePolicy{Mode: MisfireModeSkip}),
+	)
+
+	skipped := make(chan *Event, 1)
+	log := logger.All(logger.OptOutput(new(bytes.Buffer)))
+	log.Listen(FlagMisfireSkipped, "test", NewEventListener(func(_ context.Context, e *Event) {
+		skipped <- e
+	}))
+
+	last := Now().Add(-2*time.Hour - 30*time.Minute)
+	js := NewJobScheduler(job, OptJobSchedulerLog(log))
+	js.Last = &JobInvocation{JobName: "test", Started: last}
+
+	go js.Start()
+	<-js.NotifyStarted()
+	e := <-skipped
+	assert.Nil(js.Stop())
+
+	assert.Equal(2, e.Missed)
+	assert.False(ran)
+}
+
+func TestJobSchedulerMisfireUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	js := NewJobScheduler(NewJob("test", noop, OptJobBuilderSchedule(Every(time.Hour))))
+	assert.Empty(js.MisfirePolicyProvider().Mode)
+
+	// the next runtime is evaluated from now, not the last run.
+	last := Now().Add(-3 * time.Hour)
+	js.Last = &JobInvocation{JobName: "test", Started: last}
+	go js.Start()
+	<-js.NotifyStarted()
+	assert.Nil(js.Stop())
+	assert.True(js.NextRuntime.After(Now()))
+}
@@ -20,31 +20,43 @@ func EveryHourAtUTC(minute, second int) Schedule {
 	return OnTheHourAtUTCSchedule{Minute: minute, Second: second}
 }
 
+// EveryHourAt returns a schedule that fires every hour at a given minute in a location.
+// The location only matters for locations with offsets that are not whole hours, and for
+// hours that are repeated or skipped by daylight saving time transitions.
+func EveryHourAt(minute, second int, location *time.Location) Schedule {
+	return OnTheHourAtUTCSchedule{Minute: minute, Second: second, Location: location}
+}
+
 // OnTheHourAtUTCSchedule is a schedule that fires every hour on the given minute.
+//
+// If Location is set, the minute and second are a wall clock time in that location.
+// See `Schedule` for how daylight saving time transitions are handled.
 type OnTheHourAtUTCSchedule struct {
-	Minute int
-	Second int
+	Minute   int
+	Second   int
+	Location *time.Location
 }
 
 // String returns a string representation of the schedule.
 func (o OnTheHourAtUTCSchedule) String() string {
+	if o.Location != nil && o.Location != time.UTC {
+		return fmt.Sprintf("on the hour at %v:%v %s", o.Minute, o.Second, o.Location.String())
+	}
 	return fmt.Sprintf("on the hour at %v:%v", o.Minute, o.Second)
 }
 
 // Next implements the chronometer Schedule api.
 func (o OnTheHourAtUTCSchedule) Next(after time.Time) time.Time {
-	var returnValue time.Time
-	now := Now()
 	if after.IsZero() {
-		returnValue = time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(now) {
-			returnValue = returnValue.Add(time.Hour)
-		}
-	} else {
-		returnValue = time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), o.Minute, o.Second, 0, time.UTC)
-		if returnValue.Before(after) {
-			returnValue = returnValue.Add(time.Hour)
+		after = Now()
+	}
+	local := inLocation(after, o.Location)
+	// a skipped hour can push the next wall clock time past the following hour, so look a day ahead.
+	for hour := 0; hour < 25; hour++ {
+		wall := time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+hour, o.Minute, o.Second, 0, time.UTC)
+		if next := wallTime(o.Location, wall); next.After(after) {
+			return next
 		}
 	}
-	return returnValue
+	return Zero
 }
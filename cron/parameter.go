@@ -0,0 +1,228 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ParameterType is the type of a job parameter.
+type ParameterType string
+
+// Parameter types.
+const (
+	ParameterTypeString   ParameterType = "string"
+	ParameterTypeInt      ParameterType = "int"
+	ParameterTypeFloat    ParameterType = "float"
+	ParameterTypeBool     ParameterType = "bool"
+	ParameterTypeDuration ParameterType = "duration"
+	// ParameterTypeTime values are formatted as RFC3339.
+	ParameterTypeTime ParameterType = "time"
+)
+
+// Parameter is a typed parameter a job's invocations can be started with.
+type Parameter struct {
+	Name        string        `json:"name" yaml:"name"`
+	Type        ParameterType `json:"type" yaml:"type"`
+	Description string        `json:"description,omitempty" yaml:"description"`
+	// Default is the value used when none is given, formatted as it would be given.
+	Default  string `json:"default,omitempty" yaml:"default"`
+	Required bool   `json:"required,omitempty" yaml:"required"`
+}
+
+// ValidateParameterDefinitions validates the parameters a job declares.
+// Names must be unique, and valid environment variable names, e.g. `batchSize` or `batch_size` but not `batch-size`,
+// so they can be passed to commands as environment variables.
+func ValidateParameterDefinitions(declared []Parameter) error {
+	seen := map[string]bool{}
+	for _, parameter := range declared {
+		if parameter.Name == "" {
+			return ex.New(ErrParameterInvalid, ex.OptMessage("parameter name is required"))
+		}
+		if !isParameterName(parameter.Name) {
+			return ex.New(ErrParameterInvalid, ex.OptMessagef("parameter: %s, names may only contain letters, digits and underscores, and may not start with a digit", parameter.Name))
+		}
+		if seen[parameter.Name] {
+			return ex.New(ErrParameterInvalid, ex.OptMessagef("parameter: %s, is declared more than once", parameter.Name))
+		}
+		seen[parameter.Name] = true
+	}
+	return nil
+}
+
+// isParameterName returns if a name is a valid environment variable name.
+func isParameterName(name string) bool {
+	for index, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && index > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Parse parses a value for the parameter.
+func (p Parameter) Parse(value string) (interface{}, error) {
+	var parsed interface{}
+	var err error
+	switch p.Type {
+	case ParameterTypeString, "":
+		parsed = value
+	case ParameterTypeInt:
+		parsed, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	case ParameterTypeFloat:
+		parsed, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+	case ParameterTypeBool:
+		parsed, err = strconv.ParseBool(strings.TrimSpace(value))
+	case ParameterTypeDuration:
+		parsed, err = time.ParseDuration(strings.TrimSpace(value))
+	case ParameterTypeTime:
+		parsed, err = time.Parse(time.RFC3339, strings.TrimSpace(value))
+	default:
+		return nil, ex.New(ErrParameterInvalid, ex.OptMessagef("parameter: %s, unknown type: %s", p.Name, p.Type))
+	}
+	if err != nil {
+		return nil, ex.New(ErrParameterInvalid, ex.OptMessagef("parameter: %s, invalid %s: %q", p.Name, p.Type, value))
+	}
+	return parsed, nil
+}
+
+// ValidateParameters validates values for a list of declared parameters, returning the typed values.
+// Defaults are used for values that are not given, and it is an error to omit a required
+// parameter or to give a value for a parameter that is not declared.
+func ValidateParameters(declared []Parameter, values map[string]string) (Parameters, error) {
+	output := Parameters{}
+	known := map[string]bool{}
+	for _, parameter := range declared {
+		known[parameter.Name] = true
+		value, ok := values[parameter.Name]
+		if !ok || value == "" {
+			if parameter.Required && parameter.Default == "" {
+				return nil, ex.New(ErrParameterInvalid, ex.OptMessagef("parameter: %s, is required", parameter.Name))
+			}
+			if parameter.Default == "" {
+				continue
+			}
+			value = parameter.Default
+		}
+		parsed, err := parameter.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		output[parameter.Name] = parsed
+	}
+
+	var unknown []string
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, ex.New(ErrParameterInvalid, ex.OptMessagef("unknown parameters: %s", strings.Join(unknown, ", ")))
+	}
+	return output, nil
+}
+
+// Parameters are the typed parameter values an invocation was started with.
+type Parameters map[string]interface{}
+
+// GetParameters returns the parameters of the job invocation in a context.
+func GetParameters(ctx context.Context) Parameters {
+	if ji := GetJobInvocation(ctx); ji != nil && ji.Parameters != nil {
+		return ji.Parameters
+	}
+	return Parameters{}
+}
+
+// Has returns if a parameter has a value.
+func (p Parameters) Has(name string) bool {
+	_, ok := p[name]
+	return ok
+}
+
+// String returns a parameter value as a string.
+func (p Parameters) String(name string) string {
+	value, ok := p[name]
+	if !ok {
+		return ""
+	}
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case time.Time:
+		return typed.Format(time.RFC3339)
+	case float64:
+		// formatted without an exponent, e.g. for large whole numbers restored from json.
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
+
+// Int returns an int parameter value.
+func (p Parameters) Int(name string) int64 {
+	switch typed := p[name].(type) {
+	case int64:
+		return typed
+	case float64:
+		// restored from json
+		return int64(typed)
+	}
+	return 0
+}
+
+// Float returns a float parameter value.
+func (p Parameters) Float(name string) float64 {
+	typed, _ := p[name].(float64)
+	return typed
+}
+
+// Bool returns a bool parameter value.
+func (p Parameters) Bool(name string) bool {
+	typed, _ := p[name].(bool)
+	return typed
+}
+
+// Duration returns a duration parameter value.
+func (p Parameters) Duration(name string) time.Duration {
+	switch typed := p[name].(type) {
+	case time.Duration:
+		return typed
+	case float64:
+		// restored from json
+		return time.Duration(typed)
+	}
+	return 0
+}
+
+// Time returns a time parameter value.
+func (p Parameters) Time(name string) time.Time {
+	switch typed := p[name].(type) {
+	case time.Time:
+		return typed
+	case string:
+		// restored from json
+		parsed, _ := time.Parse(time.RFC3339, typed)
+		return parsed
+	}
+	return time.Time{}
+}
+
+// Names returns the parameter names in sorted order.
+func (p Parameters) Names() []string {
+	output := make([]string, 0, len(p))
+	for name := range p {
+		output = append(output, name)
+	}
+	sort.Strings(output)
+	return output
+}
@@ -0,0 +1,140 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestValidateParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	declared := []Parameter{
+		{Name: "region", Required: true},
+		{Name: "batch", Type: ParameterTypeInt, Default: "100"},
+		{Name: "ratio", Type: ParameterTypeFloat},
+		{Name: "dryRun", Type: ParameterTypeBool, Default: "true"},
+		{Name: "window", Type: ParameterTypeDuration},
+		{Name: "since", Type: ParameterTypeTime},
+	}
+
+	parameters, err := ValidateParameters(declared, map[string]string{
+		"region": "us-east-1",
+		"ratio":  "0.5",
+		"dryRun": "false",
+		"window": "90m",
+		"since":  "2019-03-01T12:00:00Z",
+	})
+	assert.Nil(err)
+	assert.Equal("us-east-1", parameters.String("region"))
+	assert.Equal(100, parameters.Int("batch"))
+	assert.Equal(0.5, parameters.Float("ratio"))
+	assert.False(parameters.Bool("dryRun"))
+	assert.Equal(90*time.Minute, parameters.Duration("window"))
+	assert.Equal(time.Date(2019, 03, 01, 12, 00, 00, 00, time.UTC), parameters.Time("since"))
+	assert.Equal([]string{"batch", "dryRun", "ratio", "region", "since", "window"}, parameters.Names())
+
+	parameters, err = ValidateParameters(declared, map[string]string{"region": "us-west-2"})
+	assert.Nil(err)
+	assert.Equal(100, parameters.Int("batch"))
+	assert.True(parameters.Bool("dryRun"))
+	assert.False(parameters.Has("window"))
+
+	_, err = ValidateParameters(declared, nil)
+	assert.True(IsParameterInvalid(err))
+	assert.Equal("parameter: region, is required", ex.ErrMessage(err))
+
+	_, err = ValidateParameters(declared, map[string]string{"region": "us-east-1", "batch": "lots"})
+	assert.True(IsParameterInvalid(err))
+	assert.Equal(`parameter: batch, invalid int: "lots"`, ex.ErrMessage(err))
+
+	_, err = ValidateParameters(declared, map[string]string{"region": "us-east-1", "extra": "1", "bogus": "2"})
+	assert.True(IsParameterInvalid(err))
+	assert.Equal("unknown parameters: bogus, extra", ex.ErrMessage(err))
+}
+
+func TestValidateParameterDefinitions(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Nil(ValidateParameterDefinitions(nil))
+	assert.Nil(ValidateParameterDefinitions([]Parameter{{Name: "batchSize"}, {Name: "batch_size"}, {Name: "_dry_run2"}}))
+
+	err := ValidateParameterDefinitions([]Parameter{{Name: ""}})
+	assert.True(IsParameterInvalid(err))
+	assert.Equal("parameter name is required", ex.ErrMessage(err))
+
+	for _, name := range []string{"batch-size", "batch size", "2fast", "région"} {
+		assert.True(IsParameterInvalid(ValidateParameterDefinitions([]Parameter{{Name: name}})), name)
+	}
+
+	err = ValidateParameterDefinitions([]Parameter{{Name: "batch"}, {Name: "region"}, {Name: "batch", Type: ParameterTypeInt}})
+	assert.True(IsParameterInvalid(err))
+	assert.Equal("parameter: batch, is declared more than once", ex.ErrMessage(err))
+
+	jm := New()
+	err = jm.LoadJobs(NewJob("test", noop, OptJobBuilderParameters(Parameter{Name: "batch-size"})))
+	assert.True(IsParameterInvalid(err))
+	assert.Empty(jm.Jobs)
+}
+
+func TestParametersStringFloat(t *testing.T) {
+	assert := assert.New(t)
+
+	parameters := Parameters{"whole": float64(12345678), "fraction": 0.25, "large": 1e21}
+	assert.Equal("12345678", parameters.String("whole"))
+	assert.Equal("0.25", parameters.String("fraction"))
+	assert.Equal("1000000000000000000000", parameters.String("large"))
+}
+
+func TestParametersJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	parameters, err := ValidateParameters([]Parameter{
+		{Name: "batch", Type: ParameterTypeInt},
+		{Name: "window", Type: ParameterTypeDuration},
+		{Name: "since", Type: ParameterTypeTime},
+	}, map[string]string{"batch": "5", "window": "1s", "since": "2019-03-01T12:00:00Z"})
+	assert.Nil(err)
+
+	contents, err := json.Marshal(parameters)
+	assert.Nil(err)
+	var restored Parameters
+	assert.Nil(json.Unmarshal(contents, &restored))
+	assert.Equal(5, restored.Int("batch"))
+	assert.Equal(time.Second, restored.Duration("window"))
+	assert.Equal(parameters.Time("since"), restored.Time("since"))
+}
+
+func TestJobManagerRunJobWithParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	values := make(chan Parameters, 2)
+	job := NewJob("test", func(ctx context.Context) error {
+		values <- GetParameters(ctx)
+		return nil
+	}, OptJobBuilderParameters(
+		Parameter{Name: "count", Type: ParameterTypeInt, Default: "1"},
+	))
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(job))
+
+	err := jm.RunJobWithParameters("test", map[string]string{"count": "many"})
+	assert.True(IsParameterInvalid(err))
+	assert.True(IsJobNotLoaded(jm.RunJobWithParameters("nope", nil)))
+
+	assert.Nil(jm.RunJobWithParameters("test", map[string]string{"count": "3"}))
+	assert.Equal(3, (<-values).Int("count"))
+
+	// runs without parameters use the defaults.
+	assert.Nil(jm.RunJob("test"))
+	assert.Equal(1, (<-values).Int("count"))
+
+	js, err := jm.Job("test")
+	assert.Nil(err)
+	assert.Len(js.Parameters, 1)
+}
@@ -0,0 +1,88 @@
+package cron
+
+import (
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// RetryPolicy governs if and when a failed job invocation is retried.
+//
+// Retries are attempts of the same invocation; they share its id, context and timeout,
+// and the invocation only finishes (and fires `OnFailure` and `OnBroken`) once the
+// last attempt fails.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	// A zero value disables retries.
+	MaxRetries int
+	// Backoff returns the delay before a retry, given the number of the attempt that failed (starting at 1).
+	// If it is unset, retries are not delayed.
+	Backoff Backoff
+	// Retryable optionally classifies errors as retryable.
+	// If it is unset, every error is retryable.
+	Retryable func(error) bool
+}
+
+// ShouldRetry returns if an attempt that failed with a given error should be retried.
+// Cancelled invocations are never retried.
+func (rp RetryPolicy) ShouldRetry(attempt int, err error) bool {
+	if err == nil || IsJobCancelled(err) {
+		return false
+	}
+	if attempt > rp.MaxRetries {
+		return false
+	}
+	if rp.Retryable != nil {
+		return rp.Retryable(err)
+	}
+	return true
+}
+
+// Delay returns the delay before retrying a failed attempt.
+func (rp RetryPolicy) Delay(attempt int) time.Duration {
+	if rp.Backoff != nil {
+		return rp.Backoff(attempt)
+	}
+	return 0
+}
+
+// Backoff returns the delay before a retry given the number of the attempt that failed.
+type Backoff func(attempt int) time.Duration
+
+// ConstantBackoff returns a backoff that always delays by a given duration.
+func ConstantBackoff(delay time.Duration) Backoff {
+	return func(_ int) time.Duration {
+		return delay
+	}
+}
+
+// ExponentialBackoff returns a backoff that doubles the delay after every attempt,
+// starting from an initial delay, up to an optional maximum delay.
+func ExponentialBackoff(initial, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for x := 1; x < attempt; x++ {
+			delay = delay * 2
+			if max > 0 && delay >= max {
+				return max
+			}
+		}
+		if max > 0 && delay > max {
+			return max
+		}
+		return delay
+	}
+}
+
+// RetryableErrorClasses returns a retryable classifier that matches errors
+// with any of the given classes (as with `ex.Is`).
+func RetryableErrorClasses(classes ...error) func(error) bool {
+	return func(err error) bool {
+		for _, class := range classes {
+			if ex.Is(err, class) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,146 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	policy := RetryPolicy{MaxRetries: 2}
+	assert.False(policy.ShouldRetry(1, nil))
+	assert.True(policy.ShouldRetry(1, fmt.Errorf("test")))
+	assert.True(policy.ShouldRetry(2, fmt.Errorf("test")))
+	assert.False(policy.ShouldRetry(3, fmt.Errorf("test")))
+	assert.False(policy.ShouldRetry(1, ErrJobCancelled))
+	assert.False(RetryPolicy{}.ShouldRetry(1, fmt.Errorf("test")))
+
+	policy.Retryable = RetryableErrorClasses(ex.Class("retryable"))
+	assert.True(policy.ShouldRetry(1, ex.New("retryable")))
+	assert.False(policy.ShouldRetry(1, ex.New("not retryable")))
+}
+
+func TestBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Zero(RetryPolicy{}.Delay(1))
+	assert.Equal(time.Second, RetryPolicy{Backoff: ConstantBackoff(time.Second)}.Delay(3))
+
+	exponential := ExponentialBackoff(time.Second, 5*time.Second)
+	assert.Equal(time.Second, exponential(1))
+	assert.Equal(2*time.Second, exponential(2))
+	assert.Equal(4*time.Second, exponential(3))
+	assert.Equal(5*time.Second, exponential(4))
+	assert.Equal(5*time.Second, exponential(100))
+
+	assert.Equal(8*time.Second, ExponentialBackoff(time.Second, 0)(4))
+}
+
+func TestJobSchedulerRetry(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts []int
+	var retries, failures int
+	job := NewJob("test", func(ctx context.Context) error {
+		ji := GetJobInvocation(ctx)
+		attempts = append(attempts, ji.Attempt)
+		if ji.Attempt < 3 {
+			return fmt.Errorf("attempt %d", ji.Attempt)
+		}
+		return nil
+	},
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(time.Millisecond)}),
+		OptJobBuilderOnRetry(func(_ *JobInvocation) { retries++ }),
+		OptJobBuilderOnFailure(func(_ *JobInvocation) { failures++ }),
+	)
+
+	js := NewJobScheduler(job)
+	js.Run()
+
+	assert.Equal([]int{1, 2, 3}, attempts)
+	assert.Equal(2, retries)
+	assert.Zero(failures)
+	assert.Len(js.History, 1)
+	assert.Equal(JobStatusComplete, js.History[0].Status)
+	assert.Equal(3, js.History[0].Attempt)
+}
+
+func TestJobSchedulerRetryExhausted(t *testing.T) {
+	assert := assert.New(t)
+
+	var shouldFail bool
+	var attempts, broken, failures int
+	job := NewJob("test", func(ctx context.Context) error {
+		attempts++
+		if shouldFail {
+			return fmt.Errorf("only a test")
+		}
+		return nil
+	},
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxRetries: 2}),
+		OptJobBuilderOnBroken(func(ji *JobInvocation) {
+			broken++
+			assert.Equal(3, ji.Attempt)
+		}),
+		OptJobBuilderOnFailure(func(_ *JobInvocation) { failures++ }),
+	)
+
+	js := NewJobScheduler(job)
+	js.Run()
+	assert.Equal(1, attempts)
+
+	shouldFail = true
+	js.Run()
+	assert.Equal(4, attempts)
+	assert.Equal(1, failures)
+	assert.Equal(1, broken)
+	assert.Len(js.History, 2)
+	assert.Equal(JobStatusFailed, js.History[1].Status)
+	assert.Equal(3, js.History[1].Attempt)
+}
+
+func TestJobSchedulerRetryNotRetryable(t *testing.T) {
+	assert := assert.New(t)
+
+	var attempts int
+	job := NewJob("test", func(ctx context.Context) error {
+		attempts++
+		return ex.New("not retryable")
+	}, OptJobBuilderRetryPolicy(RetryPolicy{MaxRetries: 2, Retryable: RetryableErrorClasses(ex.Class("retryable"))}))
+
+	js := NewJobScheduler(job)
+	js.Run()
+	assert.Equal(1, attempts)
+	assert.Equal(JobStatusFailed, js.Last.Status)
+}
+
+func TestJobSchedulerRetryCancelledDuringBackoff(t *testing.T) {
+	assert := assert.New(t)
+
+	retrying := make(chan struct{})
+	job := NewJob("test", func(ctx context.Context) error {
+		return fmt.Errorf("only a test")
+	},
+		OptJobBuilderRetryPolicy(RetryPolicy{MaxRetries: 2, Backoff: ConstantBackoff(time.Minute)}),
+		OptJobBuilderOnRetry(func(_ *JobInvocation) { close(retrying) }),
+	)
+
+	js := NewJobScheduler(job)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		js.Run()
+	}()
+	<-retrying
+	js.Cancel()
+	<-done
+
+	assert.Equal(JobStatusCancelled, js.Last.Status)
+	assert.Equal(1, js.Last.Attempt)
+}
@@ -5,6 +5,12 @@ import (
 )
 
 // Schedule is a type that provides a next runtime after a given previous runtime.
+//
+// Schedules that fire at wall clock times (e.g. `DailyAt`, `EveryHourAt` or string schedules with a `CRON_TZ=` prefix)
+// can be evaluated in a location, and handle daylight saving time transitions in that location as follows:
+// wall clock times that are skipped when clocks are set forward fire once, shifted forward by the length of the gap,
+// and wall clock times that are repeated when clocks are set back only fire the first time they occur.
+// Schedules that fire on a fixed interval or at a fixed instant are not affected by locations.
 type Schedule interface {
 	// GetNextRuntime should return the next runtime after a given previous runtime. If `after` is time.Time{} it should be assumed
 	// the job hasn't run yet. If time.Time{} is returned by the schedule it is inferred that the job should not run again.
@@ -116,3 +116,129 @@ func TestOnceAt(t *testing.T) {
 	result = s.Next(after)
 	assert.True(result.IsZero())
 }
+
+func TestDailyScheduleLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	testCases := []struct {
+		Name     string
+		Schedule Schedule
+		After    time.Time
+		Expected []time.Time
+	}{
+		{
+			Name:     "9am across spring forward",
+			Schedule: DailyAt(9, 0, 0, newYork),
+			After:    time.Date(2019, 03, 9, 14, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 03, 10, 13, 0, 0, 0, time.UTC), time.Date(2019, 03, 11, 13, 0, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "9am across fall back",
+			Schedule: DailyAt(9, 0, 0, newYork),
+			After:    time.Date(2019, 11, 2, 13, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 11, 3, 14, 0, 0, 0, time.UTC), time.Date(2019, 11, 4, 14, 0, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "skipped time runs once",
+			Schedule: DailyAt(2, 30, 0, newYork),
+			After:    time.Date(2019, 03, 9, 7, 30, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 03, 10, 7, 30, 0, 0, time.UTC), time.Date(2019, 03, 11, 6, 30, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "repeated time runs once",
+			Schedule: DailyAt(1, 30, 0, newYork),
+			After:    time.Date(2019, 11, 2, 5, 30, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 11, 3, 5, 30, 0, 0, time.UTC), time.Date(2019, 11, 4, 6, 30, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "weekdays are local",
+			Schedule: WeeklyAt(20, 0, 0, newYork, time.Monday),
+			After:    time.Date(2019, 07, 1, 0, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 07, 2, 0, 0, 0, 0, time.UTC), time.Date(2019, 07, 9, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "weekdays",
+			Schedule: WeekdaysAt(9, 0, 0, newYork),
+			After:    time.Date(2019, 03, 8, 14, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 03, 11, 13, 0, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "weekends",
+			Schedule: WeekendsAt(9, 0, 0, newYork),
+			After:    time.Date(2019, 03, 8, 14, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 03, 9, 14, 0, 0, 0, time.UTC), time.Date(2019, 03, 10, 13, 0, 0, 0, time.UTC), time.Date(2019, 03, 16, 13, 0, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "utc",
+			Schedule: DailyAtUTC(9, 0, 0),
+			After:    time.Date(2019, 03, 9, 14, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 03, 10, 9, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tc := range testCases {
+		after := tc.After
+		for _, expected := range tc.Expected {
+			after = tc.Schedule.Next(after)
+			assert.Equal(expected, after, tc.Name)
+		}
+	}
+}
+
+func TestOnTheHourAtLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	newYork := mustLoadLocation(t, "America/New_York")
+	kolkata := mustLoadLocation(t, "Asia/Kolkata")
+
+	testCases := []struct {
+		Name     string
+		Schedule Schedule
+		After    time.Time
+		Expected []time.Time
+	}{
+		{
+			Name:     "spring forward",
+			Schedule: EveryHourAt(30, 0, newYork),
+			After:    time.Date(2019, 03, 10, 5, 0, 0, 0, time.UTC),
+			Expected: []time.Time{
+				time.Date(2019, 03, 10, 5, 30, 0, 0, time.UTC), // 00:30 EST
+				time.Date(2019, 03, 10, 6, 30, 0, 0, time.UTC), // 01:30 EST
+				time.Date(2019, 03, 10, 7, 30, 0, 0, time.UTC), // 03:30 EDT, the skipped 02:30 runs once
+				time.Date(2019, 03, 10, 8, 30, 0, 0, time.UTC), // 04:30 EDT
+			},
+		},
+		{
+			Name:     "fall back",
+			Schedule: EveryHourAt(30, 0, newYork),
+			After:    time.Date(2019, 11, 3, 4, 0, 0, 0, time.UTC),
+			Expected: []time.Time{
+				time.Date(2019, 11, 3, 4, 30, 0, 0, time.UTC), // 00:30 EDT
+				time.Date(2019, 11, 3, 5, 30, 0, 0, time.UTC), // 01:30 EDT, the repeated 01:30 EST does not run
+				time.Date(2019, 11, 3, 7, 30, 0, 0, time.UTC), // 02:30 EST
+			},
+		},
+		{
+			Name:     "half hour offset",
+			Schedule: EveryHourAt(0, 0, kolkata),
+			After:    time.Date(2019, 07, 1, 0, 0, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 07, 1, 0, 30, 0, 0, time.UTC), time.Date(2019, 07, 1, 1, 30, 0, 0, time.UTC)},
+		},
+		{
+			Name:     "on the run time",
+			Schedule: EveryHourAtUTC(30, 0),
+			After:    time.Date(2019, 07, 1, 0, 30, 0, 0, time.UTC),
+			Expected: []time.Time{time.Date(2019, 07, 1, 1, 30, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tc := range testCases {
+		after := tc.After
+		for _, expected := range tc.Expected {
+			after = tc.Schedule.Next(after)
+			assert.Equal(expected, after, tc.Name)
+		}
+	}
+}
@@ -4,4 +4,18 @@ package cron
 type Status struct {
 	Jobs    []*JobScheduler             `json:"jobs"`
 	Running map[string][]*JobInvocation `json:"running,omitempty"`
+	// Leader is the job manager lease if the replica is the leader.
+	Leader *Lease `json:"leader,omitempty"`
+	// Concurrency is the number of running and queued invocations, if concurrency is limited.
+	Concurrency *ConcurrencyStatus `json:"concurrency,omitempty"`
+	// Workflows are the loaded workflows and their current and last runs.
+	Workflows []WorkflowStatus `json:"workflows,omitempty"`
+}
+
+// WorkflowStatus is the status of a workflow.
+type WorkflowStatus struct {
+	Name    string         `json:"name"`
+	Steps   []WorkflowStep `json:"steps"`
+	Current *WorkflowRun   `json:"current,omitempty"`
+	Last    *WorkflowRun   `json:"last,omitempty"`
 }
@@ -36,9 +36,42 @@ You can also use shorthands for the cron string:
 	@hourly is equivalent to "0 0 * * * * *"
 	@every xyz will parse the `xyz` value as a duration and return an every schedule for that
 */
+/*
+The string is evaluated in UTC unless it is prefixed with a time zone, in which case it is evaluated
+in that time zone, e.g. `CRON_TZ=America/New_York 0 0 9 * * * *` fires at 9am every day in New York.
+`TZ=` is also accepted as the prefix.
+*/
 func ParseString(cronString string) (Schedule, error) {
+	return ParseStringInLocation(cronString, nil)
+}
+
+// ParseStringInLocation parses a cron formatted string into a schedule that is evaluated in a given location.
+// A time zone prefix on the string, e.g. `CRON_TZ=America/New_York`, takes precedence over the location.
+// See `ParseString` for the format of the string.
+func ParseStringInLocation(cronString string, location *time.Location) (Schedule, error) {
+	original := cronString
+	cronString = strings.TrimSpace(cronString)
+
+	// strip the time zone prefix.
+	for _, prefix := range []string{StringSchedulePrefixCronTimeZone, StringSchedulePrefixTimeZone} {
+		if !strings.HasPrefix(cronString, prefix) {
+			continue
+		}
+		fields := strings.SplitN(strings.TrimPrefix(cronString, prefix), " ", 2)
+		var err error
+		location, err = time.LoadLocation(fields[0])
+		if err != nil {
+			return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(err), ex.OptMessagef("invalid time zone; provided string: %s", original))
+		}
+		if len(fields) < 2 {
+			return nil, ex.New(ErrStringScheduleInvalid, ex.OptInner(ErrStringScheduleComponents), ex.OptMessagef("provided string; %s", original))
+		}
+		cronString = strings.TrimSpace(fields[1])
+		break
+	}
+
 	// escape shorthands.
-	if shorthand, ok := StringScheduleShorthands[strings.TrimSpace(cronString)]; ok {
+	if shorthand, ok := StringScheduleShorthands[cronString]; ok {
 		cronString = shorthand
 	}
 
@@ -98,7 +131,8 @@ func ParseString(cronString string) (Schedule, error) {
 	}
 
 	schedule := &StringSchedule{
-		Original:    cronString,
+		Original:    original,
+		Location:    location,
 		Seconds:     seconds,
 		Minutes:     minutes,
 		Hours:       hours,
@@ -118,6 +152,12 @@ const (
 	ErrStringScheduleInvalidRange    ex.Class = "cron: range (from-to) invalid"
 )
 
+// String schedule time zone prefixes
+const (
+	StringSchedulePrefixCronTimeZone = "CRON_TZ="
+	StringSchedulePrefixTimeZone     = "TZ="
+)
+
 // String schedule shorthands labels
 const (
 	StringScheduleShorthandAnnually = "@annually"
@@ -146,8 +186,12 @@ var (
 )
 
 // StringSchedule is a schedule generated from a cron string.
+//
+// The fields are matched against wall clock times in Location, or UTC if it is not set.
+// See `Schedule` for how daylight saving time transitions are handled.
 type StringSchedule struct {
 	Original string
+	Location *time.Location
 
 	Seconds     []int
 	Minutes     []int
@@ -179,167 +223,53 @@ func (ss *StringSchedule) FullString() string {
 }
 
 // Next implements cron.Schedule.
+//
+// It searches forward from the second after `after` for the first wall clock time whose
+// components match the schedule; a component that does not match advances the search to
+// the start of the next value of that component.
 func (ss *StringSchedule) Next(after time.Time) time.Time {
-	working := after
 	if after.IsZero() {
-		working = Now()
+		after = Now()
 	}
-	original := working
 
-	if len(ss.Years) > 0 {
-		for _, year := range ss.Years {
-			if year >= working.Year() {
-				working = advanceYearTo(working, year)
-				break
-			}
-		}
-	}
-
-	if len(ss.Months) > 0 {
-		var didSet bool
-		for _, month := range ss.Months {
-			if time.Month(month) == working.Month() && working.After(original) {
-				didSet = true
-				break
-			}
-			if time.Month(month) > working.Month() {
-				working = advanceMonthTo(working, time.Month(month))
-				didSet = true
-				break
+	working := wallClock(inLocation(after, ss.Location)).Truncate(time.Second).Add(time.Second)
+	limit := working.AddDate(stringScheduleSearchYears, 0, 0)
+	for working.Before(limit) {
+		if !containsInt(ss.Years, working.Year()) {
+			if len(ss.Years) > 0 && working.Year() > ss.Years[len(ss.Years)-1] {
+				return Zero
 			}
-		}
-		if !didSet {
 			working = advanceYear(working)
-			for _, month := range ss.Months {
-				if time.Month(month) >= working.Month() {
-					working = advanceMonthTo(working, time.Month(month))
-					break
-				}
-			}
-		}
-	}
-
-	if len(ss.DaysOfMonth) > 0 {
-		var didSet bool
-		for _, day := range ss.DaysOfMonth {
-			if day == working.Day() && working.After(original) {
-				didSet = true
-				break
-			}
-			if day > working.Day() {
-				working = advanceDayTo(working, day)
-				didSet = true
-				break
-			}
+			continue
 		}
-		if !didSet {
+		if !containsInt(ss.Months, int(working.Month())) {
 			working = advanceMonth(working)
-			for _, day := range ss.DaysOfMonth {
-				if day >= working.Day() {
-					working = advanceDayTo(working, day)
-					break
-				}
-			}
-		}
-	}
-
-	if len(ss.DaysOfWeek) > 0 {
-		var didSet bool
-		for _, dow := range ss.DaysOfWeek {
-			if dow == int(working.Weekday()) && working.After(original) {
-				didSet = true
-				break
-			}
-			if dow > int(working.Weekday()) {
-				working = advanceDayBy(working, (dow - int(working.Weekday())))
-				didSet = true
-				break
-			}
-		}
-		if !didSet {
-			working = advanceToNextSunday(working)
-			for _, dow := range ss.DaysOfWeek {
-				if dow >= int(working.Weekday()) {
-					working = advanceDayBy(working, (dow - int(working.Weekday())))
-					break
-				}
-			}
-		}
-	}
-
-	if len(ss.Hours) > 0 {
-		var didSet bool
-		for _, hour := range ss.Hours {
-			if hour == working.Hour() && working.After(original) {
-				didSet = true
-				break
-			}
-			if hour > working.Hour() {
-				working = advanceHourTo(working, hour)
-				didSet = true
-				break
-			}
+			continue
 		}
-		if !didSet {
+		if !containsInt(ss.DaysOfMonth, working.Day()) || !containsInt(ss.DaysOfWeek, int(working.Weekday())) {
 			working = advanceDay(working)
-			for _, hour := range ss.Hours {
-				if hour >= working.Hour() {
-					working = advanceHourTo(working, hour)
-					break
-				}
-			}
-		}
-	}
-
-	if len(ss.Minutes) > 0 {
-		var didSet bool
-		for _, minute := range ss.Minutes {
-			if minute == working.Minute() && working.After(original) {
-				didSet = true
-				break
-			}
-			if minute > working.Minute() {
-				working = advanceMinuteTo(working, minute)
-				didSet = true
-				break
-			}
+			continue
 		}
-		if !didSet {
+		if !containsInt(ss.Hours, working.Hour()) {
 			working = advanceHour(working)
-			for _, minute := range ss.Minutes {
-				if minute >= working.Minute() {
-					working = advanceMinuteTo(working, minute)
-					break
-				}
-			}
-		}
-	}
-
-	if len(ss.Seconds) > 0 {
-		var didSet bool
-		for _, second := range ss.Seconds {
-			if second == working.Second() && working.After(original) {
-				didSet = true
-				break
-			}
-			if second > working.Second() {
-				working = advanceSecondTo(working, second)
-				didSet = true
-				break
-			}
+			continue
 		}
-		if !didSet {
+		if !containsInt(ss.Minutes, working.Minute()) {
 			working = advanceMinute(working)
-			for _, second := range ss.Hours {
-				if second >= working.Second() {
-					working = advanceSecondTo(working, second)
-					break
-				}
-			}
+			continue
+		}
+		if !containsInt(ss.Seconds, working.Second()) {
+			working = working.Add(time.Second)
+			continue
 		}
+		// the wall clock time may have occurred before `after` if it was repeated
+		// by a daylight saving time transition, in which case keep searching.
+		if next := wallTime(ss.Location, working); next.After(after) {
+			return next
+		}
+		working = working.Add(time.Second)
 	}
-
-	return working
+	return Zero
 }
 
 func parsePart(values string, parser func(string) (int, error), validator func(int) bool) ([]int, error) {
@@ -474,6 +404,15 @@ func between(min, max int) func(int) bool {
 	}
 }
 
+// containsInt returns if a sorted list of values contains a value, or if the list is empty (i.e. `*`).
+func containsInt(values []int, value int) bool {
+	if len(values) == 0 {
+		return true
+	}
+	index := sort.SearchInts(values, value)
+	return index < len(values) && values[index] == value
+}
+
 func mapKeysToArray(values map[int]bool) []int {
 	output := make([]int, len(values))
 	var index int
@@ -493,55 +432,22 @@ func advanceYear(t time.Time) time.Time {
 	return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location()).AddDate(1, 0, 0)
 }
 
-func advanceYearTo(t time.Time, year int) time.Time {
-	return time.Date(year, 1, 1, 0, 0, 0, 0, t.Location())
-}
-
 func advanceMonth(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location()).AddDate(0, 1, 0)
 }
 
-func advanceMonthTo(t time.Time, month time.Month) time.Time {
-	return time.Date(t.Year(), month, 1, 0, 0, 0, 0, t.Location())
-}
-
-func advanceDayTo(t time.Time, day int) time.Time {
-	return time.Date(t.Year(), t.Month(), day, 0, 0, 0, 0, t.Location())
-}
-
-func advanceToNextSunday(t time.Time) time.Time {
-	daysUntilSunday := 7 - int(t.Weekday())
-	return t.AddDate(0, 0, daysUntilSunday)
-}
-
 func advanceDay(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, 1)
 }
 
-func advanceDayBy(t time.Time, days int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).AddDate(0, 0, days)
-}
-
 func advanceHour(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location()).Add(time.Hour)
 }
 
-func advanceHourTo(t time.Time, hour int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), hour, 0, 0, 0, t.Location())
-}
-
 func advanceMinute(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, t.Location()).Add(time.Minute)
 }
 
-func advanceMinuteTo(t time.Time, minute int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, 0, 0, t.Location())
-}
-
-func advanceSecondTo(t time.Time, second int) time.Time {
-	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), second, 0, t.Location())
-}
-
 func csvOfInts(values []int, placeholder string) string {
 	if len(values) == 0 {
 		return placeholder
@@ -553,6 +459,10 @@ func csvOfInts(values []int, placeholder string) string {
 	return strings.Join(valueStrings, ",")
 }
 
+// stringScheduleSearchYears is how far ahead string schedules search for a next runtime
+// before concluding there is none (e.g. `0 0 0 30 2 *`); leap days can be eight years apart.
+const stringScheduleSearchYears = 9
+
 // these are special characters
 const (
 	cronSpecialComma    = ',' //
@@ -57,6 +57,61 @@ func TestParseString(t *testing.T) {
 	}
 }
 
+func TestParseStringLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	testCases := []stringScheduleTestCase{
+		{Input: "CRON_TZ=America/New_York 0 0 9 * * * *", After: time.Date(2019, 03, 9, 14, 0, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 13, 0, 0, 0, time.UTC)},  // 9am across spring forward
+		{Input: "CRON_TZ=America/New_York 0 0 9 * * * *", After: time.Date(2019, 03, 10, 13, 0, 0, 0, time.UTC), Expected: time.Date(2019, 03, 11, 13, 0, 0, 0, time.UTC)}, // 9am after spring forward
+		{Input: "CRON_TZ=America/New_York 0 0 9 * * * *", After: time.Date(2019, 11, 2, 13, 0, 0, 0, time.UTC), Expected: time.Date(2019, 11, 3, 14, 0, 0, 0, time.UTC)},   // 9am across fall back
+		{Input: "CRON_TZ=America/New_York 0 * * * * *", After: time.Date(2019, 03, 10, 6, 59, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 7, 0, 0, 0, time.UTC)},    // every minute into the gap
+		{Input: "CRON_TZ=America/New_York 0 * * * * *", After: time.Date(2019, 03, 10, 7, 0, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 7, 1, 0, 0, time.UTC)},     // every minute after the gap
+		{Input: "CRON_TZ=America/New_York 0 * * * * *", After: time.Date(2019, 11, 3, 5, 59, 0, 0, time.UTC), Expected: time.Date(2019, 11, 3, 7, 0, 0, 0, time.UTC)},      // every minute skips the repeated hour
+		{Input: "CRON_TZ=America/New_York 0 10 1 * * *", After: time.Date(2019, 11, 3, 6, 5, 0, 0, time.UTC), Expected: time.Date(2019, 11, 4, 6, 10, 0, 0, time.UTC)},     // started in the repeated hour
+		{Input: "CRON_TZ=America/New_York 0 0 20 * * MON", After: time.Date(2019, 07, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2019, 07, 2, 0, 0, 0, 0, time.UTC)},    // local day of week
+		{Input: "TZ=Europe/Berlin 0 30 2 * * *", After: time.Date(2019, 03, 30, 1, 30, 0, 0, time.UTC), Expected: time.Date(2019, 03, 31, 1, 30, 0, 0, time.UTC)},          // skipped time runs once
+		{Input: "TZ=Europe/Berlin 0 30 2 * * *", After: time.Date(2019, 03, 31, 1, 30, 0, 0, time.UTC), Expected: time.Date(2019, 04, 1, 0, 30, 0, 0, time.UTC)},           // after skipped time
+		{Input: "TZ=Europe/Berlin 0 30 2 * * *", After: time.Date(2019, 10, 26, 0, 30, 0, 0, time.UTC), Expected: time.Date(2019, 10, 27, 0, 30, 0, 0, time.UTC)},          // repeated time runs the first time
+		{Input: "TZ=Europe/Berlin 0 30 2 * * *", After: time.Date(2019, 10, 27, 0, 30, 0, 0, time.UTC), Expected: time.Date(2019, 10, 28, 1, 30, 0, 0, time.UTC)},          // repeated time does not run twice
+		{Input: "CRON_TZ=Asia/Kolkata @daily", After: time.Date(2019, 07, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2019, 07, 1, 18, 30, 0, 0, time.UTC)},              // shorthand
+		{Input: "CRON_TZ=America/New_York @every 1h", After: time.Date(2019, 03, 10, 6, 30, 0, 0, time.UTC), Expected: time.Date(2019, 03, 10, 7, 30, 0, 0, time.UTC)},     // intervals are not affected
+		{Input: "0 0 0 29 2 *", After: time.Date(2019, 01, 1, 0, 0, 0, 0, time.UTC), Expected: time.Date(2020, 02, 29, 0, 0, 0, 0, time.UTC)},                              // leap day
+		{Input: "0 0 0 30 2 *", After: time.Date(2019, 01, 1, 0, 0, 0, 0, time.UTC), Expected: Zero},                                                                       // never
+		{Input: "0 0 0 1 1 * 2019", After: time.Date(2019, 06, 1, 0, 0, 0, 0, time.UTC), Expected: Zero},                                                                   // past years
+		{Input: "CRON_TZ=Not/AZone 0 0 9 * * *", ExpectedErr: ErrStringScheduleInvalid},
+		{Input: "CRON_TZ=America/New_York", ExpectedErr: ErrStringScheduleInvalid},
+	}
+
+	for _, tc := range testCases {
+		parsed, err := ParseString(tc.Input)
+		if tc.ExpectedErr != nil {
+			assert.NotNil(err, tc.Input)
+			assert.True(ex.Is(err, tc.ExpectedErr), tc.Input)
+			continue
+		}
+		assert.Nil(err, tc.Input)
+		assert.Equal(tc.Expected, parsed.Next(tc.After), tc.Input)
+	}
+}
+
+func TestParseStringInLocation(t *testing.T) {
+	assert := assert.New(t)
+
+	newYork := mustLoadLocation(t, "America/New_York")
+	after := time.Date(2019, 07, 1, 0, 0, 0, 0, time.UTC)
+
+	schedule, err := ParseStringInLocation("0 0 9 * * *", newYork)
+	assert.Nil(err)
+	assert.Equal(time.Date(2019, 07, 1, 13, 0, 0, 0, time.UTC), schedule.Next(after))
+
+	// the prefix takes precedence.
+	schedule, err = ParseStringInLocation("CRON_TZ=Europe/Berlin 0 0 9 * * *", newYork)
+	assert.Nil(err)
+	assert.Equal(time.Date(2019, 07, 1, 7, 0, 0, 0, time.UTC), schedule.Next(after))
+	assert.Equal("CRON_TZ=Europe/Berlin 0 0 9 * * *", schedule.(*StringSchedule).String())
+	assert.Equal("Europe/Berlin", schedule.(*StringSchedule).Location.String())
+}
+
 func TestStringScheduleEvery(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,409 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// Interface assertions.
+var (
+	_ Job                        = (*Workflow)(nil)
+	_ ScheduleProvider           = (*Workflow)(nil)
+	_ HistoryStateProvider       = (*Workflow)(nil)
+	_ InvocationStateProvider    = (*Workflow)(nil)
+	_ WaitOnCancellationProvider = (*Workflow)(nil)
+)
+
+// NewWorkflow returns a new workflow.
+func NewWorkflow(name string, options ...WorkflowOption) *Workflow {
+	w := &Workflow{
+		name: name,
+	}
+	for _, option := range options {
+		option(w)
+	}
+	return w
+}
+
+// WorkflowOption is an option for workflows.
+type WorkflowOption func(*Workflow)
+
+// OptWorkflowStep adds a step to the workflow that runs a loaded job after its dependencies finish.
+// Steps without dependencies run as soon as the workflow starts.
+func OptWorkflowStep(jobName string, dependsOn ...WorkflowDependency) WorkflowOption {
+	return func(w *Workflow) {
+		w.Steps = append(w.Steps, WorkflowStep{JobName: jobName, DependsOn: dependsOn})
+	}
+}
+
+// OptWorkflowSchedule sets the workflow schedule.
+func OptWorkflowSchedule(schedule Schedule) WorkflowOption {
+	return func(w *Workflow) { w.schedule = schedule }
+}
+
+// WorkflowCondition is a condition on the outcome of an upstream step.
+type WorkflowCondition string
+
+// Workflow conditions.
+const (
+	// WorkflowConditionSuccess is satisfied if the upstream step completed.
+	WorkflowConditionSuccess WorkflowCondition = "success"
+	// WorkflowConditionFailure is satisfied if the upstream step failed.
+	WorkflowConditionFailure WorkflowCondition = "failure"
+	// WorkflowConditionComplete is satisfied if the upstream step ran, regardless of its outcome.
+	WorkflowConditionComplete WorkflowCondition = "complete"
+)
+
+// AfterSuccess returns a dependency on a step that must complete successfully.
+func AfterSuccess(jobName string) WorkflowDependency {
+	return WorkflowDependency{JobName: jobName, Condition: WorkflowConditionSuccess}
+}
+
+// AfterFailure returns a dependency on a step that must fail.
+func AfterFailure(jobName string) WorkflowDependency {
+	return WorkflowDependency{JobName: jobName, Condition: WorkflowConditionFailure}
+}
+
+// AfterCompletion returns a dependency on a step that must run, regardless of its outcome.
+func AfterCompletion(jobName string) WorkflowDependency {
+	return WorkflowDependency{JobName: jobName, Condition: WorkflowConditionComplete}
+}
+
+// WorkflowDependency is a dependency of a workflow step on an upstream step.
+type WorkflowDependency struct {
+	JobName   string            `json:"jobName"`
+	Condition WorkflowCondition `json:"condition"`
+}
+
+// IsSatisfied returns if the dependency is satisfied by the status of the upstream step.
+func (wd WorkflowDependency) IsSatisfied(status JobStatus) bool {
+	switch wd.Condition {
+	case WorkflowConditionFailure:
+		return status == JobStatusFailed
+	case WorkflowConditionComplete:
+		return status == JobStatusComplete || status == JobStatusFailed || status == JobStatusCancelled
+	default:
+		return status == JobStatusComplete
+	}
+}
+
+// WorkflowStep is a job in a workflow and the steps it depends on.
+type WorkflowStep struct {
+	JobName   string               `json:"jobName"`
+	DependsOn []WorkflowDependency `json:"dependsOn,omitempty"`
+}
+
+// Workflow is a graph of loaded jobs that run when the jobs they depend on finish.
+//
+// A workflow is itself a job; when it is loaded with `JobManager.LoadWorkflows` it runs on its
+// own schedule or on demand like any other job, and its invocations are the workflow runs.
+// Each run starts the steps without dependencies, and then starts each downstream step once all
+// of its upstream steps have finished, if every dependency condition is satisfied. Steps whose
+// conditions are not satisfied are skipped, as are the steps that depend on them.
+//
+// The jobs in a workflow keep their own schedules, if they have them, and run independently of it.
+// A run fails if any of its steps fail.
+type Workflow struct {
+	Steps []WorkflowStep
+
+	name     string
+	schedule Schedule
+	jobs     map[string]*JobScheduler
+}
+
+// Name implements Job.
+func (w *Workflow) Name() string {
+	return w.name
+}
+
+// Schedule implements ScheduleProvider.
+func (w *Workflow) Schedule() Schedule {
+	return w.schedule
+}
+
+// HistoryState implements HistoryStateProvider.
+func (w *Workflow) HistoryState() interface{} {
+	return &WorkflowRun{}
+}
+
+// InvocationState implements InvocationStateProvider, and returns the run for a workflow invocation.
+func (w *Workflow) InvocationState(ji *JobInvocation) interface{} {
+	run := NewWorkflowRun(w)
+	run.ID = ji.ID
+	return run
+}
+
+// WaitOnCancellation implements WaitOnCancellationProvider.
+// Cancelled runs wait for their running steps, which are cancelled with them, so the run records their final status.
+func (w *Workflow) WaitOnCancellation() bool {
+	return true
+}
+
+// Validate validates the workflow's steps, and returns `ErrWorkflowCycle`
+// if the dependencies between them are not acyclic.
+func (w *Workflow) Validate() error {
+	if w.name == "" {
+		return ex.New(ErrWorkflowInvalid, ex.OptMessage("workflow name is required"))
+	}
+	if len(w.Steps) == 0 {
+		return ex.New(ErrWorkflowInvalid, ex.OptMessagef("workflow: %s, at least one step is required", w.name))
+	}
+	steps := make(map[string]WorkflowStep)
+	for _, step := range w.Steps {
+		if _, ok := steps[step.JobName]; ok {
+			return ex.New(ErrWorkflowInvalid, ex.OptMessagef("workflow: %s, duplicate step: %s", w.name, step.JobName))
+		}
+		steps[step.JobName] = step
+	}
+	for _, step := range w.Steps {
+		for _, dependency := range step.DependsOn {
+			if _, ok := steps[dependency.JobName]; !ok {
+				return ex.New(ErrWorkflowInvalid, ex.OptMessagef("workflow: %s, step: %s, unknown dependency: %s", w.name, step.JobName, dependency.JobName))
+			}
+		}
+	}
+
+	// depth first search for back edges, in step order so errors are stable.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(string) error
+	visit = func(jobName string) error {
+		switch state[jobName] {
+		case visited:
+			return nil
+		case visiting:
+			for index := range path {
+				if path[index] == jobName {
+					cycle := append(append([]string{}, path[index:]...), jobName)
+					return ex.New(ErrWorkflowCycle, ex.OptMessagef("workflow: %s, cycle: %s", w.name, strings.Join(cycle, " > ")))
+				}
+			}
+		}
+		state[jobName] = visiting
+		path = append(path, jobName)
+		for _, dependency := range steps[jobName].DependsOn {
+			if err := visit(dependency.JobName); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[jobName] = visited
+		return nil
+	}
+	for _, step := range w.Steps {
+		if err := visit(step.JobName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute implements Job, and runs the workflow's steps.
+// Cancelling the context cancels the running steps, and skips the steps that have not started.
+func (w *Workflow) Execute(ctx context.Context) error {
+	// the scheduler sets the run as the invocation state before the invocation starts.
+	var run *WorkflowRun
+	if ji := GetJobInvocation(ctx); ji != nil {
+		run, _ = ji.State.(*WorkflowRun)
+	}
+	if run == nil {
+		run = NewWorkflowRun(w)
+	}
+
+	results := make(chan workflowStepResult, len(w.Steps))
+	var running int
+	for {
+		if ctx.Err() == nil {
+			for _, jobName := range run.next() {
+				running++
+				go func(jobName string) {
+					results <- workflowStepResult{JobName: jobName, Invocation: w.jobs[jobName].RunContext(ctx)}
+				}(jobName)
+			}
+		}
+		if running == 0 {
+			break
+		}
+		result := <-results
+		running--
+		run.finish(result.JobName, result.Invocation)
+	}
+	run.skipPending()
+
+	if err := ctx.Err(); err != nil {
+		return ex.New(ErrJobCancelled, ex.OptMessagef("workflow: %s", w.name))
+	}
+	if failed := run.Failed(); len(failed) > 0 {
+		return ex.New(ErrWorkflowStepFailed, ex.OptMessagef("workflow: %s, failed steps: %s", w.name, strings.Join(failed, ", ")))
+	}
+	return nil
+}
+
+// NewWorkflowRun returns a new workflow run with every step pending.
+func NewWorkflowRun(w *Workflow) *WorkflowRun {
+	run := &WorkflowRun{
+		Workflow: w.name,
+		Steps:    make([]WorkflowStepRun, len(w.Steps)),
+	}
+	for index, step := range w.Steps {
+		run.Steps[index] = WorkflowStepRun{WorkflowStep: step, Status: JobStatusPending}
+	}
+	return run
+}
+
+// WorkflowRun is the state of a workflow invocation, and is set as the invocation's `State`.
+type WorkflowRun struct {
+	sync.Mutex
+	// ID is the id of the workflow invocation.
+	ID       string            `json:"id"`
+	Workflow string            `json:"workflow"`
+	Steps    []WorkflowStepRun `json:"steps"`
+}
+
+// WorkflowStepRun is the state of a step in a workflow run.
+type WorkflowStepRun struct {
+	WorkflowStep
+	Status       JobStatus     `json:"status"`
+	InvocationID string        `json:"invocationID,omitempty"`
+	Started      time.Time     `json:"started,omitempty"`
+	Finished     time.Time     `json:"finished,omitempty"`
+	Elapsed      time.Duration `json:"elapsed,omitempty"`
+	ErrMessage   string        `json:"errMessage,omitempty"`
+}
+
+// Snapshot returns a copy of the run that is safe to read while the run continues.
+func (wr *WorkflowRun) Snapshot() *WorkflowRun {
+	wr.Lock()
+	defer wr.Unlock()
+	return &WorkflowRun{
+		ID:       wr.ID,
+		Workflow: wr.Workflow,
+		Steps:    append([]WorkflowStepRun{}, wr.Steps...),
+	}
+}
+
+// Failed returns the names of the failed steps.
+func (wr *WorkflowRun) Failed() (output []string) {
+	wr.Lock()
+	defer wr.Unlock()
+	for _, step := range wr.Steps {
+		if step.Status == JobStatusFailed {
+			output = append(output, step.JobName)
+		}
+	}
+	return
+}
+
+// MarshalJSON implements json.Marshaler.
+func (wr *WorkflowRun) MarshalJSON() ([]byte, error) {
+	snapshot := wr.Snapshot()
+	return json.Marshal(struct {
+		ID       string            `json:"id"`
+		Workflow string            `json:"workflow"`
+		Steps    []WorkflowStepRun `json:"steps"`
+	}{
+		ID:       snapshot.ID,
+		Workflow: snapshot.Workflow,
+		Steps:    snapshot.Steps,
+	})
+}
+
+// next marks the pending steps whose upstream steps have all finished as running, and returns them.
+// Steps with an unsatisfied dependency are skipped, which can in turn finish other steps' upstream steps.
+func (wr *WorkflowRun) next() (output []string) {
+	wr.Lock()
+	defer wr.Unlock()
+
+	for changed := true; changed; {
+		changed = false
+		for index := range wr.Steps {
+			step := &wr.Steps[index]
+			if step.Status != JobStatusPending {
+				continue
+			}
+			ready, satisfied := true, true
+			for _, dependency := range step.DependsOn {
+				status := wr.statusUnsafe(dependency.JobName)
+				if status == JobStatusPending || status == JobStatusRunning {
+					ready = false
+					break
+				}
+				satisfied = satisfied && dependency.IsSatisfied(status)
+			}
+			if !ready {
+				continue
+			}
+			if satisfied {
+				step.Status = JobStatusRunning
+				step.Started = Now()
+				output = append(output, step.JobName)
+			} else {
+				step.Status = JobStatusSkipped
+				changed = true
+			}
+		}
+	}
+	return
+}
+
+// finish records the finished invocation of a step.
+// A nil invocation means the step's job was not allowed to run (e.g. it is disabled), and the step is skipped.
+func (wr *WorkflowRun) finish(jobName string, ji *JobInvocation) {
+	wr.Lock()
+	defer wr.Unlock()
+	for index := range wr.Steps {
+		step := &wr.Steps[index]
+		if step.JobName != jobName {
+			continue
+		}
+		if ji == nil {
+			step.Status = JobStatusSkipped
+			step.Started = time.Time{}
+			continue
+		}
+		step.Status = ji.Status
+		step.InvocationID = ji.ID
+		step.Started = ji.Started
+		step.Finished = ji.Finished
+		step.Elapsed = ji.Elapsed
+		if ji.Err != nil {
+			step.ErrMessage = ji.Err.Error()
+		}
+	}
+}
+
+// skipPending marks the steps that never started as skipped.
+func (wr *WorkflowRun) skipPending() {
+	wr.Lock()
+	defer wr.Unlock()
+	for index := range wr.Steps {
+		if wr.Steps[index].Status == JobStatusPending {
+			wr.Steps[index].Status = JobStatusSkipped
+		}
+	}
+}
+
+// workflowStepResult is the result of running a workflow step.
+type workflowStepResult struct {
+	JobName    string
+	Invocation *JobInvocation
+}
+
+func (wr *WorkflowRun) statusUnsafe(jobName string) JobStatus {
+	for _, step := range wr.Steps {
+		if step.JobName == jobName {
+			return step.Status
+		}
+	}
+	return JobStatusSkipped
+}
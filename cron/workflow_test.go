@@ -0,0 +1,304 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestWorkflowValidate(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(IsWorkflowInvalid(NewWorkflow("").Validate()))
+	assert.True(IsWorkflowInvalid(NewWorkflow("empty").Validate()))
+	assert.True(IsWorkflowInvalid(NewWorkflow("duplicate",
+		OptWorkflowStep("a"),
+		OptWorkflowStep("a"),
+	).Validate()))
+	assert.True(IsWorkflowInvalid(NewWorkflow("unknown",
+		OptWorkflowStep("a", AfterSuccess("b")),
+	).Validate()))
+
+	err := NewWorkflow("cycle",
+		OptWorkflowStep("a"),
+		OptWorkflowStep("b", AfterSuccess("a"), AfterSuccess("d")),
+		OptWorkflowStep("c", AfterSuccess("b")),
+		OptWorkflowStep("d", AfterFailure("c")),
+	).Validate()
+	assert.True(IsWorkflowCycle(err))
+	assert.Contains(ex.ErrMessage(err), "b > d > c > b")
+
+	assert.True(IsWorkflowCycle(NewWorkflow("self", OptWorkflowStep("a", AfterSuccess("a"))).Validate()))
+
+	assert.Nil(NewWorkflow("diamond",
+		OptWorkflowStep("a"),
+		OptWorkflowStep("b", AfterSuccess("a")),
+		OptWorkflowStep("c", AfterSuccess("a")),
+		OptWorkflowStep("d", AfterSuccess("b"), AfterSuccess("c")),
+	).Validate())
+}
+
+func TestWorkflowDependencyIsSatisfied(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(AfterSuccess("a").IsSatisfied(JobStatusComplete))
+	assert.False(AfterSuccess("a").IsSatisfied(JobStatusFailed))
+	assert.False(AfterSuccess("a").IsSatisfied(JobStatusSkipped))
+	assert.True(AfterFailure("a").IsSatisfied(JobStatusFailed))
+	assert.False(AfterFailure("a").IsSatisfied(JobStatusComplete))
+	assert.True(AfterCompletion("a").IsSatisfied(JobStatusComplete))
+	assert.True(AfterCompletion("a").IsSatisfied(JobStatusFailed))
+	assert.False(AfterCompletion("a").IsSatisfied(JobStatusSkipped))
+}
+
+func TestJobManagerLoadWorkflows(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("a", noop), NewJob("b", noop)))
+
+	assert.True(IsJobNotLoaded(jm.LoadWorkflows(NewWorkflow("missing", OptWorkflowStep("a"), OptWorkflowStep("c")))))
+	assert.True(IsWorkflowCycle(jm.LoadWorkflows(NewWorkflow("cycle",
+		OptWorkflowStep("a", AfterSuccess("b")),
+		OptWorkflowStep("b", AfterSuccess("a")),
+	))))
+	assert.True(IsJobAlreadyLoaded(jm.LoadWorkflows(NewWorkflow("a", OptWorkflowStep("b")))))
+
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("test", OptWorkflowStep("a"), OptWorkflowStep("b", AfterSuccess("a")))))
+	assert.True(jm.HasJob("test"))
+	assert.NotNil(jm.Workflows["test"])
+}
+
+func TestJobManagerLoadWorkflowsStarted(t *testing.T) {
+	assert := assert.New(t)
+
+	ran := make(chan struct{}, 1)
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("a", func(_ context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	})))
+	assert.Nil(jm.StartAsync())
+	defer jm.Stop()
+
+	// workflows loaded after the manager starts run on their schedule.
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("test", OptWorkflowStep("a"), OptWorkflowSchedule(Every(10*time.Millisecond)))))
+	select {
+	case <-ran:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("the workflow should run on its schedule")
+	}
+}
+
+func TestWorkflowInvocationState(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("slow", func(_ context.Context) error {
+		close(started)
+		<-proceed
+		return nil
+	})))
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("pipeline", OptWorkflowStep("slow"))))
+	pipeline, err := jm.Job("pipeline")
+	assert.Nil(err)
+
+	done := make(chan *JobInvocation)
+	go func() { done <- pipeline.RunContext(context.Background()) }()
+	<-started
+
+	// the run is set before the invocation is current.
+	current := pipeline.CurrentInvocation()
+	assert.NotNil(current)
+	run, ok := current.State.(*WorkflowRun)
+	assert.True(ok)
+	assert.Equal(current.ID, run.ID)
+	close(proceed)
+	assert.Equal(JobStatusComplete, (<-done).Status)
+}
+
+func TestWorkflowFanOutFanIn(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	finished := map[string]bool{}
+	var ordered []string
+	step := func(name string, upstream ...string) Job {
+		return NewJob(name, func(_ context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			for _, jobName := range upstream {
+				if !finished[jobName] {
+					return fmt.Errorf("%s ran before %s", name, jobName)
+				}
+			}
+			finished[name] = true
+			ordered = append(ordered, name)
+			return nil
+		})
+	}
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		step("export"),
+		step("transform-a", "export"),
+		step("transform-b", "export"),
+		step("upload", "transform-a", "transform-b"),
+	))
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("pipeline",
+		OptWorkflowStep("export"),
+		OptWorkflowStep("transform-a", AfterSuccess("export")),
+		OptWorkflowStep("transform-b", AfterSuccess("export")),
+		OptWorkflowStep("upload", AfterSuccess("transform-a"), AfterSuccess("transform-b")),
+	)))
+	assert.Nil(jm.RunJobs("pipeline"))
+
+	assert.Len(ordered, 4)
+	assert.Equal("export", ordered[0])
+	assert.Equal("upload", ordered[3])
+
+	pipeline, err := jm.Job("pipeline")
+	assert.Nil(err)
+	assert.NotNil(pipeline.Last)
+	assert.Equal(JobStatusComplete, pipeline.Last.Status)
+
+	run, ok := pipeline.Last.State.(*WorkflowRun)
+	assert.True(ok)
+	assert.Equal(pipeline.Last.ID, run.ID)
+	for _, stepRun := range run.Steps {
+		assert.Equal(JobStatusComplete, stepRun.Status, stepRun.JobName)
+		assert.NotEmpty(stepRun.InvocationID)
+	}
+}
+
+func TestWorkflowConditions(t *testing.T) {
+	assert := assert.New(t)
+
+	var lock sync.Mutex
+	ran := map[string]bool{}
+	step := func(name string, err error) Job {
+		return NewJob(name, func(_ context.Context) error {
+			lock.Lock()
+			defer lock.Unlock()
+			ran[name] = true
+			return err
+		})
+	}
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		step("export", fmt.Errorf("this is only a test")),
+		step("upload", nil),
+		step("notify", nil),
+		step("rollback", nil),
+		step("cleanup", nil),
+		step("disabled", nil),
+		step("after-disabled", nil),
+	))
+	assert.Nil(jm.DisableJobs("disabled"))
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("pipeline",
+		OptWorkflowStep("export"),
+		OptWorkflowStep("upload", AfterSuccess("export")),
+		OptWorkflowStep("notify", AfterSuccess("upload")),
+		OptWorkflowStep("rollback", AfterFailure("export")),
+		OptWorkflowStep("cleanup", AfterCompletion("export")),
+		OptWorkflowStep("disabled"),
+		OptWorkflowStep("after-disabled", AfterCompletion("disabled")),
+	)))
+	assert.Nil(jm.RunJobs("pipeline"))
+
+	assert.True(ran["export"])
+	assert.False(ran["upload"])
+	assert.False(ran["notify"])
+	assert.True(ran["rollback"])
+	assert.True(ran["cleanup"])
+	assert.False(ran["disabled"])
+	assert.False(ran["after-disabled"])
+
+	pipeline, err := jm.Job("pipeline")
+	assert.Nil(err)
+	assert.Equal(JobStatusFailed, pipeline.Last.Status)
+	assert.True(ex.Is(pipeline.Last.Err, ErrWorkflowStepFailed))
+
+	statuses := map[string]JobStatus{}
+	for _, stepRun := range pipeline.Last.State.(*WorkflowRun).Steps {
+		statuses[stepRun.JobName] = stepRun.Status
+	}
+	assert.Equal(JobStatusFailed, statuses["export"])
+	assert.Equal(JobStatusSkipped, statuses["upload"])
+	assert.Equal(JobStatusSkipped, statuses["notify"])
+	assert.Equal(JobStatusComplete, statuses["rollback"])
+	assert.Equal(JobStatusComplete, statuses["cleanup"])
+	assert.Equal(JobStatusSkipped, statuses["disabled"])
+	assert.Equal(JobStatusSkipped, statuses["after-disabled"])
+}
+
+func TestWorkflowCancel(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan struct{})
+	jm := New()
+	assert.Nil(jm.LoadJobs(
+		NewJob("slow", func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}),
+		NewJob("next", noop),
+	))
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("pipeline",
+		OptWorkflowStep("slow"),
+		OptWorkflowStep("next", AfterCompletion("slow")),
+	)))
+
+	pipeline, err := jm.Job("pipeline")
+	assert.Nil(err)
+	done := make(chan *JobInvocation)
+	go func() { done <- pipeline.RunContext(context.Background()) }()
+	<-started
+	assert.Nil(jm.CancelJob("pipeline"))
+	ji := <-done
+
+	assert.Equal(JobStatusCancelled, ji.Status)
+	run := ji.State.(*WorkflowRun)
+	assert.Equal(JobStatusCancelled, run.Steps[0].Status)
+	assert.Equal(JobStatusSkipped, run.Steps[1].Status)
+}
+
+func TestJobManagerStatusWorkflows(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := New()
+	assert.Nil(jm.LoadJobs(NewJob("a", noop), NewJob("b", noop)))
+	assert.Nil(jm.LoadWorkflows(NewWorkflow("test", OptWorkflowStep("a"), OptWorkflowStep("b", AfterSuccess("a")))))
+
+	status := jm.Status()
+	assert.Len(status.Workflows, 1)
+	assert.Equal("test", status.Workflows[0].Name)
+	assert.Len(status.Workflows[0].Steps, 2)
+	assert.Nil(status.Workflows[0].Last)
+
+	assert.Nil(jm.RunJobs("test"))
+	status = jm.Status()
+	assert.NotNil(status.Workflows[0].Last)
+	assert.Len(status.Workflows[0].Last.Steps, 2)
+
+	contents, err := json.Marshal(status.Workflows[0].Last)
+	assert.Nil(err)
+	var restored WorkflowRun
+	assert.Nil(json.Unmarshal(contents, &restored))
+	assert.Equal("test", restored.Workflow)
+	assert.Equal(JobStatusComplete, restored.Steps[1].Status)
+	assert.Equal("a", restored.Steps[1].DependsOn[0].JobName)
+}
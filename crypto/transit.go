@@ -0,0 +1,15 @@
+package crypto
+
+import "io"
+
+// assert LocalTransit implements Transit.
+var (
+	_ Transit = (*LocalTransit)(nil)
+)
+
+// Transit encrypts and decrypts streams, either with local keys or with a remote key service.
+// Code that depends on Transit can swap implementations by config.
+type Transit interface {
+	Encrypt(dst io.Writer, src io.Reader) error
+	Decrypt(dst io.Writer, src io.Reader) error
+}
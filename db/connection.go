@@ -65,6 +65,9 @@ func Open(conn *Connection, err error) (*Connection, error) {
 }
 
 // Connection is the basic wrapper for connection parameters and saves a reference to the created sql.Connection.
+//
+// The `Connection` and `PlanCache` fields are replaced when credentials are rotated, so once the connection
+// is open they should be read with `GetConnection` and `GetPlanCache`.
 type Connection struct {
 	sync.Mutex
 	Config               Config
@@ -74,16 +77,35 @@ type Connection struct {
 	BufferPool           *bufferutil.Pool
 	Log                  logger.Log
 	PlanCache            *PlanCache
+	// CredentialRotator, if set, issues the connection credentials from a vault database engine role
+	// and replaces the underlying `Connection` and `PlanCache` with ones using new credentials before they expire.
+	CredentialRotator *CredentialRotator
+
+	poolMu sync.RWMutex
 }
 
 // Close implements a closer.
+// If the connection has a credential rotator, it is stopped, and pools it replaced are closed
+// and the credentials leases revoked, before close returns.
 func (dbc *Connection) Close() error {
-	if dbc.PlanCache != nil {
-		if err := dbc.PlanCache.Close(); err != nil {
+	if dbc.CredentialRotator != nil && dbc.CredentialRotator.CanStop() {
+		if err := dbc.CredentialRotator.Stop(); err != nil {
 			return err
 		}
 	}
-	return dbc.Connection.Close()
+	conn, planCache := dbc.pool()
+	if planCache != nil {
+		if err := planCache.Close(); err != nil {
+			return err
+		}
+	}
+	if err := conn.Close(); err != nil {
+		return err
+	}
+	if dbc.CredentialRotator != nil {
+		return dbc.CredentialRotator.Close(context.Background())
+	}
+	return nil
 }
 
 // Open returns a connection object, either a cached connection object or creating a new one in the process.
@@ -92,7 +114,7 @@ func (dbc *Connection) Open() error {
 	defer dbc.Unlock()
 
 	// bail if we've already opened the connection.
-	if dbc.Connection != nil {
+	if dbc.GetConnection() != nil {
 		return Error(ErrConnectionAlreadyOpen)
 	}
 	if dbc.Config.IsZero() {
@@ -101,31 +123,105 @@ func (dbc *Connection) Open() error {
 	if dbc.BufferPool == nil {
 		dbc.BufferPool = bufferutil.NewPool(dbc.Config.BufferPoolSizeOrDefault())
 	}
-	if dbc.PlanCache == nil {
-		dbc.PlanCache = NewPlanCache()
+	username, password := dbc.Config.Username, dbc.Config.Password
+	if dbc.CredentialRotator != nil {
+		credentials, err := dbc.CredentialRotator.Issue(context.Background())
+		if err != nil {
+			return err
+		}
+		username, password = credentials.Username, credentials.Password
 	}
 
-	dsn := dbc.Config.CreateDSN()
-	namedValues, err := ParseURL(dsn)
+	// open the connection
+	dbConn, err := dbc.openPool(username, password)
 	if err != nil {
 		return err
 	}
 
-	// open the connection
-	dbConn, err := sql.Open(dbc.Config.EngineOrDefault(), namedValues)
-	if err != nil {
-		return Error(err)
+	dbc.poolMu.Lock()
+	if dbc.PlanCache == nil {
+		dbc.PlanCache = NewPlanCache()
 	}
-
 	dbc.PlanCache.WithConnection(dbConn)
 	dbc.PlanCache.WithEnabled(!dbc.Config.PlanCacheDisabled)
 	dbc.Connection = dbConn
-	dbc.Connection.SetConnMaxLifetime(dbc.Config.MaxLifetimeOrDefault())
-	dbc.Connection.SetMaxIdleConns(dbc.Config.IdleConnectionsOrDefault())
-	dbc.Connection.SetMaxOpenConns(dbc.Config.MaxConnectionsOrDefault())
+	dbc.poolMu.Unlock()
+
+	if dbc.CredentialRotator != nil && dbc.CredentialRotator.CanStart() {
+		dbc.CredentialRotator.Conn = dbc
+		dbc.CredentialRotator.Log = dbc.Log
+		started := dbc.CredentialRotator.NotifyStarted()
+		go dbc.CredentialRotator.Start()
+		<-started
+	}
 	return nil
 }
 
+// openPool opens a driver connection pool from the config with a given username and password.
+func (dbc *Connection) openPool(username, password string) (*sql.DB, error) {
+	cfg := dbc.Config
+	if username != cfg.Username || password != cfg.Password {
+		if cfg.DSN != "" {
+			resolved, err := cfg.Resolve()
+			if err != nil {
+				return nil, err
+			}
+			cfg.DSN = ""
+			cfg.Host, cfg.Port, cfg.Database = resolved.Host, resolved.Port, resolved.Database
+			cfg.SSLMode, cfg.ConnectTimeout = resolved.SSLMode, resolved.ConnectTimeout
+		}
+		cfg.Username, cfg.Password = username, password
+	}
+
+	namedValues, err := ParseURL(cfg.CreateDSN())
+	if err != nil {
+		return nil, err
+	}
+	dbConn, err := sql.Open(cfg.EngineOrDefault(), namedValues)
+	if err != nil {
+		return nil, Error(err)
+	}
+	dbConn.SetConnMaxLifetime(cfg.MaxLifetimeOrDefault())
+	dbConn.SetMaxIdleConns(cfg.IdleConnectionsOrDefault())
+	dbConn.SetMaxOpenConns(cfg.MaxConnectionsOrDefault())
+	return dbConn, nil
+}
+
+// GetConnection returns the driver connection pool.
+// It is nil until the connection is opened, and is replaced when credentials are rotated.
+func (dbc *Connection) GetConnection() *sql.DB {
+	conn, _ := dbc.pool()
+	return conn
+}
+
+// GetPlanCache returns the plan cache for the driver connection pool.
+// It is replaced with the pool when credentials are rotated.
+func (dbc *Connection) GetPlanCache() *PlanCache {
+	_, planCache := dbc.pool()
+	return planCache
+}
+
+// pool returns the driver connection pool and the plan cache for it.
+// They are read together as they are replaced together when credentials are rotated.
+func (dbc *Connection) pool() (*sql.DB, *PlanCache) {
+	dbc.poolMu.RLock()
+	defer dbc.poolMu.RUnlock()
+	return dbc.Connection, dbc.PlanCache
+}
+
+// swapPool replaces the driver connection pool, and the plan cache with a new one for it,
+// returning the previous pool and plan cache so they can be closed once drained.
+func (dbc *Connection) swapPool(conn *sql.DB) (previous *sql.DB, previousPlanCache *PlanCache) {
+	dbc.poolMu.Lock()
+	defer dbc.poolMu.Unlock()
+	previous, previousPlanCache = dbc.Connection, dbc.PlanCache
+	dbc.Connection = conn
+	if previousPlanCache != nil {
+		dbc.PlanCache = NewPlanCache().WithConnection(conn).WithEnabled(previousPlanCache.Enabled())
+	}
+	return
+}
+
 // Begin starts a new transaction.
 func (dbc *Connection) Begin(opts ...*sql.TxOptions) (*sql.Tx, error) {
 	return dbc.BeginContext(context.Background(), opts...)
@@ -133,14 +229,15 @@ func (dbc *Connection) Begin(opts ...*sql.TxOptions) (*sql.Tx, error) {
 
 // BeginContext starts a new transaction in a givent context.
 func (dbc *Connection) BeginContext(context context.Context, opts ...*sql.TxOptions) (*sql.Tx, error) {
-	if dbc.Connection == nil {
+	conn, _ := dbc.pool()
+	if conn == nil {
 		return nil, ex.New(ErrConnectionClosed)
 	}
 	if len(opts) > 0 {
-		tx, err := dbc.Connection.BeginTx(context, opts[0])
+		tx, err := conn.BeginTx(context, opts[0])
 		return tx, Error(err)
 	}
-	tx, err := dbc.Connection.BeginTx(context, nil)
+	tx, err := conn.BeginTx(context, nil)
 	return tx, Error(err)
 }
 
@@ -156,11 +253,12 @@ func (dbc *Connection) PrepareContext(context context.Context, cachedPlanKey, st
 		stmt, err = tx.PrepareContext(context, statement)
 		return
 	}
-	if dbc.PlanCache != nil && dbc.PlanCache.Enabled() && cachedPlanKey != "" {
-		stmt, err = dbc.PlanCache.PrepareContext(context, cachedPlanKey, statement)
+	conn, planCache := dbc.pool()
+	if planCache != nil && planCache.Enabled() && cachedPlanKey != "" {
+		stmt, err = planCache.PrepareContext(context, cachedPlanKey, statement)
 		return
 	}
-	stmt, err = dbc.Connection.PrepareContext(context, statement)
+	stmt, err = conn.PrepareContext(context, statement)
 	return
 }
 
@@ -185,7 +283,8 @@ func (dbc *Connection) Invoke(options ...InvocationOption) *Invocation {
 
 // Ping checks the db connection.
 func (dbc *Connection) Ping() error {
-	return Error(dbc.Connection.Ping())
+	conn, _ := dbc.pool()
+	return Error(conn.Ping())
 }
 
 // PingContext checks the db connection.
@@ -199,7 +298,8 @@ func (dbc *Connection) PingContext(context context.Context) (err error) {
 		}
 	}
 
-	err = Error(dbc.Connection.PingContext(context))
+	conn, _ := dbc.pool()
+	err = Error(conn.PingContext(context))
 	return
 }
 
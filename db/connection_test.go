@@ -64,15 +64,15 @@ func TestConnectionStatementCacheExecute(t *testing.T) {
 	a.Nil(err)
 	a.Nil(conn.Open())
 	defer conn.Close()
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 
 	a.Nil(conn.Exec("select 'ok!'"))
 	a.Nil(conn.Exec("select 'ok!'"))
-	a.False(conn.PlanCache.HasStatement("select 'ok!'"))
+	a.False(conn.GetPlanCache().HasStatement("select 'ok!'"))
 
 	a.Nil(conn.Invoke(OptCachedPlanKey("ping")).Exec("select 'ok!'"))
 	a.Nil(conn.Invoke(OptCachedPlanKey("ping")).Exec("select 'ok!'"))
-	a.True(conn.PlanCache.HasStatement("ping"))
+	a.True(conn.GetPlanCache().HasStatement("ping"))
 }
 
 func TestConnectionStatementCacheQuery(t *testing.T) {
@@ -83,7 +83,7 @@ func TestConnectionStatementCacheQuery(t *testing.T) {
 	a.Nil(conn.Open())
 	defer conn.Close()
 
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 
 	var ok string
 	a.Nil(conn.Invoke(OptCachedPlanKey("status")).Query("select 'ok!'").Scan(&ok))
@@ -92,7 +92,7 @@ func TestConnectionStatementCacheQuery(t *testing.T) {
 	a.Nil(conn.Invoke(OptCachedPlanKey("status")).Query("select 'ok!'").Scan(&ok))
 	a.Equal("ok!", ok)
 
-	a.True(conn.PlanCache.HasStatement("status"))
+	a.True(conn.GetPlanCache().HasStatement("status"))
 }
 
 func TestConnectionOpen(t *testing.T) {
@@ -104,8 +104,8 @@ func TestConnectionOpen(t *testing.T) {
 	defer conn.Close()
 
 	a.NotNil(conn.BufferPool)
-	a.NotNil(conn.Connection)
-	a.NotNil(conn.PlanCache)
+	a.NotNil(conn.GetConnection())
+	a.NotNil(conn.GetPlanCache())
 }
 
 func TestExec(t *testing.T) {
@@ -126,7 +126,7 @@ func TestConnectionInvalidatesBadCachedStatements(t *testing.T) {
 	assert.Nil(conn.Open())
 	defer conn.Close()
 
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 
 	createTableStatement := `CREATE TABLE state_invalidation (id int not null, name varchar(64))`
 	insertStatement := `INSERT INTO state_invalidation (id, name) VALUES ($1, $2)`
@@ -47,4 +47,7 @@ const (
 	DefaultMaxLifetime = time.Duration(0)
 	// DefaultBufferPoolSize is the default number of buffer pool entries to maintain.
 	DefaultBufferPoolSize = 1024
+
+	// DefaultCredentialDrainDelay is the default time a replaced connection pool is kept open before it is closed.
+	DefaultCredentialDrainDelay = 30 * time.Second
 )
@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/secrets"
+)
+
+/*
+NewCredentialRotator returns a new credential rotator that issues connection credentials for a vault database engine role.
+
+Set it on a connection with `OptCredentialRotator` or `OptVaultCredentials`; the connection then opens with issued
+credentials instead of the config username and password, and starts the rotator, which renews the credentials lease
+and, before the lease reaches its max ttl, swaps the connection pool for one using newly issued credentials.
+
+Example:
+
+	conn, err := db.Open(db.New(
+		db.OptConfig(cfg),
+		db.OptVaultCredentials(vaultClient, "app-readwrite"),
+	))
+*/
+func NewCredentialRotator(client *secrets.VaultClient, role string, options ...CredentialRotatorOption) *CredentialRotator {
+	cr := CredentialRotator{
+		Client: client,
+		Role:   role,
+		closed: make(chan struct{}),
+	}
+	cr.Renewer = secrets.NewRenewer(cr.acquire, cr.renew)
+	for _, option := range options {
+		option(&cr)
+	}
+	return &cr
+}
+
+// CredentialRotatorOption is an option for a credential rotator.
+type CredentialRotatorOption func(*CredentialRotator)
+
+// OptCredentialRotatorRenewFraction sets the fraction of the lease ttl the rotator waits before renewing the lease.
+func OptCredentialRotatorRenewFraction(fraction float64) CredentialRotatorOption {
+	return func(cr *CredentialRotator) {
+		cr.RenewFraction = fraction
+	}
+}
+
+// OptCredentialRotatorRetryDelay sets the delay before the rotator retries a failed rotation.
+func OptCredentialRotatorRetryDelay(delay time.Duration) CredentialRotatorOption {
+	return func(cr *CredentialRotator) {
+		cr.RetryDelay = delay
+	}
+}
+
+// OptCredentialRotatorIncrement sets the lease ttl increment requested on renewal.
+func OptCredentialRotatorIncrement(increment time.Duration) CredentialRotatorOption {
+	return func(cr *CredentialRotator) {
+		cr.Increment = increment
+	}
+}
+
+// OptCredentialRotatorDrainDelay sets how long a replaced connection pool is kept open before it is closed.
+func OptCredentialRotatorDrainDelay(delay time.Duration) CredentialRotatorOption {
+	return func(cr *CredentialRotator) {
+		cr.DrainDelay = delay
+	}
+}
+
+// OptCredentialRotatorErrors sets a channel the rotator sends renewal and rotation errors to.
+// Errors are dropped if the channel is not ready to receive them.
+func OptCredentialRotatorErrors(errors chan error) CredentialRotatorOption {
+	return func(cr *CredentialRotator) {
+		cr.Errors = errors
+	}
+}
+
+// CredentialRotator is a background worker that keeps a connection's vault issued credentials valid.
+//
+// It is started and stopped with the embedded renewer, which waits the renew fraction of the lease ttl
+// before renewing it, and the retry delay before retrying a failed rotation.
+//
+// Replacing the credentials opens a new pool and swaps it into the connection, so new queries use the
+// new credentials. The previous pool is closed after the drain delay, which waits for the queries that
+// were started on it to finish, and then its lease is revoked.
+type CredentialRotator struct {
+	*secrets.Renewer
+	Client *secrets.VaultClient
+	// Role is the database engine role credentials are issued for.
+	Role       string
+	Increment  time.Duration
+	DrainDelay time.Duration
+	// Conn is the connection the rotator swaps pools on; it is set when the connection is opened.
+	Conn *Connection
+
+	rotateMu      sync.Mutex
+	credentialsMu sync.Mutex
+	credentials   *secrets.DatabaseCredentials
+
+	drains    sync.WaitGroup
+	closeOnce sync.Once
+	// closed is closed when the rotator is closed, to end the drain delays of replaced pools.
+	closed chan struct{}
+}
+
+// DrainDelayOrDefault returns the drain delay or a default.
+func (cr *CredentialRotator) DrainDelayOrDefault() time.Duration {
+	if cr.DrainDelay > 0 {
+		return cr.DrainDelay
+	}
+	return DefaultCredentialDrainDelay
+}
+
+// Credentials returns the current credentials, or nil if none have been issued.
+func (cr *CredentialRotator) Credentials() *secrets.DatabaseCredentials {
+	cr.credentialsMu.Lock()
+	defer cr.credentialsMu.Unlock()
+	return cr.credentials
+}
+
+// Issue issues new credentials for the role and makes them the current credentials.
+func (cr *CredentialRotator) Issue(ctx context.Context) (*secrets.DatabaseCredentials, error) {
+	if cr.Client == nil {
+		return nil, ex.New(ErrCredentialRotatorClientUnset)
+	}
+	credentials, err := cr.Client.Database.Credentials(ctx, cr.Role)
+	if err != nil {
+		return nil, err
+	}
+	cr.setCredentials(credentials)
+	return credentials, nil
+}
+
+// Rotate issues new credentials, opens a pool with them and swaps it into the connection.
+// The previous pool is drained and closed in the background.
+func (cr *CredentialRotator) Rotate(ctx context.Context) error {
+	if cr.Client == nil {
+		return ex.New(ErrCredentialRotatorClientUnset)
+	}
+	if cr.Conn == nil {
+		return ex.New(ErrConnectionClosed)
+	}
+	cr.rotateMu.Lock()
+	defer cr.rotateMu.Unlock()
+
+	previous := cr.Credentials()
+	credentials, err := cr.Client.Database.Credentials(ctx, cr.Role)
+	if err != nil {
+		return err
+	}
+	pool, err := cr.Conn.openPool(credentials.Username, credentials.Password)
+	if err != nil {
+		return err
+	}
+	if err = pool.PingContext(ctx); err != nil {
+		pool.Close()
+		return Error(err)
+	}
+	cr.setCredentials(credentials)
+	previousPool, previousPlanCache := cr.Conn.swapPool(pool)
+	logger.MaybeInfof(cr.Conn.Log, "db: rotated credentials for role: %s", cr.Role)
+	cr.drains.Add(1)
+	go func() {
+		defer cr.drains.Done()
+		cr.drain(previousPool, previousPlanCache, previous)
+	}()
+	return nil
+}
+
+// Close ends the drain delays of replaced pools and waits for them to be closed and their leases revoked,
+// then revokes the lease of the current credentials. It is called by the connection when it is closed,
+// after the rotator is stopped and the current pool is closed.
+func (cr *CredentialRotator) Close(ctx context.Context) error {
+	cr.closeOnce.Do(func() { close(cr.closed) })
+	cr.drains.Wait()
+
+	credentials := cr.Credentials()
+	if credentials == nil || credentials.LeaseID == "" || cr.Client == nil {
+		return nil
+	}
+	if err := cr.Client.RevokeLease(ctx, credentials.LeaseID); err != nil {
+		return err
+	}
+	cr.setCredentials(nil)
+	return nil
+}
+
+// acquire returns the ttl of the credentials issued when the connection was opened.
+func (cr *CredentialRotator) acquire(_ context.Context) (time.Duration, error) {
+	return cr.ttl(), nil
+}
+
+// renew refreshes the credentials before their lease ends.
+func (cr *CredentialRotator) renew(ctx context.Context) (time.Duration, error) {
+	if err := cr.refresh(ctx); err != nil {
+		return 0, err
+	}
+	return cr.ttl(), nil
+}
+
+// ttl returns the lease ttl of the current credentials.
+func (cr *CredentialRotator) ttl() time.Duration {
+	if credentials := cr.Credentials(); credentials != nil {
+		return time.Duration(credentials.LeaseDuration) * time.Second
+	}
+	return 0
+}
+
+// refresh renews the credentials lease if it is renewable, and rotates the credentials if it isn't,
+// if renewal fails, or if the renewed ttl is shorter than the previous ttl because the lease is reaching its max ttl.
+func (cr *CredentialRotator) refresh(ctx context.Context) error {
+	previous := cr.Credentials()
+	if previous != nil && previous.Renewable {
+		lease, err := cr.Client.RenewLease(ctx, previous.LeaseID, cr.Increment)
+		if err != nil {
+			cr.HandleError(err)
+		} else if lease.LeaseDuration >= previous.LeaseDuration {
+			renewed := *previous
+			renewed.Lease = *lease
+			cr.setCredentials(&renewed)
+			return nil
+		}
+	}
+	return cr.Rotate(ctx)
+}
+
+// drain closes a replaced pool after the drain delay, so queries that got the pool before it was replaced
+// can start, then revokes the lease of its credentials. The delay ends early if the rotator is closed.
+func (cr *CredentialRotator) drain(pool *sql.DB, planCache *PlanCache, credentials *secrets.DatabaseCredentials) {
+	delay := time.NewTimer(cr.DrainDelayOrDefault())
+	defer delay.Stop()
+	select {
+	case <-delay.C:
+	case <-cr.closed:
+	}
+	if planCache != nil {
+		if err := planCache.Close(); err != nil {
+			cr.HandleError(Error(err))
+		}
+	}
+	if pool != nil {
+		// close waits for the queries that have started on the pool to finish.
+		if err := pool.Close(); err != nil {
+			cr.HandleError(Error(err))
+		}
+	}
+	if credentials != nil && credentials.LeaseID != "" {
+		if err := cr.Client.RevokeLease(context.Background(), credentials.LeaseID); err != nil {
+			cr.HandleError(err)
+		}
+	}
+}
+
+func (cr *CredentialRotator) setCredentials(credentials *secrets.DatabaseCredentials) {
+	cr.credentialsMu.Lock()
+	cr.credentials = credentials
+	cr.credentialsMu.Unlock()
+}
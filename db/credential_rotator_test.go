@@ -0,0 +1,189 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/secrets"
+)
+
+// mockDatabaseEngine issues the test database credentials with a new lease each time.
+type mockDatabaseEngine struct {
+	sync.Mutex
+	Config        Config
+	LeaseDuration int
+	RenewDuration int
+	Issued        int
+	Renewed       []string
+	Revoked       []string
+}
+
+func (mde *mockDatabaseEngine) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	mde.Lock()
+	defer mde.Unlock()
+
+	var input secrets.LeaseRenewInput
+	if req.Body != nil {
+		json.NewDecoder(req.Body).Decode(&input)
+	}
+	switch req.URL.Path {
+	case "/v1/database/creds/app":
+		mde.Issued++
+		json.NewEncoder(rw).Encode(map[string]interface{}{
+			"lease_id":       fmt.Sprintf("database/creds/app/%d", mde.Issued),
+			"lease_duration": mde.LeaseDuration,
+			"renewable":      true,
+			"data":           map[string]string{"username": mde.Config.Username, "password": mde.Config.Password},
+		})
+	case "/v1/sys/leases/renew":
+		mde.Renewed = append(mde.Renewed, input.LeaseID)
+		json.NewEncoder(rw).Encode(secrets.Lease{LeaseID: input.LeaseID, LeaseDuration: mde.RenewDuration, Renewable: true})
+	case "/v1/sys/leases/revoke":
+		mde.Revoked = append(mde.Revoked, input.LeaseID)
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (mde *mockDatabaseEngine) counts() (issued, renewed, revoked int) {
+	mde.Lock()
+	defer mde.Unlock()
+	return mde.Issued, len(mde.Renewed), len(mde.Revoked)
+}
+
+func waitFor(predicate func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if predicate() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestCredentialRotatorRotate(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	cfg, err := NewConfigFromEnv()
+	assert.Nil(err)
+	engine := &mockDatabaseEngine{Config: cfg, LeaseDuration: 3600, RenewDuration: 3600}
+	server := httptest.NewServer(engine)
+	defer server.Close()
+	client, err := secrets.New(secrets.OptRemote(server.URL))
+	assert.Nil(err)
+
+	conn, err := Open(New(OptConfig(cfg), OptVaultCredentials(client, "app", OptCredentialRotatorDrainDelay(time.Millisecond))))
+	assert.Nil(err)
+	defer conn.Close()
+	assert.True(conn.CredentialRotator.IsStarted())
+	assert.Equal("database/creds/app/1", conn.CredentialRotator.Credentials().LeaseID)
+	assert.Nil(conn.Invoke(OptCachedPlanKey("rotate")).Exec("select 'ok!'"))
+
+	previous, previousPlanCache := conn.pool()
+	tx, err := conn.Begin()
+	assert.Nil(err)
+
+	assert.Nil(conn.CredentialRotator.Rotate(todo))
+	assert.Equal("database/creds/app/2", conn.CredentialRotator.Credentials().LeaseID)
+	current, currentPlanCache := conn.pool()
+	assert.True(current != previous)
+	assert.True(currentPlanCache != previousPlanCache)
+	assert.False(currentPlanCache.HasStatement("rotate"))
+
+	// the previous pool is drained and its lease revoked, but the transaction started on it isn't dropped.
+	assert.True(waitFor(func() bool {
+		_, _, revoked := engine.counts()
+		return revoked == 1
+	}))
+	engine.Lock()
+	assert.Equal([]string{"database/creds/app/1"}, engine.Revoked)
+	engine.Unlock()
+	assert.Nil(tx.Exec("select 'ok!'"))
+	assert.Nil(tx.Commit())
+	assert.NotNil(previous.Ping())
+
+	assert.Nil(conn.Invoke(OptCachedPlanKey("rotate")).Exec("select 'ok!'"))
+	assert.True(currentPlanCache.HasStatement("rotate"))
+}
+
+func TestCredentialRotatorRenews(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg, err := NewConfigFromEnv()
+	assert.Nil(err)
+	engine := &mockDatabaseEngine{Config: cfg, LeaseDuration: 1, RenewDuration: 1}
+	server := httptest.NewServer(engine)
+	defer server.Close()
+	client, err := secrets.New(secrets.OptRemote(server.URL))
+	assert.Nil(err)
+
+	rotator := NewCredentialRotator(client, "app", OptCredentialRotatorRenewFraction(0.1), OptCredentialRotatorDrainDelay(time.Millisecond))
+	conn, err := Open(New(OptConfig(cfg), OptCredentialRotator(rotator)))
+	assert.Nil(err)
+	defer conn.Close()
+
+	// renewals that keep the lease ttl keep the credentials.
+	assert.True(waitFor(func() bool {
+		_, renewed, _ := engine.counts()
+		return renewed >= 2
+	}))
+	issued, _, _ := engine.counts()
+	assert.Equal(1, issued)
+
+	// renewals that shorten the lease ttl mean it is reaching its max ttl, so the credentials are rotated.
+	engine.Lock()
+	engine.RenewDuration = 0
+	engine.Unlock()
+	assert.True(waitFor(func() bool {
+		issued, _, revoked := engine.counts()
+		return issued >= 2 && revoked >= 1
+	}))
+	assert.Nil(conn.Exec("select 'ok!'"))
+}
+
+func TestCredentialRotatorClose(t *testing.T) {
+	assert := assert.New(t)
+
+	cfg, err := NewConfigFromEnv()
+	assert.Nil(err)
+	engine := &mockDatabaseEngine{Config: cfg, LeaseDuration: 3600, RenewDuration: 3600}
+	server := httptest.NewServer(engine)
+	defer server.Close()
+	client, err := secrets.New(secrets.OptRemote(server.URL))
+	assert.Nil(err)
+
+	conn, err := Open(New(OptConfig(cfg), OptVaultCredentials(client, "app", OptCredentialRotatorDrainDelay(time.Hour))))
+	assert.Nil(err)
+	previous, _ := conn.pool()
+	assert.Nil(conn.CredentialRotator.Rotate(context.TODO()))
+
+	// closing doesn't wait for the drain delay, and revokes both the replaced and current leases before it returns.
+	assert.Nil(conn.Close())
+	assert.False(conn.CredentialRotator.IsStarted())
+	assert.NotNil(previous.Ping())
+	assert.Nil(conn.CredentialRotator.Credentials())
+	engine.Lock()
+	assert.Equal([]string{"database/creds/app/1", "database/creds/app/2"}, engine.Revoked)
+	engine.Unlock()
+}
+
+func TestCredentialRotatorClientUnset(t *testing.T) {
+	assert := assert.New(t)
+
+	conn, err := New(OptConfigFromEnv(), OptCredentialRotator(NewCredentialRotator(nil, "app")))
+	assert.Nil(err)
+	err = conn.Open()
+	assert.True(ex.Is(err, ErrCredentialRotatorClientUnset))
+	assert.Nil(conn.GetConnection())
+}
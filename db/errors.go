@@ -31,6 +31,8 @@ const (
 	ErrNoPrimaryKey ex.Class = "db: no primary key on object"
 	// ErrRowsNotColumnsProvider is returned by `PopulateByName` if you do not pass in `sql.Rows` as the scanner.
 	ErrRowsNotColumnsProvider ex.Class = "db: rows is not a columns provider"
+	// ErrCredentialRotatorClientUnset is an error returned by a credential rotator without a secrets client.
+	ErrCredentialRotatorClientUnset ex.Class = "db: the credential rotator secrets client is unset"
 )
 
 // IsConfigUnset returns if the error is an `ErrConfigUnset`.
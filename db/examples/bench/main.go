@@ -66,8 +66,8 @@ func reportStats(log logger.Log, conn *db.Connection) {
 	for {
 		select {
 		case <-ticker:
-			log.Infof("[%d] connections currently open", conn.Connection.Stats().OpenConnections)
-			log.Infof("[%v] wait duration", conn.Connection.Stats().WaitDuration)
+			log.Infof("[%d] connections currently open", conn.GetConnection().Stats().OpenConnections)
+			log.Infof("[%v] wait duration", conn.GetConnection().Stats().WaitDuration)
 		}
 	}
 }
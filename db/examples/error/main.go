@@ -15,7 +15,7 @@ func main() {
 	}
 	conn.Open()
 
-	_, err = conn.Connection.Query("select * from foo")
+	_, err = conn.GetConnection().Query("select * from foo")
 	fmt.Printf("error: %#v\n", err)
 	fmt.Printf("parsed: %#v\n", db.Error(err))
 }
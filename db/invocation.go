@@ -276,7 +276,8 @@ func (i *Invocation) CreateMany(objects interface{}) (err error) {
 	if i.Tx != nil {
 		_, err = i.Tx.ExecContext(i.Context, queryBody, colValues...)
 	} else {
-		_, err = i.Conn.Connection.ExecContext(i.Context, queryBody, colValues...)
+		conn, _ := i.Conn.pool()
+		_, err = conn.ExecContext(i.Context, queryBody, colValues...)
 	}
 	if err != nil {
 		err = Error(err)
@@ -843,7 +844,7 @@ func (i *Invocation) CloseStatement(stmt *sql.Stmt, err error) error {
 		return err
 	}
 	// if the statement is cached, DO NOT CLOSE THE STATEMENT.
-	if i.Conn.PlanCache != nil && i.Conn.PlanCache.Enabled() && i.CachedPlanKey != "" {
+	if _, planCache := i.Conn.pool(); planCache != nil && planCache.Enabled() && i.CachedPlanKey != "" {
 		return err
 	}
 	// close the statement.
@@ -122,10 +122,10 @@ func TestInvocationExecError(t *testing.T) {
 
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Exec("not a select"))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Exec("not a select"))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("exec_error_test")).Exec("not a select"))
 }
@@ -144,10 +144,10 @@ func TestInvocationGetError(t *testing.T) {
 	var getError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Get(&getError, uuid.V4().String()))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Get(&getError, uuid.V4().String()))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("get_error_test")).Get(&getError, uuid.V4().String()))
 }
@@ -158,10 +158,10 @@ func TestInvocationGetAllError(t *testing.T) {
 	var mustError []modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().All(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().All(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("get_all_error_test")).All(&mustError))
 }
@@ -172,10 +172,10 @@ func TestInvocationCreateError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Create(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Create(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("create_error_test")).Create(&mustError))
 }
@@ -186,10 +186,10 @@ func TestInvocationCreateIfNotExistsError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().CreateIfNotExists(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().CreateIfNotExists(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("cne_error_test")).CreateIfNotExists(&mustError))
 }
@@ -200,10 +200,10 @@ func TestInvocationUpdateError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Update(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Update(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("update_error_test")).Update(&mustError))
 }
@@ -214,10 +214,10 @@ func TestInvocationUpsertError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Upsert(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Upsert(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("upsert_error_test")).Upsert(&mustError))
 }
@@ -232,10 +232,10 @@ func TestInvocationExistsError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(boolErr(conn.Invoke().Exists(mustError)))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(boolErr(conn.Invoke().Exists(mustError)))
 	assert.NotNil(boolErr(conn.Invoke(OptCachedPlanKey("exists_error_test")).Exists(mustError)))
 }
@@ -247,7 +247,7 @@ func TestInvocationCreateManyEmpty(t *testing.T) {
 
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.Nil(conn.Invoke().CreateMany(objs))
 }
@@ -261,10 +261,10 @@ func TestInvocationCreateManyError(t *testing.T) {
 	}
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().CreateMany(mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().CreateMany(mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("cm_error_test")).CreateMany(mustError))
 }
@@ -275,10 +275,10 @@ func TestInvocationDeleteError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Delete(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Delete(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("delete_error_test")).Delete(&mustError))
 }
@@ -289,10 +289,10 @@ func TestTruncateError(t *testing.T) {
 	var mustError modelTableNameError
 	conn, err := New(OptConfigFromEnv())
 	assert.Nil(err)
-	conn.PlanCache.WithEnabled(false)
+	conn.GetPlanCache().WithEnabled(false)
 	assert.Nil(conn.Open())
 	assert.NotNil(conn.Invoke().Truncate(&mustError))
-	conn.PlanCache.WithEnabled(true)
+	conn.GetPlanCache().WithEnabled(true)
 	assert.NotNil(conn.Invoke().Truncate(&mustError))
 	assert.NotNil(conn.Invoke(OptCachedPlanKey("truncate_error_test")).Truncate(&mustError))
 }
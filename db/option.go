@@ -4,6 +4,7 @@ import (
 	"database/sql"
 
 	"github.com/blend/go-sdk/logger"
+	"github.com/blend/go-sdk/secrets"
 )
 
 // Option is an option for database connections.
@@ -60,3 +61,17 @@ func OptConfigFromEnv() Option {
 		return nil
 	}
 }
+
+// OptCredentialRotator sets the credential rotator that issues and rotates the connection credentials.
+func OptCredentialRotator(rotator *CredentialRotator) Option {
+	return func(c *Connection) error {
+		c.CredentialRotator = rotator
+		return nil
+	}
+}
+
+// OptVaultCredentials sets the connection to use credentials issued for a vault database engine role
+// instead of the config username and password, and to rotate them before they expire.
+func OptVaultCredentials(client *secrets.VaultClient, role string, options ...CredentialRotatorOption) Option {
+	return OptCredentialRotator(NewCredentialRotator(client, role, options...))
+}
@@ -127,7 +127,7 @@ func TestMultipleQueriesPerTransaction(t *testing.T) {
 	wg := sync.WaitGroup{}
 	wg.Add(3)
 
-	a.NotNil(Default().Connection)
+	a.NotNil(Default().GetConnection())
 
 	err = seedObjects(10, nil)
 	a.Nil(err)
@@ -174,7 +174,7 @@ func TestMultipleQueriesPerTransactionWithFailure(t *testing.T) {
 	wg := sync.WaitGroup{}
 	wg.Add(3)
 
-	a.NotNil(Default().Connection)
+	a.NotNil(Default().GetConnection())
 
 	go func() {
 		defer wg.Done()
@@ -1,6 +1,7 @@
 package fileutil
 
 import (
+	"context"
 	"os"
 	"time"
 
@@ -12,11 +13,23 @@ const (
 	ErrWatchStopped ex.Class = "watch file should stop"
 )
 
+// DefaultWatchInterval is the default interval files are polled on for changes.
+const DefaultWatchInterval = 500 * time.Millisecond
+
 // Watch watches a file for changes and calls the action if there are changes.
 // It does this by polling the file for ModTime changes every 500ms.
 // It is not designed for watching a large number of files.
 // You should probably call this within a go routine.
 func Watch(path string, action func(*os.File) error) error {
+	return WatchContext(context.Background(), path, DefaultWatchInterval, action)
+}
+
+// WatchContext watches a file for changes like `Watch`, polling it on a given interval,
+// and returns nil when the context is done. Intervals of zero or less use the default interval.
+func WatchContext(ctx context.Context, path string, interval time.Duration, action func(*os.File) error) error {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
 	stat, err := os.Stat(path)
 	if err != nil {
 		return ex.New(err)
@@ -24,10 +37,13 @@ func Watch(path string, action func(*os.File) error) error {
 
 	lastMod := stat.ModTime()
 
-	ticker := time.Tick(500 * time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
 	for {
 		select {
-		case <-ticker:
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
 			stat, err = os.Stat(path)
 			if err != nil {
 				return ex.New(err)
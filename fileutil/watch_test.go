@@ -0,0 +1,65 @@
+package fileutil
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestWatchContext(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := NewTemp([]byte("before"))
+	assert.Nil(err)
+	defer f.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchContext(ctx, f.Name(), 10*time.Millisecond, func(changedFile *os.File) error {
+			changedFile.Close()
+			changed <- struct{}{}
+			return nil
+		})
+	}()
+
+	// wait for the watch to stat the file before changing it.
+	time.Sleep(50 * time.Millisecond)
+	modTime := time.Now().Add(time.Second)
+	assert.Nil(os.Chtimes(f.Name(), modTime, modTime))
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		assert.FailNow("watch action was not called")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.Nil(err)
+	case <-time.After(5 * time.Second):
+		assert.FailNow("watch did not stop")
+	}
+}
+
+func TestWatchContextNotExist(t *testing.T) {
+	assert := assert.New(t)
+	assert.NotNil(WatchContext(context.Background(), "not-a-file", time.Millisecond, func(*os.File) error { return nil }))
+}
+
+func TestWatchContextDefaultInterval(t *testing.T) {
+	assert := assert.New(t)
+
+	f, err := NewTemp([]byte("contents"))
+	assert.Nil(err)
+	defer f.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Nil(WatchContext(ctx, f.Name(), 0, func(*os.File) error { return nil }))
+	assert.Nil(WatchContext(ctx, f.Name(), -time.Second, func(*os.File) error { return nil }))
+}
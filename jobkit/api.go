@@ -0,0 +1,321 @@
+package jobkit
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/web"
+)
+
+// AddAPIRoutes adds the versioned json api routes to a management server app.
+//
+// If the config sets `UseSessionAuth`, the routes require a session from the app's auth manager,
+// which is set from the web config or with `web.OptAuth(...)`.
+func AddAPIRoutes(app *web.App, jm *cron.JobManager, cfg Config) {
+	middleware := apiMiddleware(cfg)
+
+	app.GET(APIPrefix+"/jobs", func(_ *web.Ctx) web.Result {
+		status := jm.Status()
+		output := make([]JobModel, 0, len(status.Jobs))
+		for _, job := range status.Jobs {
+			output = append(output, NewJobModel(job))
+		}
+		return web.JSON.Result(output)
+	}, middleware...)
+	app.GET(APIPrefix+"/job/:jobName", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return apiErrorResult(err)
+		}
+		return web.JSON.Result(NewJobModel(job))
+	}, middleware...)
+	app.GET(APIPrefix+"/job/:jobName/history", func(r *web.Ctx) web.Result {
+		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+		if err != nil {
+			return apiErrorResult(err)
+		}
+		limit, err := queryInt(r, "limit")
+		if err != nil {
+			return apiErrorResult(err)
+		}
+		history := job.InvocationHistory()
+		if limit > 0 && len(history) > limit {
+			history = history[len(history)-limit:]
+		}
+		output := make([]cron.HistoryEntry, 0, len(history))
+		for index := range history {
+			output = append(output, NewInvocationModel(&history[index], false))
+		}
+		return web.JSON.Result(output)
+	}, middleware...)
+	app.GET(APIPrefix+"/job/:jobName/invocation/:invocation", func(r *web.Ctx) web.Result {
+		invocation, result := apiInvocation(jm, r)
+		if result != nil {
+			return result
+		}
+		return web.JSON.Result(NewInvocationModel(invocation, true))
+	}, middleware...)
+	app.GET(APIPrefix+"/job/:jobName/invocation/:invocation/output", func(r *web.Ctx) web.Result {
+		invocation, result := apiInvocation(jm, r)
+		if result != nil {
+			return result
+		}
+		query, err := invocationOutputQuery(r)
+		if err != nil {
+			return apiErrorResult(err)
+		}
+		output, err := NewInvocationOutput(invocation, query)
+		if err != nil {
+			return apiErrorResult(err)
+		}
+		return web.JSON.Result(output)
+	}, middleware...)
+	app.GET(APIPrefix+"/job/:jobName/invocation/:invocation/stream", func(r *web.Ctx) web.Result {
+		invocation, result := apiInvocation(jm, r)
+		if result != nil {
+			return result
+		}
+		return OutputStreamResult{Invocation: invocation}
+	}, middleware...)
+	app.POST(APIPrefix+"/job/:jobName/run", func(r *web.Ctx) web.Result {
+		jobName := web.StringValue(r.RouteParam("jobName"))
+		values, err := runParameterValues(jm, jobName, r)
+		if err != nil {
+			return apiErrorResult(err)
+		}
+		if err := jm.RunJobWithParameters(jobName, values); err != nil {
+			return apiErrorResult(err)
+		}
+		return web.JSON.OK()
+	}, middleware...)
+	app.POST(APIPrefix+"/job/:jobName/cancel", func(r *web.Ctx) web.Result {
+		if err := jm.CancelJob(web.StringValue(r.RouteParam("jobName"))); err != nil {
+			return apiErrorResult(err)
+		}
+		return web.JSON.OK()
+	}, middleware...)
+	app.POST(APIPrefix+"/job/:jobName/enable", func(r *web.Ctx) web.Result {
+		if err := jm.EnableJobs(web.StringValue(r.RouteParam("jobName"))); err != nil {
+			return apiErrorResult(err)
+		}
+		return web.JSON.OK()
+	}, middleware...)
+	app.POST(APIPrefix+"/job/:jobName/disable", func(r *web.Ctx) web.Result {
+		if err := jm.DisableJobs(web.StringValue(r.RouteParam("jobName"))); err != nil {
+			return apiErrorResult(err)
+		}
+		return web.JSON.OK()
+	}, middleware...)
+}
+
+// NewJobModel returns the api model for a job.
+func NewJobModel(job *cron.JobScheduler) JobModel {
+	job.Lock()
+	model := JobModel{
+		Name:        job.Name,
+		Description: job.Description,
+		Disabled:    job.Disabled,
+		NextRuntime: job.NextRuntime,
+		Parameters:  job.Parameters,
+	}
+	if typed, ok := job.Schedule.(fmt.Stringer); ok {
+		model.Schedule = typed.String()
+	}
+	job.Unlock()
+
+	if current := job.CurrentInvocation(); current != nil {
+		entry := NewInvocationModel(current, false)
+		model.Current = &entry
+	}
+	if last := job.LastInvocation(); last != nil {
+		entry := NewInvocationModel(last, false)
+		model.Last = &entry
+	}
+	return model
+}
+
+// JobModel is the api model for a job.
+type JobModel struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Schedule    string             `json:"schedule,omitempty"`
+	Disabled    bool               `json:"disabled"`
+	NextRuntime time.Time          `json:"nextRuntime,omitempty"`
+	Parameters  []cron.Parameter   `json:"parameters,omitempty"`
+	Current     *cron.HistoryEntry `json:"current,omitempty"`
+	Last        *cron.HistoryEntry `json:"last,omitempty"`
+}
+
+// NewInvocationModel returns the api model for an invocation, which is its history entry.
+// The invocation state (e.g. its output) is only included if `withState` is set.
+func NewInvocationModel(ji *cron.JobInvocation, withState bool) cron.HistoryEntry {
+	invocation := *ji
+	if !withState {
+		invocation.State = nil
+	} else if typed, ok := invocation.State.(*cron.WorkflowRun); ok {
+		invocation.State = typed.Snapshot()
+	}
+	// the state is the only part of an entry that can fail to serialize, in which case it is omitted.
+	entry, _ := cron.NewHistoryEntry(invocation)
+	return entry
+}
+
+// InvocationOutputQuery selects the part of an invocation's output to return.
+type InvocationOutputQuery struct {
+	// Stream is the output stream; `OutputStreamOutput` (the default) or `OutputStreamError`.
+	Stream string
+	// Offset is the byte offset to start at.
+	Offset int
+	// Limit is the maximum number of bytes to return; zero is unlimited.
+	Limit int
+	// Tail returns only the last lines of the output, and takes precedence over the offset.
+	Tail int
+}
+
+// NewInvocationOutput returns the output of an invocation for a query.
+func NewInvocationOutput(ji *cron.JobInvocation, query InvocationOutputQuery) (*InvocationOutput, error) {
+	output := &InvocationOutput{
+		JobName:      ji.JobName,
+		InvocationID: ji.ID,
+		Stream:       query.Stream,
+		Complete:     !ji.Finished.IsZero(),
+	}
+	if output.Stream == "" {
+		output.Stream = OutputStreamOutput
+	}
+
+	if output.Stream != OutputStreamOutput && output.Stream != OutputStreamError {
+		return nil, ex.New(ErrAPIBadRequest, ex.OptMessagef("invalid output stream: %s", query.Stream))
+	}
+
+	var contents []byte
+	if typed, ok := ji.State.(*JobInvocationState); ok {
+		if output.Stream == OutputStreamOutput && typed.Output != nil {
+			contents = typed.Output.Bytes()
+		} else if output.Stream == OutputStreamError && typed.ErrorOutput != nil {
+			contents = typed.ErrorOutput.Bytes()
+		}
+	}
+
+	output.Total = len(contents)
+	start, end := query.Offset, len(contents)
+	if query.Tail > 0 {
+		start = tailOffset(contents, query.Tail)
+	}
+	if start > end {
+		start = end
+	}
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+	output.Offset = start
+	output.Data = string(contents[start:end])
+	return output, nil
+}
+
+// InvocationOutput is a range of an invocation's output.
+type InvocationOutput struct {
+	JobName      string `json:"jobName"`
+	InvocationID string `json:"invocationID"`
+	Stream       string `json:"stream"`
+	// Offset is the byte offset of the data in the output.
+	Offset int `json:"offset"`
+	// Total is the total number of bytes of output captured so far.
+	Total int `json:"total"`
+	// Complete indicates the invocation has finished, and the output will not grow.
+	Complete bool   `json:"complete"`
+	Data     string `json:"data"`
+}
+
+// APIError is the api model for errors.
+type APIError struct {
+	Class   string `json:"class"`
+	Message string `json:"message,omitempty"`
+}
+
+// Err returns the api error as an exception with the same class, so it can be checked
+// with the same helpers as the original error, e.g. `cron.IsJobNotFound(err)`.
+func (ae APIError) Err() error {
+	return ex.New(ex.Class(ae.Class), ex.OptMessage(ae.Message))
+}
+
+// apiMiddleware returns the middleware for the api routes.
+func apiMiddleware(cfg Config) (middleware []web.Middleware) {
+	if cfg.UseSessionAuth {
+		middleware = append(middleware, web.SessionMiddleware(func(_ *web.Ctx) web.Result {
+			return web.JSON.Status(http.StatusUnauthorized, APIError{Class: string(ErrAPINotAuthorized)})
+		}))
+	}
+	return
+}
+
+// apiErrorResult returns the result for an error.
+func apiErrorResult(err error) web.Result {
+	statusCode := http.StatusBadRequest
+	if cron.IsJobNotLoaded(err) || cron.IsJobNotFound(err) || IsInvocationNotFound(err) {
+		statusCode = http.StatusNotFound
+	}
+	return web.JSON.Status(statusCode, APIError{Class: ex.ErrClass(err), Message: ex.ErrMessage(err)})
+}
+
+// apiInvocation returns the invocation for a request, or the result if it is not found.
+func apiInvocation(jm *cron.JobManager, r *web.Ctx) (*cron.JobInvocation, web.Result) {
+	job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
+	if err != nil {
+		return nil, apiErrorResult(err)
+	}
+	invocationID := web.StringValue(r.RouteParam("invocation"))
+	invocation := job.GetInvocationByID(invocationID)
+	if invocation == nil {
+		return nil, apiErrorResult(ex.New(ErrInvocationNotFound, ex.OptMessagef("job: %s, invocation: %s", job.Name, invocationID)))
+	}
+	return invocation, nil
+}
+
+// invocationOutputQuery returns the output query for a request.
+func invocationOutputQuery(r *web.Ctx) (query InvocationOutputQuery, err error) {
+	query.Stream, _ = r.QueryValue("stream")
+	if query.Offset, err = queryInt(r, "offset"); err != nil {
+		return
+	}
+	if query.Limit, err = queryInt(r, "limit"); err != nil {
+		return
+	}
+	query.Tail, err = queryInt(r, "tail")
+	return
+}
+
+// queryInt returns a non-negative integer query value, or zero if it is unset.
+func queryInt(r *web.Ctx, key string) (int, error) {
+	value, _ := r.QueryValue(key)
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, ex.New(ErrAPIBadRequest, ex.OptMessagef("invalid %s: %q", key, value))
+	}
+	return parsed, nil
+}
+
+// tailOffset returns the offset of the last lines of some contents.
+// A trailing newline does not start a new line.
+func tailOffset(contents []byte, lines int) int {
+	end := len(contents)
+	if end > 0 && contents[end-1] == '\n' {
+		end--
+	}
+	for index := end - 1; index >= 0; index-- {
+		if contents[index] == '\n' {
+			lines--
+			if lines == 0 {
+				return index + 1
+			}
+		}
+	}
+	return 0
+}
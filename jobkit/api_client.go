@@ -0,0 +1,168 @@
+package jobkit
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+// NewAPIClient returns a new client for the versioned api of a management server at a given remote url,
+// e.g. `https://jobs.example.com`. The default options are applied to every request, and can be used
+// to set a session cookie for servers that require auth, e.g. `r2.OptCookieValue("SID", sessionValue)`.
+func NewAPIClient(remote string, defaults ...r2.Option) *APIClient {
+	return &APIClient{
+		Remote:   strings.TrimSuffix(remote, "/"),
+		Defaults: defaults,
+	}
+}
+
+// APIClient is a typed client for the versioned management server api.
+//
+// Errors returned by the api have the same class as the server side error,
+// so they can be checked with the same helpers, e.g. `cron.IsJobNotLoaded(err)`.
+type APIClient struct {
+	Remote   string
+	Defaults []r2.Option
+}
+
+// Jobs returns the loaded jobs.
+func (c APIClient) Jobs(ctx context.Context) (output []JobModel, err error) {
+	err = c.do(ctx, http.MethodGet, "/jobs", nil, &output)
+	return
+}
+
+// Job returns a job by name.
+func (c APIClient) Job(ctx context.Context, jobName string) (output JobModel, err error) {
+	err = c.do(ctx, http.MethodGet, c.jobPath(jobName), nil, &output)
+	return
+}
+
+// History returns the invocation history for a job, oldest first.
+// If limit is set, only the most recent invocations are returned.
+func (c APIClient) History(ctx context.Context, jobName string, limit int) (output []cron.HistoryEntry, err error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	err = c.do(ctx, http.MethodGet, c.jobPath(jobName, "history"), query, &output)
+	return
+}
+
+// Invocation returns an invocation of a job by id, including its state.
+func (c APIClient) Invocation(ctx context.Context, jobName, invocationID string) (output cron.HistoryEntry, err error) {
+	err = c.do(ctx, http.MethodGet, c.jobPath(jobName, "invocation", invocationID), nil, &output)
+	return
+}
+
+// InvocationOutput returns part of the output of an invocation of a job.
+func (c APIClient) InvocationOutput(ctx context.Context, jobName, invocationID string, query InvocationOutputQuery) (output InvocationOutput, err error) {
+	values := url.Values{}
+	if query.Stream != "" {
+		values.Set("stream", query.Stream)
+	}
+	if query.Offset > 0 {
+		values.Set("offset", strconv.Itoa(query.Offset))
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+	if query.Tail > 0 {
+		values.Set("tail", strconv.Itoa(query.Tail))
+	}
+	err = c.do(ctx, http.MethodGet, c.jobPath(jobName, "invocation", invocationID, "output"), values, &output)
+	return
+}
+
+// StreamOutput streams the output of an invocation of a job, calling a handler with the stream
+// (`OutputStreamOutput` or `OutputStreamError`) and line for each line of output, until the invocation finishes.
+func (c APIClient) StreamOutput(ctx context.Context, jobName, invocationID string, handler func(string, OutputLine)) error {
+	path := c.jobPath(jobName, "invocation", invocationID, "stream")
+	options := append(append([]r2.Option{}, c.Defaults...), r2.OptContext(ctx), r2.OptHeaderValue("Accept", "text/event-stream"))
+	req := r2.New(c.Remote+APIPrefix+path, options...)
+	defer req.Close()
+	res, err := req.Do()
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		contents, _ := ioutil.ReadAll(res.Body)
+		return c.responseError(http.MethodGet, path, res.StatusCode, contents)
+	}
+	return readOutputStream(ctx, res.Body, handler)
+}
+
+// Run runs a job with optional parameter values.
+func (c APIClient) Run(ctx context.Context, jobName string, parameters map[string]string) error {
+	if parameters == nil {
+		parameters = map[string]string{}
+	}
+	return c.do(ctx, http.MethodPost, c.jobPath(jobName, "run"), nil, nil, r2.OptJSONBody(parameters))
+}
+
+// Cancel cancels the running invocation of a job.
+func (c APIClient) Cancel(ctx context.Context, jobName string) error {
+	return c.do(ctx, http.MethodPost, c.jobPath(jobName, "cancel"), nil, nil)
+}
+
+// Enable enables a job.
+func (c APIClient) Enable(ctx context.Context, jobName string) error {
+	return c.do(ctx, http.MethodPost, c.jobPath(jobName, "enable"), nil, nil)
+}
+
+// Disable disables a job.
+func (c APIClient) Disable(ctx context.Context, jobName string) error {
+	return c.do(ctx, http.MethodPost, c.jobPath(jobName, "disable"), nil, nil)
+}
+
+//
+// utils
+//
+
+func (c APIClient) jobPath(jobName string, segments ...string) string {
+	path := "/job/" + url.PathEscape(jobName)
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+	return path
+}
+
+// do sends a request to the api, deserializing the response into the output if it is set.
+func (c APIClient) do(ctx context.Context, method, path string, query url.Values, output interface{}, options ...r2.Option) error {
+	options = append(append([]r2.Option{}, c.Defaults...), append([]r2.Option{
+		r2.OptContext(ctx),
+		r2.OptMethod(method),
+		r2.OptQuery(query),
+	}, options...)...)
+	contents, res, err := r2.New(c.Remote+APIPrefix+path, options...).BytesWithResponse()
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		return c.responseError(method, path, res.StatusCode, contents)
+	}
+	if output == nil {
+		return nil
+	}
+	if err := json.Unmarshal(contents, output); err != nil {
+		return ex.New(err, ex.OptMessagef("%s %s", method, path))
+	}
+	return nil
+}
+
+// responseError returns the error for a response with a status code that is not successful.
+func (c APIClient) responseError(method, path string, statusCode int, contents []byte) error {
+	var apiErr APIError
+	if jsonErr := json.Unmarshal(contents, &apiErr); jsonErr == nil && apiErr.Class != "" {
+		return apiErr.Err()
+	}
+	return ex.New(ErrAPIUnexpectedResponse, ex.OptMessagef("%s %s: %d %s", method, path, statusCode, strings.TrimSpace(string(contents))))
+}
@@ -0,0 +1,169 @@
+package jobkit
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestAPIClient(t *testing.T) {
+	assert := assert.New(t)
+
+	job, err := NewJob(JobConfig{
+		Name:       "report",
+		Schedule:   "@every 1h",
+		Parameters: []cron.Parameter{{Name: "lines", Type: cron.ParameterTypeInt, Default: "1"}},
+	}, func(ctx context.Context) error {
+		jis := GetJobInvocationState(ctx)
+		for line := int64(0); line < cron.GetParameters(ctx).Int("lines"); line++ {
+			fmt.Fprintf(jis.Output, "line %d\n", line)
+		}
+		fmt.Fprint(jis.ErrorOutput, "warning")
+		return nil
+	})
+	assert.Nil(err)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(job))
+	server := httptest.NewServer(NewManagementServer(jm, Config{}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client := NewAPIClient(server.URL)
+
+	jobs, err := client.Jobs(ctx)
+	assert.Nil(err)
+	assert.Len(jobs, 1)
+	assert.Equal("report", jobs[0].Name)
+	assert.Len(jobs[0].Parameters, 1)
+
+	_, err = client.Job(ctx, "missing")
+	assert.True(cron.IsJobNotLoaded(err))
+
+	err = client.Run(ctx, "report", map[string]string{"lines": "lots"})
+	assert.True(cron.IsParameterInvalid(err))
+
+	assert.Nil(client.Run(ctx, "report", map[string]string{"lines": "3"}))
+	var last *cron.HistoryEntry
+	for last == nil {
+		model, err := client.Job(ctx, "report")
+		assert.Nil(err)
+		last = model.Last
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(cron.JobStatusComplete, last.Status)
+	assert.Equal(3, last.Parameters.Int("lines"))
+
+	history, err := client.History(ctx, "report", 10)
+	assert.Nil(err)
+	assert.Len(history, 1)
+	assert.Empty(history[0].State)
+
+	invocation, err := client.Invocation(ctx, "report", last.ID)
+	assert.Nil(err)
+	assert.NotEmpty(invocation.State)
+
+	_, err = client.Invocation(ctx, "report", "missing")
+	assert.True(IsInvocationNotFound(err))
+
+	output, err := client.InvocationOutput(ctx, "report", last.ID, InvocationOutputQuery{Tail: 1})
+	assert.Nil(err)
+	assert.True(output.Complete)
+	assert.Equal("line 2\n", output.Data)
+
+	output, err = client.InvocationOutput(ctx, "report", last.ID, InvocationOutputQuery{Stream: OutputStreamError})
+	assert.Nil(err)
+	assert.Equal("warning", output.Data)
+
+	assert.Nil(client.Disable(ctx, "report"))
+	model, err := client.Job(ctx, "report")
+	assert.Nil(err)
+	assert.True(model.Disabled)
+	assert.Nil(client.Enable(ctx, "report"))
+	model, err = client.Job(ctx, "report")
+	assert.Nil(err)
+	assert.False(model.Disabled)
+
+	assert.True(cron.IsJobNotFound(client.Cancel(ctx, "missing")))
+}
+
+func TestAPIClientSessionAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test", func(_ context.Context) error { return nil })))
+
+	sessions := web.NewLocalSessionCache()
+	sessions.Upsert(web.NewSession("example-user", "example-session"))
+	app := NewManagementServer(jm, Config{UseSessionAuth: true}, web.OptAuth(web.NewLocalAuthManagerFromCache(sessions)))
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	_, err := NewAPIClient(server.URL).Jobs(context.Background())
+	assert.True(IsAPINotAuthorized(err))
+
+	jobs, err := NewAPIClient(server.URL, r2.OptCookieValue(web.DefaultCookieName, "example-session")).Jobs(context.Background())
+	assert.Nil(err)
+	assert.Len(jobs, 1)
+}
+
+func TestAPIClientStreamOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan struct{})
+	proceed := make(chan struct{})
+	job, err := NewJob(JobConfig{Name: "stream", Schedule: "@every 1h"}, func(ctx context.Context) error {
+		jis := GetJobInvocationState(ctx)
+		fmt.Fprintln(jis.Output, "before")
+		close(started)
+		<-proceed
+		fmt.Fprintln(jis.ErrorOutput, "warning")
+		fmt.Fprint(jis.Output, "after")
+		return nil
+	})
+	assert.Nil(err)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(job))
+	server := httptest.NewServer(NewManagementServer(jm, Config{}))
+	defer server.Close()
+
+	assert.Nil(jm.RunJob("stream"))
+	<-started
+	js, err := jm.Job("stream")
+	assert.Nil(err)
+	invocationID := js.CurrentInvocation().ID
+
+	type streamed struct {
+		Stream string
+		Data   string
+	}
+	lines := make(chan streamed, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- NewAPIClient(server.URL).StreamOutput(context.Background(), "stream", invocationID, func(stream string, line OutputLine) {
+			lines <- streamed{Stream: stream, Data: line.Data}
+		})
+	}()
+
+	// output captured before the stream started is sent first.
+	assert.Equal(streamed{OutputStreamOutput, "before"}, <-lines)
+	close(proceed)
+	assert.Nil(<-done)
+	close(lines)
+
+	var rest []streamed
+	for line := range lines {
+		rest = append(rest, line)
+	}
+	assert.Len(rest, 2)
+	assert.Any(rest, func(v interface{}) bool { return v.(streamed) == streamed{OutputStreamError, "warning"} })
+	assert.Any(rest, func(v interface{}) bool { return v.(streamed) == streamed{OutputStreamOutput, "after"} })
+}
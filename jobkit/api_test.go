@@ -0,0 +1,98 @@
+package jobkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestNewInvocationOutput(t *testing.T) {
+	assert := assert.New(t)
+
+	jis := NewJobInvocationState()
+	fmt.Fprint(jis.Output, "one\ntwo\nthree\n")
+	fmt.Fprint(jis.ErrorOutput, "oops")
+	ji := &cron.JobInvocation{ID: "test-invocation", JobName: "test", State: jis}
+
+	output, err := NewInvocationOutput(ji, InvocationOutputQuery{})
+	assert.Nil(err)
+	assert.Equal(OutputStreamOutput, output.Stream)
+	assert.Equal("one\ntwo\nthree\n", output.Data)
+	assert.Equal(14, output.Total)
+	assert.False(output.Complete)
+
+	output, err = NewInvocationOutput(ji, InvocationOutputQuery{Offset: 4, Limit: 3})
+	assert.Nil(err)
+	assert.Equal(4, output.Offset)
+	assert.Equal("two", output.Data)
+
+	output, err = NewInvocationOutput(ji, InvocationOutputQuery{Tail: 2})
+	assert.Nil(err)
+	assert.Equal(4, output.Offset)
+	assert.Equal("two\nthree\n", output.Data)
+
+	output, err = NewInvocationOutput(ji, InvocationOutputQuery{Tail: 10})
+	assert.Nil(err)
+	assert.Zero(output.Offset)
+
+	output, err = NewInvocationOutput(ji, InvocationOutputQuery{Offset: 100})
+	assert.Nil(err)
+	assert.Equal(14, output.Offset)
+	assert.Empty(output.Data)
+
+	output, err = NewInvocationOutput(ji, InvocationOutputQuery{Stream: OutputStreamError})
+	assert.Nil(err)
+	assert.Equal("oops", output.Data)
+
+	_, err = NewInvocationOutput(ji, InvocationOutputQuery{Stream: "bogus"})
+	assert.NotNil(err)
+
+	output, err = NewInvocationOutput(&cron.JobInvocation{ID: "other"}, InvocationOutputQuery{})
+	assert.Nil(err)
+	assert.Zero(output.Total)
+}
+
+func TestNewJobModel(t *testing.T) {
+	assert := assert.New(t)
+
+	job, err := NewJob(JobConfig{Name: "test", Schedule: "@every 1h"}, func(ctx context.Context) error {
+		fmt.Fprint(GetJobInvocationState(ctx).Output, "hello")
+		return fmt.Errorf("failed")
+	})
+	assert.Nil(err)
+	js := cron.NewJobScheduler(job)
+	ji := js.RunContext(context.Background())
+	assert.NotNil(ji)
+
+	model := NewJobModel(js)
+	assert.Equal("test", model.Name)
+	assert.Equal("every 1h0m0s", model.Schedule)
+	assert.Nil(model.Current)
+	assert.NotNil(model.Last)
+	assert.Equal(ji.ID, model.Last.ID)
+	assert.Equal("failed", model.Last.ErrClass)
+	assert.Empty(model.Last.State, "job models do not include invocation state")
+
+	assert.NotEmpty(NewInvocationModel(ji, true).State)
+}
+
+func TestAPIInvocationNotFound(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test", func(_ context.Context) error { return nil })))
+	app := NewManagementServer(jm, Config{})
+
+	meta, err := web.MockGet(app, APIPrefix+"/job/test/invocation/missing").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+
+	meta, err = web.MockGet(app, APIPrefix+"/job/missing/invocation/missing").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusNotFound, meta.StatusCode)
+}
@@ -14,15 +14,17 @@ import (
 
 // Config is the jobkit config.
 type Config struct {
-	MaxLogBytes int             `yaml:"maxLogBytes"`
-	Cron        cron.Config     `yaml:"cron"`
-	Logger      logger.Config   `yaml:"logger"`
-	Web         web.Config      `yaml:"web"`
-	Airbrake    airbrake.Config `yaml:"airbrake"`
-	AWS         aws.Config      `yaml:"aws"`
-	Email       email.Message   `yaml:"email"`
-	Datadog     datadog.Config  `yaml:"datadog"`
-	Slack       slack.Config    `yaml:"slack"`
+	MaxLogBytes int `yaml:"maxLogBytes"`
+	// UseSessionAuth requires a session from the web auth manager for the management server routes, except the health check.
+	UseSessionAuth bool            `yaml:"useSessionAuth"`
+	Cron           cron.Config     `yaml:"cron"`
+	Logger         logger.Config   `yaml:"logger"`
+	Web            web.Config      `yaml:"web"`
+	Airbrake       airbrake.Config `yaml:"airbrake"`
+	AWS            aws.Config      `yaml:"aws"`
+	Email          email.Message   `yaml:"email"`
+	Datadog        datadog.Config  `yaml:"datadog"`
+	Slack          slack.Config    `yaml:"slack"`
 }
 
 // Resolve applies resolution steps to the config.
@@ -4,3 +4,12 @@ package jobkit
 const (
 	DefaultMaxLogBytes = 10 * (1 << 10)
 )
+
+// APIPrefix is the path prefix of the versioned api.
+const APIPrefix = "/api/v1"
+
+// Output streams.
+const (
+	OutputStreamOutput = "output"
+	OutputStreamError  = "error"
+)
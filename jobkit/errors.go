@@ -0,0 +1,33 @@
+package jobkit
+
+import "github.com/blend/go-sdk/ex"
+
+const (
+	// ErrInvocationNotFound is returned by the api when an invocation is not found.
+	ErrInvocationNotFound ex.Class = "invocation not found"
+
+	// ErrAPIBadRequest is returned by the api for requests with invalid values.
+	ErrAPIBadRequest ex.Class = "bad request"
+
+	// ErrAPINotAuthorized is returned by the api for requests without a valid session.
+	ErrAPINotAuthorized ex.Class = "not authorized"
+
+	// ErrAPIUnexpectedResponse is returned by the api client for responses that are not api errors.
+	ErrAPIUnexpectedResponse ex.Class = "unexpected api response"
+
+	// ErrNotificationNon2xx is returned by notification clients when the remote returns a non-2xx status code.
+	ErrNotificationNon2xx ex.Class = "notification; non-2xx status code returned from remote"
+
+	// ErrWebhookInvalidBody is returned when a webhook body template does not render valid json.
+	ErrWebhookInvalidBody ex.Class = "webhook body is not valid json"
+)
+
+// IsInvocationNotFound returns if the error is an invocation not found error.
+func IsInvocationNotFound(err error) bool {
+	return ex.Is(err, ErrInvocationNotFound)
+}
+
+// IsAPINotAuthorized returns if the error is an api not authorized error.
+func IsAPINotAuthorized(err error) bool {
+	return ex.Is(err, ErrAPINotAuthorized)
+}
@@ -456,6 +456,10 @@ var headerTemplate = `
 			background-color: #FFB366;
 		}
 
+		pre span.timestamp {
+			color: #999;
+		}
+
 		ul.breadcrumbs {
 			font-size: 12px;
 			margin-top: 10px;
@@ -4,6 +4,8 @@ var indexTemplate = `
 {{ define "index" }}
 {{ template "header" . }}
 <div class="container">
+		{{ template "reloads" (.Ctx.StateValue "jobkit.reloads") }}
+		{{ template "workflows" .ViewModel.Workflows }}
 		{{ range $index, $job := .ViewModel.Jobs }}
 		<table class="job u-full-width">
 			<thead>
@@ -81,6 +83,9 @@ var indexTemplate = `
 					</form>
 					{{else}}
 					<form method="POST" action="/job.run/{{ $job.Name }}">
+						{{ range $parameter := $job.Parameters }}
+						<input type="text" name="{{ $parameter.Name }}" value="{{ $parameter.Default }}" placeholder="{{ $parameter.Name }} ({{ if $parameter.Type }}{{ $parameter.Type }}{{ else }}string{{ end }})" title="{{ $parameter.Description }}" {{ if $parameter.Required }}required{{ end }} />
+						{{ end }}
 						<input type="submit" class="button button-primary" value="Run" />
 					</form>
 					{{end}}
@@ -9,75 +9,125 @@ var invocationTemplate = `
 		<li>{{ .ViewModel.JobName }}</li>
 		<li>{{ .ViewModel.ID }}</li>
 	</ul>
+	{{ template "invocation_summary" .ViewModel }}
+	{{ if .ViewModel.State }}
 	<table class="u-full-width">
 		<thead>
 			<tr>
-				<th>Invocation</th>
-				<th>Started</th>
-				<th>Finished</th>
-				<th>Timeout</th>
-				<th>Cancelled</th>
-				<th>Elapsed</th>
+				<th>Error Output</th>
 			</tr>
 		</thead>
 		<tbody>
 			<tr>
-				<td>{{ .ViewModel.ID }}</td>
-				<td>{{ .ViewModel.Started | rfc3339 }}</td>
-				<td>{{ if .ViewModel.Finished.IsZero }}-{{ else }}{{ .ViewModel.Finished | rfc3339 }}{{ end }}</td>
-				<td>{{ if .ViewModel.Timeout.IsZero }}-{{ else }}{{ .ViewModel.Timeout | rfc3339 }}{{ end }}</td>
-				<td>{{ if .ViewModel.Cancelled.IsZero }}-{{ else }}{{ .ViewModel.Cancelled | rfc3339 }}{{ end }}</td>
-				<td>{{ .ViewModel.Elapsed }}</td>
+				<td>
+					<pre id="error-output">{{ if ne .ViewModel.Status "running" }}{{ template "output_lines" .ViewModel.State.ErrorOutput.Lines }}{{ end }}</pre>
+				</td>
 			</tr>
 		</tbody>
 	</table>
-	{{ if .ViewModel.Err }}
 	<table class="u-full-width">
 		<thead>
 			<tr>
-				<th>Error</th>
+				<th>Output</th>
 			</tr>
 		</thead>
 		<tbody>
 			<tr>
 				<td>
-					<pre>{{ .ViewModel.Err }}</pre>
+					<pre id="output">{{ if ne .ViewModel.Status "running" }}{{ template "output_lines" .ViewModel.State.Output.Lines }}{{ end }}</pre>
 				</td>
 			</tr>
 		</tbody>
 	</table>
+	{{ if eq .ViewModel.Status "running" }}
+	<script>
+		(function() {
+			var url = "/api/v1/job/" + encodeURIComponent({{ .ViewModel.JobName }}) + "/invocation/" + encodeURIComponent({{ .ViewModel.ID }}) + "/stream";
+			var source = new EventSource(url);
+			var append = function(id) {
+				return function(e) {
+					var line = JSON.parse(e.data);
+					var timestamp = document.createElement("span");
+					timestamp.className = "timestamp";
+					timestamp.textContent = line.timestamp;
+					var element = document.getElementById(id);
+					element.appendChild(timestamp);
+					element.appendChild(document.createTextNode(" " + line.data + "\n"));
+				};
+			};
+			source.addEventListener("output", append("output"));
+			source.addEventListener("errorOutput", append("error-output"));
+			source.addEventListener("complete", function() {
+				source.close();
+				window.location.reload();
+			});
+		})();
+	</script>
 	{{ end }}
-	{{ if .ViewModel.State }}
+	{{ end }}
+</div>
+{{ template "footer" . }}
+{{ end }}
+
+{{ define "output_lines" }}{{ range $line := . }}<span class="timestamp">{{ $line.Timestamp | rfc3339 }}</span> {{ $line.Data }}
+{{ end }}{{ end }}
+
+{{ define "invocation_summary" }}
 	<table class="u-full-width">
 		<thead>
 			<tr>
-				<th>Error Output</th>
+				<th>Invocation</th>
+				<th>Started</th>
+				<th>Finished</th>
+				<th>Timeout</th>
+				<th>Cancelled</th>
+				<th>Elapsed</th>
 			</tr>
 		</thead>
 		<tbody>
 			<tr>
-				<td>
-					<pre>{{ .ViewModel.State.ErrorOutput }}</pre>
-				</td>
+				<td>{{ .ID }}</td>
+				<td>{{ .Started | rfc3339 }}</td>
+				<td>{{ if .Finished.IsZero }}-{{ else }}{{ .Finished | rfc3339 }}{{ end }}</td>
+				<td>{{ if .Timeout.IsZero }}-{{ else }}{{ .Timeout | rfc3339 }}{{ end }}</td>
+				<td>{{ if .Cancelled.IsZero }}-{{ else }}{{ .Cancelled | rfc3339 }}{{ end }}</td>
+				<td>{{ .Elapsed }}</td>
 			</tr>
 		</tbody>
 	</table>
+	{{ if .Parameters }}
 	<table class="u-full-width">
 		<thead>
 			<tr>
-				<th>Output</th>
+				<th>Parameter</th>
+				<th>Value</th>
+			</tr>
+		</thead>
+		<tbody>
+			{{ range $name := .Parameters.Names }}
+			<tr>
+				<td>{{ $name }}</td>
+				<td>{{ $.Parameters.String $name }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+	{{ end }}
+	{{ if .Err }}
+	<table class="u-full-width">
+		<thead>
+			<tr>
+				<th>Error</th>
 			</tr>
 		</thead>
 		<tbody>
 			<tr>
 				<td>
-					<pre>{{ .ViewModel.State.Output }}</pre>
+					<pre>{{ .Err }}</pre>
 				</td>
 			</tr>
 		</tbody>
 	</table>
 	{{ end }}
-</div>
-{{ template "footer" . }}
 {{ end }}
 `
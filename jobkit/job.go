@@ -14,17 +14,24 @@ import (
 )
 
 var (
-	_ cron.Job                    = (*Job)(nil)
-	_ cron.TimeoutProvider        = (*Job)(nil)
-	_ cron.ScheduleProvider       = (*Job)(nil)
-	_ cron.OnStartReceiver        = (*Job)(nil)
-	_ cron.OnCompleteReceiver     = (*Job)(nil)
-	_ cron.OnFailureReceiver      = (*Job)(nil)
-	_ cron.OnCancellationReceiver = (*Job)(nil)
-	_ cron.OnBrokenReceiver       = (*Job)(nil)
-	_ cron.OnFixedReceiver        = (*Job)(nil)
-	_ cron.OnDisabledReceiver     = (*Job)(nil)
-	_ cron.OnEnabledReceiver      = (*Job)(nil)
+	_ cron.Job                      = (*Job)(nil)
+	_ cron.TimeoutProvider          = (*Job)(nil)
+	_ cron.RetryProvider            = (*Job)(nil)
+	_ cron.MisfireProvider          = (*Job)(nil)
+	_ cron.ConcurrencyGroupProvider = (*Job)(nil)
+	_ cron.PriorityProvider         = (*Job)(nil)
+	_ cron.ParametersProvider       = (*Job)(nil)
+	_ cron.ScheduleProvider         = (*Job)(nil)
+	_ cron.OnStartReceiver          = (*Job)(nil)
+	_ cron.OnCompleteReceiver       = (*Job)(nil)
+	_ cron.OnFailureReceiver        = (*Job)(nil)
+	_ cron.OnCancellationReceiver   = (*Job)(nil)
+	_ cron.OnBrokenReceiver         = (*Job)(nil)
+	_ cron.OnFixedReceiver          = (*Job)(nil)
+	_ cron.OnDisabledReceiver       = (*Job)(nil)
+	_ cron.OnEnabledReceiver        = (*Job)(nil)
+	_ cron.HistoryStateProvider     = (*Job)(nil)
+	_ cron.InvocationStateProvider  = (*Job)(nil)
 )
 
 // Job is the main job body.
@@ -33,15 +40,19 @@ type Job struct {
 	description string
 	config      JobConfig
 
-	schedule cron.Schedule
-	timeout  time.Duration
-	action   func(context.Context) error
+	schedule    cron.Schedule
+	timeout     time.Duration
+	maxLogBytes int
+	action      func(context.Context) error
 
 	log         logger.Log
 	statsClient stats.Collector
 	slackClient slack.Sender
 	emailClient email.Sender
 	errorClient diagnostics.Notifier
+
+	webhookSenders []WebhookSender
+	incidentSender IncidentSender
 }
 
 // Name returns the job name.
@@ -102,6 +113,45 @@ func (job *Job) WithTimeout(d time.Duration) *Job {
 	return job
 }
 
+// MaxLogBytes returns the maximum number of bytes of output retained for each of the job's invocations.
+func (job Job) MaxLogBytes() int {
+	if job.maxLogBytes > 0 {
+		return job.maxLogBytes
+	}
+	return DefaultMaxLogBytes
+}
+
+// WithMaxLogBytes sets the maximum number of bytes of output retained for each of the job's invocations.
+func (job *Job) WithMaxLogBytes(maxLogBytes int) *Job {
+	job.maxLogBytes = maxLogBytes
+	return job
+}
+
+// RetryPolicy returns the retry policy from the job config.
+func (job Job) RetryPolicy() cron.RetryPolicy {
+	return job.config.RetryPolicy()
+}
+
+// MisfirePolicy returns the misfire policy from the job config.
+func (job Job) MisfirePolicy() cron.MisfirePolicy {
+	return job.config.MisfirePolicy()
+}
+
+// ConcurrencyGroup returns the concurrency group from the job config.
+func (job Job) ConcurrencyGroup() string {
+	return job.config.ConcurrencyGroup
+}
+
+// Priority returns the priority from the job config.
+func (job Job) Priority() int {
+	return job.config.Priority
+}
+
+// Parameters returns the parameters from the job config.
+func (job Job) Parameters() []cron.Parameter {
+	return job.config.Parameters
+}
+
 // WithLogger sets the job logger.
 func (job *Job) WithLogger(log logger.Log) *Job {
 	job.log = log
@@ -132,6 +182,19 @@ func (job *Job) WithErrorClient(client diagnostics.Notifier) *Job {
 	return job
 }
 
+// WithWebhookSenders sets the job webhook senders.
+func (job *Job) WithWebhookSenders(senders ...WebhookSender) *Job {
+	job.webhookSenders = senders
+	return job
+}
+
+// WithIncidentSender sets the job incident sender, which triggers an incident when
+// the job breaks and resolves it when the job is fixed.
+func (job *Job) WithIncidentSender(sender IncidentSender) *Job {
+	job.incidentSender = sender
+	return job
+}
+
 // OnStart is a lifecycle event handler.
 func (job Job) OnStart(ctx context.Context) {
 	if job.config.NotifyOnStartOrDefault() {
@@ -154,17 +217,29 @@ func (job Job) OnFailure(ctx context.Context) {
 }
 
 // OnBroken is a lifecycle event handler.
+// It triggers an incident if the job has an incident sender.
 func (job Job) OnBroken(ctx context.Context) {
 	if job.config.NotifyOnBrokenOrDefault() {
 		job.notify(ctx, cron.FlagBroken)
 	}
+	if job.incidentSender != nil {
+		if ji := cron.GetJobInvocation(ctx); ji != nil {
+			logger.MaybeError(job.log, job.incidentSender.Trigger(context.Background(), ji))
+		}
+	}
 }
 
 // OnFixed is a lifecycle event handler.
+// It resolves the job's incident if the job has an incident sender.
 func (job Job) OnFixed(ctx context.Context) {
 	if job.config.NotifyOnFixedOrDefault() {
 		job.notify(ctx, cron.FlagFixed)
 	}
+	if job.incidentSender != nil {
+		if ji := cron.GetJobInvocation(ctx); ji != nil {
+			logger.MaybeError(job.log, job.incidentSender.Resolve(context.Background(), ji))
+		}
+	}
 }
 
 // OnCancellation is a lifecycle event handler.
@@ -214,9 +289,31 @@ func (job Job) notify(ctx context.Context, flag string) {
 			job.errorClient.Notify(ji.Err)
 		}
 	}
+	if len(job.webhookSenders) > 0 {
+		if ji := cron.GetJobInvocation(ctx); ji != nil {
+			for _, sender := range job.webhookSenders {
+				logger.MaybeError(job.log, sender.Send(context.Background(), flag, ji))
+			}
+		}
+	}
+}
+
+// HistoryState implements cron.HistoryStateProvider.
+func (job Job) HistoryState() interface{} {
+	return NewJobInvocationState()
+}
+
+// InvocationState implements cron.InvocationStateProvider, and returns the state that captures an invocation's output.
+// It is set before the invocation starts, so output can be read as soon as it does, and is kept across retries.
+func (job Job) InvocationState(_ *cron.JobInvocation) interface{} {
+	return NewBoundedJobInvocationState(job.MaxLogBytes())
 }
 
 // Execute is the job body.
 func (job Job) Execute(ctx context.Context) error {
-	return job.action(WithJobInvocationState(ctx, NewJobInvocationState()))
+	// the scheduler sets the state before the invocation starts; it is only missing if the job is executed directly.
+	if GetJobInvocationState(ctx) == nil {
+		ctx = WithJobInvocationState(ctx, NewBoundedJobInvocationState(job.MaxLogBytes()))
+	}
+	return job.action(ctx)
 }
@@ -2,6 +2,8 @@ package jobkit
 
 import (
 	"time"
+
+	"github.com/blend/go-sdk/cron"
 )
 
 // JobConfig is something you can use to give your jobs some knobs to turn
@@ -13,10 +15,33 @@ type JobConfig struct {
 	// Description is a description of the job.
 	Description string `json:"description" yaml:"description"`
 	// Schedule returns the job schedule.
+	// It is evaluated in UTC unless it has a time zone prefix, e.g. `CRON_TZ=America/New_York 0 0 9 * * *`.
 	Schedule string `json:"schedule" yaml:"schedule"`
 	// Timeout represents the abort threshold for the job.
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
 
+	// MaxRetries is the number of times a failed invocation is retried before it is marked failed.
+	MaxRetries int `json:"maxRetries" yaml:"maxRetries"`
+	// RetryBackoff is the delay before the first retry; it doubles for each subsequent retry.
+	RetryBackoff time.Duration `json:"retryBackoff" yaml:"retryBackoff"`
+	// RetryMaxBackoff optionally caps the delay between retries.
+	RetryMaxBackoff time.Duration `json:"retryMaxBackoff" yaml:"retryMaxBackoff"`
+
+	// Misfire is what happens to scheduled runs that were missed, one of `skip`, `runOnce` or `runAll`.
+	// If unset, missed runs are not checked for.
+	Misfire string `json:"misfire" yaml:"misfire"`
+	// MisfireMaxRuns bounds the number of missed runs that are run with `runAll`.
+	MisfireMaxRuns int `json:"misfireMaxRuns" yaml:"misfireMaxRuns"`
+	// StartDeadline is how late a run can start relative to the time it was scheduled for before it is skipped.
+	StartDeadline time.Duration `json:"startDeadline" yaml:"startDeadline"`
+
+	// ConcurrencyGroup is the concurrency group the job's invocations are limited by, if any.
+	ConcurrencyGroup string `json:"concurrencyGroup" yaml:"concurrencyGroup"`
+	// Priority orders the job's invocations ahead of lower priority ones when invocations are queued.
+	Priority int `json:"priority" yaml:"priority"`
+	// Parameters are the parameters the job can be run with on demand.
+	Parameters []cron.Parameter `json:"parameters" yaml:"parameters"`
+
 	// NotifyOnStart governs if we should send notifications job start.
 	NotifyOnStart *bool `json:"notifyOnStart" yaml:"notifyOnStart"`
 	// NotifyOnSuccess governs if we should send notifications on any success.
@@ -31,6 +56,11 @@ type JobConfig struct {
 	NotifyOnEnabled *bool `json:"notifyOnEnabled" yaml:"notifyOnEnabled"`
 	// NotifyOnDisabled governs if we should send notifications when a job is disabled.
 	NotifyOnDisabled *bool `json:"notifyOnDisabled" yaml:"notifyOnDisabled"`
+
+	// Webhooks are sent for the notifications enabled above.
+	Webhooks []WebhookConfig `json:"webhooks" yaml:"webhooks"`
+	// PagerDuty triggers an incident when the job breaks, and resolves it when the job is fixed.
+	PagerDuty PagerDutyConfig `json:"pagerDuty" yaml:"pagerDuty"`
 }
 
 // ScheduleOrDefault returns the schedule or a default (every 5 minutes).
@@ -41,6 +71,23 @@ func (jc JobConfig) ScheduleOrDefault() string {
 	return "* */5 * * * * *"
 }
 
+// RetryPolicy returns the retry policy for the config.
+func (jc JobConfig) RetryPolicy() cron.RetryPolicy {
+	return cron.RetryPolicy{
+		MaxRetries: jc.MaxRetries,
+		Backoff:    cron.ExponentialBackoff(jc.RetryBackoff, jc.RetryMaxBackoff),
+	}
+}
+
+// MisfirePolicy returns the misfire policy for the config.
+func (jc JobConfig) MisfirePolicy() cron.MisfirePolicy {
+	return cron.MisfirePolicy{
+		Mode:          cron.MisfireMode(jc.Misfire),
+		MaxRuns:       jc.MisfireMaxRuns,
+		StartDeadline: jc.StartDeadline,
+	}
+}
+
 // NotifyOnStartOrDefault returns a value or a default.
 func (jc JobConfig) NotifyOnStartOrDefault() bool {
 	if jc.NotifyOnStart != nil {
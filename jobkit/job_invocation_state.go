@@ -1,8 +1,8 @@
 package jobkit
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
 
 	"github.com/blend/go-sdk/cron"
 )
@@ -33,14 +33,71 @@ func GetJobInvocationState(ctx context.Context) *JobInvocationState {
 
 // NewJobInvocationState returns a new job invocation state.
 func NewJobInvocationState() *JobInvocationState {
+	return NewBoundedJobInvocationState(0)
+}
+
+// NewBoundedJobInvocationState returns a new job invocation state whose output buffers
+// retain at most a given number of bytes each; if it is zero they are unbounded.
+func NewBoundedJobInvocationState(maxLogBytes int) *JobInvocationState {
 	return &JobInvocationState{
-		Output:      new(bytes.Buffer),
-		ErrorOutput: new(bytes.Buffer),
+		Output:      NewOutputBuffer(maxLogBytes),
+		ErrorOutput: NewOutputBuffer(maxLogBytes),
 	}
 }
 
 // JobInvocationState is the state object for a job invocation.
 type JobInvocationState struct {
-	Output      *bytes.Buffer
-	ErrorOutput *bytes.Buffer
+	Output      *OutputBuffer
+	ErrorOutput *OutputBuffer
+}
+
+// jobInvocationStateJSON is the serialized form of the job invocation state.
+// The output is serialized as timestamped lines, and whether the last line is incomplete.
+// The output strings are only read, from state persisted before output was captured as lines.
+type jobInvocationStateJSON struct {
+	Output             string       `json:"output,omitempty"`
+	ErrorOutput        string       `json:"errorOutput,omitempty"`
+	OutputLines        []OutputLine `json:"outputLines,omitempty"`
+	OutputPartial      bool         `json:"outputPartial,omitempty"`
+	ErrorOutputLines   []OutputLine `json:"errorOutputLines,omitempty"`
+	ErrorOutputPartial bool         `json:"errorOutputPartial,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+// The output buffers are serialized so captured output can be persisted.
+func (jis JobInvocationState) MarshalJSON() ([]byte, error) {
+	var values jobInvocationStateJSON
+	if jis.Output != nil {
+		values.OutputLines = jis.Output.Lines()
+		values.OutputPartial = jis.Output.Partial() != nil
+	}
+	if jis.ErrorOutput != nil {
+		values.ErrorOutputLines = jis.ErrorOutput.Lines()
+		values.ErrorOutputPartial = jis.ErrorOutput.Partial() != nil
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// State persisted without timestamped lines is restored from the output strings.
+func (jis *JobInvocationState) UnmarshalJSON(contents []byte) error {
+	var values jobInvocationStateJSON
+	if err := json.Unmarshal(contents, &values); err != nil {
+		return err
+	}
+	jis.Output = restoreOutputBuffer(values.Output, values.OutputLines, values.OutputPartial)
+	jis.ErrorOutput = restoreOutputBuffer(values.ErrorOutput, values.ErrorOutputLines, values.ErrorOutputPartial)
+	return nil
+}
+
+func restoreOutputBuffer(output string, lines []OutputLine, partial bool) *OutputBuffer {
+	buffer := NewOutputBuffer(0)
+	if len(lines) > 0 {
+		buffer.restore(lines, partial)
+		return buffer
+	}
+	if output != "" {
+		buffer.Write([]byte(output))
+	}
+	return buffer
 }
@@ -0,0 +1,24 @@
+package jobkit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestJobInvocationStateJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	jis := NewJobInvocationState()
+	jis.Output.WriteString("this is output")
+	jis.ErrorOutput.WriteString("this is error output")
+
+	contents, err := json.Marshal(jis)
+	assert.Nil(err)
+
+	var restored JobInvocationState
+	assert.Nil(json.Unmarshal(contents, &restored))
+	assert.Equal("this is output", restored.Output.String())
+	assert.Equal("this is error output", restored.ErrorOutput.String())
+}
@@ -34,6 +34,76 @@ func TestJobProperties(t *testing.T) {
 	assert.Equal(time.Second, job.Timeout())
 }
 
+func TestJobRetryPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	job := (&Job{}).WithConfig(JobConfig{
+		MaxRetries:      3,
+		RetryBackoff:    time.Second,
+		RetryMaxBackoff: 3 * time.Second,
+	})
+	policy := job.RetryPolicy()
+	assert.Equal(3, policy.MaxRetries)
+	assert.Equal(time.Second, policy.Delay(1))
+	assert.Equal(2*time.Second, policy.Delay(2))
+	assert.Equal(3*time.Second, policy.Delay(3))
+
+	assert.Zero((&Job{}).RetryPolicy().MaxRetries)
+}
+
+func TestJobInvocationStateRetries(t *testing.T) {
+	assert := assert.New(t)
+
+	started := make(chan *JobInvocationState, 2)
+	proceed := make(chan struct{})
+	var attempts int
+	job, err := NewJob(JobConfig{Name: "retries", MaxRetries: 1, RetryBackoff: time.Millisecond}, func(ctx context.Context) error {
+		attempts++
+		jis := GetJobInvocationState(ctx)
+		fmt.Fprintf(jis.Output, "attempt %d\n", attempts)
+		started <- jis
+		<-proceed
+		if attempts == 1 {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+	assert.Nil(err)
+	js := cron.NewJobScheduler(job)
+
+	done := make(chan *cron.JobInvocation)
+	go func() { done <- js.RunContext(context.Background()) }()
+	first := <-started
+
+	// the state is set before the invocation is current, and is kept across retries.
+	current := js.CurrentInvocation()
+	assert.NotNil(current)
+	assert.True(current.State == first)
+	proceed <- struct{}{}
+	assert.True(<-started == first)
+	close(proceed)
+
+	ji := <-done
+	assert.Equal(cron.JobStatusComplete, ji.Status)
+	assert.Equal("attempt 1\nattempt 2\n", ji.State.(*JobInvocationState).Output.String())
+}
+
+func TestJobMisfirePolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	job := (&Job{}).WithConfig(JobConfig{
+		Misfire:        "runAll",
+		MisfireMaxRuns: 3,
+		StartDeadline:  time.Hour,
+	})
+	policy := job.MisfirePolicy()
+	assert.Equal(cron.MisfireModeRunAll, policy.Mode)
+	assert.Equal(3, policy.MaxRunsOrDefault())
+	assert.Equal(time.Hour, policy.StartDeadline)
+
+	assert.Empty((&Job{}).MisfirePolicy().Mode)
+}
+
 func TestJobLifecycleHooksNotificationsUnset(t *testing.T) {
 	assert := assert.New(t)
 
@@ -156,3 +226,87 @@ func TestJobLifecycleHooksNotificationsSetEnabled(t *testing.T) {
 	msg = <-slackMessages
 	assert.Contains("cron.fixed", msg.Text)
 }
+
+type mockWebhookSender chan string
+
+func (mws mockWebhookSender) Send(_ context.Context, event string, _ *cron.JobInvocation) error {
+	mws <- event
+	return nil
+}
+
+type mockIncidentSender chan string
+
+func (mis mockIncidentSender) Trigger(_ context.Context, _ *cron.JobInvocation) error {
+	mis <- PagerDutyEventActionTrigger
+	return nil
+}
+
+func (mis mockIncidentSender) Resolve(_ context.Context, _ *cron.JobInvocation) error {
+	mis <- PagerDutyEventActionResolve
+	return nil
+}
+
+func TestJobLifecycleHooksWebhooksAndIncidents(t *testing.T) {
+	assert := assert.New(t)
+
+	ctx := cron.WithJobInvocation(context.Background(), &cron.JobInvocation{
+		ID:      uuid.V4().String(),
+		JobName: "test-job",
+		Err:     fmt.Errorf("only a test"),
+	})
+
+	webhooks := make(mockWebhookSender, 6)
+	incidents := make(mockIncidentSender, 2)
+
+	job := (&Job{
+		config: JobConfig{
+			NotifyOnFailure: ref.Bool(true),
+		},
+	}).WithWebhookSenders(webhooks).WithIncidentSender(incidents)
+
+	job.OnStart(ctx)
+	job.OnFailure(ctx)
+	job.OnBroken(ctx)
+	job.OnFixed(ctx)
+
+	assert.Len(webhooks, 1)
+	assert.Equal(cron.FlagFailed, <-webhooks)
+
+	assert.Len(incidents, 2)
+	assert.Equal(PagerDutyEventActionTrigger, <-incidents)
+	assert.Equal(PagerDutyEventActionResolve, <-incidents)
+}
+
+func TestNewJobNotificationSenders(t *testing.T) {
+	assert := assert.New(t)
+
+	job, err := NewJob(JobConfig{
+		Name:      "test-job",
+		Webhooks:  []WebhookConfig{{URL: "https://example.com/one"}, {}, {URL: "https://example.com/two"}},
+		PagerDuty: PagerDutyConfig{RoutingKey: "test-key"},
+	}, func(_ context.Context) error { return nil })
+	assert.Nil(err)
+	assert.Len(job.webhookSenders, 2)
+	assert.NotNil(job.incidentSender)
+
+	job, err = NewJob(JobConfig{Name: "test-job"}, func(_ context.Context) error { return nil })
+	assert.Nil(err)
+	assert.Empty(job.webhookSenders)
+	assert.Nil(job.incidentSender)
+}
+
+func TestNewJobParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewJob(JobConfig{
+		Name:       "test-job",
+		Parameters: []cron.Parameter{{Name: "batch_size", Type: cron.ParameterTypeInt}},
+	}, func(_ context.Context) error { return nil })
+	assert.Nil(err)
+
+	_, err = NewJob(JobConfig{
+		Name:       "test-job",
+		Parameters: []cron.Parameter{{Name: "batch-size", Type: cron.ParameterTypeInt}},
+	}, func(_ context.Context) error { return nil })
+	assert.True(cron.IsParameterInvalid(err), "parameter names that aren't valid environment variable names should be rejected")
+}
@@ -1,14 +1,21 @@
 package jobkit
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
 	"github.com/blend/go-sdk/web"
 )
 
 // NewManagementServer returns a new management server that lets you
 // trigger jobs or look at job statuses via. a json api.
+//
+// If the config sets `UseSessionAuth`, every route except the health check requires a session
+// from the app's auth manager, which is set from the web config or with `web.OptAuth(...)`.
 func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option) *web.App {
 	app := web.New(append([]web.Option{web.OptConfig(cfg.Web)}, options...)...)
 	app.Views.AddLiterals(
@@ -16,10 +23,14 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 		footerTemplate,
 		indexTemplate,
 		invocationTemplate,
+		workflowsTemplate,
+		workflowInvocationTemplate,
+		reloadsTemplate,
 	)
+	api, views := managementMiddleware(cfg)
 	app.GET("/", func(r *web.Ctx) web.Result {
 		return r.Views.View("index", jm.Status())
-	})
+	}, views...)
 	app.GET("/healthz", func(_ *web.Ctx) web.Result {
 		if jm.IsStarted() {
 			return web.JSON.OK()
@@ -28,7 +39,10 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 	})
 	app.GET("/api/jobs", func(_ *web.Ctx) web.Result {
 		return web.JSON.Result(jm.Status())
-	})
+	}, api...)
+	app.GET("/api/workflows", func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(jm.Status().Workflows)
+	}, api...)
 	app.GET("/api/job.status/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -39,27 +53,35 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.Result(status)
-	})
+	}, api...)
 	app.POST("/job.run/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
 			return r.Views.BadRequest(err)
 		}
-		if err := jm.RunJob(jobName); err != nil {
+		values, err := runParameterValues(jm, jobName, r)
+		if err != nil {
+			return r.Views.BadRequest(err)
+		}
+		if err := jm.RunJobWithParameters(jobName, values); err != nil {
 			return r.Views.BadRequest(err)
 		}
 		return web.RedirectWithMethod("GET", "/")
-	})
+	}, views...)
 	app.POST("/api/job.run/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
 			return web.JSON.BadRequest(err)
 		}
-		if err := jm.RunJob(jobName); err != nil {
+		values, err := runParameterValues(jm, jobName, r)
+		if err != nil {
+			return web.JSON.BadRequest(err)
+		}
+		if err := jm.RunJobWithParameters(jobName, values); err != nil {
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.OK()
-	})
+	}, api...)
 	app.POST("/api/job.cancel/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -69,7 +91,7 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.OK()
-	})
+	}, api...)
 	app.POST("/job.cancel/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -79,7 +101,7 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return r.Views.BadRequest(err)
 		}
 		return web.RedirectWithMethod("GET", "/")
-	})
+	}, views...)
 	app.POST("/api/job.disable/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -89,7 +111,7 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.Result(fmt.Sprintf("%s disabled", jobName))
-	})
+	}, api...)
 	app.POST("/job.disable/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -99,7 +121,7 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return r.Views.BadRequest(err)
 		}
 		return web.RedirectWithMethod("GET", "/")
-	})
+	}, views...)
 	app.POST("/api/job.enable/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -109,7 +131,7 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return web.JSON.BadRequest(err)
 		}
 		return web.JSON.Result(fmt.Sprintf("%s enabled", jobName))
-	})
+	}, api...)
 	app.POST("/job.enable/:jobName", func(r *web.Ctx) web.Result {
 		jobName, err := r.RouteParam("jobName")
 		if err != nil {
@@ -119,7 +141,7 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return r.Views.BadRequest(err)
 		}
 		return web.RedirectWithMethod("GET", "/")
-	})
+	}, views...)
 	app.GET("/job.invocation/:jobName/:invocation", func(r *web.Ctx) web.Result {
 		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
 		if err != nil {
@@ -129,8 +151,14 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 		if invocation == nil {
 			return r.Views.NotFound()
 		}
+		if run, ok := invocation.State.(*cron.WorkflowRun); ok {
+			// render a copy of the run as it may still be in progress.
+			copied := *invocation
+			copied.State = run.Snapshot()
+			return r.Views.View("workflow_invocation", &copied)
+		}
 		return r.Views.View("invocation", invocation)
-	})
+	}, views...)
 	app.GET("/api/job.invocation/:jobName/:invocation", func(r *web.Ctx) web.Result {
 		job, err := jm.Job(web.StringValue(r.RouteParam("jobName")))
 		if err != nil {
@@ -141,6 +169,51 @@ func NewManagementServer(jm *cron.JobManager, cfg Config, options ...web.Option)
 			return web.JSON.NotFound()
 		}
 		return web.JSON.Result(invocation)
-	})
+	}, api...)
+	AddAPIRoutes(app, jm, cfg)
 	return app
 }
+
+// managementMiddleware returns the middleware for the json api and html routes.
+func managementMiddleware(cfg Config) (api, views []web.Middleware) {
+	api = apiMiddleware(cfg)
+	if cfg.UseSessionAuth {
+		views = append(views, web.SessionMiddleware(nil))
+	}
+	return
+}
+
+// runParameterValues returns the parameter values for a run request, either from a json object
+// post body, or from the form values for the job's declared parameters.
+func runParameterValues(jm *cron.JobManager, jobName string, r *web.Ctx) (map[string]string, error) {
+	job, err := jm.Job(jobName)
+	if err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	if strings.HasPrefix(r.Request.Header.Get(web.HeaderContentType), "application/json") {
+		body, err := r.PostBody()
+		if err != nil || len(body) == 0 {
+			return values, err
+		}
+		// decode numbers as written, so large integers aren't formatted in exponent notation.
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var posted map[string]interface{}
+		if err := decoder.Decode(&posted); err != nil {
+			return nil, ex.New(cron.ErrParameterInvalid, ex.OptMessage("parameters must be a json object"))
+		}
+		for name, value := range posted {
+			if value != nil {
+				values[name] = fmt.Sprint(value)
+			}
+		}
+		return values, nil
+	}
+	for _, parameter := range job.Parameters {
+		if value, _ := r.FormValue(parameter.Name); value != "" {
+			values[parameter.Name] = value
+		}
+	}
+	return values, nil
+}
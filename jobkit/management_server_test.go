@@ -1,14 +1,15 @@
 package jobkit
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/blend/go-sdk/assert"
 	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/r2"
 	"github.com/blend/go-sdk/uuid"
 	"github.com/blend/go-sdk/web"
 )
@@ -83,10 +84,7 @@ func TestManagementServerIndex(t *testing.T) {
 		{
 			ID:      invocationID,
 			JobName: jobName,
-			State: JobInvocationState{
-				Output:      bytes.NewBufferString(output),
-				ErrorOutput: bytes.NewBufferString(errorOutput),
-			},
+			State:   newTestJobInvocationState(output, errorOutput),
 		},
 	}
 
@@ -110,3 +108,182 @@ func TestManagementServerIndex(t *testing.T) {
 	assert.Contains(string(contents), output)
 	assert.Contains(string(contents), errorOutput)
 }
+
+func TestManagementServerWorkflows(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(
+		cron.NewJob("export", func(_ context.Context) error { return nil }),
+		cron.NewJob("upload", func(_ context.Context) error { return fmt.Errorf("upload failed") }),
+	))
+	assert.Nil(jm.LoadWorkflows(cron.NewWorkflow("pipeline",
+		cron.OptWorkflowStep("export"),
+		cron.OptWorkflowStep("upload", cron.AfterSuccess("export")),
+	)))
+
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	contents, meta, err := web.MockGet(app, "/").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), "pipeline")
+	assert.Contains(string(contents), "<code>export</code> (success)")
+
+	assert.Nil(jm.RunJobs("pipeline"))
+	pipeline, err := jm.Job("pipeline")
+	assert.Nil(err)
+	run := pipeline.Last.State.(*cron.WorkflowRun)
+
+	contents, meta, err = web.MockGet(app, "/").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), run.Steps[0].InvocationID)
+
+	contents, meta, err = web.MockGet(app, fmt.Sprintf("/job.invocation/pipeline/%s", pipeline.Last.ID)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), run.Steps[1].InvocationID)
+	assert.Contains(string(contents), "upload failed")
+
+	var workflows []cron.WorkflowStatus
+	meta, err = web.MockGet(app, "/api/workflows").JSONWithResponse(&workflows)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Len(workflows, 1)
+	assert.Equal(cron.JobStatusFailed, workflows[0].Last.Steps[1].Status)
+}
+
+func TestManagementServerRunParameters(t *testing.T) {
+	assert := assert.New(t)
+
+	values := make(chan cron.Parameters, 2)
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("backfill", func(ctx context.Context) error {
+		values <- cron.GetParameters(ctx)
+		return nil
+	}, cron.OptJobBuilderParameters(
+		cron.Parameter{Name: "region", Required: true},
+		cron.Parameter{Name: "days", Type: cron.ParameterTypeInt, Default: "1"},
+	))))
+
+	app := NewManagementServer(jm, Config{
+		Web: web.Config{
+			Port: 5000,
+		},
+	})
+
+	contents, meta, err := web.MockGet(app, "/").BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), `name="region"`)
+	assert.Contains(string(contents), `name="days" value="1"`)
+
+	meta, err = web.MockPost(app, "/api/job.run/backfill", nil, r2.OptJSONBody(map[string]interface{}{"days": 3})).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusBadRequest, meta.StatusCode)
+
+	meta, err = web.MockPost(app, "/api/job.run/backfill", nil, r2.OptJSONBody(map[string]interface{}{"region": "us-east-1", "days": 3})).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	parameters := <-values
+	assert.Equal("us-east-1", parameters.String("region"))
+	assert.Equal(3, parameters.Int("days"))
+
+	// large integers are passed as written, not in exponent notation.
+	meta, err = web.MockPost(app, "/api/job.run/backfill", nil, r2.OptJSONBody(map[string]interface{}{"region": "us-east-1", "days": 1000000})).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	parameters = <-values
+	assert.Equal(1000000, parameters.Int("days"))
+
+	meta, err = web.MockPost(app, "/job.run/backfill", nil, r2.OptPostFormValue("region", "us-west-2")).DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	parameters = <-values
+	assert.Equal("us-west-2", parameters.String("region"))
+	assert.Equal(1, parameters.Int("days"))
+
+	backfill, err := jm.Job("backfill")
+	assert.Nil(err)
+	for backfill.CurrentInvocation() != nil || len(backfill.InvocationHistory()) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	contents, meta, err = web.MockGet(app, fmt.Sprintf("/job.invocation/backfill/%s", backfill.InvocationHistory()[2].ID)).BytesWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Contains(string(contents), "us-west-2")
+}
+
+func newTestJobInvocationState(output, errorOutput string) *JobInvocationState {
+	jis := NewJobInvocationState()
+	jis.Output.WriteString(output)
+	jis.ErrorOutput.WriteString(errorOutput)
+	return jis
+}
+
+func TestManagementServerSessionAuth(t *testing.T) {
+	assert := assert.New(t)
+
+	ran := make(chan struct{}, 1)
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test", func(_ context.Context) error {
+		ran <- struct{}{}
+		return nil
+	})))
+	jm.StartAsync()
+	defer jm.Stop()
+
+	sessions := web.NewLocalSessionCache()
+	sessions.Upsert(web.NewSession("example-user", "example-session"))
+	app := NewManagementServer(jm, Config{UseSessionAuth: true}, web.OptAuth(web.NewLocalAuthManagerFromCache(sessions)))
+
+	// the legacy json and html routes require a session like the versioned api.
+	for _, route := range []struct {
+		Method string
+		Path   string
+	}{
+		{"GET", "/"},
+		{"GET", "/api/jobs"},
+		{"GET", "/api/job.status/test"},
+		{"POST", "/api/job.run/test"},
+		{"POST", "/api/job.cancel/test"},
+		{"POST", "/api/job.disable/test"},
+		{"POST", "/api/job.enable/test"},
+		{"GET", "/api/job.invocation/test/missing"},
+		{"POST", "/job.run/test"},
+		{"POST", "/job.cancel/test"},
+		{"POST", "/job.disable/test"},
+		{"POST", "/job.enable/test"},
+		{"GET", "/job.invocation/test/missing"},
+	} {
+		meta, err := web.MockMethod(app, route.Method, route.Path).DiscardWithResponse()
+		assert.Nil(err)
+		assert.True(meta.StatusCode == http.StatusUnauthorized || meta.StatusCode == http.StatusForbidden, route.Method, route.Path, meta.StatusCode)
+	}
+	select {
+	case <-ran:
+		assert.FailNow("the job should not run without a session")
+	default:
+	}
+	js, err := jm.Job("test")
+	assert.Nil(err)
+	js.Lock()
+	assert.False(js.Disabled)
+	js.Unlock()
+
+	// the health check doesn't require a session.
+	meta, err := web.MockGet(app, "/healthz").DiscardWithResponse()
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+
+	var status cron.Status
+	meta, err = web.MockGet(app, "/api/jobs", r2.OptCookieValue(web.DefaultCookieName, "example-session")).JSONWithResponse(&status)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Len(status.Jobs, 1)
+}
@@ -12,6 +12,9 @@ func NewJob(cfg JobConfig, action func(context.Context) error) (*Job, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err = cron.ValidateParameterDefinitions(cfg.Parameters); err != nil {
+		return nil, err
+	}
 
 	job := (&Job{action: action}).
 		WithName(cfg.Name).
@@ -20,5 +23,14 @@ func NewJob(cfg JobConfig, action func(context.Context) error) (*Job, error) {
 		WithSchedule(schedule).
 		WithTimeout(cfg.Timeout)
 
+	for _, webhook := range cfg.Webhooks {
+		if !webhook.IsZero() {
+			job.WithWebhookSenders(append(job.webhookSenders, NewWebhookClient(webhook))...)
+		}
+	}
+	if !cfg.PagerDuty.IsZero() {
+		job.WithIncidentSender(NewPagerDutyClient(cfg.PagerDuty))
+	}
+
 	return job, nil
 }
@@ -0,0 +1,219 @@
+package jobkit
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// NewOutputBuffer returns a new output buffer that retains at most a given number of bytes;
+// if it is zero the buffer is unbounded.
+func NewOutputBuffer(maxBytes int) *OutputBuffer {
+	return &OutputBuffer{
+		MaxBytes: maxBytes,
+		changed:  make(chan struct{}),
+	}
+}
+
+// OutputBuffer captures output as lines, timestamped with when each line started.
+//
+// It is safe to write to while it is read from, e.g. while a job is running, and
+// readers can wait for new output with `NotifyChanged`. Lines are numbered from when
+// the buffer was created, so a reader can pick up where it left off with `LinesSince`.
+// If the buffer exceeds `MaxBytes`, the oldest lines are discarded.
+type OutputBuffer struct {
+	sync.Mutex
+	// MaxBytes is the maximum number of bytes of output retained; zero is unbounded.
+	MaxBytes int
+
+	// lines are the retained complete lines; discarded lines are resliced off the front,
+	// and their space is reclaimed when appending reallocates the slice.
+	lines []OutputLine
+	// partial is the data of the incomplete last line, if hasPartial is set.
+	partial          strings.Builder
+	partialTimestamp time.Time
+	hasPartial       bool
+	first            int
+	size             int
+	changed          chan struct{}
+}
+
+// OutputLine is a line of output. The data does not include the line ending.
+type OutputLine struct {
+	Timestamp time.Time `json:"timestamp"`
+	Data      string    `json:"data"`
+}
+
+// Write implements io.Writer.
+func (ob *OutputBuffer) Write(contents []byte) (int, error) {
+	ob.Lock()
+	defer ob.Unlock()
+
+	now := time.Now().UTC()
+	remaining := contents
+	for len(remaining) > 0 {
+		if !ob.hasPartial {
+			ob.hasPartial, ob.partialTimestamp = true, now
+		}
+		index := bytes.IndexByte(remaining, '\n')
+		if index < 0 {
+			ob.partial.Write(remaining)
+			ob.size += len(remaining)
+			break
+		}
+		ob.partial.Write(remaining[:index])
+		ob.size += index + 1
+		ob.lines = append(ob.lines, ob.partialLineUnsafe())
+		ob.hasPartial = false
+		ob.partial.Reset()
+		remaining = remaining[index+1:]
+	}
+	ob.trimUnsafe()
+	ob.notifyUnsafe()
+	return len(contents), nil
+}
+
+// NotifyChanged returns a channel that is closed the next time output is written.
+func (ob *OutputBuffer) NotifyChanged() <-chan struct{} {
+	ob.Lock()
+	defer ob.Unlock()
+	if ob.changed == nil {
+		ob.changed = make(chan struct{})
+	}
+	return ob.changed
+}
+
+// LinesSince returns the complete lines from a given line number on, along with the line number
+// to read from next. Lines that were discarded to stay within `MaxBytes` are skipped.
+func (ob *OutputBuffer) LinesSince(cursor int) (lines []OutputLine, next int) {
+	ob.Lock()
+	defer ob.Unlock()
+	if cursor < ob.first {
+		cursor = ob.first
+	}
+	if index := cursor - ob.first; index < len(ob.lines) {
+		lines = append(lines, ob.lines[index:]...)
+	}
+	next = ob.first + len(ob.lines)
+	return
+}
+
+// Partial returns the incomplete last line of output, if there is one.
+func (ob *OutputBuffer) Partial() *OutputLine {
+	ob.Lock()
+	defer ob.Unlock()
+	if !ob.hasPartial {
+		return nil
+	}
+	partial := ob.partialLineUnsafe()
+	return &partial
+}
+
+// Lines returns all of the retained output, including an incomplete last line.
+func (ob *OutputBuffer) Lines() []OutputLine {
+	ob.Lock()
+	defer ob.Unlock()
+	output := make([]OutputLine, 0, len(ob.lines)+1)
+	output = append(output, ob.lines...)
+	if ob.hasPartial {
+		output = append(output, ob.partialLineUnsafe())
+	}
+	return output
+}
+
+// Bytes returns the retained output.
+func (ob *OutputBuffer) Bytes() []byte {
+	ob.Lock()
+	defer ob.Unlock()
+	output := bytes.NewBuffer(make([]byte, 0, ob.size))
+	for _, line := range ob.lines {
+		output.WriteString(line.Data)
+		output.WriteByte('\n')
+	}
+	if ob.hasPartial {
+		output.WriteString(ob.partial.String())
+	}
+	return output.Bytes()
+}
+
+// String returns the retained output as a string.
+func (ob *OutputBuffer) String() string {
+	return string(ob.Bytes())
+}
+
+// Len returns the number of bytes of retained output.
+func (ob *OutputBuffer) Len() int {
+	ob.Lock()
+	defer ob.Unlock()
+	return ob.size
+}
+
+// WriteString writes a string to the buffer.
+func (ob *OutputBuffer) WriteString(contents string) (int, error) {
+	return ob.Write([]byte(contents))
+}
+
+// restore replaces the buffer contents with lines, e.g. from persisted state.
+// If partial is set, the last line is incomplete.
+func (ob *OutputBuffer) restore(lines []OutputLine, partial bool) {
+	ob.Lock()
+	defer ob.Unlock()
+	ob.lines, ob.first, ob.size = append([]OutputLine(nil), lines...), 0, 0
+	ob.partial.Reset()
+	ob.hasPartial = false
+	for _, line := range ob.lines {
+		ob.size += len(line.Data) + 1
+	}
+	if partial && len(ob.lines) > 0 {
+		last := ob.lines[len(ob.lines)-1]
+		ob.lines = ob.lines[:len(ob.lines)-1]
+		ob.hasPartial, ob.partialTimestamp = true, last.Timestamp
+		ob.partial.WriteString(last.Data)
+		ob.size--
+	}
+	ob.notifyUnsafe()
+}
+
+// trimUnsafe discards the oldest output until the buffer is within `MaxBytes`.
+func (ob *OutputBuffer) trimUnsafe() {
+	if ob.MaxBytes <= 0 {
+		return
+	}
+	var discard int
+	for discard < len(ob.lines) && ob.size > ob.MaxBytes {
+		ob.size -= len(ob.lines[discard].Data) + 1
+		// clear the discarded line so its data can be collected before the slice is reallocated.
+		ob.lines[discard] = OutputLine{}
+		discard++
+	}
+	if discard > 0 {
+		ob.lines = ob.lines[discard:]
+		ob.first += discard
+	}
+	// a single incomplete line can still be too long, in which case its start is discarded,
+	// up to the start of a character so multi-byte characters aren't split.
+	if ob.hasPartial && ob.size > ob.MaxBytes {
+		current := ob.partial.String()
+		data := current[ob.size-ob.MaxBytes:]
+		for len(data) > 0 && !utf8.RuneStart(data[0]) {
+			data = data[1:]
+		}
+		ob.size -= len(current) - len(data)
+		ob.partial.Reset()
+		ob.partial.WriteString(data)
+	}
+}
+
+// partialLineUnsafe returns the incomplete last line.
+func (ob *OutputBuffer) partialLineUnsafe() OutputLine {
+	return OutputLine{Timestamp: ob.partialTimestamp, Data: ob.partial.String()}
+}
+
+func (ob *OutputBuffer) notifyUnsafe() {
+	if ob.changed != nil {
+		close(ob.changed)
+	}
+	ob.changed = make(chan struct{})
+}
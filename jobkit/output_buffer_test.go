@@ -0,0 +1,116 @@
+package jobkit
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOutputBufferLines(t *testing.T) {
+	assert := assert.New(t)
+
+	ob := NewOutputBuffer(0)
+	changed := ob.NotifyChanged()
+	ob.WriteString("one\ntw")
+	<-changed
+	ob.WriteString("o\nthree")
+
+	lines, next := ob.LinesSince(0)
+	assert.Len(lines, 2)
+	assert.Equal("one", lines[0].Data)
+	assert.Equal("two", lines[1].Data)
+	assert.False(lines[0].Timestamp.IsZero())
+	assert.Equal(2, next)
+	assert.Equal("three", ob.Partial().Data)
+	assert.Equal("one\ntwo\nthree", ob.String())
+	assert.Equal(13, ob.Len())
+
+	lines, next = ob.LinesSince(next)
+	assert.Empty(lines)
+	assert.Equal(2, next)
+
+	ob.WriteString("\n")
+	lines, next = ob.LinesSince(next)
+	assert.Len(lines, 1)
+	assert.Equal("three", lines[0].Data)
+	assert.Equal(3, next)
+	assert.Nil(ob.Partial())
+}
+
+func TestOutputBufferMaxBytes(t *testing.T) {
+	assert := assert.New(t)
+
+	ob := NewOutputBuffer(10)
+	ob.WriteString("one\ntwo\nthree\n")
+	assert.Equal("two\nthree\n", ob.String())
+
+	// readers skip discarded lines.
+	lines, next := ob.LinesSince(0)
+	assert.Len(lines, 2)
+	assert.Equal("two", lines[0].Data)
+	assert.Equal(3, next)
+
+	ob.WriteString("a very long incomplete line")
+	assert.Equal(10, ob.Len())
+	assert.Equal("plete line", ob.String())
+
+	// multi-byte characters are not split when an incomplete line is trimmed.
+	ob.WriteString("\n")
+	ob.WriteString("ééééééx")
+	assert.Equal("ééééx", ob.String())
+	assert.Equal(9, ob.Len())
+}
+
+func TestOutputBufferLongLines(t *testing.T) {
+	assert := assert.New(t)
+
+	// an incomplete line written in pieces is kept whole, and completed once.
+	ob := NewOutputBuffer(0)
+	for x := 0; x < 1000; x++ {
+		ob.WriteString("abc")
+	}
+	assert.Equal(3000, len(ob.Partial().Data))
+	ob.WriteString("\n")
+	assert.Nil(ob.Partial())
+	lines := ob.Lines()
+	assert.Len(lines, 1)
+	assert.Equal(3000, len(lines[0].Data))
+
+	// lines discarded from a full buffer don't change the numbering or retained lines.
+	ob = NewOutputBuffer(8)
+	for x := 0; x < 100; x++ {
+		fmt.Fprintf(ob, "%03d\n", x)
+	}
+	assert.Equal("098\n099\n", ob.String())
+	lines, next := ob.LinesSince(0)
+	assert.Len(lines, 2)
+	assert.Equal("098", lines[0].Data)
+	assert.Equal(100, next)
+}
+
+func TestJobInvocationStatePartialJSON(t *testing.T) {
+	assert := assert.New(t)
+
+	jis := NewJobInvocationState()
+	jis.Output.WriteString("complete\n")
+	jis.ErrorOutput.WriteString("complete\nincomplete")
+
+	contents, err := jis.MarshalJSON()
+	assert.Nil(err)
+	// the output is stored once, as lines.
+	assert.NotContains(string(contents), `"output"`)
+	assert.Equal(1, strings.Count(string(contents), "incomplete"))
+
+	var restored JobInvocationState
+	assert.Nil(restored.UnmarshalJSON(contents))
+	assert.Equal("complete\n", restored.Output.String())
+	assert.Equal("complete\nincomplete", restored.ErrorOutput.String())
+	assert.Equal(jis.Output.Lines()[0].Timestamp, restored.Output.Lines()[0].Timestamp)
+
+	// state persisted before output was captured as lines.
+	assert.Nil(restored.UnmarshalJSON([]byte(`{"output":"legacy\noutput"}`)))
+	assert.Equal("legacy\noutput", restored.Output.String())
+	assert.Empty(restored.ErrorOutput.String())
+}
@@ -0,0 +1,164 @@
+package jobkit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/web"
+)
+
+// Output stream server-sent event names.
+// Error output is not sent as `error` events as browsers use them for connection errors.
+const (
+	OutputStreamEventOutput      = "output"
+	OutputStreamEventErrorOutput = "errorOutput"
+	// OutputStreamEventComplete is sent when the invocation has finished and all of its output has been sent.
+	OutputStreamEventComplete = "complete"
+)
+
+// OutputStreamResult streams the output of an invocation as server-sent events until it finishes.
+//
+// Each line of output is sent as an event named for its stream (`output` or `errorOutput`), with the
+// json `OutputLine` as its data. Output captured before the request is sent first, and an
+// incomplete last line is only sent once the invocation finishes, followed by a `complete` event.
+type OutputStreamResult struct {
+	Invocation *cron.JobInvocation
+}
+
+// Render implements web.Result.
+func (osr OutputStreamResult) Render(r *web.Ctx) error {
+	r.Response.Header().Set(web.HeaderContentType, "text/event-stream")
+	r.Response.Header().Set(web.HeaderCacheControl, "no-cache")
+	r.Response.WriteHeader(http.StatusOK)
+
+	jis, _ := osr.Invocation.State.(*JobInvocationState)
+	if jis == nil {
+		return osr.writeEvent(r, OutputStreamEventComplete, struct{}{})
+	}
+
+	var outputCursor, errorCursor int
+	finished := invocationFinished(osr.Invocation)
+	for {
+		// take the notification channels before reading, so writes in between are not missed.
+		outputChanged, errorChanged := jis.Output.NotifyChanged(), jis.ErrorOutput.NotifyChanged()
+		if err := osr.writeLines(r, OutputStreamEventOutput, jis.Output, &outputCursor); err != nil {
+			return err
+		}
+		if err := osr.writeLines(r, OutputStreamEventErrorOutput, jis.ErrorOutput, &errorCursor); err != nil {
+			return err
+		}
+		r.Response.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-finished:
+			return osr.complete(r, jis, outputCursor, errorCursor)
+		case <-outputChanged:
+		case <-errorChanged:
+		}
+	}
+}
+
+// complete writes the remaining output, including incomplete lines, and the complete event.
+func (osr OutputStreamResult) complete(r *web.Ctx, jis *JobInvocationState, outputCursor, errorCursor int) error {
+	for _, stream := range []struct {
+		Name   string
+		Buffer *OutputBuffer
+		Cursor int
+	}{
+		{OutputStreamEventOutput, jis.Output, outputCursor},
+		{OutputStreamEventErrorOutput, jis.ErrorOutput, errorCursor},
+	} {
+		if err := osr.writeLines(r, stream.Name, stream.Buffer, &stream.Cursor); err != nil {
+			return err
+		}
+		if partial := stream.Buffer.Partial(); partial != nil {
+			if err := osr.writeEvent(r, stream.Name, partial); err != nil {
+				return err
+			}
+		}
+	}
+	if err := osr.writeEvent(r, OutputStreamEventComplete, struct{}{}); err != nil {
+		return err
+	}
+	r.Response.Flush()
+	return nil
+}
+
+func (osr OutputStreamResult) writeLines(r *web.Ctx, event string, buffer *OutputBuffer, cursor *int) error {
+	var lines []OutputLine
+	lines, *cursor = buffer.LinesSince(*cursor)
+	for _, line := range lines {
+		if err := osr.writeEvent(r, event, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (osr OutputStreamResult) writeEvent(r *web.Ctx, event string, data interface{}) error {
+	contents, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(r.Response, "event: %s\ndata: %s\n\n", event, contents)
+	return err
+}
+
+// invocationFinished returns a channel that is closed when an invocation finishes.
+// The invocation context is cancelled when it finishes; invocations without one
+// (e.g. those restored from history) have already finished.
+func invocationFinished(ji *cron.JobInvocation) <-chan struct{} {
+	if ji.Context != nil {
+		return ji.Context.Done()
+	}
+	finished := make(chan struct{})
+	close(finished)
+	return finished
+}
+
+// readOutputStream reads server-sent output events, calling a handler with the stream
+// and line for each line, until the complete event or the end of the stream.
+func readOutputStream(ctx context.Context, body io.Reader, handler func(string, OutputLine)) error {
+	var event string
+	var data []byte
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(text, "event:"))
+		case strings.HasPrefix(text, "data:"):
+			data = append(data, strings.TrimSpace(strings.TrimPrefix(text, "data:"))...)
+		case text == "":
+			if event == OutputStreamEventComplete {
+				return nil
+			}
+			if event != "" && len(data) > 0 {
+				var line OutputLine
+				if err := json.Unmarshal(data, &line); err != nil {
+					return ex.New(err)
+				}
+				if event == OutputStreamEventErrorOutput {
+					handler(OutputStreamError, line)
+				} else {
+					handler(OutputStreamOutput, line)
+				}
+			}
+			event, data = "", nil
+		}
+	}
+	return ex.New(scanner.Err())
+}
@@ -0,0 +1,221 @@
+package jobkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+)
+
+// PagerDuty defaults.
+const (
+	// DefaultPagerDutyEndpoint is the default events v2 endpoint.
+	DefaultPagerDutyEndpoint = "https://events.pagerduty.com/v2/enqueue"
+	// DefaultPagerDutySeverity is the default severity of triggered incidents.
+	DefaultPagerDutySeverity = "error"
+	// DefaultPagerDutyTimeout is the default request timeout.
+	DefaultPagerDutyTimeout = 10 * time.Second
+)
+
+// PagerDuty event actions.
+const (
+	PagerDutyEventActionTrigger = "trigger"
+	PagerDutyEventActionResolve = "resolve"
+)
+
+// IncidentSender triggers and resolves incidents for jobs.
+type IncidentSender interface {
+	Trigger(ctx context.Context, ji *cron.JobInvocation) error
+	Resolve(ctx context.Context, ji *cron.JobInvocation) error
+}
+
+// PagerDutyConfig is the config for triggering incidents with the events v2 api
+// when a job breaks, and resolving them when it is fixed.
+type PagerDutyConfig struct {
+	// RoutingKey is the integration key of the service incidents are triggered for.
+	RoutingKey string `json:"routingKey" yaml:"routingKey"`
+	// Endpoint is the events api endpoint, and defaults to `DefaultPagerDutyEndpoint`.
+	Endpoint string `json:"endpoint" yaml:"endpoint"`
+	// DedupKey identifies the incident for a job, so it is resolved when the job is fixed.
+	// It defaults to the job name.
+	DedupKey string `json:"dedupKey" yaml:"dedupKey"`
+	// Severity is the incident severity, one of `critical`, `error`, `warning` or `info`; it defaults to `error`.
+	Severity string `json:"severity" yaml:"severity"`
+	// Source is the affected system, and defaults to the hostname.
+	Source string `json:"source" yaml:"source"`
+	// Component is an optional component of the source.
+	Component string `json:"component" yaml:"component"`
+	// Group is an optional logical grouping of components.
+	Group string `json:"group" yaml:"group"`
+	// Class is an optional class or type of the event.
+	Class string `json:"class" yaml:"class"`
+	// Timeout is the request timeout, and defaults to 10 seconds.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// IsZero returns if the config is set.
+func (pdc PagerDutyConfig) IsZero() bool {
+	return pdc.RoutingKey == ""
+}
+
+// EndpointOrDefault returns the endpoint or a default.
+func (pdc PagerDutyConfig) EndpointOrDefault() string {
+	if pdc.Endpoint != "" {
+		return pdc.Endpoint
+	}
+	return DefaultPagerDutyEndpoint
+}
+
+// DedupKeyOrDefault returns the dedup key or a default.
+func (pdc PagerDutyConfig) DedupKeyOrDefault(jobName string) string {
+	if pdc.DedupKey != "" {
+		return pdc.DedupKey
+	}
+	return jobName
+}
+
+// SeverityOrDefault returns the severity or a default.
+func (pdc PagerDutyConfig) SeverityOrDefault() string {
+	if pdc.Severity != "" {
+		return pdc.Severity
+	}
+	return DefaultPagerDutySeverity
+}
+
+// SourceOrDefault returns the source or a default.
+func (pdc PagerDutyConfig) SourceOrDefault() string {
+	if pdc.Source != "" {
+		return pdc.Source
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "jobkit"
+}
+
+// TimeoutOrDefault returns the timeout or a default.
+func (pdc PagerDutyConfig) TimeoutOrDefault() time.Duration {
+	if pdc.Timeout > 0 {
+		return pdc.Timeout
+	}
+	return DefaultPagerDutyTimeout
+}
+
+// PagerDutyEvent is an events v2 api event.
+type PagerDutyEvent struct {
+	RoutingKey  string                 `json:"routing_key"`
+	EventAction string                 `json:"event_action"`
+	DedupKey    string                 `json:"dedup_key,omitempty"`
+	Payload     *PagerDutyEventPayload `json:"payload,omitempty"`
+}
+
+// PagerDutyEventPayload is the payload of a trigger event.
+type PagerDutyEventPayload struct {
+	Summary       string                 `json:"summary"`
+	Source        string                 `json:"source"`
+	Severity      string                 `json:"severity"`
+	Timestamp     string                 `json:"timestamp,omitempty"`
+	Component     string                 `json:"component,omitempty"`
+	Group         string                 `json:"group,omitempty"`
+	Class         string                 `json:"class,omitempty"`
+	CustomDetails map[string]interface{} `json:"custom_details,omitempty"`
+}
+
+var (
+	_ IncidentSender = (*PagerDutyClient)(nil)
+)
+
+// NewPagerDutyClient returns a new pagerduty client.
+func NewPagerDutyClient(cfg PagerDutyConfig) *PagerDutyClient {
+	return &PagerDutyClient{Config: cfg}
+}
+
+// PagerDutyClient triggers and resolves incidents with the events v2 api.
+type PagerDutyClient struct {
+	Config PagerDutyConfig
+}
+
+// Trigger triggers an incident for a broken job.
+func (pdc PagerDutyClient) Trigger(ctx context.Context, ji *cron.JobInvocation) error {
+	return pdc.Send(ctx, NewPagerDutyTriggerEvent(pdc.Config, ji))
+}
+
+// Resolve resolves the incident for a fixed job.
+func (pdc PagerDutyClient) Resolve(ctx context.Context, ji *cron.JobInvocation) error {
+	return pdc.Send(ctx, NewPagerDutyResolveEvent(pdc.Config, ji))
+}
+
+// Send sends an event.
+func (pdc PagerDutyClient) Send(ctx context.Context, event PagerDutyEvent) error {
+	contents, res, err := r2.New(pdc.Config.EndpointOrDefault(),
+		r2.OptContext(ctx),
+		r2.OptMethod(http.MethodPost),
+		r2.OptTimeout(pdc.Config.TimeoutOrDefault()),
+		r2.OptJSONBody(event),
+	).BytesWithResponse()
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		return ex.New(ErrNotificationNon2xx, ex.OptMessagef("pagerduty %s, status: %d, response: %s", event.EventAction, res.StatusCode, strings.TrimSpace(string(contents))))
+	}
+	return nil
+}
+
+// NewPagerDutyTriggerEvent returns the trigger event for a broken job.
+func NewPagerDutyTriggerEvent(cfg PagerDutyConfig, ji *cron.JobInvocation) PagerDutyEvent {
+	details := map[string]interface{}{
+		"jobName":      ji.JobName,
+		"invocationID": ji.ID,
+		"status":       ji.Status,
+		"elapsed":      ji.Elapsed.String(),
+	}
+	if len(ji.Parameters) > 0 {
+		details["parameters"] = ji.Parameters
+	}
+
+	summary := fmt.Sprintf("%s %s", ji.JobName, ji.Status)
+	if errMessage := invocationErrMessage(ji); errMessage != "" {
+		details["error"] = errMessage
+		summary = fmt.Sprintf("%s: %s", summary, errMessage)
+	}
+	// summaries are truncated to 1024 characters by the api.
+	if len(summary) > 1024 {
+		summary = summary[:1024]
+	}
+
+	var timestamp string
+	if !ji.Finished.IsZero() {
+		timestamp = ji.Finished.UTC().Format(time.RFC3339)
+	}
+	return PagerDutyEvent{
+		RoutingKey:  cfg.RoutingKey,
+		EventAction: PagerDutyEventActionTrigger,
+		DedupKey:    cfg.DedupKeyOrDefault(ji.JobName),
+		Payload: &PagerDutyEventPayload{
+			Summary:       summary,
+			Source:        cfg.SourceOrDefault(),
+			Severity:      cfg.SeverityOrDefault(),
+			Timestamp:     timestamp,
+			Component:     cfg.Component,
+			Group:         cfg.Group,
+			Class:         cfg.Class,
+			CustomDetails: details,
+		},
+	}
+}
+
+// NewPagerDutyResolveEvent returns the resolve event for a fixed job.
+func NewPagerDutyResolveEvent(cfg PagerDutyConfig, ji *cron.JobInvocation) PagerDutyEvent {
+	return PagerDutyEvent{
+		RoutingKey:  cfg.RoutingKey,
+		EventAction: PagerDutyEventActionResolve,
+		DedupKey:    cfg.DedupKeyOrDefault(ji.JobName),
+	}
+}
@@ -0,0 +1,79 @@
+package jobkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestNewPagerDutyTriggerEvent(t *testing.T) {
+	assert := assert.New(t)
+
+	ji := &cron.JobInvocation{
+		ID:       "test-invocation",
+		JobName:  "test-job",
+		Status:   cron.JobStatusFailed,
+		Finished: time.Date(2020, 01, 02, 03, 04, 05, 0, time.UTC),
+		Err:      fmt.Errorf("only a test"),
+	}
+
+	event := NewPagerDutyTriggerEvent(PagerDutyConfig{RoutingKey: "test-key", Source: "test-source", Component: "test-component"}, ji)
+	assert.Equal("test-key", event.RoutingKey)
+	assert.Equal(PagerDutyEventActionTrigger, event.EventAction)
+	assert.Equal("test-job", event.DedupKey)
+	assert.NotNil(event.Payload)
+	assert.Equal("test-job failed: only a test", event.Payload.Summary)
+	assert.Equal("test-source", event.Payload.Source)
+	assert.Equal(DefaultPagerDutySeverity, event.Payload.Severity)
+	assert.Equal("2020-01-02T03:04:05Z", event.Payload.Timestamp)
+	assert.Equal("test-component", event.Payload.Component)
+	assert.Equal("only a test", event.Payload.CustomDetails["error"])
+	assert.Equal("test-invocation", event.Payload.CustomDetails["invocationID"])
+
+	event = NewPagerDutyResolveEvent(PagerDutyConfig{RoutingKey: "test-key", DedupKey: "test-dedup"}, ji)
+	assert.Equal(PagerDutyEventActionResolve, event.EventAction)
+	assert.Equal("test-dedup", event.DedupKey)
+	assert.Nil(event.Payload)
+}
+
+func TestPagerDutyClient(t *testing.T) {
+	assert := assert.New(t)
+
+	events := make(chan PagerDutyEvent, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var event PagerDutyEvent
+		if err := json.NewDecoder(req.Body).Decode(&event); err != nil || event.RoutingKey != "test-key" {
+			rw.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(rw, `{"status":"invalid event"}`)
+			return
+		}
+		events <- event
+		rw.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(rw, `{"status":"success"}`)
+	}))
+	defer server.Close()
+
+	ji := &cron.JobInvocation{ID: "test-invocation", JobName: "test-job", Status: cron.JobStatusFailed}
+
+	client := NewPagerDutyClient(PagerDutyConfig{RoutingKey: "test-key", Endpoint: server.URL})
+	assert.Nil(client.Trigger(context.Background(), ji))
+	assert.Nil(client.Resolve(context.Background(), ji))
+
+	trigger, resolve := <-events, <-events
+	assert.Equal(PagerDutyEventActionTrigger, trigger.EventAction)
+	assert.Equal(PagerDutyEventActionResolve, resolve.EventAction)
+	assert.Equal(trigger.DedupKey, resolve.DedupKey)
+
+	client = NewPagerDutyClient(PagerDutyConfig{RoutingKey: "bad-key", Endpoint: server.URL})
+	err := client.Trigger(context.Background(), ji)
+	assert.True(ex.Is(err, ErrNotificationNon2xx))
+	assert.Contains(ex.ErrMessage(err), "invalid event")
+}
@@ -0,0 +1,120 @@
+package jobkit
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/web"
+)
+
+// DefaultReloadHistoryMaxCount is the default number of reload results retained.
+const DefaultReloadHistoryMaxCount = 10
+
+// ReloadHistoryStateKey is the app state key the reload history is stored with for the management server views.
+const ReloadHistoryStateKey = "jobkit.reloads"
+
+// AddReloadRoutes adds the reload history to a management server, which shows the recent
+// reloads on the index page, and returns them from the versioned api at `/api/v1/reloads`.
+func AddReloadRoutes(app *web.App, cfg Config, reloads *ReloadHistory) {
+	app.State.Set(ReloadHistoryStateKey, reloads)
+	app.GET(APIPrefix+"/reloads", func(_ *web.Ctx) web.Result {
+		return web.JSON.Result(reloads.Results())
+	}, apiMiddleware(cfg)...)
+}
+
+// ReloadResult is the result of reloading jobs, e.g. when a config file changes.
+type ReloadResult struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Source is what the jobs were reloaded from, e.g. a config path.
+	Source  string   `json:"source,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Updated []string `json:"updated,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Err is the error the reload failed with, if any.
+	Err string `json:"err,omitempty"`
+}
+
+// Changed returns if the reload changed any jobs.
+func (rr ReloadResult) Changed() bool {
+	return len(rr.Added) > 0 || len(rr.Updated) > 0 || len(rr.Removed) > 0
+}
+
+// String returns a summary of the reload result.
+func (rr ReloadResult) String() string {
+	if rr.Err != "" {
+		return fmt.Sprintf("reload failed: %s", rr.Err)
+	}
+	if !rr.Changed() {
+		return "reloaded; no changes"
+	}
+	var changes []string
+	if len(rr.Added) > 0 {
+		changes = append(changes, fmt.Sprintf("added: %s", strings.Join(rr.Added, ", ")))
+	}
+	if len(rr.Updated) > 0 {
+		changes = append(changes, fmt.Sprintf("updated: %s", strings.Join(rr.Updated, ", ")))
+	}
+	if len(rr.Removed) > 0 {
+		changes = append(changes, fmt.Sprintf("removed: %s", strings.Join(rr.Removed, ", ")))
+	}
+	return fmt.Sprintf("reloaded; %s", strings.Join(changes, "; "))
+}
+
+// NewReloadHistory returns a new reload history that retains a given number of results;
+// if it is zero, `DefaultReloadHistoryMaxCount` are retained.
+func NewReloadHistory(maxCount int) *ReloadHistory {
+	return &ReloadHistory{MaxCount: maxCount}
+}
+
+// ReloadHistory is the recent reload results. It is safe to use from multiple goroutines.
+type ReloadHistory struct {
+	sync.Mutex
+	MaxCount int
+
+	results []ReloadResult
+}
+
+// MaxCountOrDefault returns the max count or a default.
+func (rh *ReloadHistory) MaxCountOrDefault() int {
+	if rh.MaxCount > 0 {
+		return rh.MaxCount
+	}
+	return DefaultReloadHistoryMaxCount
+}
+
+// Add adds a result, discarding the oldest results beyond the max count.
+func (rh *ReloadHistory) Add(result ReloadResult) {
+	rh.Lock()
+	defer rh.Unlock()
+	if result.Timestamp.IsZero() {
+		result.Timestamp = time.Now().UTC()
+	}
+	rh.results = append(rh.results, result)
+	if maxCount := rh.MaxCountOrDefault(); len(rh.results) > maxCount {
+		rh.results = append([]ReloadResult(nil), rh.results[len(rh.results)-maxCount:]...)
+	}
+}
+
+// Results returns the retained results, most recent first.
+func (rh *ReloadHistory) Results() []ReloadResult {
+	rh.Lock()
+	defer rh.Unlock()
+	output := make([]ReloadResult, 0, len(rh.results))
+	for index := len(rh.results) - 1; index >= 0; index-- {
+		output = append(output, rh.results[index])
+	}
+	return output
+}
+
+// Latest returns the most recent result, if there is one.
+func (rh *ReloadHistory) Latest() *ReloadResult {
+	rh.Lock()
+	defer rh.Unlock()
+	if len(rh.results) == 0 {
+		return nil
+	}
+	latest := rh.results[len(rh.results)-1]
+	return &latest
+}
@@ -0,0 +1,26 @@
+package jobkit
+
+var reloadsTemplate = `
+{{ define "reloads" }}
+		{{ with . }}{{ with .Results }}
+		<table class="reloads u-full-width">
+			<thead>
+				<tr>
+					<th>Reloaded</th>
+					<th>Source</th>
+					<th>Result</th>
+				</tr>
+			</thead>
+			<tbody>
+				{{ range $index, $result := . }}
+				<tr class="{{ if $result.Err }}failed{{ else }}ok{{ end }}">
+					<td>{{ $result.Timestamp | rfc3339 }}</td>
+					<td>{{ if $result.Source }}{{ $result.Source }}{{ else }}<span class="none">-</span>{{ end }}</td>
+					<td>{{ $result.String }}</td>
+				</tr>
+				{{ end }}
+			</tbody>
+		</table>
+		{{ end }}{{ end }}
+{{ end }}
+`
@@ -0,0 +1,67 @@
+package jobkit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/web"
+)
+
+func TestReloadHistory(t *testing.T) {
+	assert := assert.New(t)
+
+	reloads := NewReloadHistory(2)
+	assert.Nil(reloads.Latest())
+	assert.Empty(reloads.Results())
+
+	reloads.Add(ReloadResult{Source: "one"})
+	reloads.Add(ReloadResult{Source: "two"})
+	reloads.Add(ReloadResult{Source: "three"})
+
+	results := reloads.Results()
+	assert.Len(results, 2)
+	assert.Equal("three", results[0].Source)
+	assert.Equal("two", results[1].Source)
+	assert.False(results[0].Timestamp.IsZero())
+	assert.Equal("three", reloads.Latest().Source)
+}
+
+func TestReloadResultString(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("reloaded; no changes", ReloadResult{}.String())
+	assert.Equal("reload failed: bad config", ReloadResult{Added: []string{"one"}, Err: "bad config"}.String())
+	assert.Equal("reloaded; added: one, two; removed: three", ReloadResult{Added: []string{"one", "two"}, Removed: []string{"three"}}.String())
+	assert.True(ReloadResult{Updated: []string{"one"}}.Changed())
+	assert.False(ReloadResult{}.Changed())
+}
+
+func TestManagementServerReloads(t *testing.T) {
+	assert := assert.New(t)
+
+	jm := cron.New()
+	assert.Nil(jm.LoadJobs(cron.NewJob("test0", func(_ context.Context) error { return nil })))
+
+	app := NewManagementServer(jm, Config{})
+	contents, err := web.MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.NotContains(string(contents), "reloaded;")
+
+	reloads := NewReloadHistory(0)
+	reloads.Add(ReloadResult{Source: "config.yml", Added: []string{"test0"}})
+	AddReloadRoutes(app, Config{}, reloads)
+
+	contents, err = web.MockGet(app, "/").Bytes()
+	assert.Nil(err)
+	assert.Contains(string(contents), "reloaded; added: test0")
+
+	var results []ReloadResult
+	meta, err := web.MockGet(app, APIPrefix+"/reloads").JSONWithResponse(&results)
+	assert.Nil(err)
+	assert.Equal(http.StatusOK, meta.StatusCode)
+	assert.Len(results, 1)
+	assert.Equal("config.yml", results[0].Source)
+}
@@ -0,0 +1,201 @@
+package jobkit
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/r2"
+	"github.com/blend/go-sdk/template"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// Webhook defaults.
+const (
+	// DefaultWebhookSignatureHeader is the default header the request body signature is sent in.
+	DefaultWebhookSignatureHeader = "X-Signature"
+	// DefaultWebhookTimeout is the default webhook request timeout.
+	DefaultWebhookTimeout = 10 * time.Second
+
+	// DefaultWebhookBodyTemplate is the default webhook body template.
+	DefaultWebhookBodyTemplate = `{
+	"event": {{ .Var "event" | to_json }},
+	"jobName": {{ .Var "jobName" | to_json }},
+	"invocationID": {{ .Var "invocationID" | to_json }},
+	"status": {{ .Var "status" | to_json }},
+	"started": {{ .Var "started" | to_json }},
+	"finished": {{ .Var "finished" | to_json }},
+	"elapsed": {{ .Var "elapsed" | to_json }},
+	"error": {{ .Var "err" | to_json }},
+	"parameters": {{ .Var "parameters" | to_json }}
+}`
+)
+
+// WebhookSender sends job notifications as webhooks.
+type WebhookSender interface {
+	Send(ctx context.Context, event string, ji *cron.JobInvocation) error
+}
+
+// WebhookConfig is the config for an outgoing webhook sent on job notifications.
+type WebhookConfig struct {
+	// URL is the webhook url.
+	URL string `json:"url" yaml:"url"`
+	// Method is the request method, and defaults to `POST`.
+	Method string `json:"method" yaml:"method"`
+	// Headers are additional request headers.
+	Headers map[string]string `json:"headers" yaml:"headers"`
+	// Body is a template for the json request body. See `NewWebhookVars` for the variables it can use.
+	// It defaults to `DefaultWebhookBodyTemplate`.
+	Body string `json:"body" yaml:"body"`
+	// SigningKey, if set, is used to sign the request body with an HMAC-SHA512.
+	SigningKey string `json:"signingKey" yaml:"signingKey"`
+	// SignatureHeader is the header the signature is sent in, as `sha512=<hex signature>`.
+	// It defaults to `X-Signature`.
+	SignatureHeader string `json:"signatureHeader" yaml:"signatureHeader"`
+	// Timeout is the request timeout, and defaults to 10 seconds.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+}
+
+// IsZero returns if the config is set.
+func (wc WebhookConfig) IsZero() bool {
+	return wc.URL == ""
+}
+
+// MethodOrDefault returns the method or a default.
+func (wc WebhookConfig) MethodOrDefault() string {
+	if wc.Method != "" {
+		return strings.ToUpper(wc.Method)
+	}
+	return http.MethodPost
+}
+
+// BodyOrDefault returns the body template or a default.
+func (wc WebhookConfig) BodyOrDefault() string {
+	if wc.Body != "" {
+		return wc.Body
+	}
+	return DefaultWebhookBodyTemplate
+}
+
+// SignatureHeaderOrDefault returns the signature header or a default.
+func (wc WebhookConfig) SignatureHeaderOrDefault() string {
+	if wc.SignatureHeader != "" {
+		return wc.SignatureHeader
+	}
+	return DefaultWebhookSignatureHeader
+}
+
+// TimeoutOrDefault returns the timeout or a default.
+func (wc WebhookConfig) TimeoutOrDefault() time.Duration {
+	if wc.Timeout > 0 {
+		return wc.Timeout
+	}
+	return DefaultWebhookTimeout
+}
+
+var (
+	_ WebhookSender = (*WebhookClient)(nil)
+)
+
+// NewWebhookClient returns a new webhook client.
+func NewWebhookClient(cfg WebhookConfig) *WebhookClient {
+	return &WebhookClient{Config: cfg}
+}
+
+// WebhookClient sends job notifications to a webhook.
+type WebhookClient struct {
+	Config WebhookConfig
+}
+
+// Send sends a webhook for a job notification event, e.g. `cron.FlagFailed`.
+func (wc WebhookClient) Send(ctx context.Context, event string, ji *cron.JobInvocation) error {
+	body, err := NewWebhookBody(wc.Config.BodyOrDefault(), event, ji)
+	if err != nil {
+		return err
+	}
+
+	options := []r2.Option{
+		r2.OptContext(ctx),
+		r2.OptMethod(wc.Config.MethodOrDefault()),
+		r2.OptTimeout(wc.Config.TimeoutOrDefault()),
+		r2.OptHeaderValue(webutil.HeaderContentType, webutil.ContentTypeApplicationJSON),
+		r2.RequestOption(webutil.OptBodyBytes(body)),
+	}
+	for key, value := range wc.Config.Headers {
+		options = append(options, r2.OptHeaderValue(key, value))
+	}
+	if wc.Config.SigningKey != "" {
+		options = append(options, r2.OptHeaderValue(wc.Config.SignatureHeaderOrDefault(), WebhookSignature([]byte(wc.Config.SigningKey), body)))
+	}
+
+	contents, res, err := r2.New(wc.Config.URL, options...).BytesWithResponse()
+	if err != nil {
+		return err
+	}
+	if res.StatusCode < http.StatusOK || res.StatusCode > 299 {
+		return ex.New(ErrNotificationNon2xx, ex.OptMessagef("webhook: %s, status: %d, response: %s", wc.Config.URL, res.StatusCode, strings.TrimSpace(string(contents))))
+	}
+	return nil
+}
+
+// WebhookSignature returns the signature of a webhook body, as `sha512=<hex signature>`.
+func WebhookSignature(key, body []byte) string {
+	return "sha512=" + hex.EncodeToString(crypto.HMAC512(key, body))
+}
+
+// NewWebhookBody renders a webhook body template for an event and invocation.
+// The rendered body must be valid json.
+func NewWebhookBody(body, event string, ji *cron.JobInvocation) ([]byte, error) {
+	rendered, err := template.New().WithBody(body).WithVars(NewWebhookVars(event, ji)).ProcessString()
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	if !json.Valid([]byte(rendered)) {
+		return nil, ex.New(ErrWebhookInvalidBody, ex.OptMessage(rendered))
+	}
+	return []byte(rendered), nil
+}
+
+// NewWebhookVars returns the template variables for a webhook body, which are:
+//
+// - `event`: the notification event, e.g. `cron.failed`
+// - `jobName`, `invocationID` and `status`
+// - `started` and `finished`, which are times, and `elapsed`, which is a duration string
+// - `err`: the error message, or an empty string
+// - `parameters`: the invocation parameters
+// - `invocation`: the full history entry for the invocation
+func NewWebhookVars(event string, ji *cron.JobInvocation) template.Vars {
+	parameters := ji.Parameters
+	if parameters == nil {
+		parameters = cron.Parameters{}
+	}
+	return template.Vars{
+		"event":        event,
+		"jobName":      ji.JobName,
+		"invocationID": ji.ID,
+		"status":       ji.Status,
+		"started":      ji.Started,
+		"finished":     ji.Finished,
+		"elapsed":      ji.Elapsed.String(),
+		"err":          invocationErrMessage(ji),
+		"parameters":   parameters,
+		"invocation":   NewInvocationModel(ji, false),
+	}
+}
+
+// invocationErrMessage returns the error of an invocation, including its message, or an empty string.
+func invocationErrMessage(ji *cron.JobInvocation) string {
+	if ji.Err == nil {
+		return ""
+	}
+	if message := ex.ErrMessage(ji.Err); message != "" {
+		return ji.Err.Error() + ": " + message
+	}
+	return ji.Err.Error()
+}
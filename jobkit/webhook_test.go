@@ -0,0 +1,108 @@
+package jobkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/cron"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
+
+func TestNewWebhookBody(t *testing.T) {
+	assert := assert.New(t)
+
+	ji := &cron.JobInvocation{
+		ID:         "test-invocation",
+		JobName:    "test-job",
+		Status:     cron.JobStatusFailed,
+		Elapsed:    time.Second,
+		Err:        ex.New("test error", ex.OptMessage("with a \"quoted\" message")),
+		Parameters: cron.Parameters{"batchSize": int64(10)},
+	}
+
+	body, err := NewWebhookBody(DefaultWebhookBodyTemplate, cron.FlagFailed, ji)
+	assert.Nil(err)
+
+	var payload map[string]interface{}
+	assert.Nil(json.Unmarshal(body, &payload))
+	assert.Equal(cron.FlagFailed, payload["event"])
+	assert.Equal("test-job", payload["jobName"])
+	assert.Equal("test-invocation", payload["invocationID"])
+	assert.Equal(cron.JobStatusFailed, payload["status"])
+	assert.Equal("1s", payload["elapsed"])
+	assert.Equal(`test error: with a "quoted" message`, payload["error"])
+	assert.Equal(map[string]interface{}{"batchSize": float64(10)}, payload["parameters"])
+
+	body, err = NewWebhookBody(`{"text":{{ printf "%s %s" (.Var "jobName") (.Var "status") | to_json }}}`, cron.FlagFailed, ji)
+	assert.Nil(err)
+	assert.Equal(`{"text":"test-job failed"}`, string(body))
+
+	_, err = NewWebhookBody(`{"text":{{ .Var "jobName" }}}`, cron.FlagFailed, ji)
+	assert.True(ex.Is(err, ErrWebhookInvalidBody))
+}
+
+func TestWebhookClientSend(t *testing.T) {
+	assert := assert.New(t)
+
+	requests := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		requests <- req
+		bodies <- body
+		if req.Header.Get("X-Fail") != "" {
+			rw.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(rw, "failed")
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ji := &cron.JobInvocation{ID: "test-invocation", JobName: "test-job", Status: cron.JobStatusComplete}
+
+	client := NewWebhookClient(WebhookConfig{
+		URL:        server.URL,
+		Method:     "put",
+		Headers:    map[string]string{"X-Test": "test-value"},
+		SigningKey: "test-key",
+	})
+	assert.Nil(client.Send(context.Background(), cron.FlagComplete, ji))
+
+	req, body := <-requests, <-bodies
+	assert.Equal(http.MethodPut, req.Method)
+	assert.Equal("test-value", req.Header.Get("X-Test"))
+	assert.Equal(webutil.ContentTypeApplicationJSON, req.Header.Get(webutil.HeaderContentType))
+	assert.Equal(WebhookSignature([]byte("test-key"), body), req.Header.Get(DefaultWebhookSignatureHeader))
+	assert.True(json.Valid(body))
+
+	client = NewWebhookClient(WebhookConfig{
+		URL:     server.URL,
+		Headers: map[string]string{"X-Fail": "true"},
+	})
+	err := client.Send(context.Background(), cron.FlagComplete, ji)
+	assert.True(ex.Is(err, ErrNotificationNon2xx))
+	assert.Contains(ex.ErrMessage(err), "failed")
+
+	req = <-requests
+	<-bodies
+	assert.Equal(http.MethodPost, req.Method)
+	assert.Empty(req.Header.Get(DefaultWebhookSignatureHeader))
+}
+
+func TestWebhookSignature(t *testing.T) {
+	assert := assert.New(t)
+
+	signature := WebhookSignature([]byte("test-key"), []byte("test-body"))
+	assert.Equal(signature, WebhookSignature([]byte("test-key"), []byte("test-body")))
+	assert.NotEqual(signature, WebhookSignature([]byte("other-key"), []byte("test-body")))
+	assert.True(len(signature) == len("sha512=")+128)
+}
@@ -0,0 +1,96 @@
+package jobkit
+
+var workflowsTemplate = `
+{{ define "workflows" }}
+		{{ range $index, $workflow := . }}
+		{{ $run := $workflow.Last }}
+		{{ if $workflow.Current }}{{ $run = $workflow.Current }}{{ end }}
+		<table class="job workflow u-full-width">
+			<thead>
+				<tr>
+					<th>Workflow</th>
+					<th>Step</th>
+					<th>Depends On</th>
+					<th>{{ if $workflow.Current }}Current{{ else }}Last{{ end }} Status</th>
+					<th>Elapsed</th>
+					<th>Invocation</th>
+				</tr>
+			</thead>
+			<tbody>
+				{{ if $run }}
+				{{ range $stepIndex, $step := $run.Steps }}
+				<tr class="{{ if $step.Status | eq "failed" }}failed{{ else if $step.Status | eq "cancelled"}}cancelled{{else}}ok{{end}}">
+					<td>{{ if eq $stepIndex 0 }}<a href="/job.invocation/{{ $workflow.Name }}/{{ $run.ID }}">{{ $workflow.Name }}</a>{{ end }}</td>
+					<td>{{ $step.JobName }}</td>
+					<td>{{ template "workflow_dependencies" $step.DependsOn }}</td>
+					<td>{{ $step.Status }}</td>
+					<td>{{ if $step.Finished.IsZero }}-{{ else }}{{ $step.Elapsed }}{{ end }}</td>
+					<td>{{ if $step.InvocationID }}<a href="/job.invocation/{{ $step.JobName }}/{{ $step.InvocationID }}">{{ $step.InvocationID }}</a>{{ else }}-{{ end }}</td>
+				</tr>
+				{{ end }}
+				{{ else }}
+				{{ range $stepIndex, $step := $workflow.Steps }}
+				<tr>
+					<td>{{ if eq $stepIndex 0 }}{{ $workflow.Name }}{{ end }}</td>
+					<td>{{ $step.JobName }}</td>
+					<td>{{ template "workflow_dependencies" $step.DependsOn }}</td>
+					<td><span class="none">-</span></td>
+					<td><span class="none">-</span></td>
+					<td><span class="none">-</span></td>
+				</tr>
+				{{ end }}
+				{{ end }}
+			</tbody>
+		</table>
+		{{ end }}
+{{ end }}
+
+{{ define "workflow_dependencies" }}
+	{{ range $index, $dependency := . }}
+		<code>{{ $dependency.JobName }}</code> ({{ $dependency.Condition }})
+	{{ else }}
+		<span class="none">-</span>
+	{{ end }}
+{{ end }}
+`
+
+var workflowInvocationTemplate = `
+{{ define "workflow_invocation" }}
+{{ template "header" . }}
+<div class="container">
+	<ul class="breadcrumbs">
+		<li><a href="/">Jobs</a></li>
+		<li>{{ .ViewModel.JobName }}</li>
+		<li>{{ .ViewModel.ID }}</li>
+	</ul>
+	{{ template "invocation_summary" .ViewModel }}
+	<table class="u-full-width">
+		<thead>
+			<tr>
+				<th>Step</th>
+				<th>Depends On</th>
+				<th>Status</th>
+				<th>Started</th>
+				<th>Elapsed</th>
+				<th>Invocation</th>
+				<th>Error</th>
+			</tr>
+		</thead>
+		<tbody>
+			{{ range $index, $step := .ViewModel.State.Steps }}
+			<tr class="{{ if $step.Status | eq "failed" }}failed{{ else if $step.Status | eq "cancelled"}}cancelled{{else}}ok{{end}}">
+				<td>{{ $step.JobName }}</td>
+				<td>{{ template "workflow_dependencies" $step.DependsOn }}</td>
+				<td>{{ $step.Status }}</td>
+				<td>{{ if $step.Started.IsZero }}-{{ else }}{{ $step.Started | rfc3339 }}{{ end }}</td>
+				<td>{{ if $step.Finished.IsZero }}-{{ else }}{{ $step.Elapsed }}{{ end }}</td>
+				<td>{{ if $step.InvocationID }}<a href="/job.invocation/{{ $step.JobName }}/{{ $step.InvocationID }}">{{ $step.InvocationID }}</a>{{ else }}-{{ end }}</td>
+				<td>{{ if $step.ErrMessage }}<code>{{ $step.ErrMessage }}</code>{{ else }}-{{ end }}</td>
+			</tr>
+			{{ end }}
+		</tbody>
+	</table>
+</div>
+{{ template "footer" . }}
+{{ end }}
+`
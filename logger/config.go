@@ -1,17 +1,30 @@
 package logger
 
 import (
+	"os"
 	"strings"
+	"time"
 
 	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/ex"
 )
 
 // Config is the logger config.
 type Config struct {
-	Flags  []string   `json:"flags,omitempty" yaml:"flags,omitempty" env:"LOG_FLAGS,csv"`
-	Format string     `json:"format,omitempty" yaml:"format,omitempty" env:"LOG_FORMAT"`
-	Text   TextConfig `json:"text,omitempty" yaml:"text,omitempty"`
-	JSON   JSONConfig `json:"json,omitempty" yaml:"json,omitempty"`
+	Flags  []string `json:"flags,omitempty" yaml:"flags,omitempty" env:"LOG_FLAGS,csv"`
+	Hidden []string `json:"hidden,omitempty" yaml:"hidden,omitempty" env:"LOG_HIDDEN,csv"`
+	// Scopes are flag overrides by sub-context path, e.g. `db > migrations: [debug]`.
+	Scopes map[string][]string `json:"scopes,omitempty" yaml:"scopes,omitempty"`
+	Format string              `json:"format,omitempty" yaml:"format,omitempty" env:"LOG_FORMAT"`
+	Text   TextConfig          `json:"text,omitempty" yaml:"text,omitempty"`
+	JSON   JSONConfig          `json:"json,omitempty" yaml:"json,omitempty"`
+	Logfmt LogfmtConfig        `json:"logfmt,omitempty" yaml:"logfmt,omitempty"`
+	OTel   OTelConfig          `json:"otel,omitempty" yaml:"otel,omitempty"`
+
+	Sampling map[string]SamplerConfig `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	Dedupe   DedupeConfig             `json:"dedupe,omitempty" yaml:"dedupe,omitempty"`
+
+	Sinks []SinkConfig `json:"sinks,omitempty" yaml:"sinks,omitempty"`
 }
 
 // Resolve resolves the config.
@@ -27,6 +40,23 @@ func (c Config) FlagsOrDefault() []string {
 	return DefaultFlags
 }
 
+// HiddenFlags returns the hidden flags.
+func (c Config) HiddenFlags() *Flags {
+	return NewFlags(c.Hidden...)
+}
+
+// ScopeFlags returns the flag overrides by scope name.
+func (c Config) ScopeFlags() map[string]*Flags {
+	if len(c.Scopes) == 0 {
+		return nil
+	}
+	output := make(map[string]*Flags)
+	for name, flags := range c.Scopes {
+		output[ScopeName(ParseScopeName(name)...)] = NewFlags(flags...)
+	}
+	return output
+}
+
 // FormatOrDefault returns the output format or a default.
 func (c Config) FormatOrDefault() string {
 	if c.Format != "" {
@@ -37,14 +67,133 @@ func (c Config) FormatOrDefault() string {
 
 // Formatter returns the configured writers
 func (c Config) Formatter() WriteFormatter {
-	switch strings.ToLower(string(c.FormatOrDefault())) {
+	return formatterFor(c.FormatOrDefault(), c.Text, c.JSON, c.Logfmt, c.OTel)
+}
+
+// Samplers returns the configured samplers by flag.
+func (c Config) Samplers() map[string]*Sampler {
+	if len(c.Sampling) == 0 {
+		return nil
+	}
+	output := make(map[string]*Sampler)
+	for flag, cfg := range c.Sampling {
+		output[strings.ToLower(strings.TrimSpace(flag))] = NewSamplerFromConfig(cfg)
+	}
+	return output
+}
+
+// Deduper returns the configured deduper, or nil if deduplication is disabled.
+func (c Config) Deduper() *Deduper {
+	if !c.Dedupe.Enabled {
+		return nil
+	}
+	return NewDeduperFromConfig(c.Dedupe)
+}
+
+// OutputSinks returns the configured sinks.
+func (c Config) OutputSinks() ([]*Sink, error) {
+	var sinks []*Sink
+	for _, sinkConfig := range c.Sinks {
+		sink, err := sinkConfig.Sink()
+		if err != nil {
+			for _, opened := range sinks {
+				opened.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// formatterFor returns a formatter for a given format.
+func formatterFor(format string, text TextConfig, json JSONConfig, logfmt LogfmtConfig, otel OTelConfig) WriteFormatter {
+	switch strings.ToLower(format) {
 	case FormatJSON:
-		return NewJSONOutputFormatter(OptJSONConfig(&c.JSON))
+		return NewJSONOutputFormatter(OptJSONConfig(&json))
+	case FormatLogfmt:
+		return NewLogfmtOutputFormatter(OptLogfmtConfig(&logfmt))
+	case FormatOTel:
+		return NewOTelOutputFormatter(OptOTelConfig(&otel))
 	case FormatText:
-		return NewTextOutputFormatter(OptTextConfig(&c.Text))
+		return NewTextOutputFormatter(OptTextConfig(&text))
+	default:
+		return NewTextOutputFormatter(OptTextConfig(&text))
+	}
+}
+
+// SinkConfig is the config for an additional output sink.
+type SinkConfig struct {
+	Name   string       `json:"name,omitempty" yaml:"name,omitempty"`
+	Flags  []string     `json:"flags,omitempty" yaml:"flags,omitempty"`
+	Format string       `json:"format,omitempty" yaml:"format,omitempty"`
+	Text   TextConfig   `json:"text,omitempty" yaml:"text,omitempty"`
+	JSON   JSONConfig   `json:"json,omitempty" yaml:"json,omitempty"`
+	Logfmt LogfmtConfig `json:"logfmt,omitempty" yaml:"logfmt,omitempty"`
+	OTel   OTelConfig   `json:"otel,omitempty" yaml:"otel,omitempty"`
+	// Output is one of `stdout`, `stderr` or `file`.
+	Output string             `json:"output,omitempty" yaml:"output,omitempty"`
+	File   RotatingFileConfig `json:"file,omitempty" yaml:"file,omitempty"`
+}
+
+// FormatOrDefault returns the output format or a default.
+func (sc SinkConfig) FormatOrDefault() string {
+	if sc.Format != "" {
+		return sc.Format
+	}
+	return FormatText
+}
+
+// OutputOrDefault returns the output destination or a default.
+// If a file path is set it defaults to `file`, otherwise `stdout`.
+func (sc SinkConfig) OutputOrDefault() string {
+	if sc.Output != "" {
+		return strings.ToLower(sc.Output)
+	}
+	if sc.File.Path != "" {
+		return OutputFile
+	}
+	return OutputStdout
+}
+
+// Sink returns a new sink for the config.
+func (sc SinkConfig) Sink() (*Sink, error) {
+	options := []SinkOption{
+		OptSinkFormatter(formatterFor(sc.FormatOrDefault(), sc.Text, sc.JSON, sc.Logfmt, sc.OTel)),
+	}
+	if len(sc.Flags) > 0 {
+		options = append(options, OptSinkFlags(sc.Flags...))
+	}
+	switch output := sc.OutputOrDefault(); output {
+	case OutputStdout:
+		options = append(options, OptSinkOutput(os.Stdout))
+	case OutputStderr:
+		options = append(options, OptSinkOutput(os.Stderr))
+	case OutputFile:
+		file, err := NewRotatingFileFromConfig(sc.File)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, OptSinkRotatingFile(file))
 	default:
-		return NewTextOutputFormatter(OptTextConfig(&c.Text))
+		return nil, ex.New(ErrInvalidSinkOutput, ex.OptMessagef("sink: %s, output: %s", sc.Name, output))
 	}
+	return NewSink(sc.Name, options...), nil
+}
+
+// RotatingFileConfig is the config for a rotating file.
+type RotatingFileConfig struct {
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// MaxSizeBytes is the size after which the file is rotated.
+	MaxSizeBytes int64 `json:"maxSizeBytes,omitempty" yaml:"maxSizeBytes,omitempty"`
+	// RotateEvery is the interval after which the file is rotated.
+	RotateEvery time.Duration `json:"rotateEvery,omitempty" yaml:"rotateEvery,omitempty"`
+	// Retain is the number of rotated files to keep; if unset all rotated files are kept.
+	Retain int `json:"retain,omitempty" yaml:"retain,omitempty"`
+	// Compress gzips rotated files.
+	Compress bool `json:"compress,omitempty" yaml:"compress,omitempty"`
+	// ReopenOnSIGHUP reopens the file when the process receives a SIGHUP.
+	ReopenOnSIGHUP bool `json:"reopenOnSIGHUP,omitempty" yaml:"reopenOnSIGHUP,omitempty"`
 }
 
 // TextConfig is the config for a text formatter.
@@ -82,3 +231,73 @@ func (jc JSONConfig) PrettyIndentOrDefault() string {
 	}
 	return "  "
 }
+
+// SamplerConfig is the config for sampling events for a flag.
+type SamplerConfig struct {
+	// First is the number of events to keep per interval before sampling kicks in.
+	First int `json:"first,omitempty" yaml:"first,omitempty"`
+	// Every keeps 1 in `Every` events once `First` is exhausted (or always if `First` is unset).
+	Every int `json:"every,omitempty" yaml:"every,omitempty"`
+	// Interval is the window `First` applies to.
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+}
+
+// IntervalOrDefault returns the interval or a default.
+func (sc SamplerConfig) IntervalOrDefault() time.Duration {
+	if sc.Interval > 0 {
+		return sc.Interval
+	}
+	return DefaultSamplerInterval
+}
+
+// DedupeConfig is the config for suppressing duplicate events.
+type DedupeConfig struct {
+	Enabled  bool          `json:"enabled,omitempty" yaml:"enabled,omitempty" env:"LOG_DEDUPE"`
+	Interval time.Duration `json:"interval,omitempty" yaml:"interval,omitempty" env:"LOG_DEDUPE_INTERVAL"`
+	Flags    []string      `json:"flags,omitempty" yaml:"flags,omitempty" env:"LOG_DEDUPE_FLAGS,csv"`
+}
+
+// IntervalOrDefault returns the interval or a default.
+func (dc DedupeConfig) IntervalOrDefault() time.Duration {
+	if dc.Interval > 0 {
+		return dc.Interval
+	}
+	return DefaultDedupeInterval
+}
+
+// LogfmtConfig is the config for a logfmt formatter.
+type LogfmtConfig struct {
+	HideTimestamp bool   `json:"hideTimestamp,omitempty" yaml:"hideTimestamp,omitempty" env:"LOG_HIDE_TIMESTAMP"`
+	TimeFormat    string `json:"timeFormat,omitempty" yaml:"timeFormat,omitempty" env:"LOG_TIME_FORMAT"`
+}
+
+// TimeFormatOrDefault returns a field value or a default.
+func (lc LogfmtConfig) TimeFormatOrDefault() string {
+	if len(lc.TimeFormat) > 0 {
+		return lc.TimeFormat
+	}
+	return DefaultLogfmtTimeFormat
+}
+
+// OTelConfig is the config for an OpenTelemetry formatter.
+type OTelConfig struct {
+	// ServiceName is written as the `service.name` resource attribute.
+	ServiceName string `json:"serviceName,omitempty" yaml:"serviceName,omitempty" env:"SERVICE_NAME"`
+	// Resource are additional resource attributes.
+	Resource map[string]string `json:"resource,omitempty" yaml:"resource,omitempty"`
+}
+
+// ResourceOrDefault returns the resource attributes including the service name.
+func (oc OTelConfig) ResourceOrDefault() map[string]string {
+	if oc.ServiceName == "" && len(oc.Resource) == 0 {
+		return nil
+	}
+	output := make(map[string]string)
+	for key, value := range oc.Resource {
+		output[key] = value
+	}
+	if oc.ServiceName != "" {
+		output["service.name"] = oc.ServiceName
+	}
+	return output
+}
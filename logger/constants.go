@@ -20,8 +20,10 @@ const (
 
 // Output Formats
 const (
-	FormatJSON = "json"
-	FormatText = "text"
+	FormatJSON   = "json"
+	FormatText   = "text"
+	FormatLogfmt = "logfmt"
+	FormatOTel   = "otel"
 )
 
 // Default flags
@@ -34,6 +36,7 @@ var (
 // Environment Variable Names
 const (
 	EnvVarFlags      = "LOG_FLAGS"
+	EnvVarHidden     = "LOG_HIDDEN"
 	EnvVarFormat     = "LOG_FORMAT"
 	EnvVarNoColor    = "NO_COLOR"
 	EnvVarHideTime   = "LOG_HIDE_TIME"
@@ -44,6 +47,8 @@ const (
 const (
 	// DefaultBufferPoolSize is the default buffer pool size.
 	DefaultBufferPoolSize = 1 << 8 // 256
+	// DefaultLogfmtTimeFormat is the default logfmt time format.
+	DefaultLogfmtTimeFormat = time.RFC3339Nano
 	// DefaultTextTimeFormat is the default time format.
 	DefaultTextTimeFormat = time.RFC3339Nano
 	// DefaultTextWriterUseColor is a default setting for writers.
@@ -60,18 +65,69 @@ const (
 	DefaultWorkerQueueDepth = 1 << 10
 )
 
+const (
+	// DefaultSamplerInterval is the default interval for sampler `First` limits.
+	DefaultSamplerInterval = time.Second
+	// DefaultDedupeInterval is the default interval duplicate events are suppressed for.
+	DefaultDedupeInterval = 10 * time.Second
+)
+
+const (
+	// DefaultRotatingFileMode is the default file mode for rotating files.
+	DefaultRotatingFileMode = 0644
+	// RotatingFileTimestampFormat is the format of the timestamp suffix for rotated files.
+	RotatingFileTimestampFormat = "20060102T150405.000000000Z"
+	// RotatingFileTempExtension is the extension used for in-progress compressed files.
+	RotatingFileTempExtension = ".tmp"
+)
+
+// Output Destinations
+const (
+	OutputStdout = "stdout"
+	OutputStderr = "stderr"
+	OutputFile   = "file"
+)
+
 // String constants
 const (
-	Space   = " "
-	Newline = "\n"
+	Space          = " "
+	Newline        = "\n"
+	ScopeSeparator = " > "
 )
 
 // Common json fields
 const (
-	FieldFlag      = "flag"
-	FieldTimestamp = "_timestamp"
-	FieldMessage   = "message"
-	FieldFields    = "fields"
+	FieldFlag        = "flag"
+	FieldTimestamp   = "_timestamp"
+	FieldMessage     = "message"
+	FieldFields      = "fields"
+	FieldErr         = "err"
+	FieldPath        = "_path"
+	FieldLabels      = "labels"
+	FieldAnnotations = "annotations"
+)
+
+// Flags handler audit event fields
+const (
+	FlagsAuditNoun        = "logger.flags"
+	FlagsAuditVerbEnable  = "enable"
+	FlagsAuditVerbDisable = "disable"
+	FlagsAuditVerbUnset   = "unset"
+	FlagsAuditVerbHide    = "hide"
+	FlagsAuditVerbShow    = "show"
+	FlagsAuditVerbRevert  = "revert"
+)
+
+// OpenTelemetry attribute names
+const (
+	OTelAttributeFlag = "logger.flag"
+	OTelAttributePath = "logger.path"
+
+	// Prefixes of the attributes for sub-context fields, event labels and event annotations,
+	// so they don't overwrite the fields of the event.
+	OTelAttributePrefixField      = "field."
+	OTelAttributePrefixLabel      = "label."
+	OTelAttributePrefixAnnotation = "annotation."
 )
 
 // JSON Formatter defaults
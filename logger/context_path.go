@@ -1,6 +1,9 @@
 package logger
 
-import "context"
+import (
+	"context"
+	"strings"
+)
 
 type subContextMetaKey struct{}
 
@@ -28,3 +31,18 @@ func GetSubContextMeta(ctx context.Context) (path []string, fields Fields) {
 	}
 	return
 }
+
+// ScopeName returns the scope name for a sub-context path, e.g. `db > migrations`.
+func ScopeName(path ...string) string {
+	return strings.Join(path, ScopeSeparator)
+}
+
+// ParseScopeName returns the sub-context path for a scope name.
+func ParseScopeName(name string) (path []string) {
+	for _, segment := range strings.Split(name, strings.TrimSpace(ScopeSeparator)) {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			path = append(path, segment)
+		}
+	}
+	return
+}
@@ -0,0 +1,189 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NewDeduper returns a new deduper.
+func NewDeduper(options ...DeduperOption) *Deduper {
+	d := &Deduper{
+		Interval: DefaultDedupeInterval,
+		entries:  make(map[string]*dedupeEntry),
+	}
+	for _, option := range options {
+		option(d)
+	}
+	return d
+}
+
+// NewDeduperFromConfig returns a new deduper from a config.
+func NewDeduperFromConfig(cfg DedupeConfig) *Deduper {
+	return NewDeduper(
+		OptDeduperInterval(cfg.IntervalOrDefault()),
+		OptDeduperFlags(cfg.Flags...),
+	)
+}
+
+// DeduperOption is an option for dedupers.
+type DeduperOption func(*Deduper)
+
+// OptDeduperInterval sets the deduper interval.
+func OptDeduperInterval(interval time.Duration) DeduperOption {
+	return func(d *Deduper) { d.Interval = interval }
+}
+
+// OptDeduperFlags sets the flags the deduper applies to.
+// If no flags are set, the deduper applies to all flags.
+func OptDeduperFlags(flags ...string) DeduperOption {
+	return func(d *Deduper) {
+		if len(flags) > 0 {
+			d.Flags = NewFlags(flags...)
+		}
+	}
+}
+
+// Deduper suppresses identical events, that is events with the same flag, sub-context path and
+// text output, that are triggered within an interval of the first occurrence.
+//
+// Once the interval elapses, a summary event noting how many times the event was repeated is emitted,
+// either when the event is next triggered or, for a logger, when it sweeps the deduper on the interval.
+type Deduper struct {
+	sync.Mutex
+
+	Interval time.Duration
+	Flags    *Flags
+
+	entries   map[string]*dedupeEntry
+	lastSweep time.Time
+}
+
+type dedupeEntry struct {
+	Flag     string
+	Path     []string
+	Fields   Fields
+	Message  string
+	First    time.Time
+	Repeated int
+}
+
+// Check returns if the event should be kept, along with any summary events
+// for suppressed events whose interval has elapsed.
+func (d *Deduper) Check(ctx context.Context, e Event, now time.Time) (allow bool, summaries []*DedupeSummaryEvent) {
+	flag := e.GetFlag()
+	if d.Flags != nil && !d.Flags.IsEnabled(flag) {
+		allow = true
+		return
+	}
+
+	path, fields := GetSubContextMeta(ctx)
+	message := DedupeMessage(e)
+	if message == "" {
+		allow = true
+		return
+	}
+	key := flag + "|" + strings.Join(path, ">") + "|" + message
+
+	d.Lock()
+	defer d.Unlock()
+
+	summaries = d.sweepUnsafe(now)
+
+	if entry, ok := d.entries[key]; ok {
+		if now.Sub(entry.First) < d.Interval {
+			entry.Repeated++
+			return
+		}
+		if entry.Repeated > 0 {
+			summaries = append(summaries, entry.Summary())
+		}
+	}
+
+	d.entries[key] = &dedupeEntry{
+		Flag:    flag,
+		Path:    path,
+		Fields:  fields,
+		Message: message,
+		First:   now,
+	}
+	allow = true
+	return
+}
+
+// Flush removes all tracked events and returns summary events for any that were suppressed.
+func (d *Deduper) Flush() (summaries []*DedupeSummaryEvent) {
+	d.Lock()
+	defer d.Unlock()
+
+	for key, entry := range d.entries {
+		if entry.Repeated > 0 {
+			summaries = append(summaries, entry.Summary())
+		}
+		delete(d.entries, key)
+	}
+	return
+}
+
+// Sweep removes the tracked events whose interval has elapsed, and returns summary events for any that were suppressed.
+func (d *Deduper) Sweep(now time.Time) []*DedupeSummaryEvent {
+	d.Lock()
+	defer d.Unlock()
+	d.lastSweep = now
+	return d.expireUnsafe(now)
+}
+
+// sweepUnsafe removes expired entries, returning summaries for those that were suppressed.
+// It will only do work once per interval.
+func (d *Deduper) sweepUnsafe(now time.Time) []*DedupeSummaryEvent {
+	if now.Sub(d.lastSweep) < d.Interval {
+		return nil
+	}
+	d.lastSweep = now
+	return d.expireUnsafe(now)
+}
+
+func (d *Deduper) expireUnsafe(now time.Time) (summaries []*DedupeSummaryEvent) {
+	for key, entry := range d.entries {
+		if now.Sub(entry.First) < d.Interval {
+			continue
+		}
+		if entry.Repeated > 0 {
+			summaries = append(summaries, entry.Summary())
+		}
+		delete(d.entries, key)
+	}
+	return
+}
+
+// Summary returns the summary event for the entry.
+func (de dedupeEntry) Summary() *DedupeSummaryEvent {
+	return &DedupeSummaryEvent{
+		MessageEvent: NewMessageEvent(de.Flag, fmt.Sprintf("%s (repeated %d times)", de.Message, de.Repeated)),
+		Path:         de.Path,
+		Fields:       de.Fields,
+		Repeated:     de.Repeated,
+	}
+}
+
+// DedupeSummaryEvent is emitted when suppressed duplicate events are collapsed.
+type DedupeSummaryEvent struct {
+	*MessageEvent
+	Path     []string
+	Fields   Fields
+	Repeated int
+}
+
+// Context returns the sub-context the original events were triggered in.
+func (dse DedupeSummaryEvent) Context(ctx context.Context) context.Context {
+	return WithSubContextMeta(ctx, dse.Path, dse.Fields)
+}
+
+// DedupeMessage returns the text used to compare events for equality.
+// Events that are neither TextWritable nor fmt.Stringers return an empty string,
+// and are never deduplicated.
+func DedupeMessage(e Event) string {
+	return EventText(e)
+}
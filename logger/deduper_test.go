@@ -0,0 +1,141 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestDeduperCheck(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeduper(OptDeduperInterval(time.Minute))
+	now := time.Date(2019, 01, 01, 12, 00, 00, 00, time.UTC)
+
+	allow, summaries := d.Check(context.Background(), NewErrorEvent(Error, fmt.Errorf("test")), now)
+	assert.True(allow)
+	assert.Empty(summaries)
+
+	allow, summaries = d.Check(context.Background(), NewErrorEvent(Error, fmt.Errorf("test")), now.Add(time.Second))
+	assert.False(allow)
+	assert.Empty(summaries)
+
+	allow, summaries = d.Check(context.Background(), NewErrorEvent(Error, fmt.Errorf("test")), now.Add(2*time.Second))
+	assert.False(allow)
+	assert.Empty(summaries)
+
+	// different message
+	allow, _ = d.Check(context.Background(), NewErrorEvent(Error, fmt.Errorf("not test")), now.Add(2*time.Second))
+	assert.True(allow)
+
+	// different sub-context path
+	allow, _ = d.Check(WithSubContextMeta(context.Background(), []string{"sc"}, nil), NewErrorEvent(Error, fmt.Errorf("test")), now.Add(2*time.Second))
+	assert.True(allow)
+
+	allow, summaries = d.Check(context.Background(), NewErrorEvent(Error, fmt.Errorf("test")), now.Add(time.Minute))
+	assert.True(allow)
+	assert.Len(summaries, 1)
+	assert.Equal(2, summaries[0].Repeated)
+	assert.Equal(Error, summaries[0].GetFlag())
+	assert.Equal("test (repeated 2 times)", summaries[0].Message)
+}
+
+func TestDeduperFlags(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeduper(OptDeduperFlags(Error))
+	now := time.Now().UTC()
+
+	allow, _ := d.Check(context.Background(), NewMessageEvent(Info, "test"), now)
+	assert.True(allow)
+	allow, _ = d.Check(context.Background(), NewMessageEvent(Info, "test"), now)
+	assert.True(allow)
+}
+
+func TestDeduperFlush(t *testing.T) {
+	assert := assert.New(t)
+
+	d := NewDeduper()
+	now := time.Now().UTC()
+
+	d.Check(context.Background(), NewMessageEvent(Info, "test"), now)
+	d.Check(context.Background(), NewMessageEvent(Info, "test"), now)
+	d.Check(context.Background(), NewMessageEvent(Info, "once"), now)
+
+	summaries := d.Flush()
+	assert.Len(summaries, 1)
+	assert.Equal(1, summaries[0].Repeated)
+	assert.Empty(d.Flush())
+}
+
+func TestLoggerDedupe(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log, err := New(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptDedupe(OptDeduperInterval(time.Hour)),
+	)
+	assert.Nil(err)
+
+	sc := log.SubContext("dedupe")
+	for x := 0; x < 5; x++ {
+		sc.Errorf("this is errorf")
+	}
+	assert.Nil(log.Drain())
+
+	assert.Equal(1, strings.Count(output.String(), "[error] [dedupe] this is errorf\n"))
+	assert.Contains(output.String(), "[error] [dedupe] this is errorf (repeated 4 times)")
+}
+
+// lockedBuffer is a buffer that can be written and read concurrently.
+type lockedBuffer struct {
+	sync.Mutex
+	bytes.Buffer
+}
+
+func (lb *lockedBuffer) Write(contents []byte) (int, error) {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.Buffer.Write(contents)
+}
+
+func (lb *lockedBuffer) String() string {
+	lb.Lock()
+	defer lb.Unlock()
+	return lb.Buffer.String()
+}
+
+func TestLoggerDedupeSweep(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(lockedBuffer)
+	log, err := New(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptDedupe(OptDeduperInterval(20*time.Millisecond)),
+	)
+	assert.Nil(err)
+	defer log.Close()
+
+	// the summary of a burst is written on the interval, without the event being triggered again.
+	for x := 0; x < 3; x++ {
+		log.SyncTrigger(context.Background(), NewMessageEvent(Info, "burst"))
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(output.String(), "burst (repeated 2 times)") && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Contains(output.String(), "[info] burst (repeated 2 times)")
+	assert.Equal(1, strings.Count(output.String(), "[info] burst\n"))
+
+	assert.Nil(log.Close())
+	assert.Nil(log.dedupeStop)
+}
@@ -79,7 +79,7 @@ func (e ErrorEvent) WriteText(formatter TextFormatter, output io.Writer) {
 // MarshalJSON implements json.Marshaler.
 func (e ErrorEvent) MarshalJSON() ([]byte, error) {
 	return json.Marshal(MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
-		"err":   e.Err,
+		"err":   MarshalableError(e.Err),
 		"state": e.State,
 	}))
 }
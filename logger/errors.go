@@ -0,0 +1,16 @@
+package logger
+
+import "github.com/blend/go-sdk/ex"
+
+const (
+	// ErrInvalidSinkOutput is returned if a sink config has an unknown output.
+	ErrInvalidSinkOutput ex.Class = "logger; invalid sink output"
+	// ErrFlagsChangeEmpty is returned if a flags change has no flags.
+	ErrFlagsChangeEmpty ex.Class = "logger; flags change is empty"
+	// ErrFlagsChangeInvalid is returned if a flags change is invalid.
+	ErrFlagsChangeInvalid ex.Class = "logger; flags change is invalid"
+	// ErrInvalidJSONEvent is returned if json formatter output cannot be parsed as an event.
+	ErrInvalidJSONEvent ex.Class = "logger; invalid json event"
+	// ErrRotatingFileClosed is returned if a rotating file is written to, rotated or reopened after it is closed.
+	ErrRotatingFileClosed ex.Class = "logger; rotating file is closed"
+)
@@ -59,6 +59,12 @@ func (em EventMeta) GetFlag() string { return em.Flag }
 // GetTimestamp returns the event timestamp.
 func (em EventMeta) GetTimestamp() time.Time { return em.Timestamp }
 
+// GetLabels returns the event labels.
+func (em EventMeta) GetLabels() Labels { return em.Labels }
+
+// GetAnnotations returns the event annotations.
+func (em EventMeta) GetAnnotations() Annotations { return em.Annotations }
+
 // GetFlagColor returns the event flag color
 func (em EventMeta) GetFlagColor() ansi.Color { return em.FlagColor }
 
@@ -69,5 +75,11 @@ func (em EventMeta) Decompose() map[string]interface{} {
 		FieldTimestamp: em.Timestamp.Format(time.RFC3339Nano),
 		FieldFields:    em.Fields,
 	}
+	if len(em.Labels) > 0 {
+		output[FieldLabels] = em.Labels
+	}
+	if len(em.Annotations) > 0 {
+		output[FieldAnnotations] = em.Annotations
+	}
 	return output
 }
@@ -1,7 +1,9 @@
 package logger
 
 import (
+	"sort"
 	"strings"
+	"sync"
 )
 
 // NewFlags returns a new flag set from an array of flag values.
@@ -41,7 +43,9 @@ func FlagsAll() *Flags { return &Flags{all: true, flags: make(map[string]bool)}
 func FlagsNone() *Flags { return &Flags{none: true, flags: make(map[string]bool)} }
 
 // Flags is a set of event flags.
+// It is safe to modify flags while they are being read.
 type Flags struct {
+	lock  sync.RWMutex
 	flags map[string]bool
 	all   bool
 	none  bool
@@ -49,34 +53,48 @@ type Flags struct {
 
 // Enable enables an event flag.
 func (efs *Flags) Enable(flags ...string) {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+
 	efs.none = false
 	for _, flag := range flags {
-		efs.flags[strings.ToLower(strings.TrimSpace(flag))] = true
+		efs.flags[normalizeFlag(flag)] = true
 	}
 }
 
 // Disable disables a flag.
 func (efs *Flags) Disable(flags ...string) {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+
 	for _, flag := range flags {
-		efs.flags[strings.ToLower(strings.TrimSpace(flag))] = false
+		efs.flags[normalizeFlag(flag)] = false
 	}
 }
 
 // SetAll flips the `all` bit on the flag set to true.
 // Note: flags that are explicitly disabled will remain disabled.
 func (efs *Flags) SetAll() {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+
 	efs.all = true
 	efs.none = false
 }
 
 // All returns if the all bit is flipped to true.
 func (efs *Flags) All() bool {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
 	return efs.all
 }
 
 // SetNone flips the `none` bit on the flag set to true.
 // It also disables the `all` bit.
 func (efs *Flags) SetNone() {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+
 	efs.all = false
 	efs.flags = make(map[string]bool)
 	efs.none = true
@@ -84,11 +102,16 @@ func (efs *Flags) SetNone() {
 
 // None returns if the none bit is flipped to true.
 func (efs *Flags) None() bool {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
 	return efs.none
 }
 
 // IsEnabled checks to see if an event is enabled.
-func (efs Flags) IsEnabled(flag string) bool {
+func (efs *Flags) IsEnabled(flag string) bool {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
+
 	if efs.all {
 		// figure out if we explicitly disabled the flag.
 		if enabled, hasEvent := efs.flags[flag]; hasEvent && !enabled {
@@ -107,7 +130,62 @@ func (efs Flags) IsEnabled(flag string) bool {
 	return false
 }
 
-func (efs Flags) String() string {
+// Explicit returns if a flag is enabled and if the flag set explicitly
+// enables or disables it, either by name or with the `all` or `none` bits.
+func (efs *Flags) Explicit(flag string) (enabled, ok bool) {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
+
+	if enabled, ok = efs.flags[flag]; ok {
+		return
+	}
+	if efs.all {
+		return true, true
+	}
+	if efs.none {
+		return false, true
+	}
+	return
+}
+
+// Unset removes any explicit setting for a flag.
+func (efs *Flags) Unset(flags ...string) {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+
+	for _, flag := range flags {
+		delete(efs.flags, normalizeFlag(flag))
+	}
+}
+
+// Enabled returns the explicitly enabled flags, sorted.
+func (efs *Flags) Enabled() []string {
+	return efs.byValue(true)
+}
+
+// Disabled returns the explicitly disabled flags, sorted.
+func (efs *Flags) Disabled() []string {
+	return efs.byValue(false)
+}
+
+func (efs *Flags) byValue(enabled bool) []string {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
+
+	output := []string{}
+	for key, value := range efs.flags {
+		if value == enabled {
+			output = append(output, key)
+		}
+	}
+	sort.Strings(output)
+	return output
+}
+
+func (efs *Flags) String() string {
+	efs.lock.RLock()
+	defer efs.lock.RUnlock()
+
 	if efs.none {
 		return FlagNone
 	}
@@ -131,7 +209,12 @@ func (efs Flags) String() string {
 }
 
 // MergeWith sets the set from another, with the other taking precedence.
-func (efs Flags) MergeWith(other *Flags) {
+func (efs *Flags) MergeWith(other *Flags) {
+	efs.lock.Lock()
+	defer efs.lock.Unlock()
+	other.lock.RLock()
+	defer other.lock.RUnlock()
+
 	if other.all {
 		efs.all = true
 	}
@@ -142,3 +225,8 @@ func (efs Flags) MergeWith(other *Flags) {
 		efs.flags[key] = value
 	}
 }
+
+// normalizeFlag returns a flag trimmed and lowercased.
+func normalizeFlag(flag string) string {
+	return strings.ToLower(strings.TrimSpace(flag))
+}
@@ -0,0 +1,384 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
+
+var (
+	_ http.Handler = (*FlagsHandler)(nil)
+)
+
+// NewFlagsHandler returns a new handler that shows and changes the flags of a logger at runtime.
+//
+// It can be served standalone, or mounted in a web.App with:
+//
+//	handler := web.WrapHandler(logger.NewFlagsHandler(log))
+//	app.Handle(http.MethodGet, "/logger/flags", handler)
+//	app.Handle(http.MethodPost, "/logger/flags", handler)
+func NewFlagsHandler(log *Logger, options ...FlagsHandlerOption) *FlagsHandler {
+	fh := &FlagsHandler{
+		Log:       log,
+		Principal: DefaultFlagsHandlerPrincipal,
+	}
+	for _, option := range options {
+		option(fh)
+	}
+	return fh
+}
+
+// FlagsHandlerOption is an option for flags handlers.
+type FlagsHandlerOption func(*FlagsHandler)
+
+// OptFlagsHandlerPrincipal sets the function that returns the principal for a request,
+// which is used for audit events.
+func OptFlagsHandlerPrincipal(principal func(*http.Request) string) FlagsHandlerOption {
+	return func(fh *FlagsHandler) { fh.Principal = principal }
+}
+
+// OptFlagsHandlerMaxTTL sets the maximum time a change can be applied for.
+// If set, changes without a ttl will use the max ttl.
+func OptFlagsHandlerMaxTTL(maxTTL time.Duration) FlagsHandlerOption {
+	return func(fh *FlagsHandler) { fh.MaxTTL = maxTTL }
+}
+
+// DefaultFlagsHandlerPrincipal returns the basic auth username for a request if set,
+// or the remote address.
+func DefaultFlagsHandlerPrincipal(r *http.Request) string {
+	if username, _, ok := r.BasicAuth(); ok && username != "" {
+		return username
+	}
+	return webutil.GetRemoteAddr(r)
+}
+
+// FlagsHandler is an http.Handler that shows and changes the enabled and hidden flags,
+// and the scope overrides, of a logger at runtime.
+//
+// `GET` returns the current `FlagsState`, and `POST` or `PUT` apply a `FlagsChange` and return the new state.
+// Changes can optionally be reverted automatically after a ttl.
+// Every change and revert is triggered on the logger as an `AuditEvent`.
+type FlagsHandler struct {
+	sync.Mutex
+
+	Log       *Logger
+	Principal func(*http.Request) string
+	MaxTTL    time.Duration
+
+	pending []*flagsRevert
+}
+
+// FlagsChange is a change to the flags of a logger.
+type FlagsChange struct {
+	// Scope is an optional sub-context path, e.g. `db > migrations`, to change flag overrides for.
+	Scope   string   `json:"scope,omitempty"`
+	Enable  []string `json:"enable,omitempty"`
+	Disable []string `json:"disable,omitempty"`
+	// Unset removes explicit settings for flags.
+	Unset []string `json:"unset,omitempty"`
+	// Hide and Show change the hidden flags, and cannot be used with a scope.
+	Hide []string `json:"hide,omitempty"`
+	Show []string `json:"show,omitempty"`
+	// TTL is an optional duration, e.g. `15m`, after which the change is reverted.
+	TTL string `json:"ttl,omitempty"`
+}
+
+// IsZero returns if the change is empty.
+func (fc FlagsChange) IsZero() bool {
+	return len(fc.Enable) == 0 && len(fc.Disable) == 0 && len(fc.Unset) == 0 && len(fc.Hide) == 0 && len(fc.Show) == 0
+}
+
+// FlagsState is the current state of the flags for a logger.
+type FlagsState struct {
+	Flags   FlagsStatus            `json:"flags"`
+	Hidden  []string               `json:"hidden"`
+	Scopes  map[string]FlagsStatus `json:"scopes,omitempty"`
+	Pending []FlagsPendingRevert   `json:"pending,omitempty"`
+}
+
+// FlagsStatus is the state of a set of flags.
+type FlagsStatus struct {
+	All      bool     `json:"all,omitempty"`
+	None     bool     `json:"none,omitempty"`
+	Enabled  []string `json:"enabled"`
+	Disabled []string `json:"disabled"`
+}
+
+// NewFlagsStatus returns the status for a set of flags.
+func NewFlagsStatus(flags *Flags) FlagsStatus {
+	return FlagsStatus{
+		All:      flags.All(),
+		None:     flags.None(),
+		Enabled:  flags.Enabled(),
+		Disabled: flags.Disabled(),
+	}
+}
+
+// FlagsPendingRevert is a change that will be reverted.
+type FlagsPendingRevert struct {
+	Change   FlagsChange `json:"change"`
+	RevertAt time.Time   `json:"revertAt"`
+}
+
+// ServeHTTP implements http.Handler.
+func (fh *FlagsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		webutil.WriteJSON(w, http.StatusOK, fh.State())
+	case http.MethodPost, http.MethodPut:
+		var change FlagsChange
+		if err := webutil.DeserializeReaderAsJSON(&change, r.Body); err != nil {
+			webutil.WriteJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		if err := fh.Apply(fh.auditContext(r), change); err != nil {
+			webutil.WriteJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+			return
+		}
+		webutil.WriteJSON(w, http.StatusOK, fh.State())
+	default:
+		w.Header().Set("Allow", "GET, POST, PUT")
+		webutil.WriteJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": http.StatusText(http.StatusMethodNotAllowed)})
+	}
+}
+
+// State returns the current flags state.
+func (fh *FlagsHandler) State() FlagsState {
+	state := FlagsState{
+		Flags:  NewFlagsStatus(fh.Log.Flags),
+		Hidden: []string{},
+	}
+	if fh.Log.Hidden != nil {
+		state.Hidden = fh.Log.Hidden.Enabled()
+	}
+
+	fh.Log.Lock()
+	if len(fh.Log.Scopes) > 0 {
+		state.Scopes = make(map[string]FlagsStatus)
+		for name, scope := range fh.Log.Scopes {
+			state.Scopes[name] = NewFlagsStatus(scope)
+		}
+	}
+	fh.Log.Unlock()
+
+	fh.Lock()
+	for _, pending := range fh.pending {
+		state.Pending = append(state.Pending, FlagsPendingRevert{Change: pending.Change, RevertAt: pending.RevertAt})
+	}
+	fh.Unlock()
+	sort.Slice(state.Pending, func(i, j int) bool {
+		return state.Pending[i].RevertAt.Before(state.Pending[j].RevertAt)
+	})
+	return state
+}
+
+// Apply applies a change to the logger flags.
+// The audit context should be created with `WithFlagsAuditMeta`.
+func (fh *FlagsHandler) Apply(ctx context.Context, change FlagsChange) error {
+	if change.IsZero() {
+		return ex.New(ErrFlagsChangeEmpty)
+	}
+	if change.Scope != "" && (len(change.Hide) > 0 || len(change.Show) > 0) {
+		return ex.New(ErrFlagsChangeInvalid, ex.OptMessage("hide and show cannot be used with a scope"))
+	}
+
+	var ttl time.Duration
+	if change.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(change.TTL); err != nil {
+			return ex.New(ErrFlagsChangeInvalid, ex.OptInner(err))
+		}
+		if ttl <= 0 {
+			return ex.New(ErrFlagsChangeInvalid, ex.OptMessage("ttl must be positive"))
+		}
+	}
+	if fh.MaxTTL > 0 && (ttl == 0 || ttl > fh.MaxTTL) {
+		ttl = fh.MaxTTL
+	}
+
+	path := ParseScopeName(change.Scope)
+	revert := &flagsRevert{Change: change}
+	fh.Lock()
+	defer fh.Unlock()
+
+	flags := fh.Log.Flags
+	if len(path) > 0 {
+		fh.Log.Lock()
+		_, revert.ScopeExisted = fh.Log.Scopes[ScopeName(path...)]
+		fh.Log.Unlock()
+		flags = fh.Log.Scope(path...)
+	}
+	revert.Flags = snapshotFlags(flags, change.Enable, change.Disable, change.Unset)
+
+	if len(change.Hide) > 0 || len(change.Show) > 0 {
+		if fh.Log.Hidden == nil {
+			fh.Log.Hidden = NewFlags()
+		}
+		revert.Hidden = snapshotFlags(fh.Log.Hidden, change.Hide, change.Show)
+	}
+
+	flags.Enable(change.Enable...)
+	flags.Disable(change.Disable...)
+	flags.Unset(change.Unset...)
+	if fh.Log.Hidden != nil {
+		fh.Log.Hidden.Enable(change.Hide...)
+		fh.Log.Hidden.Unset(change.Show...)
+	}
+	fh.audit(ctx, change, "")
+
+	if ttl > 0 {
+		revert.RevertAt = time.Now().UTC().Add(ttl)
+		revert.Timer = time.AfterFunc(ttl, func() {
+			fh.revert(ctx, revert)
+		})
+		fh.pending = append(fh.pending, revert)
+	}
+	return nil
+}
+
+// Close cancels any pending reverts.
+func (fh *FlagsHandler) Close() {
+	fh.Lock()
+	defer fh.Unlock()
+
+	for _, pending := range fh.pending {
+		pending.Timer.Stop()
+	}
+	fh.pending = nil
+}
+
+// revert reverts a change.
+func (fh *FlagsHandler) revert(ctx context.Context, revert *flagsRevert) {
+	fh.Lock()
+	defer fh.Unlock()
+
+	for index, pending := range fh.pending {
+		if pending == revert {
+			fh.pending = append(fh.pending[:index], fh.pending[index+1:]...)
+			break
+		}
+	}
+
+	path := ParseScopeName(revert.Change.Scope)
+	if len(path) > 0 && !revert.ScopeExisted {
+		fh.Log.RemoveScope(path...)
+	} else if len(path) > 0 {
+		revert.Flags.restore(fh.Log.Scope(path...))
+	} else {
+		revert.Flags.restore(fh.Log.Flags)
+	}
+	if revert.Hidden != nil && fh.Log.Hidden != nil {
+		revert.Hidden.restore(fh.Log.Hidden)
+	}
+	fh.audit(ctx, revert.Change, FlagsAuditVerbRevert)
+}
+
+func (fh *FlagsHandler) auditContext(r *http.Request) context.Context {
+	return WithFlagsAuditMeta(r.Context(), fh.Principal(r), webutil.GetRemoteAddr(r), webutil.GetUserAgent(r))
+}
+
+// audit triggers audit events for a change.
+func (fh *FlagsHandler) audit(ctx context.Context, change FlagsChange, verbOverride string) {
+	meta := GetFlagsAuditMeta(ctx)
+	// the request context may be cancelled by the time a revert fires.
+	auditCtx := context.Background()
+	for verb, flags := range map[string][]string{
+		FlagsAuditVerbEnable:  change.Enable,
+		FlagsAuditVerbDisable: change.Disable,
+		FlagsAuditVerbUnset:   change.Unset,
+		FlagsAuditVerbHide:    change.Hide,
+		FlagsAuditVerbShow:    change.Show,
+	} {
+		for _, flag := range flags {
+			extra := map[string]string{"action": verb}
+			if change.TTL != "" {
+				extra["ttl"] = change.TTL
+			}
+			auditVerb := verb
+			if verbOverride != "" {
+				auditVerb = verbOverride
+			}
+			fh.Log.Trigger(auditCtx, NewAuditEvent(meta.Principal, auditVerb,
+				OptAuditEventNoun(FlagsAuditNoun),
+				OptAuditEventSubject(flag),
+				OptAuditEventProperty(change.Scope),
+				OptAuditEventRemoteAddress(meta.RemoteAddress),
+				OptAuditEventUserAgent(meta.UserAgent),
+				OptAuditEventExtra(extra),
+			))
+		}
+	}
+}
+
+// flagsRevert is a change that will be reverted.
+type flagsRevert struct {
+	Change       FlagsChange
+	RevertAt     time.Time
+	Timer        *time.Timer
+	ScopeExisted bool
+	Flags        flagsSnapshot
+	Hidden       flagsSnapshot
+}
+
+// flagsSnapshot holds the explicit settings for flags before a change.
+type flagsSnapshot map[string]*bool
+
+func snapshotFlags(flags *Flags, sets ...[]string) flagsSnapshot {
+	snapshot := make(flagsSnapshot)
+	flags.lock.RLock()
+	defer flags.lock.RUnlock()
+	for _, set := range sets {
+		for _, flag := range set {
+			flag = normalizeFlag(flag)
+			if enabled, ok := flags.flags[flag]; ok {
+				snapshot[flag] = &enabled
+			} else {
+				snapshot[flag] = nil
+			}
+		}
+	}
+	return snapshot
+}
+
+func (fs flagsSnapshot) restore(flags *Flags) {
+	for flag, enabled := range fs {
+		if enabled == nil {
+			flags.Unset(flag)
+		} else if *enabled {
+			flags.Enable(flag)
+		} else {
+			flags.Disable(flag)
+		}
+	}
+}
+
+type flagsAuditMetaKey struct{}
+
+// FlagsAuditMeta is the request metadata used for flags audit events.
+type FlagsAuditMeta struct {
+	Principal     string
+	RemoteAddress string
+	UserAgent     string
+}
+
+// WithFlagsAuditMeta adds audit metadata to a context.
+func WithFlagsAuditMeta(ctx context.Context, principal, remoteAddress, userAgent string) context.Context {
+	return context.WithValue(ctx, flagsAuditMetaKey{}, FlagsAuditMeta{
+		Principal:     principal,
+		RemoteAddress: remoteAddress,
+		UserAgent:     userAgent,
+	})
+}
+
+// GetFlagsAuditMeta returns audit metadata from a context.
+func GetFlagsAuditMeta(ctx context.Context) FlagsAuditMeta {
+	if typed, ok := ctx.Value(flagsAuditMetaKey{}).(FlagsAuditMeta); ok {
+		return typed
+	}
+	return FlagsAuditMeta{}
+}
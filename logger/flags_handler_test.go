@@ -0,0 +1,147 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestFlagsHandlerGet(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptEnabled(Debug), OptDisabled(Info), OptHidden(Query), OptScope("db > migrations", Debug))
+	handler := NewFlagsHandler(log)
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(http.StatusOK, res.Code)
+
+	var state FlagsState
+	assert.Nil(json.Unmarshal(res.Body.Bytes(), &state))
+	assert.Equal([]string{Debug, Error, Fatal}, state.Flags.Enabled)
+	assert.Equal([]string{Info}, state.Flags.Disabled)
+	assert.Equal([]string{Query}, state.Hidden)
+	assert.Equal([]string{Debug}, state.Scopes["db > migrations"].Enabled)
+}
+
+func TestFlagsHandlerPost(t *testing.T) {
+	assert := assert.New(t)
+
+	audit := new(bytes.Buffer)
+	log := MustNew(OptOutput(nil), OptSink(NewSink("audit", OptSinkFlags(Audit), OptSinkOutput(audit), OptSinkFormatter(NewJSONOutputFormatter()))))
+	log.Enable(Audit)
+	handler := NewFlagsHandler(log)
+
+	res := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"enable":["debug"],"disable":["info"],"hide":["error"]}`))
+	req.SetBasicAuth("ops", "")
+	handler.ServeHTTP(res, req)
+	assert.Equal(http.StatusOK, res.Code, res.Body.String())
+
+	assert.True(log.IsEnabled(Debug))
+	assert.False(log.IsEnabled(Info))
+	assert.True(log.IsHidden(Error))
+	assert.Nil(log.Drain())
+
+	assert.Contains(audit.String(), `"principal":"ops"`)
+	assert.Contains(audit.String(), `"verb":"enable"`)
+	assert.Contains(audit.String(), `"subject":"debug"`)
+	assert.Contains(audit.String(), `"verb":"hide"`)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{}`)))
+	assert.Equal(http.StatusBadRequest, res.Code)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"enable":["debug"],"ttl":"not a duration"}`)))
+	assert.Equal(http.StatusBadRequest, res.Code)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodDelete, "/", nil))
+	assert.Equal(http.StatusMethodNotAllowed, res.Code)
+}
+
+func TestFlagsHandlerScopeRevert(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil))
+	handler := NewFlagsHandler(log)
+	defer handler.Close()
+
+	ctx := WithSubContextMeta(context.Background(), []string{"db", "migrations"}, nil)
+	assert.False(log.IsEnabledFor(ctx, Debug))
+
+	assert.Nil(handler.Apply(context.Background(), FlagsChange{Scope: "db", Enable: []string{Debug}, TTL: "50ms"}))
+	assert.True(log.IsEnabledFor(ctx, Debug))
+	assert.False(log.IsEnabled(Debug))
+	assert.Len(handler.State().Pending, 1)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for log.IsEnabledFor(ctx, Debug) && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.False(log.IsEnabledFor(ctx, Debug))
+	assert.Empty(handler.State().Pending)
+	assert.Empty(log.Scopes)
+}
+
+func TestFlagsHandlerRevertRestoresPrevious(t *testing.T) {
+	assert := assert.New(t)
+
+	log := MustNew(OptOutput(nil), OptDisabled(Debug))
+	handler := NewFlagsHandler(log, OptFlagsHandlerMaxTTL(time.Hour))
+	defer handler.Close()
+
+	assert.Nil(handler.Apply(context.Background(), FlagsChange{Enable: []string{Debug, Query}}))
+	assert.True(log.IsEnabled(Debug))
+	assert.True(log.IsEnabled(Query))
+
+	pending := handler.pending[0]
+	assert.True(pending.RevertAt.After(time.Now().UTC().Add(59 * time.Minute)))
+	pending.Timer.Stop()
+	handler.revert(context.Background(), pending)
+
+	assert.False(log.IsEnabled(Debug))
+	assert.False(log.IsEnabled(Query))
+	_, explicit := log.Flags.Explicit(Query)
+	assert.False(explicit)
+	enabled, explicit := log.Flags.Explicit(Debug)
+	assert.True(explicit)
+	assert.False(enabled)
+}
+
+func TestLoggerScopesAndHidden(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log := MustNew(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptScope("db", Debug, "-"+Info),
+		OptHidden(Error),
+	)
+
+	var errors int
+	log.Listen(Error, "counter", NewErrorEventListener(func(_ context.Context, _ *ErrorEvent) { errors++ }))
+
+	db := log.SubContext("db")
+	db.SubContext("migrations").Debugf("this is debug")
+	db.Infof("this is info")
+	log.Infof("this is also info")
+	log.Debugf("this is also debug")
+	log.SyncTrigger(context.Background(), NewErrorEvent(Error, nil))
+
+	assert.Contains(output.String(), "[debug] [db > migrations] this is debug")
+	assert.NotContains(output.String(), "this is info")
+	assert.Contains(output.String(), "this is also info")
+	assert.NotContains(output.String(), "this is also debug")
+	assert.NotContains(output.String(), "[error]")
+	assert.Equal(1, errors)
+}
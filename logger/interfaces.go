@@ -28,6 +28,16 @@ type WriteTriggerable interface {
 	Writable
 }
 
+// LabelsProvider is a type that provides labels.
+type LabelsProvider interface {
+	GetLabels() Labels
+}
+
+// AnnotationsProvider is a type that provides annotations.
+type AnnotationsProvider interface {
+	GetAnnotations() Annotations
+}
+
 // InfoReceiver is a type that defines Info.
 type InfoReceiver interface {
 	Info(...interface{})
@@ -0,0 +1,238 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// ParseJSONEvent parses a line of json formatter output into an event.
+//
+// Events with built-in flags are reconstructed as their typed events (e.g. `*HTTPResponseEvent`
+// for `http.response`) so they render the same way they would have originally with a text formatter.
+// Events with other flags are reconstructed as `*ErrorEvent` if they have an error,
+// as `*TimedEvent` if they have an elapsed time, and as `*MessageEvent` otherwise.
+func ParseJSONEvent(contents []byte) (*JSONEvent, error) {
+	raw := make(map[string]interface{})
+	if err := json.Unmarshal(contents, &raw); err != nil {
+		return nil, ex.New(err)
+	}
+	flag := jsonString(raw, FieldFlag)
+	if flag == "" {
+		return nil, ex.New(ErrInvalidJSONEvent, ex.OptMessagef("missing field %q", FieldFlag))
+	}
+
+	meta := NewEventMeta(flag)
+	if timestamp := jsonString(raw, FieldTimestamp); timestamp != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return nil, ex.New(ErrInvalidJSONEvent, ex.OptInner(err))
+		}
+		meta.Timestamp = parsed
+	}
+	meta.Labels = Labels(jsonStringMap(raw, FieldLabels))
+	meta.Annotations = Annotations(jsonStringMap(raw, FieldAnnotations))
+
+	je := &JSONEvent{
+		Event:  jsonTypedEvent(meta, raw),
+		Raw:    raw,
+		Fields: jsonStringMap(raw, FieldFields),
+	}
+	if path, ok := raw[FieldPath].([]interface{}); ok {
+		for _, segment := range path {
+			je.Path = append(je.Path, fmt.Sprint(segment))
+		}
+	}
+	return je, nil
+}
+
+// JSONEvent is an event parsed from json formatter output.
+type JSONEvent struct {
+	// Event is the typed event.
+	Event Event
+	// Path is the sub-context path the event was triggered in.
+	Path []string
+	// Fields are the sub-context and event fields.
+	Fields Fields
+	// Raw is the decoded json object.
+	Raw map[string]interface{}
+}
+
+// Context returns the sub-context the event was triggered in.
+func (je JSONEvent) Context(ctx context.Context) context.Context {
+	return WithSubContextMeta(ctx, je.Path, je.Fields)
+}
+
+// GetLabels returns the event labels.
+func (je JSONEvent) GetLabels() Labels {
+	if typed, ok := je.Event.(LabelsProvider); ok {
+		return typed.GetLabels()
+	}
+	return nil
+}
+
+// GetAnnotations returns the event annotations.
+func (je JSONEvent) GetAnnotations() Annotations {
+	if typed, ok := je.Event.(AnnotationsProvider); ok {
+		return typed.GetAnnotations()
+	}
+	return nil
+}
+
+// jsonTypedEvent returns the typed event for a decoded json object.
+func jsonTypedEvent(meta *EventMeta, raw map[string]interface{}) Event {
+	switch meta.Flag {
+	case HTTPRequest:
+		return &HTTPRequestEvent{
+			EventMeta: meta,
+			Request:   jsonRequest(raw),
+		}
+	case HTTPResponse:
+		return &HTTPResponseEvent{
+			EventMeta:       meta,
+			Request:         jsonRequest(raw),
+			ContentLength:   int(jsonNumber(raw, "contentLength")),
+			ContentType:     jsonString(raw, "contentType"),
+			ContentEncoding: jsonString(raw, "contentEncoding"),
+			StatusCode:      int(jsonNumber(raw, "statusCode")),
+			Elapsed:         jsonElapsed(raw),
+		}
+	case Query:
+		return &QueryEvent{
+			EventMeta:  meta,
+			Database:   jsonString(raw, "database"),
+			Engine:     jsonString(raw, "engine"),
+			Username:   jsonString(raw, "username"),
+			QueryLabel: jsonString(raw, "queryLabel"),
+			Body:       jsonString(raw, "body"),
+			Elapsed:    jsonElapsed(raw),
+			Err:        jsonError(raw[FieldErr]),
+		}
+	case RPC:
+		return &RPCEvent{
+			EventMeta:   meta,
+			Engine:      jsonString(raw, "engine"),
+			Peer:        jsonString(raw, "peer"),
+			Method:      jsonString(raw, "method"),
+			UserAgent:   jsonString(raw, "userAgent"),
+			Authority:   jsonString(raw, "authority"),
+			ContentType: jsonString(raw, "contentType"),
+			Elapsed:     jsonElapsed(raw),
+			Err:         jsonError(raw[FieldErr]),
+		}
+	case Audit:
+		return &AuditEvent{
+			EventMeta:     meta,
+			Context:       jsonString(raw, "context"),
+			Principal:     jsonString(raw, "principal"),
+			Verb:          jsonString(raw, "verb"),
+			Noun:          jsonString(raw, "noun"),
+			Subject:       jsonString(raw, "subject"),
+			Property:      jsonString(raw, "property"),
+			RemoteAddress: jsonString(raw, "remoteAddr"),
+			UserAgent:     jsonString(raw, "ua"),
+			Extra:         jsonStringMap(raw, "extra"),
+		}
+	}
+
+	if err := jsonError(raw[FieldErr]); err != nil {
+		return &ErrorEvent{
+			EventMeta: meta,
+			Err:       err,
+			State:     raw["state"],
+		}
+	}
+	if _, ok := raw["elapsed"].(float64); ok {
+		return &TimedEvent{
+			EventMeta: meta,
+			Message:   jsonString(raw, FieldMessage),
+			Elapsed:   jsonElapsed(raw),
+		}
+	}
+	return &MessageEvent{
+		EventMeta: meta,
+		Message:   jsonString(raw, FieldMessage),
+	}
+}
+
+// jsonRequest returns a request with the fields written for http events.
+func jsonRequest(raw map[string]interface{}) *http.Request {
+	req := &http.Request{
+		Method:     jsonString(raw, "verb"),
+		URL:        &url.URL{Path: jsonString(raw, "path")},
+		Host:       jsonString(raw, "host"),
+		RemoteAddr: jsonString(raw, "ip"),
+		Header:     http.Header{},
+	}
+	if userAgent := jsonString(raw, "userAgent"); userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+	return req
+}
+
+// jsonError returns an error for a decoded `err` field.
+// Exceptions are reconstructed as `*ex.Ex`, and other errors as an `ex.Class` of their message.
+func jsonError(value interface{}) error {
+	switch typed := value.(type) {
+	case string:
+		if typed == "" {
+			return nil
+		}
+		return ex.Class(typed)
+	case map[string]interface{}:
+		parsed := &ex.Ex{
+			Class:   ex.Class(jsonString(typed, "Class")),
+			Message: jsonString(typed, "Message"),
+			Inner:   jsonError(typed["Inner"]),
+		}
+		if stack, ok := typed["Stack"].([]interface{}); ok {
+			var frames ex.StackStrings
+			for _, frame := range stack {
+				frames = append(frames, fmt.Sprint(frame))
+			}
+			parsed.Stack = frames
+		}
+		return parsed
+	}
+	return nil
+}
+
+// jsonElapsed returns the `elapsed` field, which is written in milliseconds.
+func jsonElapsed(raw map[string]interface{}) time.Duration {
+	return time.Duration(jsonNumber(raw, "elapsed") * float64(time.Millisecond))
+}
+
+func jsonString(raw map[string]interface{}, key string) string {
+	if value, ok := raw[key].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func jsonNumber(raw map[string]interface{}, key string) float64 {
+	if value, ok := raw[key].(float64); ok {
+		return value
+	}
+	return 0
+}
+
+func jsonStringMap(raw map[string]interface{}, key string) map[string]string {
+	values, ok := raw[key].(map[string]interface{})
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	output := make(map[string]string, len(values))
+	for key, value := range values {
+		if typed, ok := value.(string); ok {
+			output[key] = typed
+		} else {
+			output[key] = fmt.Sprint(value)
+		}
+	}
+	return output
+}
@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestParseJSONEventRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2019, 01, 02, 03, 04, 05, 00, time.UTC)
+	req := &http.Request{Method: "GET", Host: "example.com", URL: &url.URL{Path: "/foo"}, RemoteAddr: "127.0.0.1", Header: http.Header{"User-Agent": []string{"go-sdk"}}}
+	qe := NewQueryEvent("select 1", time.Millisecond, OptEventMetaTimestamp(ts))
+	qe.Database = "postgres"
+	qe.Err = fmt.Errorf("bad query")
+
+	testCases := []Event{
+		NewMessageEvent(Info, "this is a test", OptEventMetaTimestamp(ts)),
+		NewErrorEvent(Error, ex.New("this is an error"), OptErrorEventMetaOptions(OptEventMetaTimestamp(ts))),
+		NewHTTPRequestEvent(req, OptHTTPRequestEventOptionMetaOptions(OptEventMetaTimestamp(ts))),
+		NewHTTPResponseEvent(req, OptHTTPResponseStatusCode(200), OptHTTPResponseContentLength(512), OptHTTPResponseElapsed(time.Second), OptHTTPResponseMetaOptions(OptEventMetaTimestamp(ts))),
+		qe,
+		NewAuditEvent("bailey", "pet", OptAuditEventExtra(map[string]string{"dog": "woof"}), OptAuditEventMetaOptions(OptEventMetaTimestamp(ts))),
+	}
+
+	jf := NewJSONOutputFormatter()
+	tf := NewTextOutputFormatter(OptTextNoColor())
+	for _, tc := range testCases {
+		jsonOutput := new(bytes.Buffer)
+		assert.Nil(jf.WriteFormat(context.Background(), jsonOutput, tc))
+
+		je, err := ParseJSONEvent(jsonOutput.Bytes())
+		assert.Nil(err)
+		assert.Equal(tc.GetFlag(), je.Event.GetFlag())
+		assert.Equal(ts, je.Event.GetTimestamp())
+
+		expected, actual := new(bytes.Buffer), new(bytes.Buffer)
+		assert.Nil(tf.WriteFormat(context.Background(), expected, tc))
+		assert.Nil(tf.WriteFormat(je.Context(context.Background()), actual, je.Event))
+		assert.Equal(expected.String(), actual.String())
+	}
+}
+
+func TestParseJSONEventSubContext(t *testing.T) {
+	assert := assert.New(t)
+
+	e := NewMessageEvent(Info, "this is a test", OptEventMetaTimestamp(time.Now().UTC()))
+	e.Labels = Labels{"env": "prod"}
+	e.Annotations = Annotations{"note": "hello"}
+
+	buffer := new(bytes.Buffer)
+	ctx := WithSubContextMeta(context.Background(), []string{"db", "migrations"}, Fields{"foo": "bar"})
+	assert.Nil(NewJSONOutputFormatter().WriteFormat(ctx, buffer, e))
+
+	je, err := ParseJSONEvent(buffer.Bytes())
+	assert.Nil(err)
+	assert.Equal([]string{"db", "migrations"}, je.Path)
+	assert.Equal("bar", je.Fields["foo"])
+	assert.Equal("prod", je.GetLabels()["env"])
+	assert.Equal("hello", je.GetAnnotations()["note"])
+
+	typed, ok := je.Event.(*MessageEvent)
+	assert.True(ok)
+	assert.Equal("this is a test", typed.Message)
+}
+
+func TestParseJSONEventInvalid(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := ParseJSONEvent([]byte("not json"))
+	assert.NotNil(err)
+
+	_, err = ParseJSONEvent([]byte(`{"message":"no flag"}`))
+	assert.True(ex.Is(err, ErrInvalidJSONEvent))
+}
@@ -53,9 +53,46 @@ func (jw JSONOutputFormatter) WriteFormat(ctx context.Context, output io.Writer,
 	if jw.Pretty {
 		encoder.SetIndent(jw.PrettyPrefix, jw.PrettyIndent)
 	}
-	if err := encoder.Encode(e); err != nil {
+
+	var value interface{} = e
+	if path, fields := GetSubContextMeta(ctx); len(path) > 0 || len(fields) > 0 {
+		decomposed, err := jw.withSubContextMeta(e, path, fields)
+		if err != nil {
+			return err
+		}
+		value = decomposed
+	}
+	if err := encoder.Encode(value); err != nil {
 		return err
 	}
 	_, err := io.Copy(output, buffer)
 	return err
 }
+
+// withSubContextMeta returns the decomposed event with the sub-context path and fields.
+func (jw JSONOutputFormatter) withSubContextMeta(e Event, path []string, fields Fields) (map[string]interface{}, error) {
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	decomposed := make(map[string]interface{})
+	if err = json.Unmarshal(contents, &decomposed); err != nil {
+		return nil, err
+	}
+	if len(path) > 0 {
+		decomposed[FieldPath] = path
+	}
+	if len(fields) > 0 {
+		merged := make(map[string]interface{})
+		if existing, ok := decomposed[FieldFields].(map[string]interface{}); ok {
+			for key, value := range existing {
+				merged[key] = value
+			}
+		}
+		for key, value := range fields {
+			merged[key] = value
+		}
+		decomposed[FieldFields] = merged
+	}
+	return decomposed, nil
+}
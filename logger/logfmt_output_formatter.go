@@ -0,0 +1,164 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/bufferutil"
+)
+
+var (
+	_ WriteFormatter = (*LogfmtOutputFormatter)(nil)
+)
+
+// NewLogfmtOutputFormatter returns a new logfmt event formatter.
+func NewLogfmtOutputFormatter(options ...LogfmtOutputFormatterOption) *LogfmtOutputFormatter {
+	lf := &LogfmtOutputFormatter{
+		BufferPool: bufferutil.NewPool(DefaultBufferPoolSize),
+		TimeFormat: DefaultLogfmtTimeFormat,
+	}
+	for _, option := range options {
+		option(lf)
+	}
+	return lf
+}
+
+// LogfmtOutputFormatterOption is an option for logfmt formatters.
+type LogfmtOutputFormatterOption func(*LogfmtOutputFormatter)
+
+// OptLogfmtConfig sets a logfmt formatter from a config.
+func OptLogfmtConfig(cfg *LogfmtConfig) LogfmtOutputFormatterOption {
+	return func(lf *LogfmtOutputFormatter) {
+		lf.HideTimestamp = cfg.HideTimestamp
+		lf.TimeFormat = cfg.TimeFormatOrDefault()
+	}
+}
+
+// OptLogfmtHideTimestamp hides the timestamp in output.
+func OptLogfmtHideTimestamp() LogfmtOutputFormatterOption {
+	return func(lf *LogfmtOutputFormatter) { lf.HideTimestamp = true }
+}
+
+// LogfmtOutputFormatter writes events as logfmt, i.e. `key=value` pairs.
+//
+// Each line starts with `ts`, `flag`, `scope` (the sub-context path) and `msg` (the text output of the event),
+// followed by the json fields of the event in sorted order, with nested values flattened as `parent.child=value`.
+// The `message` and `err` fields are skipped if they are the same as `msg`.
+type LogfmtOutputFormatter struct {
+	BufferPool    *bufferutil.Pool
+	HideTimestamp bool
+	TimeFormat    string
+}
+
+// WriteFormat implements write formatter.
+func (lf LogfmtOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
+	fields, err := DecomposeEvent(e)
+	if err != nil {
+		return err
+	}
+
+	buffer := lf.BufferPool.Get()
+	defer lf.BufferPool.Put(buffer)
+
+	if !lf.HideTimestamp {
+		lf.writePair(buffer, "ts", e.GetTimestamp().Format(lf.TimeFormat))
+	}
+	lf.writePair(buffer, FieldFlag, e.GetFlag())
+
+	path, contextFields := GetSubContextMeta(ctx)
+	if len(path) > 0 {
+		lf.writePair(buffer, "scope", strings.Join(path, " > "))
+	}
+	message := EventText(e)
+	if message != "" {
+		lf.writePair(buffer, "msg", message)
+	}
+
+	flattened := make(map[string]string)
+	flattenFields(flattened, "", fields)
+	for _, key := range []string{FieldMessage, FieldErr} {
+		if value, ok := flattened[key]; ok && message != "" && value == message {
+			delete(flattened, key)
+		}
+	}
+	for key, value := range contextFields {
+		flattened[key] = value
+	}
+	keys := make([]string, 0, len(flattened))
+	for key := range flattened {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lf.writePair(buffer, key, flattened[key])
+	}
+
+	buffer.WriteString(Newline)
+	_, err = io.Copy(output, buffer)
+	return err
+}
+
+func (lf LogfmtOutputFormatter) writePair(buffer *bytes.Buffer, key, value string) {
+	if key == "" {
+		return
+	}
+	if buffer.Len() > 0 {
+		buffer.WriteString(Space)
+	}
+	buffer.WriteString(LogfmtKey(key))
+	buffer.WriteString("=")
+	buffer.WriteString(LogfmtValue(value))
+}
+
+// LogfmtKey returns a key with characters that are invalid in logfmt keys replaced with `_`.
+func LogfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+// LogfmtValue returns a value quoted if required by logfmt.
+func LogfmtValue(value string) string {
+	if value == "" {
+		return `""`
+	}
+	if strings.IndexFunc(value, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == '\\'
+	}) != -1 {
+		return fmt.Sprintf("%q", value)
+	}
+	return value
+}
+
+// flattenFields flattens nested decomposed fields into `parent.child` keys.
+func flattenFields(output map[string]string, prefix string, fields map[string]interface{}) {
+	for key, value := range fields {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch typed := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			flattenFields(output, key, typed)
+		case []interface{}:
+			values := make([]string, 0, len(typed))
+			for _, element := range typed {
+				values = append(values, fmt.Sprint(element))
+			}
+			output[key] = strings.Join(values, ",")
+		case time.Time:
+			output[key] = typed.Format(time.RFC3339Nano)
+		default:
+			output[key] = fmt.Sprint(typed)
+		}
+	}
+}
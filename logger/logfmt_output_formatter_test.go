@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestLogfmtOutputFormatterMessage(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2019, 01, 02, 03, 04, 05, 00, time.UTC)
+	buffer := new(bytes.Buffer)
+	lf := NewLogfmtOutputFormatter()
+
+	ctx := WithSubContextMeta(context.Background(), []string{"one", "two"}, Fields{"foo": "bar"})
+	assert.Nil(lf.WriteFormat(ctx, buffer, NewMessageEvent(Info, "this is a test", OptEventMetaTimestamp(ts))))
+	assert.Equal(`ts=2019-01-02T03:04:05Z flag=info scope="one > two" msg="this is a test" foo=bar`+"\n", buffer.String())
+}
+
+func TestLogfmtOutputFormatterError(t *testing.T) {
+	assert := assert.New(t)
+
+	buffer := new(bytes.Buffer)
+	lf := NewLogfmtOutputFormatter(OptLogfmtHideTimestamp())
+
+	assert.Nil(lf.WriteFormat(context.Background(), buffer, NewErrorEvent(Error, fmt.Errorf("this is an error"))))
+	assert.Equal(`flag=error msg="this is an error"`+"\n", buffer.String())
+}
+
+func TestLogfmtOutputFormatterEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Method: "GET", URL: &url.URL{Path: "/foo"}, RemoteAddr: "127.0.0.1:8080"}
+	qe := NewQueryEvent("select 1", time.Millisecond)
+	qe.Database = "postgres"
+	qe.Err = fmt.Errorf("bad query")
+
+	testCases := []struct {
+		Event    Event
+		Expected []string
+	}{
+		{Event: NewHTTPRequestEvent(req), Expected: []string{"flag=http.request", "verb=GET", "path=/foo", "ip=127.0.0.1"}},
+		{Event: NewHTTPResponseEvent(req, OptHTTPResponseStatusCode(200)), Expected: []string{"flag=http.response", "statusCode=200", "elapsed=0"}},
+		{Event: qe, Expected: []string{"flag=db.query", "database=postgres", `body="select 1"`, `err="bad query"`, "elapsed=1"}},
+		{Event: NewRPCEvent("/foo.Bar", time.Second), Expected: []string{"flag=rpc", "method=/foo.Bar", "elapsed=1000"}},
+		{Event: NewAuditEvent("bailey", "pet", OptAuditEventExtra(map[string]string{"dog": "woof"})), Expected: []string{"flag=audit", "principal=bailey", "verb=pet", "extra.dog=woof"}},
+		{Event: Timedf(Info, time.Second, "timed"), Expected: []string{"flag=info", `msg="timed (1s)"`, "elapsed=1000"}},
+	}
+
+	lf := NewLogfmtOutputFormatter(OptLogfmtHideTimestamp())
+	for _, tc := range testCases {
+		buffer := new(bytes.Buffer)
+		assert.Nil(lf.WriteFormat(context.Background(), buffer, tc.Event))
+		for _, expected := range tc.Expected {
+			assert.Contains(buffer.String(), expected)
+		}
+	}
+}
+
+func TestLogfmtOutputFormatterScope(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Method: "GET", URL: &url.URL{Path: "/foo"}}
+	buffer := new(bytes.Buffer)
+	lf := NewLogfmtOutputFormatter(OptLogfmtHideTimestamp())
+
+	ctx := WithSubContextMeta(context.Background(), []string{"api"}, nil)
+	assert.Nil(lf.WriteFormat(ctx, buffer, NewHTTPRequestEvent(req)))
+	assert.Contains(buffer.String(), "scope=api")
+	assert.Contains(buffer.String(), "path=/foo", "the sub-context path should not collide with event fields")
+}
+
+func TestLogfmtValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(`""`, LogfmtValue(""))
+	assert.Equal("foo", LogfmtValue("foo"))
+	assert.Equal(`"foo bar"`, LogfmtValue("foo bar"))
+	assert.Equal(`"foo=bar"`, LogfmtValue("foo=bar"))
+	assert.Equal(`"\"foo\""`, LogfmtValue(`"foo"`))
+	assert.Equal("foo_bar", LogfmtKey("foo bar"))
+}
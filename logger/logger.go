@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"sync"
+	"time"
 )
 
 // New returns a new logger with a given set of enabled flags.
@@ -15,6 +16,7 @@ func New(options ...Option) (*Logger, error) {
 		Output:        NewInterlockedWriter(os.Stdout),
 		RecoverPanics: DefaultRecoverPanics,
 		Flags:         NewFlags(DefaultFlags...),
+		Hidden:        NewFlags(),
 	}
 	l.Context = NewContext(l, nil)
 	var err error
@@ -23,6 +25,12 @@ func New(options ...Option) (*Logger, error) {
 			return nil, err
 		}
 	}
+	for _, sink := range l.Sinks {
+		if file, ok := sink.Closer.(*RotatingFile); ok && file.OnError == nil {
+			file.OnError = l.handleError
+		}
+	}
+	l.startDedupeSweep()
 	return l, nil
 }
 
@@ -68,6 +76,19 @@ type Logger struct {
 	Formatter WriteFormatter
 	Errors    chan error
 	Listeners map[string]map[string]*Worker
+
+	Sinks []*Sink
+
+	// Hidden are flags that trigger listeners but are not written to the output or sinks.
+	Hidden *Flags
+	// Scopes are flag overrides for sub-context paths, keyed by `ScopeName(path...)`.
+	Scopes map[string]*Flags
+
+	Samplers map[string]*Sampler
+	Deduper  *Deduper
+
+	dedupeStop    chan struct{}
+	dedupeStopped chan struct{}
 }
 
 // HasListeners returns if there are registered listener for an event.
@@ -181,7 +202,10 @@ func (l *Logger) Trigger(ctx context.Context, e Event) {
 	}
 
 	flag := e.GetFlag()
-	if !l.IsEnabled(flag) {
+	if !l.IsEnabledFor(ctx, flag) {
+		return
+	}
+	if !l.allow(ctx, e) {
 		return
 	}
 
@@ -210,7 +234,10 @@ func (l *Logger) SyncTrigger(ctx context.Context, e Event) {
 	}
 
 	flag := e.GetFlag()
-	if !l.IsEnabled(flag) {
+	if !l.IsEnabledFor(ctx, flag) {
+		return
+	}
+	if !l.allow(ctx, e) {
 		return
 	}
 
@@ -232,19 +259,171 @@ func (l *Logger) SyncTrigger(ctx context.Context, e Event) {
 }
 
 // Write writes an event synchronously to the writer either as a normal even or as an error.
+// It also writes the event to any sinks that have the event flag enabled.
 func (l *Logger) Write(ctx context.Context, e Event) {
-	// if a formater or the output are unset, bail.
-	if l.Formatter == nil || l.Output == nil {
+	if IsSkipWrite(ctx) {
+		return
+	}
+	if l.IsHidden(e.GetFlag()) {
 		return
 	}
 
-	if IsSkipWrite(ctx) {
+	// if a formater or the output are unset, skip the primary output.
+	if l.Formatter != nil && l.Output != nil {
+		err := l.Formatter.WriteFormat(ctx, l.Output, e)
+		if err != nil && l.Errors != nil {
+			l.Errors <- err
+		}
+	}
+
+	for _, sink := range l.Sinks {
+		err := sink.Write(ctx, e)
+		if err != nil && l.Errors != nil {
+			l.Errors <- err
+		}
+	}
+}
+
+// handleError reports an error from the background work of an output, e.g. compressing rotated files.
+// It is sent to the errors channel if it is set and ready to receive it, and written as an error event otherwise.
+func (l *Logger) handleError(err error) {
+	if l.Errors != nil {
+		select {
+		case l.Errors <- err:
+		default:
+		}
 		return
 	}
+	l.Context.Error(err)
+}
+
+// IsEnabledFor returns if a flag is enabled for the sub-context path of a context.
+// The most specific scope that explicitly enables or disables the flag takes precedence
+// over the logger flags.
+func (l *Logger) IsEnabledFor(ctx context.Context, flag string) bool {
+	if path, _ := GetSubContextMeta(ctx); len(path) > 0 {
+		if scope := l.scopeFor(path, flag); scope != nil {
+			enabled, _ := scope.Explicit(flag)
+			return enabled
+		}
+	}
+	return l.IsEnabled(flag)
+}
+
+// IsHidden returns if a flag is hidden, i.e. not written to the output.
+func (l *Logger) IsHidden(flag string) bool {
+	return l.Hidden != nil && l.Hidden.IsEnabled(flag)
+}
+
+// Scope returns the flag overrides for a sub-context path, creating them if they don't exist.
+func (l *Logger) Scope(path ...string) *Flags {
+	l.Lock()
+	defer l.Unlock()
 
-	err := l.Formatter.WriteFormat(ctx, l.Output, e)
-	if err != nil && l.Errors != nil {
-		l.Errors <- err
+	if l.Scopes == nil {
+		l.Scopes = make(map[string]*Flags)
+	}
+	name := ScopeName(path...)
+	scope, ok := l.Scopes[name]
+	if !ok {
+		scope = NewFlags()
+		l.Scopes[name] = scope
+	}
+	return scope
+}
+
+// RemoveScope removes the flag overrides for a sub-context path.
+func (l *Logger) RemoveScope(path ...string) {
+	l.Lock()
+	defer l.Unlock()
+
+	delete(l.Scopes, ScopeName(path...))
+}
+
+// scopeFor returns the most specific scope that explicitly sets a flag for a path.
+func (l *Logger) scopeFor(path []string, flag string) *Flags {
+	l.Lock()
+	defer l.Unlock()
+
+	if len(l.Scopes) == 0 {
+		return nil
+	}
+	for index := len(path); index > 0; index-- {
+		if scope, ok := l.Scopes[ScopeName(path[:index]...)]; ok {
+			if _, explicit := scope.Explicit(flag); explicit {
+				return scope
+			}
+		}
+	}
+	return nil
+}
+
+// allow applies sampling and duplicate suppression to an event.
+// It writes any summaries for duplicate events that are due.
+func (l *Logger) allow(ctx context.Context, e Event) bool {
+	if l.Samplers == nil && l.Deduper == nil {
+		return true
+	}
+
+	now := e.GetTimestamp()
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	if l.Samplers != nil {
+		if sampler, ok := l.Samplers[e.GetFlag()]; ok && !sampler.Allow(now) {
+			return false
+		}
+	}
+
+	if l.Deduper != nil {
+		allow, summaries := l.Deduper.Check(ctx, e, now)
+		l.writeDedupeSummaries(summaries)
+		return allow
+	}
+	return true
+}
+
+// startDedupeSweep starts writing the summaries of suppressed duplicate events on the deduper interval,
+// so the summary of a burst of duplicates is written even if the event isn't triggered again.
+func (l *Logger) startDedupeSweep() {
+	if l.Deduper == nil || l.dedupeStop != nil {
+		return
+	}
+	interval := l.Deduper.Interval
+	if interval <= 0 {
+		interval = DefaultDedupeInterval
+	}
+	deduper, stop, stopped := l.Deduper, make(chan struct{}), make(chan struct{})
+	l.dedupeStop, l.dedupeStopped = stop, stopped
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				l.writeDedupeSummaries(deduper.Sweep(now.UTC()))
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopDedupeSweep stops the deduper sweep and waits for it to finish.
+func (l *Logger) stopDedupeSweep() {
+	if l.dedupeStop == nil {
+		return
+	}
+	close(l.dedupeStop)
+	<-l.dedupeStopped
+	l.dedupeStop, l.dedupeStopped = nil, nil
+}
+
+func (l *Logger) writeDedupeSummaries(summaries []*DedupeSummaryEvent) {
+	for _, summary := range summaries {
+		l.Write(summary.Context(context.Background()), summary)
 	}
 }
 
@@ -255,12 +434,14 @@ func (l *Logger) Write(ctx context.Context, e Event) {
 // Close releases shared resources for the agent.
 func (l *Logger) Close() error {
 	l.Lock()
-	defer l.Lock()
+	defer l.Unlock()
 
 	if l.Flags != nil {
 		l.Flags.SetNone()
 	}
 
+	l.stopDedupeSweep()
+
 	for _, listeners := range l.Listeners {
 		for _, listener := range listeners {
 			listener.Stop()
@@ -272,11 +453,22 @@ func (l *Logger) Close() error {
 		delete(l.Listeners, key)
 	}
 	l.Listeners = nil
-	return nil
+
+	var err error
+	for _, sink := range l.Sinks {
+		if closeErr := sink.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	return err
 }
 
 // Drain waits for the agent to finish its queue of events before closing.
+// It will also write summaries for any suppressed duplicate events.
 func (l *Logger) Drain() error {
+	if l.Deduper != nil {
+		l.writeDedupeSummaries(l.Deduper.Flush())
+	}
 	for _, workers := range l.Listeners {
 		for _, worker := range workers {
 			worker.Drain()
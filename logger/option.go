@@ -3,6 +3,7 @@ package logger
 import (
 	"io"
 	"os"
+	"strings"
 
 	"github.com/blend/go-sdk/env"
 )
@@ -16,7 +17,13 @@ func OptConfig(cfg Config) Option {
 		l.Output = NewInterlockedWriter(os.Stdout)
 		l.Formatter = cfg.Formatter()
 		l.Flags = NewFlags(cfg.FlagsOrDefault()...)
-		return nil
+		l.Hidden = cfg.HiddenFlags()
+		l.Scopes = cfg.ScopeFlags()
+		l.Samplers = cfg.Samplers()
+		l.Deduper = cfg.Deduper()
+		var err error
+		l.Sinks, err = cfg.OutputSinks()
+		return err
 	}
 }
 
@@ -25,13 +32,19 @@ func OptConfig(cfg Config) Option {
 func OptConfigFromEnv() Option {
 	return func(l *Logger) error {
 		cfg := Config{}
-		if err := env.Env().ReadInto(&cfg); err != nil {
+		err := env.Env().ReadInto(&cfg)
+		if err != nil {
 			return err
 		}
 		l.Output = NewInterlockedWriter(os.Stdout)
 		l.Formatter = cfg.Formatter()
 		l.Flags = NewFlags(cfg.FlagsOrDefault()...)
-		return nil
+		l.Hidden = cfg.HiddenFlags()
+		l.Scopes = cfg.ScopeFlags()
+		l.Samplers = cfg.Samplers()
+		l.Deduper = cfg.Deduper()
+		l.Sinks, err = cfg.OutputSinks()
+		return err
 	}
 }
 
@@ -58,6 +71,11 @@ func OptOutput(output io.Writer) Option {
 	}
 }
 
+// OptSink adds an output sink to the logger.
+func OptSink(sink *Sink) Option {
+	return func(l *Logger) error { l.Sinks = append(l.Sinks, sink); return nil }
+}
+
 // OptJSON sets the output formatter for the logger as json.
 func OptJSON(opts ...JSONOutputFormatterOption) Option {
 	return func(l *Logger) error { l.Formatter = NewJSONOutputFormatter(opts...); return nil }
@@ -78,6 +96,22 @@ func OptFlags(flags *Flags) Option {
 	return func(l *Logger) error { l.Flags = flags; return nil }
 }
 
+// OptHidden sets flags that trigger listeners but are not written to the output.
+func OptHidden(flags ...string) Option {
+	return func(l *Logger) error { l.Hidden = NewFlags(flags...); return nil }
+}
+
+// OptScope sets flag overrides for a sub-context path, e.g. `OptScope("db > migrations", "debug")`.
+func OptScope(scope string, flags ...string) Option {
+	return func(l *Logger) error {
+		if l.Scopes == nil {
+			l.Scopes = make(map[string]*Flags)
+		}
+		l.Scopes[ScopeName(ParseScopeName(scope)...)] = NewFlags(flags...)
+		return nil
+	}
+}
+
 // OptAll sets all flags enabled on the logger by default.
 func OptAll() Option {
 	return func(l *Logger) error { l.Flags.SetAll(); return nil }
@@ -97,3 +131,19 @@ func OptEnabled(flags ...string) Option {
 func OptDisabled(flags ...string) Option {
 	return func(l *Logger) error { l.Flags.Disable(flags...); return nil }
 }
+
+// OptSampler sets a sampler for a given flag.
+func OptSampler(flag string, options ...SamplerOption) Option {
+	return func(l *Logger) error {
+		if l.Samplers == nil {
+			l.Samplers = make(map[string]*Sampler)
+		}
+		l.Samplers[strings.ToLower(strings.TrimSpace(flag))] = NewSampler(options...)
+		return nil
+	}
+}
+
+// OptDedupe enables suppressing duplicate events.
+func OptDedupe(options ...DeduperOption) Option {
+	return func(l *Logger) error { l.Deduper = NewDeduper(options...); return nil }
+}
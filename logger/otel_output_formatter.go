@@ -0,0 +1,223 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blend/go-sdk/bufferutil"
+	"github.com/blend/go-sdk/webutil"
+)
+
+var (
+	_ WriteFormatter = (*OTelOutputFormatter)(nil)
+)
+
+// OpenTelemetry severity numbers.
+// See https://github.com/open-telemetry/opentelemetry-specification/blob/main/specification/logs/data-model.md#field-severitynumber
+const (
+	OTelSeverityDebug = 5
+	OTelSeverityInfo  = 9
+	OTelSeverityWarn  = 13
+	OTelSeverityError = 17
+	OTelSeverityFatal = 21
+)
+
+var (
+	// DefaultOTelSeverityNumbers are the severity numbers for known flags.
+	// Flags that are not present are treated as `OTelSeverityInfo`.
+	DefaultOTelSeverityNumbers = map[string]int{
+		Debug:   OTelSeverityDebug,
+		Info:    OTelSeverityInfo,
+		Warning: OTelSeverityWarn,
+		Error:   OTelSeverityError,
+		Fatal:   OTelSeverityFatal,
+	}
+)
+
+// OTelSeverity returns the severity number and text for a flag.
+func OTelSeverity(flag string) (number int, text string) {
+	number, ok := DefaultOTelSeverityNumbers[flag]
+	if !ok {
+		number = OTelSeverityInfo
+	}
+	switch {
+	case number >= OTelSeverityFatal:
+		text = "FATAL"
+	case number >= OTelSeverityError:
+		text = "ERROR"
+	case number >= OTelSeverityWarn:
+		text = "WARN"
+	case number >= OTelSeverityInfo:
+		text = "INFO"
+	case number >= OTelSeverityDebug:
+		text = "DEBUG"
+	default:
+		text = "TRACE"
+	}
+	return
+}
+
+// NewOTelOutputFormatter returns a new OpenTelemetry event formatter.
+func NewOTelOutputFormatter(options ...OTelOutputFormatterOption) *OTelOutputFormatter {
+	of := &OTelOutputFormatter{
+		BufferPool: bufferutil.NewPool(DefaultBufferPoolSize),
+	}
+	for _, option := range options {
+		option(of)
+	}
+	return of
+}
+
+// OTelOutputFormatterOption is an option for OpenTelemetry formatters.
+type OTelOutputFormatterOption func(*OTelOutputFormatter)
+
+// OptOTelConfig sets an OpenTelemetry formatter from a config.
+func OptOTelConfig(cfg *OTelConfig) OTelOutputFormatterOption {
+	return func(of *OTelOutputFormatter) {
+		of.Resource = cfg.ResourceOrDefault()
+	}
+}
+
+// OptOTelResource sets the resource attributes written with every record.
+func OptOTelResource(resource map[string]string) OTelOutputFormatterOption {
+	return func(of *OTelOutputFormatter) { of.Resource = resource }
+}
+
+// OTelOutputFormatter writes events as json log records following the OpenTelemetry log data model.
+//
+// The body of the record is the text output of the event, and the json fields of the event,
+// the sub-context path and fields, and the event labels and annotations are written as attributes.
+// Sub-context fields, labels and annotations are prefixed with `field.`, `label.` and `annotation.`
+// so they don't overwrite the fields of the event.
+// Trace and span ids are read from the context with `GetTraceContext`.
+type OTelOutputFormatter struct {
+	BufferPool *bufferutil.Pool
+	Resource   map[string]string
+}
+
+// OTelLogRecord is a log record in the OpenTelemetry log data model.
+type OTelLogRecord struct {
+	TimeUnixNano         string            `json:"timeUnixNano"`
+	ObservedTimeUnixNano string            `json:"observedTimeUnixNano"`
+	SeverityNumber       int               `json:"severityNumber"`
+	SeverityText         string            `json:"severityText"`
+	Body                 string            `json:"body,omitempty"`
+	Attributes           map[string]string `json:"attributes,omitempty"`
+	Resource             map[string]string `json:"resource,omitempty"`
+	TraceID              string            `json:"traceId,omitempty"`
+	SpanID               string            `json:"spanId,omitempty"`
+}
+
+// Record returns the log record for an event.
+func (of OTelOutputFormatter) Record(ctx context.Context, e Event) (*OTelLogRecord, error) {
+	fields, err := DecomposeEvent(e)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &OTelLogRecord{
+		TimeUnixNano:         strconv.FormatInt(e.GetTimestamp().UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UTC().UnixNano(), 10),
+		Body:                 EventText(e),
+		Resource:             of.Resource,
+	}
+	record.SeverityNumber, record.SeverityText = OTelSeverity(e.GetFlag())
+	record.TraceID, record.SpanID = GetTraceContext(ctx)
+
+	attributes := make(map[string]string)
+	flattenFields(attributes, "", fields)
+
+	attributes[OTelAttributeFlag] = e.GetFlag()
+	path, contextFields := GetSubContextMeta(ctx)
+	if len(path) > 0 {
+		attributes[OTelAttributePath] = strings.Join(path, " > ")
+	}
+	for key, value := range contextFields {
+		attributes[OTelAttributePrefixField+key] = value
+	}
+	if typed, ok := e.(LabelsProvider); ok {
+		for key, value := range typed.GetLabels() {
+			attributes[OTelAttributePrefixLabel+key] = value
+		}
+	}
+	if typed, ok := e.(AnnotationsProvider); ok {
+		for key, value := range typed.GetAnnotations() {
+			attributes[OTelAttributePrefixAnnotation+key] = value
+		}
+	}
+	for key, value := range otelSemanticAttributes(e) {
+		attributes[key] = value
+	}
+
+	record.Attributes = attributes
+	return record, nil
+}
+
+// WriteFormat implements write formatter.
+func (of OTelOutputFormatter) WriteFormat(ctx context.Context, output io.Writer, e Event) error {
+	record, err := of.Record(ctx, e)
+	if err != nil {
+		return err
+	}
+
+	buffer := of.BufferPool.Get()
+	defer of.BufferPool.Put(buffer)
+
+	if err = json.NewEncoder(buffer).Encode(record); err != nil {
+		return err
+	}
+	_, err = io.Copy(output, buffer)
+	return err
+}
+
+// otelSemanticAttributes returns OpenTelemetry semantic convention attributes for built-in event types.
+func otelSemanticAttributes(e Event) map[string]string {
+	output := make(map[string]string)
+	if err := EventError(e); err != nil {
+		output["exception.message"] = err.Error()
+	}
+	switch typed := e.(type) {
+	case *HTTPRequestEvent:
+		if typed.Request != nil {
+			output["http.request.method"] = typed.Request.Method
+			output["url.path"] = typed.Request.URL.Path
+			output["client.address"] = webutil.GetRemoteAddr(typed.Request)
+			output["user_agent.original"] = webutil.GetUserAgent(typed.Request)
+		}
+		if typed.Route != "" {
+			output["http.route"] = typed.Route
+		}
+	case *HTTPResponseEvent:
+		if typed.Request != nil {
+			output["http.request.method"] = typed.Request.Method
+			output["url.path"] = typed.Request.URL.Path
+			output["client.address"] = webutil.GetRemoteAddr(typed.Request)
+			output["user_agent.original"] = webutil.GetUserAgent(typed.Request)
+		}
+		if typed.Route != "" {
+			output["http.route"] = typed.Route
+		}
+		output["http.response.status_code"] = strconv.Itoa(typed.StatusCode)
+	case *QueryEvent:
+		output["db.system"] = typed.Engine
+		output["db.name"] = typed.Database
+		output["db.user"] = typed.Username
+		output["db.statement"] = typed.Body
+	case *RPCEvent:
+		output["rpc.system"] = typed.Engine
+		output["rpc.method"] = typed.Method
+		output["net.peer.name"] = typed.Peer
+	case *AuditEvent:
+		output["enduser.id"] = typed.Principal
+	}
+	for key, value := range output {
+		if value == "" {
+			delete(output, key)
+		}
+	}
+	return output
+}
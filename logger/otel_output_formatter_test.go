@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestOTelSeverity(t *testing.T) {
+	assert := assert.New(t)
+
+	number, text := OTelSeverity(Fatal)
+	assert.Equal(OTelSeverityFatal, number)
+	assert.Equal("FATAL", text)
+
+	number, text = OTelSeverity(Warning)
+	assert.Equal(OTelSeverityWarn, number)
+	assert.Equal("WARN", text)
+
+	number, text = OTelSeverity(Query)
+	assert.Equal(OTelSeverityInfo, number)
+	assert.Equal("INFO", text)
+}
+
+func TestOTelOutputFormatterWriteFormat(t *testing.T) {
+	assert := assert.New(t)
+
+	ts := time.Date(2019, 01, 02, 03, 04, 05, 00, time.UTC)
+	buffer := new(bytes.Buffer)
+	of := NewOTelOutputFormatter(OptOTelConfig(&OTelConfig{ServiceName: "test-service"}))
+
+	ee := NewErrorEvent(Error, fmt.Errorf("this is an error"), OptErrorEventMetaOptions(OptEventMetaTimestamp(ts)))
+	ee.Labels = Labels{"env": "test", "err": "label"}
+	ee.Annotations = Annotations{"err": "annotation"}
+
+	ctx := WithTraceContext(WithSubContextMeta(context.Background(), []string{"sc"}, Fields{"err": "field"}), "trace-id", "span-id")
+	assert.Nil(of.WriteFormat(ctx, buffer, ee))
+
+	var record OTelLogRecord
+	assert.Nil(json.Unmarshal(buffer.Bytes(), &record))
+	assert.Equal(fmt.Sprint(ts.UnixNano()), record.TimeUnixNano)
+	assert.NotEmpty(record.ObservedTimeUnixNano)
+	assert.Equal(OTelSeverityError, record.SeverityNumber)
+	assert.Equal("ERROR", record.SeverityText)
+	assert.Equal("this is an error", record.Body)
+	assert.Equal("trace-id", record.TraceID)
+	assert.Equal("span-id", record.SpanID)
+	assert.Equal("test-service", record.Resource["service.name"])
+	assert.Equal(Error, record.Attributes[OTelAttributeFlag])
+	assert.Equal("sc", record.Attributes[OTelAttributePath])
+	assert.Equal("this is an error", record.Attributes["exception.message"])
+	assert.Equal("test", record.Attributes["label.env"])
+	assert.Equal("this is an error", record.Attributes["err"], "context fields, labels and annotations should not overwrite event fields")
+	assert.Equal("field", record.Attributes["field.err"])
+	assert.Equal("label", record.Attributes["label.err"])
+	assert.Equal("annotation", record.Attributes["annotation.err"])
+}
+
+func TestOTelOutputFormatterEvents(t *testing.T) {
+	assert := assert.New(t)
+
+	req := &http.Request{Method: "GET", URL: &url.URL{Path: "/foo"}, RemoteAddr: "127.0.0.1:8080"}
+	qe := NewQueryEvent("select 1", time.Millisecond)
+	qe.Engine = "postgres"
+
+	testCases := []struct {
+		Event    Event
+		Expected map[string]string
+	}{
+		{Event: NewHTTPRequestEvent(req), Expected: map[string]string{"http.request.method": "GET", "url.path": "/foo", "client.address": "127.0.0.1"}},
+		{Event: NewHTTPResponseEvent(req, OptHTTPResponseStatusCode(404)), Expected: map[string]string{"http.response.status_code": "404", "statusCode": "404"}},
+		{Event: qe, Expected: map[string]string{"db.system": "postgres", "db.statement": "select 1", "elapsed": "1"}},
+		{Event: NewRPCEvent("/foo.Bar", time.Second), Expected: map[string]string{"rpc.method": "/foo.Bar"}},
+		{Event: NewAuditEvent("bailey", "pet"), Expected: map[string]string{"enduser.id": "bailey", "verb": "pet"}},
+		{Event: NewMessageEvent(Debug, "debug"), Expected: map[string]string{"message": "debug", OTelAttributeFlag: Debug}},
+	}
+
+	of := NewOTelOutputFormatter()
+	for _, tc := range testCases {
+		record, err := of.Record(context.Background(), tc.Event)
+		assert.Nil(err)
+		for key, value := range tc.Expected {
+			assert.Equal(value, record.Attributes[key], key)
+		}
+	}
+}
+
+func TestConfigFormatter(t *testing.T) {
+	assert := assert.New(t)
+
+	_, ok := Config{Format: FormatLogfmt}.Formatter().(*LogfmtOutputFormatter)
+	assert.True(ok)
+	_, ok = Config{Format: FormatOTel}.Formatter().(*OTelOutputFormatter)
+	assert.True(ok)
+	_, ok = Config{}.Formatter().(*TextOutputFormatter)
+	assert.True(ok)
+}
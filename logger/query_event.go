@@ -92,7 +92,7 @@ func (e QueryEvent) MarshalJSON() ([]byte, error) {
 		"username":   e.Username,
 		"queryLabel": e.QueryLabel,
 		"body":       e.Body,
-		"err":        e.Err,
+		"err":        MarshalableError(e.Err),
 		"elapsed":    timeutil.Milliseconds(e.Elapsed),
 	}))
 }
@@ -0,0 +1,358 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+var (
+	_ io.WriteCloser = (*RotatingFile)(nil)
+)
+
+// NewRotatingFile opens a new rotating file.
+func NewRotatingFile(path string, options ...RotatingFileOption) (*RotatingFile, error) {
+	rf := &RotatingFile{
+		Path:     path,
+		FileMode: DefaultRotatingFileMode,
+		now:      func() time.Time { return time.Now().UTC() },
+	}
+	for _, option := range options {
+		option(rf)
+	}
+	if err := rf.Reopen(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+// NewRotatingFileFromConfig opens a new rotating file from a config.
+func NewRotatingFileFromConfig(cfg RotatingFileConfig) (*RotatingFile, error) {
+	rf, err := NewRotatingFile(cfg.Path,
+		OptRotatingFileMaxSizeBytes(cfg.MaxSizeBytes),
+		OptRotatingFileRotateEvery(cfg.RotateEvery),
+		OptRotatingFileRetain(cfg.Retain),
+		OptRotatingFileCompress(cfg.Compress),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ReopenOnSIGHUP {
+		rf.ReopenOn(syscall.SIGHUP)
+	}
+	return rf, nil
+}
+
+// RotatingFileOption is an option for rotating files.
+type RotatingFileOption func(*RotatingFile)
+
+// OptRotatingFileMaxSizeBytes sets the size after which the file is rotated.
+func OptRotatingFileMaxSizeBytes(maxSizeBytes int64) RotatingFileOption {
+	return func(rf *RotatingFile) { rf.MaxSizeBytes = maxSizeBytes }
+}
+
+// OptRotatingFileRotateEvery sets the interval after which the file is rotated.
+func OptRotatingFileRotateEvery(rotateEvery time.Duration) RotatingFileOption {
+	return func(rf *RotatingFile) { rf.RotateEvery = rotateEvery }
+}
+
+// OptRotatingFileRetain sets the number of rotated files to keep.
+func OptRotatingFileRetain(retain int) RotatingFileOption {
+	return func(rf *RotatingFile) { rf.Retain = retain }
+}
+
+// OptRotatingFileCompress sets if rotated files should be gzipped.
+func OptRotatingFileCompress(compress bool) RotatingFileOption {
+	return func(rf *RotatingFile) { rf.Compress = compress }
+}
+
+// OptRotatingFileOnError sets the handler for errors compressing and removing rotated files in the background.
+func OptRotatingFileOnError(onError func(error)) RotatingFileOption {
+	return func(rf *RotatingFile) { rf.OnError = onError }
+}
+
+// OptRotatingFileMode sets the file mode used when creating files.
+func OptRotatingFileMode(mode os.FileMode) RotatingFileOption {
+	return func(rf *RotatingFile) { rf.FileMode = mode }
+}
+
+// RotatingFile is a file writer that rotates the underlying file by size or age.
+//
+// Rotated files are renamed to `<path>.<timestamp>` (and gzipped to `<path>.<timestamp>.gz` if `Compress` is set),
+// and only the most recent `Retain` rotated files are kept if `Retain` is set.
+// Rotated files are compressed and removed in the background; errors doing so are passed to `OnError` if it is set.
+// Loggers set `OnError` for the rotating files their sinks own to report them through the logger.
+type RotatingFile struct {
+	sync.Mutex
+
+	Path         string
+	FileMode     os.FileMode
+	MaxSizeBytes int64
+	RotateEvery  time.Duration
+	Retain       int
+	Compress     bool
+	OnError      func(error)
+
+	file     *os.File
+	size     int64
+	opened   time.Time
+	now      func() time.Time
+	closed   bool
+	signals  chan os.Signal
+	stop     chan struct{}
+	stopped  chan struct{}
+	cleanups sync.WaitGroup
+	// cleanupLock serializes compression and retention work.
+	cleanupLock sync.Mutex
+}
+
+// Write writes to the file, rotating it first if necessary.
+func (rf *RotatingFile) Write(contents []byte) (written int, err error) {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.closed {
+		err = ex.New(ErrRotatingFileClosed)
+		return
+	}
+	if rf.file == nil {
+		if err = rf.openUnsafe(); err != nil {
+			return
+		}
+	}
+	if rf.shouldRotateUnsafe(int64(len(contents))) {
+		if err = rf.rotateUnsafe(); err != nil {
+			return
+		}
+	}
+	written, err = rf.file.Write(contents)
+	rf.size += int64(written)
+	if err != nil {
+		err = ex.New(err)
+	}
+	return
+}
+
+// Rotate forces a rotation of the file.
+func (rf *RotatingFile) Rotate() error {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.closed {
+		return ex.New(ErrRotatingFileClosed)
+	}
+	return rf.rotateUnsafe()
+}
+
+// Reopen closes and reopens the file at `Path`.
+// It is useful if an external process (like logrotate) has moved the file.
+func (rf *RotatingFile) Reopen() error {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.closed {
+		return ex.New(ErrRotatingFileClosed)
+	}
+	if err := rf.closeUnsafe(); err != nil {
+		return err
+	}
+	return rf.openUnsafe()
+}
+
+// ReopenOn reopens the file whenever one of the given signals is received.
+// It is typically used with `syscall.SIGHUP`.
+func (rf *RotatingFile) ReopenOn(signals ...os.Signal) {
+	rf.Lock()
+	defer rf.Unlock()
+
+	if rf.signals != nil || rf.closed {
+		return
+	}
+	rf.signals = make(chan os.Signal, 1)
+	rf.stop = make(chan struct{})
+	rf.stopped = make(chan struct{})
+	signal.Notify(rf.signals, signals...)
+
+	go func(signals chan os.Signal, stop, stopped chan struct{}) {
+		defer close(stopped)
+		for {
+			select {
+			case <-signals:
+				rf.Reopen()
+			case <-stop:
+				return
+			}
+		}
+	}(rf.signals, rf.stop, rf.stopped)
+}
+
+// Close closes the file, stops listening for signals, and waits for
+// any pending compression or retention work to finish.
+// Writes after the file is closed return an ErrRotatingFileClosed.
+func (rf *RotatingFile) Close() error {
+	rf.Lock()
+	rf.closed = true
+	var stopped chan struct{}
+	if rf.signals != nil {
+		signal.Stop(rf.signals)
+		close(rf.stop)
+		stopped = rf.stopped
+		rf.signals, rf.stop, rf.stopped = nil, nil, nil
+	}
+	err := rf.closeUnsafe()
+	rf.Unlock()
+
+	// the signal handler reopens the file under the lock, so it's waited for after the lock is released.
+	if stopped != nil {
+		<-stopped
+	}
+	rf.cleanups.Wait()
+	return err
+}
+
+// Rotated returns the paths of the rotated files, oldest first.
+func (rf *RotatingFile) Rotated() ([]string, error) {
+	matches, err := filepath.Glob(rf.Path + ".*")
+	if err != nil {
+		return nil, ex.New(err)
+	}
+	var rotated []string
+	for _, match := range matches {
+		if strings.HasSuffix(match, RotatingFileTempExtension) {
+			continue
+		}
+		rotated = append(rotated, match)
+	}
+	// the timestamp suffix sorts lexically.
+	sort.Strings(rotated)
+	return rotated, nil
+}
+
+func (rf *RotatingFile) shouldRotateUnsafe(incoming int64) bool {
+	if rf.MaxSizeBytes > 0 && rf.size > 0 && rf.size+incoming > rf.MaxSizeBytes {
+		return true
+	}
+	if rf.RotateEvery > 0 && rf.now().Sub(rf.opened) >= rf.RotateEvery {
+		return true
+	}
+	return false
+}
+
+func (rf *RotatingFile) openUnsafe() error {
+	file, err := os.OpenFile(rf.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, rf.FileMode)
+	if err != nil {
+		return ex.New(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return ex.New(err)
+	}
+	rf.file = file
+	rf.size = info.Size()
+	rf.opened = rf.now()
+	return nil
+}
+
+func (rf *RotatingFile) closeUnsafe() error {
+	if rf.file == nil {
+		return nil
+	}
+	err := rf.file.Close()
+	rf.file = nil
+	rf.size = 0
+	return ex.New(err)
+}
+
+func (rf *RotatingFile) rotateUnsafe() error {
+	if err := rf.closeUnsafe(); err != nil {
+		return err
+	}
+
+	rotatedPath := rf.Path + "." + rf.now().Format(RotatingFileTimestampFormat)
+	if err := os.Rename(rf.Path, rotatedPath); err != nil && !os.IsNotExist(err) {
+		return ex.New(err)
+	}
+	if err := rf.openUnsafe(); err != nil {
+		return err
+	}
+
+	rf.cleanups.Add(1)
+	go func() {
+		defer rf.cleanups.Done()
+		rf.cleanupLock.Lock()
+		defer rf.cleanupLock.Unlock()
+		if rf.Compress {
+			if err := compressFile(rotatedPath); err != nil {
+				rf.handleError(err)
+			}
+		}
+		if rf.Retain > 0 {
+			if err := rf.cull(); err != nil {
+				rf.handleError(err)
+			}
+		}
+	}()
+	return nil
+}
+
+// handleError passes a background error to the error handler if it is set.
+func (rf *RotatingFile) handleError(err error) {
+	if rf.OnError != nil {
+		rf.OnError(err)
+	}
+}
+
+// cull removes all but the most recent `Retain` rotated files.
+func (rf *RotatingFile) cull() error {
+	rotated, err := rf.Rotated()
+	if err != nil {
+		return err
+	}
+	if len(rotated) <= rf.Retain {
+		return nil
+	}
+	for _, path := range rotated[:len(rotated)-rf.Retain] {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return ex.New(err)
+		}
+	}
+	return nil
+}
+
+// compressFile gzips a file to `<path>.gz` and removes the original.
+func compressFile(path string) error {
+	source, err := os.Open(path)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer source.Close()
+
+	tempPath := path + RotatingFileTempExtension
+	destination, err := os.Create(tempPath)
+	if err != nil {
+		return ex.New(err)
+	}
+	defer destination.Close()
+
+	gz := gzip.NewWriter(destination)
+	if _, err = io.Copy(gz, source); err != nil {
+		return ex.New(err)
+	}
+	if err = gz.Close(); err != nil {
+		return ex.New(err)
+	}
+	if err = os.Rename(tempPath, path+".gz"); err != nil {
+		return ex.New(err)
+	}
+	return ex.New(os.Remove(path))
+}
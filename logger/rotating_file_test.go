@@ -0,0 +1,209 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestRotatingFileMaxSize(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rf, err := NewRotatingFile(path, OptRotatingFileMaxSizeBytes(10))
+	assert.Nil(err)
+
+	_, err = rf.Write([]byte("0123456789"))
+	assert.Nil(err)
+	_, err = rf.Write([]byte("abcdef"))
+	assert.Nil(err)
+	assert.Nil(rf.Close())
+
+	rotated, err := rf.Rotated()
+	assert.Nil(err)
+	assert.Len(rotated, 1)
+
+	contents, err := ioutil.ReadFile(rotated[0])
+	assert.Nil(err)
+	assert.Equal("0123456789", string(contents))
+
+	contents, err = ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("abcdef", string(contents))
+}
+
+func TestRotatingFileRotateEvery(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	now := time.Date(2019, 01, 01, 12, 00, 00, 00, time.UTC)
+	path := filepath.Join(tempDir, "app.log")
+	rf, err := NewRotatingFile(path, OptRotatingFileRotateEvery(time.Hour))
+	assert.Nil(err)
+	rf.now = func() time.Time { return now }
+	assert.Nil(rf.Reopen())
+
+	_, err = rf.Write([]byte("first"))
+	assert.Nil(err)
+	now = now.Add(30 * time.Minute)
+	_, err = rf.Write([]byte("second"))
+	assert.Nil(err)
+	now = now.Add(30 * time.Minute)
+	_, err = rf.Write([]byte("third"))
+	assert.Nil(err)
+	assert.Nil(rf.Close())
+
+	rotated, err := rf.Rotated()
+	assert.Nil(err)
+	assert.Len(rotated, 1)
+	assert.True(strings.HasSuffix(rotated[0], now.Format(RotatingFileTimestampFormat)))
+
+	contents, err := ioutil.ReadFile(rotated[0])
+	assert.Nil(err)
+	assert.Equal("firstsecond", string(contents))
+}
+
+func TestRotatingFileRetainCompress(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	now := time.Date(2019, 01, 01, 12, 00, 00, 00, time.UTC)
+	path := filepath.Join(tempDir, "app.log")
+	rf, err := NewRotatingFile(path, OptRotatingFileRetain(2), OptRotatingFileCompress(true))
+	assert.Nil(err)
+	rf.now = func() time.Time { return now }
+
+	for _, contents := range []string{"one", "two", "three", "four"} {
+		_, err = rf.Write([]byte(contents))
+		assert.Nil(err)
+		now = now.Add(time.Second)
+		assert.Nil(rf.Rotate())
+	}
+	assert.Nil(rf.Close())
+
+	rotated, err := rf.Rotated()
+	assert.Nil(err)
+	assert.Len(rotated, 2)
+
+	for index, expected := range []string{"three", "four"} {
+		assert.True(strings.HasSuffix(rotated[index], ".gz"))
+		f, err := os.Open(rotated[index])
+		assert.Nil(err)
+		gz, err := gzip.NewReader(f)
+		assert.Nil(err)
+		contents, err := ioutil.ReadAll(gz)
+		assert.Nil(err)
+		assert.Equal(expected, string(contents))
+		f.Close()
+	}
+}
+
+func TestRotatingFileReopen(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rf, err := NewRotatingFile(path)
+	assert.Nil(err)
+	defer rf.Close()
+
+	_, err = rf.Write([]byte("before"))
+	assert.Nil(err)
+
+	// simulate an external process moving the file.
+	assert.Nil(os.Rename(path, path+".moved"))
+	assert.Nil(rf.Reopen())
+
+	_, err = rf.Write([]byte("after"))
+	assert.Nil(err)
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("after", string(contents))
+}
+
+func TestRotatingFileClosed(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	path := filepath.Join(tempDir, "app.log")
+	rf, err := NewRotatingFile(path)
+	assert.Nil(err)
+	rf.ReopenOn(syscall.SIGHUP)
+
+	_, err = rf.Write([]byte("before"))
+	assert.Nil(err)
+	assert.Nil(rf.Close())
+	assert.Nil(rf.signals)
+
+	_, err = rf.Write([]byte("after"))
+	assert.True(ex.Is(err, ErrRotatingFileClosed))
+	assert.True(ex.Is(rf.Reopen(), ErrRotatingFileClosed))
+	assert.True(ex.Is(rf.Rotate(), ErrRotatingFileClosed))
+	assert.Nil(rf.file)
+
+	contents, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal("before", string(contents))
+}
+
+func TestRotatingFileOnError(t *testing.T) {
+	assert := assert.New(t)
+
+	tempDir, err := ioutil.TempDir("", "rotating_file")
+	assert.Nil(err)
+	defer os.RemoveAll(tempDir)
+
+	now := time.Date(2019, 01, 01, 12, 00, 00, 00, time.UTC)
+	path := filepath.Join(tempDir, "app.log")
+	// the temp file for compressing the rotated file can't be created.
+	assert.Nil(os.Mkdir(path+"."+now.Format(RotatingFileTimestampFormat)+RotatingFileTempExtension, 0755))
+
+	errors := make(chan error, 1)
+	rf, err := NewRotatingFile(path, OptRotatingFileCompress(true), OptRotatingFileOnError(func(err error) { errors <- err }))
+	assert.Nil(err)
+	rf.now = func() time.Time { return now }
+
+	_, err = rf.Write([]byte("one"))
+	assert.Nil(err)
+	assert.Nil(rf.Rotate())
+	assert.Nil(rf.Close())
+	assert.NotNil(<-errors)
+
+	// loggers report the errors of the rotating files their sinks own.
+	rf, err = NewRotatingFile(path, OptRotatingFileCompress(true))
+	assert.Nil(err)
+	rf.now = func() time.Time { return now }
+	log, err := New(OptOutput(nil), OptSink(NewSink("file", OptSinkRotatingFile(rf))))
+	assert.Nil(err)
+	assert.NotNil(rf.OnError)
+	log.Errors = make(chan error, 1)
+
+	assert.Nil(rf.Rotate())
+	assert.Nil(log.Close())
+	assert.NotNil(<-log.Errors)
+}
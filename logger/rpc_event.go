@@ -96,6 +96,6 @@ func (e RPCEvent) MarshalJSON() ([]byte, error) {
 		"authority":   e.Authority,
 		"contentType": e.ContentType,
 		"elapsed":     timeutil.Milliseconds(e.Elapsed),
-		"err":         e.Err,
+		"err":         MarshalableError(e.Err),
 	}))
 }
@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// NewSampler returns a new sampler.
+func NewSampler(options ...SamplerOption) *Sampler {
+	s := new(Sampler)
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// NewSamplerFromConfig returns a new sampler from a config.
+func NewSamplerFromConfig(cfg SamplerConfig) *Sampler {
+	return NewSampler(
+		OptSamplerFirst(cfg.First),
+		OptSamplerEvery(cfg.Every),
+		OptSamplerInterval(cfg.IntervalOrDefault()),
+	)
+}
+
+// SamplerOption is an option for samplers.
+type SamplerOption func(*Sampler)
+
+// OptSamplerFirst sets the number of events to keep per interval before sampling.
+func OptSamplerFirst(first int) SamplerOption {
+	return func(s *Sampler) { s.First = first }
+}
+
+// OptSamplerEvery sets the sample rate, i.e. keep 1 in `every` events.
+func OptSamplerEvery(every int) SamplerOption {
+	return func(s *Sampler) { s.Every = every }
+}
+
+// OptSamplerInterval sets the interval `First` applies to.
+func OptSamplerInterval(interval time.Duration) SamplerOption {
+	return func(s *Sampler) { s.Interval = interval }
+}
+
+// Sampler decides if an event should be kept or dropped.
+//
+// If `First` is set, the first `First` events per `Interval` are kept, after which
+// 1 in `Every` events are kept for the remainder of the interval (or none if `Every` is unset).
+// If only `Every` is set, 1 in `Every` events are kept.
+// If neither is set, all events are kept.
+type Sampler struct {
+	sync.Mutex
+
+	First    int
+	Every    int
+	Interval time.Duration
+
+	windowStart time.Time
+	count       int
+	dropped     int
+}
+
+// Allow returns if an event at a given time should be kept.
+func (s *Sampler) Allow(now time.Time) bool {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.First <= 0 && s.Every <= 1 {
+		return true
+	}
+
+	if s.First > 0 && s.Interval > 0 && now.Sub(s.windowStart) >= s.Interval {
+		s.windowStart = now
+		s.count = 0
+	}
+	s.count++
+
+	if s.First > 0 && s.count <= s.First {
+		return true
+	}
+	if s.Every > 0 && (s.count-s.First-1)%s.Every == 0 {
+		return true
+	}
+	s.dropped++
+	return false
+}
+
+// Dropped returns the total number of events the sampler has dropped.
+func (s *Sampler) Dropped() int {
+	s.Lock()
+	defer s.Unlock()
+	return s.dropped
+}
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestSamplerAllowAll(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler()
+	now := time.Now().UTC()
+	for x := 0; x < 10; x++ {
+		assert.True(s.Allow(now))
+	}
+	assert.Zero(s.Dropped())
+}
+
+func TestSamplerEvery(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(OptSamplerEvery(3))
+	now := time.Now().UTC()
+
+	var kept int
+	for x := 0; x < 9; x++ {
+		if s.Allow(now) {
+			kept++
+		}
+	}
+	assert.Equal(3, kept)
+	assert.Equal(6, s.Dropped())
+}
+
+func TestSamplerFirstThenEvery(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSampler(OptSamplerFirst(2), OptSamplerEvery(5), OptSamplerInterval(time.Second))
+	now := time.Date(2019, 01, 01, 12, 00, 00, 00, time.UTC)
+
+	assert.True(s.Allow(now))
+	assert.True(s.Allow(now))
+	assert.True(s.Allow(now), "the first event after `First` should be kept")
+	for x := 0; x < 4; x++ {
+		assert.False(s.Allow(now))
+	}
+	assert.True(s.Allow(now))
+
+	// a new interval resets the window.
+	now = now.Add(time.Second)
+	assert.True(s.Allow(now))
+	assert.True(s.Allow(now))
+}
+
+func TestSamplerFirstOnly(t *testing.T) {
+	assert := assert.New(t)
+
+	s := NewSamplerFromConfig(SamplerConfig{First: 1, Interval: time.Minute})
+	now := time.Date(2019, 01, 01, 12, 00, 00, 00, time.UTC)
+
+	assert.True(s.Allow(now))
+	assert.False(s.Allow(now.Add(time.Second)))
+	assert.False(s.Allow(now.Add(59 * time.Second)))
+	assert.True(s.Allow(now.Add(time.Minute)))
+}
+
+func TestLoggerSampling(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	log, err := New(
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptSampler(Info, OptSamplerEvery(2)),
+	)
+	assert.Nil(err)
+
+	var triggered int
+	log.Listen(Info, "counter", NewMessageEventListener(func(_ context.Context, _ *MessageEvent) {
+		triggered++
+	}))
+
+	for x := 0; x < 4; x++ {
+		log.SyncTrigger(context.Background(), NewMessageEvent(Info, fmt.Sprintf("this is infof %d", x)))
+	}
+	log.SyncTrigger(context.Background(), NewErrorEvent(Error, fmt.Errorf("this is errorf")))
+
+	assert.Equal(2, triggered)
+	assert.Contains(output.String(), "this is infof 0")
+	assert.NotContains(output.String(), "this is infof 1")
+	assert.Contains(output.String(), "this is infof 2")
+	assert.Contains(output.String(), "this is errorf")
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"context"
+	"io"
+)
+
+// NewSink returns a new sink.
+func NewSink(name string, options ...SinkOption) *Sink {
+	s := &Sink{
+		Name:      name,
+		Formatter: NewTextOutputFormatter(),
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// SinkOption is an option for sinks.
+type SinkOption func(*Sink)
+
+// OptSinkFlags sets the flags a sink will write.
+func OptSinkFlags(flags ...string) SinkOption {
+	return func(s *Sink) { s.Flags = NewFlags(flags...) }
+}
+
+// OptSinkFormatter sets the sink formatter.
+func OptSinkFormatter(formatter WriteFormatter) SinkOption {
+	return func(s *Sink) { s.Formatter = formatter }
+}
+
+// OptSinkOutput sets the sink output.
+// It will wrap the output with a synchronizer if it's not already wrapped.
+func OptSinkOutput(output io.Writer) SinkOption {
+	return func(s *Sink) {
+		if output != nil {
+			s.Output = NewInterlockedWriter(output)
+		} else {
+			s.Output = nil
+		}
+	}
+}
+
+// OptSinkRotatingFile sets the sink output to a rotating file, which the sink will close on `Close()`.
+func OptSinkRotatingFile(file *RotatingFile) SinkOption {
+	return func(s *Sink) {
+		s.Output = NewInterlockedWriter(file)
+		s.Closer = file
+	}
+}
+
+// Sink is an additional named output for a logger, with its own flags filter and formatter.
+//
+// Sinks only receive events that are enabled on the logger itself; if `Flags` is unset
+// the sink will write every event the logger writes.
+type Sink struct {
+	Name      string
+	Flags     *Flags
+	Formatter WriteFormatter
+	Output    io.Writer
+	// Closer is closed when the sink is closed, and is set for outputs the sink owns (like files).
+	Closer io.Closer
+}
+
+// IsEnabled returns if the sink should write a given flag.
+func (s *Sink) IsEnabled(flag string) bool {
+	if s.Flags == nil {
+		return true
+	}
+	return s.Flags.IsEnabled(flag)
+}
+
+// Write writes an event to the sink output.
+func (s *Sink) Write(ctx context.Context, e Event) error {
+	if s.Formatter == nil || s.Output == nil {
+		return nil
+	}
+	if !s.IsEnabled(e.GetFlag()) {
+		return nil
+	}
+	return s.Formatter.WriteFormat(ctx, s.Output, e)
+}
+
+// Close closes the sink's owned output, if any.
+func (s *Sink) Close() error {
+	if s.Closer != nil {
+		return s.Closer.Close()
+	}
+	return nil
+}
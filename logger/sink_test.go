@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+func TestSinkWrite(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	sink := NewSink("test",
+		OptSinkFlags(Error),
+		OptSinkOutput(output),
+		OptSinkFormatter(NewTextOutputFormatter(OptTextHideTimestamp(), OptTextNoColor())),
+	)
+
+	assert.Nil(sink.Write(context.Background(), NewMessageEvent(Info, "this is info")))
+	assert.Nil(sink.Write(context.Background(), NewErrorEvent(Error, fmt.Errorf("this is error"))))
+	assert.Equal("[error] this is error\n", output.String())
+	assert.Nil(sink.Close())
+}
+
+func TestLoggerSinks(t *testing.T) {
+	assert := assert.New(t)
+
+	output := new(bytes.Buffer)
+	jsonOutput := new(bytes.Buffer)
+	log, err := New(
+		OptAll(),
+		OptOutput(output),
+		OptText(OptTextHideTimestamp(), OptTextNoColor()),
+		OptSink(NewSink("json", OptSinkFlags(Error), OptSinkOutput(jsonOutput), OptSinkFormatter(NewJSONOutputFormatter()))),
+	)
+	assert.Nil(err)
+	assert.Len(log.Sinks, 1)
+
+	log.SyncTrigger(context.Background(), NewMessageEvent(Info, "this is info"))
+	log.SyncTrigger(context.Background(), NewErrorEvent(Error, fmt.Errorf("this is error")))
+
+	assert.Contains(output.String(), "[info] this is info")
+	assert.Contains(output.String(), "[error] this is error")
+	assert.NotContains(jsonOutput.String(), "this is info")
+	assert.Contains(jsonOutput.String(), `"flag":"error"`)
+}
+
+func TestSinkConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	sink, err := SinkConfig{Name: "stderr", Output: OutputStderr, Format: FormatJSON}.Sink()
+	assert.Nil(err)
+	assert.Equal("stderr", sink.Name)
+	assert.Nil(sink.Flags)
+	assert.Nil(sink.Closer)
+	_, isJSON := sink.Formatter.(*JSONOutputFormatter)
+	assert.True(isJSON)
+
+	_, err = SinkConfig{Name: "bad", Output: "not-an-output"}.Sink()
+	assert.NotNil(err)
+	assert.True(ex.Is(err, ErrInvalidSinkOutput))
+}
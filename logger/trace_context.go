@@ -0,0 +1,31 @@
+package logger
+
+import "context"
+
+type traceContextKey struct{}
+
+type traceContext struct {
+	traceID string
+	spanID  string
+}
+
+// WithTraceContext adds trace and span ids to a context.
+// They are written by formatters that support them, like the OpenTelemetry formatter.
+func WithTraceContext(ctx context.Context, traceID, spanID string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, traceContextKey{}, traceContext{traceID, spanID})
+}
+
+// GetTraceContext returns the trace and span ids for a context.
+func GetTraceContext(ctx context.Context) (traceID, spanID string) {
+	if ctx == nil {
+		return
+	}
+	if typed, ok := ctx.Value(traceContextKey{}).(traceContext); ok {
+		traceID = typed.traceID
+		spanID = typed.spanID
+	}
+	return
+}
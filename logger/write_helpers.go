@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -30,7 +32,7 @@ func WriteHTTPResponse(tf TextFormatter, wr io.Writer, req *http.Request, status
 	io.WriteString(wr, Space)
 	io.WriteString(wr, req.URL.Path)
 	io.WriteString(wr, Space)
-	io.WriteString(wr, ColorizeStatusCode(statusCode))
+	io.WriteString(wr, ColorizeStatusCodeWithFormatter(tf, statusCode))
 	io.WriteString(wr, Space)
 	io.WriteString(wr, elapsed.String())
 	if len(contentType) > 0 {
@@ -58,3 +60,77 @@ func MergeDecomposed(sets ...map[string]interface{}) map[string]interface{} {
 	}
 	return output
 }
+
+// EventText returns the uncolorized text output for an event.
+// Events that are neither TextWritable nor fmt.Stringers return an empty string.
+func EventText(e Event) string {
+	if typed, ok := e.(TextWritable); ok {
+		buffer := new(bytes.Buffer)
+		typed.WriteText(TextOutputFormatter{NoColor: true}, buffer)
+		return buffer.String()
+	}
+	if typed, ok := e.(fmt.Stringer); ok {
+		return typed.String()
+	}
+	return ""
+}
+
+// DecomposeEvent returns the json fields for an event as a map, without the common
+// flag and timestamp fields. Errors are rendered as their message.
+func DecomposeEvent(e Event) (map[string]interface{}, error) {
+	contents, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	output := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.UseNumber()
+	if err = decoder.Decode(&output); err != nil {
+		return nil, err
+	}
+	delete(output, FieldFlag)
+	delete(output, FieldTimestamp)
+	if fields, ok := output[FieldFields]; ok && fields == nil {
+		delete(output, FieldFields)
+	}
+	if _, ok := output[FieldErr]; ok {
+		if err := EventError(e); err != nil {
+			output[FieldErr] = err.Error()
+		} else {
+			delete(output, FieldErr)
+		}
+	}
+	return output, nil
+}
+
+// EventError returns the error for built-in event types that carry an error.
+func EventError(e Event) error {
+	switch typed := e.(type) {
+	case *ErrorEvent:
+		return typed.Err
+	case ErrorEvent:
+		return typed.Err
+	case *QueryEvent:
+		return typed.Err
+	case QueryEvent:
+		return typed.Err
+	case *RPCEvent:
+		return typed.Err
+	case RPCEvent:
+		return typed.Err
+	}
+	return nil
+}
+
+// MarshalableError returns an error in a form that can be marshaled as json.
+// Errors that implement json.Marshaler (like exceptions) are returned as is, other errors
+// are returned as their message.
+func MarshalableError(err error) interface{} {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(json.Marshaler); ok {
+		return err
+	}
+	return err.Error()
+}
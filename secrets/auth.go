@@ -0,0 +1,156 @@
+package secrets
+
+import (
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// assert the auth methods implement AuthMethod
+var (
+	_ AuthMethod = (*AppRoleAuth)(nil)
+	_ AuthMethod = (*KubernetesAuth)(nil)
+	_ AuthMethod = (*UserpassAuth)(nil)
+	_ AuthMethod = (*CertAuth)(nil)
+)
+
+// AuthMethod logs in to vault, returning the auth information that includes the client token.
+type AuthMethod interface {
+	Login(ctx context.Context, client *VaultClient) (*SecretAuth, error)
+}
+
+// AuthMethodFunc is a function that implements AuthMethod.
+type AuthMethodFunc func(context.Context, *VaultClient) (*SecretAuth, error)
+
+// Login implements AuthMethod.
+func (amf AuthMethodFunc) Login(ctx context.Context, client *VaultClient) (*SecretAuth, error) {
+	return amf(ctx, client)
+}
+
+// AppRoleAuth logs in with an approle role id and secret id.
+type AppRoleAuth struct {
+	// Mount is the auth mount path, it defaults to `approle`.
+	Mount    string
+	RoleID   string
+	SecretID string
+}
+
+// MountOrDefault returns the mount or a default.
+func (ara AppRoleAuth) MountOrDefault() string {
+	if ara.Mount != "" {
+		return ara.Mount
+	}
+	return AuthMountAppRole
+}
+
+// Login implements AuthMethod.
+func (ara AppRoleAuth) Login(ctx context.Context, client *VaultClient) (*SecretAuth, error) {
+	body := map[string]string{
+		"role_id": ara.RoleID,
+	}
+	if ara.SecretID != "" {
+		body["secret_id"] = ara.SecretID
+	}
+	return client.login(ctx, authPath(ara.MountOrDefault(), "login"), body)
+}
+
+// KubernetesAuth logs in with a kubernetes service account token.
+type KubernetesAuth struct {
+	// Mount is the auth mount path, it defaults to `kubernetes`.
+	Mount string
+	// Role is the vault role bound to the service account.
+	Role string
+	// JWT is the service account token; if it is unset the token is read from `JWTPath`.
+	JWT string
+	// JWTPath is the path to the service account token, it defaults to the path kubernetes mounts it at.
+	JWTPath string
+}
+
+// MountOrDefault returns the mount or a default.
+func (ka KubernetesAuth) MountOrDefault() string {
+	if ka.Mount != "" {
+		return ka.Mount
+	}
+	return AuthMountKubernetes
+}
+
+// JWTPathOrDefault returns the jwt path or a default.
+func (ka KubernetesAuth) JWTPathOrDefault() string {
+	if ka.JWTPath != "" {
+		return ka.JWTPath
+	}
+	return DefaultKubernetesJWTPath
+}
+
+// Login implements AuthMethod.
+// The service account token is read from disk on each login, as kubernetes may rotate it.
+func (ka KubernetesAuth) Login(ctx context.Context, client *VaultClient) (*SecretAuth, error) {
+	jwt := ka.JWT
+	if jwt == "" {
+		contents, err := ioutil.ReadFile(ka.JWTPathOrDefault())
+		if err != nil {
+			return nil, ex.New(err)
+		}
+		jwt = strings.TrimSpace(string(contents))
+	}
+	return client.login(ctx, authPath(ka.MountOrDefault(), "login"), map[string]string{
+		"role": ka.Role,
+		"jwt":  jwt,
+	})
+}
+
+// UserpassAuth logs in with a username and password.
+type UserpassAuth struct {
+	// Mount is the auth mount path, it defaults to `userpass`.
+	Mount    string
+	Username string
+	Password string
+}
+
+// MountOrDefault returns the mount or a default.
+func (upa UserpassAuth) MountOrDefault() string {
+	if upa.Mount != "" {
+		return upa.Mount
+	}
+	return AuthMountUserpass
+}
+
+// Login implements AuthMethod.
+func (upa UserpassAuth) Login(ctx context.Context, client *VaultClient) (*SecretAuth, error) {
+	return client.login(ctx, authPath(upa.MountOrDefault(), "login", upa.Username), map[string]string{
+		"password": upa.Password,
+	})
+}
+
+// CertAuth logs in with the tls client certificate the vault client is configured with, see `OptClientCert`.
+type CertAuth struct {
+	// Mount is the auth mount path, it defaults to `cert`.
+	Mount string
+	// Name is the certificate role to authenticate against; if unset, vault tries all roles.
+	Name string
+}
+
+// MountOrDefault returns the mount or a default.
+func (ca CertAuth) MountOrDefault() string {
+	if ca.Mount != "" {
+		return ca.Mount
+	}
+	return AuthMountCert
+}
+
+// Login implements AuthMethod.
+func (ca CertAuth) Login(ctx context.Context, client *VaultClient) (*SecretAuth, error) {
+	body := map[string]string{}
+	if ca.Name != "" {
+		body["name"] = ca.Name
+	}
+	return client.login(ctx, authPath(ca.MountOrDefault(), "login"), body)
+}
+
+// authPath returns the request path for an auth mount.
+func authPath(mount string, segments ...string) string {
+	return filepath.Join(append([]string{"/v1/auth", strings.Trim(mount, "/")}, segments...)...)
+}
@@ -0,0 +1,104 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+)
+
+// mockLoginServer returns a server that records login requests and responds with a token.
+func mockLoginServer(requests *[]*http.Request, bodies *[]map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body := map[string]string{}
+		json.NewDecoder(req.Body).Decode(&body)
+		*requests = append(*requests, req)
+		*bodies = append(*bodies, body)
+		fmt.Fprint(rw, `{"auth":{"client_token":"s.login","accessor":"accessor","policies":["default"],"lease_duration":3600,"renewable":true}}`)
+	}))
+}
+
+func TestAuthMethods(t *testing.T) {
+	assert := assert.New(t)
+
+	jwtPath := filepath.Join(os.TempDir(), "secrets_test_jwt")
+	assert.Nil(ioutil.WriteFile(jwtPath, []byte("service-account-jwt\n"), 0600))
+	defer os.Remove(jwtPath)
+
+	testCases := [...]struct {
+		Auth AuthMethod
+		Path string
+		Body map[string]string
+	}{
+		{Auth: AppRoleAuth{RoleID: "role", SecretID: "secret"}, Path: "/v1/auth/approle/login", Body: map[string]string{"role_id": "role", "secret_id": "secret"}},
+		{Auth: AppRoleAuth{Mount: "/ci/", RoleID: "role"}, Path: "/v1/auth/ci/login", Body: map[string]string{"role_id": "role"}},
+		{Auth: KubernetesAuth{Role: "app", JWTPath: jwtPath}, Path: "/v1/auth/kubernetes/login", Body: map[string]string{"role": "app", "jwt": "service-account-jwt"}},
+		{Auth: KubernetesAuth{Mount: "k8s-east", Role: "app", JWT: "jwt"}, Path: "/v1/auth/k8s-east/login", Body: map[string]string{"role": "app", "jwt": "jwt"}},
+		{Auth: UserpassAuth{Username: "bailey", Password: "hunter2"}, Path: "/v1/auth/userpass/login/bailey", Body: map[string]string{"password": "hunter2"}},
+		{Auth: CertAuth{Name: "web"}, Path: "/v1/auth/cert/login", Body: map[string]string{"name": "web"}},
+	}
+
+	for _, tc := range testCases {
+		var requests []*http.Request
+		var bodies []map[string]string
+		server := mockLoginServer(&requests, &bodies)
+
+		client, err := New(OptRemote(server.URL), OptToken("stale"), OptAuth(tc.Auth))
+		assert.Nil(err)
+
+		auth, err := client.Login(context.TODO())
+		server.Close()
+		assert.Nil(err)
+		assert.Equal("s.login", auth.ClientToken)
+		assert.Equal(3600, auth.LeaseDuration)
+		assert.Equal("s.login", client.GetToken())
+
+		assert.Len(requests, 1)
+		assert.Equal(MethodPost, requests[0].Method)
+		assert.Equal(tc.Path, requests[0].URL.Path)
+		assert.Empty(requests[0].Header.Get(HeaderVaultToken), "login requests should not send the previous token")
+		assert.Equal(tc.Body, bodies[0])
+	}
+}
+
+func TestVaultClientLoginErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	client, err := New()
+	assert.Nil(err)
+	_, err = client.Login(context.TODO())
+	assert.True(ex.Is(err, ErrAuthMethodUnset))
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/v1/auth/approle/login" {
+			fmt.Fprint(rw, `{"auth":null}`)
+			return
+		}
+		rw.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(rw, `{"errors":["invalid credentials"]}`)
+	}))
+	defer server.Close()
+
+	client, err = New(OptRemote(server.URL), OptAuth(AppRoleAuth{RoleID: "role"}))
+	assert.Nil(err)
+	_, err = client.Login(context.TODO())
+	assert.True(ex.Is(err, ErrAuthMissing))
+	assert.Empty(client.GetToken())
+
+	client.Auth = UserpassAuth{Username: "bailey"}
+	_, err = client.Login(context.TODO())
+	assert.True(ex.Is(err, ErrServerError))
+	assert.Empty(client.GetToken())
+
+	client.Auth = KubernetesAuth{JWTPath: filepath.Join(os.TempDir(), "secrets_test_missing_jwt")}
+	_, err = client.Login(context.TODO())
+	assert.NotNil(err)
+}
@@ -0,0 +1,254 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// assert CachedClient implements Client and Versioner.
+var (
+	_ Client    = (*CachedClient)(nil)
+	_ Versioner = (*CachedClient)(nil)
+)
+
+// NewCachedClient returns a new client that caches the secrets read from another client.
+func NewCachedClient(client Client, options ...CachedClientOption) *CachedClient {
+	cc := CachedClient{
+		Client:   client,
+		TTL:      DefaultCacheTTL,
+		entries:  make(map[string]*cacheEntry),
+		inflight: make(map[string]*cacheCall),
+	}
+	for _, option := range options {
+		option(&cc)
+	}
+	return &cc
+}
+
+// CachedClientOption is an option for a cached client.
+type CachedClientOption func(*CachedClient)
+
+// OptCacheTTL sets how long cached secrets are fresh for.
+func OptCacheTTL(ttl time.Duration) CachedClientOption {
+	return func(cc *CachedClient) {
+		cc.TTL = ttl
+	}
+}
+
+// OptCacheStaleTTL sets how long secrets are served after they expire while they are refreshed in the background.
+func OptCacheStaleTTL(staleTTL time.Duration) CachedClientOption {
+	return func(cc *CachedClient) {
+		cc.StaleTTL = staleTTL
+	}
+}
+
+// OptCacheLog sets the cached client logger, which receives background refresh errors.
+func OptCacheLog(log logger.Log) CachedClientOption {
+	return func(cc *CachedClient) {
+		cc.Log = log
+	}
+}
+
+// CachedClient is a client that caches the secrets read from another client.
+//
+// Secrets are fresh for the TTL. After that, for the stale TTL, the cached secret is still returned
+// while it is refreshed in the background, so reads only wait on the remote when a secret is missing
+// or too old. Gets with request options, e.g. for a specific version, are not cached.
+// Puts and deletes go to the remote and invalidate the cached secret.
+// Concurrent reads of a missing secret share one read from the remote, and expired secrets are
+// evicted as the cache is refreshed.
+type CachedClient struct {
+	Client   Client
+	TTL      time.Duration
+	StaleTTL time.Duration
+	Log      logger.Log
+
+	sync.Mutex
+	entries map[string]*cacheEntry
+	// inflight are the reads from the remote in progress; invalidating a key removes its read,
+	// so reads that started before then aren't cached.
+	inflight  map[string]*cacheCall
+	lastEvict time.Time
+}
+
+type cacheEntry struct {
+	Values     Values
+	Fetched    time.Time
+	Refreshing bool
+}
+
+type cacheCall struct {
+	done   chan struct{}
+	values Values
+	err    error
+}
+
+// Put puts a value and invalidates the cached value.
+func (cc *CachedClient) Put(ctx context.Context, key string, data Values, options ...RequestOption) error {
+	defer cc.Invalidate(key)
+	return cc.Client.Put(ctx, key, data, options...)
+}
+
+// Get gets a value at a given key, from the cache if it is fresh, or stale and being refreshed.
+func (cc *CachedClient) Get(ctx context.Context, key string, options ...RequestOption) (Values, error) {
+	if len(options) > 0 {
+		return cc.Client.Get(ctx, key, options...)
+	}
+
+	cc.Lock()
+	entry, ok := cc.entries[key]
+	if ok {
+		age := time.Since(entry.Fetched)
+		if age < cc.TTL {
+			cc.Unlock()
+			return copyValues(entry.Values), nil
+		}
+		if age < cc.TTL+cc.StaleTTL {
+			if !entry.Refreshing {
+				entry.Refreshing = true
+				go cc.refreshInBackground(key)
+			}
+			cc.Unlock()
+			return copyValues(entry.Values), nil
+		}
+		delete(cc.entries, key)
+	}
+	cc.Unlock()
+	return cc.Refresh(ctx, key)
+}
+
+// Delete deletes a key and invalidates the cached value.
+func (cc *CachedClient) Delete(ctx context.Context, key string, options ...RequestOption) error {
+	defer cc.Invalidate(key)
+	return cc.Client.Delete(ctx, key, options...)
+}
+
+// List returns a slice of key and subfolder names at this path; lists are not cached.
+func (cc *CachedClient) List(ctx context.Context, path string, options ...RequestOption) ([]string, error) {
+	return cc.Client.List(ctx, path, options...)
+}
+
+// Version returns the current version of a secret from the remote if the client is a Versioner.
+func (cc *CachedClient) Version(ctx context.Context, key string) (int, error) {
+	if typed, ok := cc.Client.(Versioner); ok {
+		return typed.Version(ctx, key)
+	}
+	return 0, ex.New(ErrVersionUnsupported, ex.OptMessagef("key: %s", key))
+}
+
+// ReadInto reads a secret into an object.
+func (cc *CachedClient) ReadInto(ctx context.Context, key string, obj interface{}) error {
+	response, err := cc.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return RestoreJSON(response, obj)
+}
+
+/*
+Refresh reads a secret from the remote and caches it.
+
+Concurrent refreshes of a key without options share one read. Refreshes with options, e.g. for the
+version of a secret a watcher saw change, always read from the remote, and replace the reads of the
+key already in progress. If the key is invalidated while it is read, e.g. by a put or delete, the value
+read is returned but not cached.
+*/
+func (cc *CachedClient) Refresh(ctx context.Context, key string, options ...RequestOption) (Values, error) {
+	cc.Lock()
+	call, ok := cc.inflight[key]
+	if !ok || len(options) > 0 {
+		call = &cacheCall{done: make(chan struct{})}
+		cc.inflight[key] = call
+		cc.Unlock()
+		cc.read(ctx, key, call, options...)
+	} else {
+		cc.Unlock()
+	}
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err != nil {
+		return nil, call.err
+	}
+	return copyValues(call.values), nil
+}
+
+// Set caches a value for a key.
+func (cc *CachedClient) Set(key string, values Values) {
+	cc.Lock()
+	defer cc.Unlock()
+	cc.entries[key] = &cacheEntry{Values: copyValues(values), Fetched: time.Now()}
+}
+
+// Invalidate removes the cached value for a key, and discards the results of refreshes of it already in progress.
+func (cc *CachedClient) Invalidate(key string) {
+	cc.Lock()
+	defer cc.Unlock()
+	delete(cc.entries, key)
+	delete(cc.inflight, key)
+}
+
+// EvictExpired removes the cached values that are past their ttl and stale ttl.
+// It is called as values are refreshed, at most once per ttl.
+func (cc *CachedClient) EvictExpired() {
+	cc.Lock()
+	defer cc.Unlock()
+	cc.evictExpiredUnsafe(time.Now())
+}
+
+// read reads a key from the remote for a call, and caches the result if the call is still the current read of the key.
+func (cc *CachedClient) read(ctx context.Context, key string, call *cacheCall, options ...RequestOption) {
+	defer close(call.done)
+	call.values, call.err = cc.Client.Get(ctx, key, options...)
+
+	cc.Lock()
+	defer cc.Unlock()
+	if cc.inflight[key] != call {
+		return
+	}
+	delete(cc.inflight, key)
+	if call.err != nil {
+		if entry, ok := cc.entries[key]; ok {
+			entry.Refreshing = false
+		}
+		return
+	}
+	now := time.Now()
+	cc.entries[key] = &cacheEntry{Values: copyValues(call.values), Fetched: now}
+	if now.Sub(cc.lastEvict) >= cc.TTL {
+		cc.evictExpiredUnsafe(now)
+	}
+}
+
+func (cc *CachedClient) evictExpiredUnsafe(now time.Time) {
+	cc.lastEvict = now
+	for key, entry := range cc.entries {
+		if now.Sub(entry.Fetched) >= cc.TTL+cc.StaleTTL {
+			delete(cc.entries, key)
+		}
+	}
+}
+
+func (cc *CachedClient) refreshInBackground(key string) {
+	if _, err := cc.Refresh(context.Background(), key); err != nil {
+		logger.MaybeError(cc.Log, err)
+	}
+}
+
+func copyValues(values Values) Values {
+	if values == nil {
+		return nil
+	}
+	output := make(Values, len(values))
+	for key, value := range values {
+		output[key] = value
+	}
+	return output
+}
@@ -0,0 +1,283 @@
+package secrets
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/webutil"
+)
+
+// countingClient is a mock client that counts gets and records the versions they request, and optionally returns versions.
+type countingClient struct {
+	*MockClient
+	sync.Mutex
+	Gets              int
+	RequestedVersions []string
+	Versions          map[string]int
+}
+
+func newCountingClient() *countingClient {
+	return &countingClient{MockClient: NewMockClient()}
+}
+
+func (cc *countingClient) Get(ctx context.Context, key string, options ...RequestOption) (Values, error) {
+	var req http.Request
+	if err := webutil.RequestOptions(options).Apply(&req); err != nil {
+		return nil, err
+	}
+	cc.Lock()
+	cc.Gets++
+	if req.URL != nil {
+		cc.RequestedVersions = append(cc.RequestedVersions, req.URL.Query().Get("version"))
+	}
+	values, err := cc.MockClient.Get(ctx, key, options...)
+	cc.Unlock()
+	return values, err
+}
+
+func (cc *countingClient) Put(ctx context.Context, key string, data Values, options ...RequestOption) error {
+	cc.Lock()
+	defer cc.Unlock()
+	if cc.Versions != nil {
+		cc.Versions[key]++
+	}
+	return cc.MockClient.Put(ctx, key, data, options...)
+}
+
+func (cc *countingClient) gets() int {
+	cc.Lock()
+	defer cc.Unlock()
+	return cc.Gets
+}
+
+// versionedClient is a counting client that is also a Versioner.
+type versionedClient struct {
+	*countingClient
+}
+
+func (vc versionedClient) Version(_ context.Context, key string) (int, error) {
+	vc.Lock()
+	defer vc.Unlock()
+	return vc.Versions[key], nil
+}
+
+func TestCachedClientGet(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+
+	cached := NewCachedClient(remote, OptCacheTTL(time.Hour))
+	values, err := cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal("one", values["password"])
+
+	values["password"] = "mutated"
+	values, err = cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal("one", values["password"], "cached values should not be mutable by callers")
+	assert.Equal(1, remote.gets())
+
+	_, err = cached.Get(todo, "foo", OptRequestVersion(1))
+	assert.Nil(err)
+	assert.Equal(2, remote.gets(), "gets with options should not be cached")
+
+	assert.Nil(cached.Put(todo, "foo", Values{"password": "two"}))
+	values, err = cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal("two", values["password"], "puts should invalidate the cache")
+
+	assert.Nil(cached.Delete(todo, "foo"))
+	_, err = cached.Get(todo, "foo")
+	assert.NotNil(err)
+
+	_, err = cached.Version(todo, "foo")
+	assert.True(ex.Is(err, ErrVersionUnsupported))
+}
+
+func TestCachedClientStaleWhileRevalidate(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+
+	cached := NewCachedClient(remote, OptCacheTTL(time.Millisecond), OptCacheStaleTTL(time.Hour))
+	_, err := cached.Get(todo, "foo")
+	assert.Nil(err)
+
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "two"}))
+	time.Sleep(5 * time.Millisecond)
+
+	values, err := cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal("one", values["password"], "stale values should be returned while they are refreshed")
+
+	assert.True(waitFor(func() bool {
+		values, _ := cached.Get(todo, "foo")
+		return values["password"] == "two"
+	}))
+
+	remote = newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+	cached = NewCachedClient(remote, OptCacheTTL(time.Millisecond))
+	_, err = cached.Get(todo, "foo")
+	assert.Nil(err)
+	gets := remote.gets()
+	time.Sleep(5 * time.Millisecond)
+	_, err = cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal(gets+1, remote.gets(), "expired values without a stale ttl should be read synchronously")
+}
+
+func TestCachedClientReadInto(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"username": `"app"`, "password": `"hunter2"`}))
+
+	var creds struct {
+		Username string `secret:"username"`
+		Password string `secret:"password"`
+	}
+	cached := NewCachedClient(remote)
+	assert.Nil(cached.ReadInto(todo, "foo", &creds))
+	assert.Equal("app", creds.Username)
+	assert.Equal("hunter2", creds.Password)
+
+	cached.Set("foo", Values{"username": `"set"`})
+	assert.Nil(cached.ReadInto(todo, "foo", &creds))
+	assert.Equal("set", creds.Username)
+	assert.Equal(1, remote.gets())
+}
+
+// blockingClient is a mock client whose gets read the value, then wait to return it until they are released.
+type blockingClient struct {
+	*MockClient
+	Read    chan struct{}
+	Release chan struct{}
+}
+
+func (bc *blockingClient) Get(ctx context.Context, key string, options ...RequestOption) (Values, error) {
+	values, err := bc.MockClient.Get(ctx, key, options...)
+	bc.Read <- struct{}{}
+	<-bc.Release
+	return values, err
+}
+
+func TestCachedClientRefreshInvalidated(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := &blockingClient{MockClient: NewMockClient(), Read: make(chan struct{}), Release: make(chan struct{})}
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+	cached := NewCachedClient(remote, OptCacheTTL(time.Hour))
+
+	// a refresh that reads the old value, and finishes after the value is overwritten.
+	refreshed := make(chan Values)
+	go func() {
+		values, _ := cached.Refresh(todo, "foo")
+		refreshed <- values
+	}()
+	<-remote.Read
+	assert.Nil(cached.Put(todo, "foo", Values{"password": "two"}))
+	close(remote.Release)
+	assert.Equal("one", (<-refreshed)["password"])
+
+	// the old value is not cached.
+	go func() { <-remote.Read }()
+	values, err := cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal("two", values["password"])
+}
+
+// gatedClient is a mock client that counts gets, and waits to return them until it is released.
+type gatedClient struct {
+	*countingClient
+	Release chan struct{}
+}
+
+func (gc gatedClient) Get(ctx context.Context, key string, options ...RequestOption) (Values, error) {
+	values, err := gc.countingClient.Get(ctx, key, options...)
+	<-gc.Release
+	return values, err
+}
+
+func TestCachedClientRefreshShared(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := gatedClient{countingClient: newCountingClient(), Release: make(chan struct{})}
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+	cached := NewCachedClient(remote, OptCacheTTL(time.Hour))
+
+	// concurrent misses either share the first read or read its cached value.
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for x := 0; x < 8; x++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			values, err := cached.Get(todo, "foo")
+			if err == nil && values["password"] != "one" {
+				err = ex.New("unexpected password")
+			}
+			errs <- err
+		}()
+	}
+	assert.True(waitFor(func() bool { return remote.gets() > 0 }))
+	close(remote.Release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.Nil(err)
+	}
+	assert.Equal(1, remote.gets())
+
+	// refreshes with options always read.
+	_, err := cached.Refresh(todo, "foo", OptRequestVersion(1))
+	assert.Nil(err)
+	assert.Equal(2, remote.gets())
+	assert.Empty(cached.inflight)
+}
+
+func TestCachedClientEvictExpired(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+	assert.Nil(remote.Put(todo, "bar", Values{"password": "one"}))
+	cached := NewCachedClient(remote, OptCacheTTL(time.Millisecond), OptCacheStaleTTL(time.Millisecond))
+
+	_, err := cached.Get(todo, "foo")
+	assert.Nil(err)
+	time.Sleep(5 * time.Millisecond)
+	cached.EvictExpired()
+	assert.Empty(cached.entries)
+
+	// expired values are evicted as other values are refreshed.
+	_, err = cached.Get(todo, "foo")
+	assert.Nil(err)
+	time.Sleep(5 * time.Millisecond)
+	_, err = cached.Get(todo, "bar")
+	assert.Nil(err)
+	assert.Len(cached.entries, 1)
+	_, ok := cached.entries["bar"]
+	assert.True(ok)
+
+	// invalidating keys doesn't leave anything behind.
+	for x := 0; x < 8; x++ {
+		cached.Invalidate("foo")
+		cached.Invalidate("bar")
+	}
+	assert.Empty(cached.entries)
+	assert.Empty(cached.inflight)
+}
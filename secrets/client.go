@@ -9,3 +9,8 @@ type Client interface {
 	Delete(ctx context.Context, key string, options ...RequestOption) error
 	List(ctx context.Context, path string, options ...RequestOption) ([]string, error)
 }
+
+// Versioner is a client that can return the current version of a secret without reading it.
+type Versioner interface {
+	Version(ctx context.Context, key string) (int, error)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/ex"
 )
 
 // EnvVars
@@ -26,17 +27,23 @@ type Config struct {
 	Token string `json:"token" yaml:"token" env:"VAULT_TOKEN"`
 	// Mount is the default mount path, it prefixes any keys.
 	Mount string `json:"mount" yaml:"mount"`
+	// TransitMount is the transit engine mount path.
+	TransitMount string `json:"transitMount" yaml:"transitMount"`
+	// DatabaseMount is the database engine mount path.
+	DatabaseMount string `json:"databaseMount" yaml:"databaseMount"`
 	// Timeout is the dial timeout for requests to the secrets store.
 	Timeout time.Duration `json:"timeout" yaml:"timeout"`
 	// RootCAs is a list of certificate authority paths.
 	RootCAs []string `json:"rootCAs" yaml:"rootCAs" env:"VAULT_CACERT,csv"`
 	// ServicePath is the path that service secrets live under
 	ServicePath string `json:"servicePath" yaml:"servicePath" env:"SECRETS_SERVICE_PATH"`
+	// Auth is the auth method used to log in instead of a static token.
+	Auth AuthConfig `json:"auth" yaml:"auth"`
 }
 
 // IsZero returns if the config is set or not.
 func (c Config) IsZero() bool {
-	return len(c.Token) == 0
+	return len(c.Token) == 0 && c.Auth.IsZero()
 }
 
 // Resolve reads the environment into the config on configutil.Read(...)
@@ -60,6 +67,22 @@ func (c Config) MountOrDefault() string {
 	return DefaultMount
 }
 
+// TransitMountOrDefault returns the transit engine mount or a default.
+func (c Config) TransitMountOrDefault() string {
+	if c.TransitMount != "" {
+		return c.TransitMount
+	}
+	return DefaultTransitMount
+}
+
+// DatabaseMountOrDefault returns the database engine mount or a default.
+func (c Config) DatabaseMountOrDefault() string {
+	if c.DatabaseMount != "" {
+		return c.DatabaseMount
+	}
+	return DefaultDatabaseMount
+}
+
 // TimeoutOrDefault returns the client timeout.
 func (c Config) TimeoutOrDefault() time.Duration {
 	if c.Timeout > 0 {
@@ -67,3 +90,48 @@ func (c Config) TimeoutOrDefault() time.Duration {
 	}
 	return DefaultTimeout
 }
+
+// AuthConfig is the auth method config.
+type AuthConfig struct {
+	// Method is the auth method, one of `approle`, `kubernetes`, `userpass` or `cert`.
+	Method string `json:"method" yaml:"method" env:"VAULT_AUTH_METHOD"`
+	// Mount is the auth mount path, it defaults to the method name.
+	Mount string `json:"mount" yaml:"mount" env:"VAULT_AUTH_MOUNT"`
+	// Role is the kubernetes role, or the cert role name.
+	Role string `json:"role" yaml:"role" env:"VAULT_AUTH_ROLE"`
+	// RoleID is the approle role id.
+	RoleID string `json:"roleID" yaml:"roleID" env:"VAULT_ROLE_ID"`
+	// SecretID is the approle secret id.
+	SecretID string `json:"secretID" yaml:"secretID" env:"VAULT_SECRET_ID"`
+	// JWTPath is the path to the kubernetes service account token.
+	JWTPath string `json:"jwtPath" yaml:"jwtPath" env:"VAULT_JWT_PATH"`
+	// Username is the userpass username.
+	Username string `json:"username" yaml:"username" env:"VAULT_USERNAME"`
+	// Password is the userpass password.
+	Password string `json:"password" yaml:"password" env:"VAULT_PASSWORD"`
+	// ClientCert is the path to the tls client certificate used by the cert method.
+	ClientCert string `json:"clientCert" yaml:"clientCert" env:"VAULT_CLIENT_CERT"`
+	// ClientKey is the path to the tls client key used by the cert method.
+	ClientKey string `json:"clientKey" yaml:"clientKey" env:"VAULT_CLIENT_KEY"`
+}
+
+// IsZero returns if the auth method is set or not.
+func (ac AuthConfig) IsZero() bool {
+	return len(ac.Method) == 0
+}
+
+// AuthMethod returns the auth method for the config.
+func (ac AuthConfig) AuthMethod() (AuthMethod, error) {
+	switch ac.Method {
+	case AuthMethodAppRole:
+		return AppRoleAuth{Mount: ac.Mount, RoleID: ac.RoleID, SecretID: ac.SecretID}, nil
+	case AuthMethodKubernetes:
+		return KubernetesAuth{Mount: ac.Mount, Role: ac.Role, JWTPath: ac.JWTPath}, nil
+	case AuthMethodUserpass:
+		return UserpassAuth{Mount: ac.Mount, Username: ac.Username, Password: ac.Password}, nil
+	case AuthMethodCert:
+		return CertAuth{Mount: ac.Mount, Name: ac.Role}, nil
+	default:
+		return nil, ex.New(ErrAuthMethodUnknown, ex.OptMessagef("method: %s", ac.Method))
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/blend/go-sdk/assert"
 	"github.com/blend/go-sdk/env"
+	"github.com/blend/go-sdk/ex"
 )
 
 func TestNewConfigFromEnv(t *testing.T) {
@@ -34,7 +35,51 @@ func TestConfig(t *testing.T) {
 	assert.Equal(DefaultAddr, cfg.AddrOrDefault())
 	assert.Empty(cfg.Token)
 	assert.Equal(DefaultMount, cfg.MountOrDefault())
+	assert.Equal(DefaultTransitMount, cfg.TransitMountOrDefault())
+	assert.Equal(DefaultDatabaseMount, cfg.DatabaseMountOrDefault())
 	assert.Equal(DefaultTimeout, cfg.TimeoutOrDefault())
 	assert.Empty(cfg.RootCAs)
 	assert.Empty(cfg.ServicePath)
 }
+
+func TestNewConfigFromEnvAuth(t *testing.T) {
+	assert := assert.New(t)
+	defer env.Restore()
+
+	env.Env().Set("VAULT_AUTH_METHOD", "approle")
+	env.Env().Set("VAULT_ROLE_ID", "role")
+	env.Env().Set("VAULT_SECRET_ID", "secret")
+
+	cfg, err := NewConfigFromEnv()
+	assert.Nil(err)
+	assert.False(cfg.IsZero())
+
+	auth, err := cfg.Auth.AuthMethod()
+	assert.Nil(err)
+	assert.Equal(AppRoleAuth{RoleID: "role", SecretID: "secret"}, auth)
+
+	client, err := New(OptConfig(cfg))
+	assert.Nil(err)
+	assert.Equal(auth, client.Auth)
+}
+
+func TestAuthConfigAuthMethod(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.True(AuthConfig{}.IsZero())
+
+	auth, err := AuthConfig{Method: AuthMethodKubernetes, Mount: "k8s", Role: "app"}.AuthMethod()
+	assert.Nil(err)
+	assert.Equal(KubernetesAuth{Mount: "k8s", Role: "app"}, auth)
+
+	auth, err = AuthConfig{Method: AuthMethodUserpass, Username: "bailey", Password: "hunter2"}.AuthMethod()
+	assert.Nil(err)
+	assert.Equal(UserpassAuth{Username: "bailey", Password: "hunter2"}, auth)
+
+	auth, err = AuthConfig{Method: AuthMethodCert, Role: "web"}.AuthMethod()
+	assert.Nil(err)
+	assert.Equal(CertAuth{Name: "web"}, auth)
+
+	_, err = AuthConfig{Method: "github"}.AuthMethod()
+	assert.True(ex.Is(err, ErrAuthMethodUnknown))
+}
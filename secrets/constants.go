@@ -11,6 +11,40 @@ const (
 
 	// DefaultMount is the default kv mount.
 	DefaultMount = "/secret"
+
+	// DefaultCacheTTL is the default time cached secrets are fresh for.
+	DefaultCacheTTL = 5 * time.Minute
+	// DefaultWatchInterval is the default interval watched secrets are polled on.
+	DefaultWatchInterval = 30 * time.Second
+
+	// DefaultTransitMount is the default transit engine mount.
+	DefaultTransitMount = "/transit"
+	// DefaultDatabaseMount is the default database engine mount.
+	DefaultDatabaseMount = "/database"
+
+	// DefaultKubernetesJWTPath is the path kubernetes mounts the service account token at.
+	DefaultKubernetesJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	// DefaultRenewFraction is the default fraction of a ttl renewers, e.g. the lifetime watcher, wait before renewing.
+	DefaultRenewFraction = 2.0 / 3.0
+	// DefaultRenewRetryDelay is the default delay before renewers retry a failed renewal.
+	DefaultRenewRetryDelay = 5 * time.Second
+)
+
+// Auth methods.
+const (
+	AuthMethodAppRole    = "approle"
+	AuthMethodKubernetes = "kubernetes"
+	AuthMethodUserpass   = "userpass"
+	AuthMethodCert       = "cert"
+)
+
+// Auth method default mounts.
+const (
+	AuthMountAppRole    = AuthMethodAppRole
+	AuthMountKubernetes = AuthMethodKubernetes
+	AuthMountUserpass   = AuthMethodUserpass
+	AuthMountCert       = AuthMethodCert
 )
 
 const (
@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// Database defines database secrets engine interactions, i.e. dynamic database credentials issued for a role.
+type Database struct {
+	Client *VaultClient
+	// Mount is the database engine mount path.
+	Mount string
+}
+
+// MountOrDefault returns the mount or a default.
+func (d Database) MountOrDefault() string {
+	if d.Mount != "" {
+		return d.Mount
+	}
+	return DefaultDatabaseMount
+}
+
+// Credentials issues new credentials for a role.
+// The credentials are valid for the lease duration, and should be renewed with `RenewLease` or replaced before it ends.
+func (d Database) Credentials(ctx context.Context, role string) (*DatabaseCredentials, error) {
+	req := d.Client.createRequest(MethodGet, d.path("creds", role)).WithContext(ctx)
+	res, err := d.Client.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var response DatabaseCredentialsResponse
+	if err := d.Client.readJSON(res, &response); err != nil {
+		return nil, err
+	}
+	return &DatabaseCredentials{
+		Lease:    response.Lease,
+		Username: response.Data.Username,
+		Password: response.Data.Password,
+	}, nil
+}
+
+func (d Database) path(segments ...string) string {
+	return filepath.Join(append([]string{"/v1", strings.Trim(d.MountOrDefault(), "/")}, segments...)...)
+}
@@ -0,0 +1,64 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestDatabaseCredentials(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	var requests []string
+	var bodies []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		body := map[string]interface{}{}
+		if req.Body != nil {
+			json.NewDecoder(req.Body).Decode(&body)
+		}
+		bodies = append(bodies, body)
+		switch req.URL.Path {
+		case "/v1/db/creds/readwrite":
+			json.NewEncoder(rw).Encode(map[string]interface{}{
+				"lease_id":       "db/creds/readwrite/abc",
+				"lease_duration": 3600,
+				"renewable":      true,
+				"data":           map[string]string{"username": "v-app-readwrite", "password": "hunter2"},
+			})
+		case "/v1/sys/leases/renew":
+			json.NewEncoder(rw).Encode(map[string]interface{}{"lease_id": body["lease_id"], "lease_duration": 1800, "renewable": true})
+		default:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL), OptDatabaseMount("/db/"))
+	assert.Nil(err)
+
+	credentials, err := client.Database.Credentials(todo, "readwrite")
+	assert.Nil(err)
+	assert.Equal("v-app-readwrite", credentials.Username)
+	assert.Equal("hunter2", credentials.Password)
+	assert.Equal("db/creds/readwrite/abc", credentials.LeaseID)
+	assert.Equal(3600, credentials.LeaseDuration)
+	assert.True(credentials.Renewable)
+
+	lease, err := client.RenewLease(todo, credentials.LeaseID, time.Hour)
+	assert.Nil(err)
+	assert.Equal("db/creds/readwrite/abc", lease.LeaseID)
+	assert.Equal(1800, lease.LeaseDuration)
+	assert.Equal(3600.0, bodies[1]["increment"])
+
+	assert.Nil(client.RevokeLease(todo, credentials.LeaseID))
+	assert.Equal("db/creds/readwrite/abc", bodies[2]["lease_id"])
+
+	assert.Equal([]string{"GET /v1/db/creds/readwrite", "PUT /v1/sys/leases/renew", "PUT /v1/sys/leases/revoke"}, requests)
+}
@@ -7,4 +7,13 @@ const (
 	ErrNotFound     ex.Class = "secrets; not found"
 	ErrUnauthorized ex.Class = "secrets; not authorized"
 	ErrServerError  ex.Class = "secrets; remote error"
+
+	ErrAuthMethodUnset   ex.Class = "secrets; auth method unset"
+	ErrAuthMethodUnknown ex.Class = "secrets; auth method unknown"
+	ErrAuthMissing       ex.Class = "secrets; login response missing auth"
+
+	ErrVersionUnsupported ex.Class = "secrets; secret versions are unsupported by the backend"
+
+	ErrTransitBatchMismatch   ex.Class = "secrets; transit batch results do not match the batch input"
+	ErrTransitInvalidEnvelope ex.Class = "secrets; transit envelope is invalid"
 )
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/blend/go-sdk/ansi"
 	"github.com/blend/go-sdk/logger"
@@ -19,6 +20,14 @@ var (
 const (
 	// Flag is the logger flag.
 	Flag = "secrets"
+	// FlagAuth is the logger flag for token lifetime events, i.e. logins and renewals.
+	FlagAuth = "secrets.auth"
+)
+
+// Auth event methods.
+const (
+	AuthEventLogin = "LOGIN"
+	AuthEventRenew = "RENEW"
 )
 
 // NewEvent returns a new event from a request.
@@ -31,21 +40,40 @@ func NewEvent(req *http.Request) *Event {
 	}
 }
 
+// NewAuthEvent returns a new token lifetime event for a login or renewal.
+func NewAuthEvent(remote, method string, auth *SecretAuth) *Event {
+	e := &Event{
+		EventMeta: logger.NewEventMeta(FlagAuth),
+		Remote:    remote,
+		Method:    method,
+	}
+	if auth != nil {
+		e.TTL = time.Duration(auth.LeaseDuration) * time.Second
+	}
+	return e
+}
+
 // Event is an event.
 type Event struct {
 	*logger.EventMeta
 	Remote string
 	Method string
 	Key    string
+	// TTL is the token ttl for auth events.
+	TTL time.Duration
 }
 
 // MarshalJSON impements json.Marshaler.
 func (e *Event) MarshalJSON() ([]byte, error) {
-	return json.Marshal(logger.MergeDecomposed(e.EventMeta.Decompose(), map[string]interface{}{
+	fields := map[string]interface{}{
 		"remote": e.Remote,
 		"method": e.Method,
 		"key":    e.Key,
-	}))
+	}
+	if e.TTL > 0 {
+		fields["ttl"] = e.TTL.String()
+	}
+	return json.Marshal(logger.MergeDecomposed(e.EventMeta.Decompose(), fields))
 }
 
 // WriteText writes text for the event.
@@ -53,6 +81,12 @@ func (e *Event) WriteText(tf logger.TextFormatter, wr io.Writer) {
 	io.WriteString(wr, "["+tf.Colorize(e.Method, ansi.ColorBlue)+"]")
 	io.WriteString(wr, logger.Space)
 	io.WriteString(wr, e.Remote)
-	io.WriteString(wr, logger.Space)
-	io.WriteString(wr, e.Key)
+	if e.Key != "" {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, e.Key)
+	}
+	if e.TTL > 0 {
+		io.WriteString(wr, logger.Space)
+		io.WriteString(wr, "ttl="+e.TTL.String())
+	}
 }
@@ -63,7 +63,7 @@ func (kv2 KV2) Delete(ctx context.Context, key string, options ...RequestOption)
 
 // List returns a slice of key and subfolder names at this path.
 func (kv2 KV2) List(ctx context.Context, path string, options ...RequestOption) ([]string, error) {
-	req := kv2.Client.createRequest(MethodList, filepath.Join("/v1/", kv2.fixSecretDataPrefix(path)), options...).WithContext(ctx)
+	req := kv2.Client.createRequest(MethodList, filepath.Join("/v1/", kv2.fixSecretMetadataPrefix(path)), options...).WithContext(ctx)
 	res, err := kv2.Client.send(req)
 	if err != nil {
 		return nil, err
@@ -77,12 +77,40 @@ func (kv2 KV2) List(ctx context.Context, path string, options ...RequestOption)
 	return response.Data.Keys, nil
 }
 
+// Metadata returns the metadata of a secret, including its current version, without reading the secret.
+func (kv2 KV2) Metadata(ctx context.Context, key string, options ...RequestOption) (*SecretMetadata, error) {
+	req := kv2.Client.createRequest(MethodGet, filepath.Join("/v1/", kv2.fixSecretMetadataPrefix(key)), options...).WithContext(ctx)
+	res, err := kv2.Client.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var response SecretMetadataV2
+	if err := json.NewDecoder(res).Decode(&response); err != nil {
+		return nil, err
+	}
+	return &response.Data, nil
+}
+
+// fixSecretMetadataPrefix ensures that a key is prefixed with secret/metadata/...
+func (kv2 KV2) fixSecretMetadataPrefix(key string) string {
+	key = strings.TrimPrefix(key, "/")
+	if strings.HasPrefix(key, "secret/data/") {
+		return "secret/metadata/" + strings.TrimPrefix(key, "secret/data/")
+	}
+	if strings.HasPrefix(key, "secret/") && !strings.HasPrefix(key, "secret/metadata/") {
+		return "secret/metadata/" + strings.TrimPrefix(key, "secret/")
+	}
+	return key
+}
+
 // fixSecretDataPrefix ensures that a key is prefixed with secret/data/...
 func (kv2 KV2) fixSecretDataPrefix(key string) string {
 	key = strings.TrimPrefix(key, "/")
 	if strings.HasPrefix(key, "secret") && !strings.HasPrefix(key, "secret/data") {
 		key = strings.TrimPrefix(key, "secret/")
-		key = "secret/data" + key
+		key = "secret/data/" + key
 	}
 	return key
 }
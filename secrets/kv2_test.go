@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestKV2Prefixes(t *testing.T) {
+	assert := assert.New(t)
+
+	kv2 := KV2{}
+	assert.Equal("secret/data/foo", kv2.fixSecretDataPrefix("secret/foo"))
+	assert.Equal("secret/data/foo", kv2.fixSecretDataPrefix("/secret/data/foo"))
+	assert.Equal("secret/metadata/foo", kv2.fixSecretMetadataPrefix("secret/foo"))
+	assert.Equal("secret/metadata/foo", kv2.fixSecretMetadataPrefix("secret/data/foo"))
+	assert.Equal("secret/metadata/foo", kv2.fixSecretMetadataPrefix("secret/metadata/foo"))
+}
+
+func TestKV2List(t *testing.T) {
+	assert := assert.New(t)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests = append(requests, req.Method+" "+req.URL.Path)
+		json.NewEncoder(rw).Encode(map[string]interface{}{"data": map[string]interface{}{"keys": []string{"db", "services/"}}})
+	}))
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL))
+	assert.Nil(err)
+
+	keys, err := client.KV2.List(context.TODO(), "secret/data/app")
+	assert.Nil(err)
+	assert.Equal([]string{"db", "services/"}, keys)
+	assert.Equal([]string{"LIST /v1/secret/metadata/app"}, requests, "kv2 keys are listed from the metadata path")
+}
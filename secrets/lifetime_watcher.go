@@ -0,0 +1,168 @@
+package secrets
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+NewLifetimeWatcher returns a new lifetime watcher for a vault client.
+
+The watcher renews the client token before its ttl expires, and logs in again with the client auth method
+when the token can't be renewed, e.g. because it reached its max ttl or was revoked.
+
+Example:
+
+	client, err := secrets.New(secrets.OptConfigFromEnv())
+	...
+	if _, err := client.Login(ctx); err != nil {
+		...
+	}
+	watcher := secrets.NewLifetimeWatcher(client)
+	go watcher.Start()
+	<-watcher.NotifyStarted()
+	defer watcher.Stop()
+*/
+func NewLifetimeWatcher(client *VaultClient, options ...LifetimeWatcherOption) *LifetimeWatcher {
+	lw := LifetimeWatcher{
+		Client: client,
+	}
+	lw.Renewer = NewRenewer(lw.acquire, lw.renew)
+	if client != nil {
+		lw.Log = client.Log
+	}
+	for _, option := range options {
+		option(&lw)
+	}
+	return &lw
+}
+
+// LifetimeWatcherOption is an option for a lifetime watcher.
+type LifetimeWatcherOption func(*LifetimeWatcher)
+
+// OptLifetimeRenewFraction sets the fraction of the token ttl the watcher waits before renewing it.
+func OptLifetimeRenewFraction(fraction float64) LifetimeWatcherOption {
+	return func(lw *LifetimeWatcher) {
+		lw.RenewFraction = fraction
+	}
+}
+
+// OptLifetimeRetryDelay sets the delay before the watcher retries a failed login.
+func OptLifetimeRetryDelay(d time.Duration) LifetimeWatcherOption {
+	return func(lw *LifetimeWatcher) {
+		lw.RetryDelay = d
+	}
+}
+
+// OptLifetimeIncrement sets the ttl the watcher requests when it renews the token.
+func OptLifetimeIncrement(d time.Duration) LifetimeWatcherOption {
+	return func(lw *LifetimeWatcher) {
+		lw.Increment = d
+	}
+}
+
+// OptLifetimeErrors sets the lifetime watcher error channel.
+func OptLifetimeErrors(errors chan error) LifetimeWatcherOption {
+	return func(lw *LifetimeWatcher) {
+		lw.Errors = errors
+	}
+}
+
+// LifetimeWatcher is a background worker that keeps a vault client token alive.
+//
+// It is started and stopped with the embedded renewer, which waits the renew fraction of the token ttl
+// before renewing it, and the retry delay before retrying a failed login.
+type LifetimeWatcher struct {
+	*Renewer
+	Client *VaultClient
+	// Increment is the ttl requested on renewal; if it is unset, vault renews the token for its default ttl.
+	Increment time.Duration
+
+	// current is the auth the renewal loop renews; it is nil after a failed login.
+	current *SecretAuth
+
+	authMu sync.Mutex
+	auth   *SecretAuth
+}
+
+// Auth returns the most recent auth information for the client token, if there is any.
+func (lw *LifetimeWatcher) Auth() *SecretAuth {
+	lw.authMu.Lock()
+	defer lw.authMu.Unlock()
+	return lw.auth
+}
+
+// acquire looks up or logs in for the token when the watcher starts.
+// If the client has no token, the watcher logs in with the client auth method, otherwise it looks up the
+// ttl of the existing token.
+func (lw *LifetimeWatcher) acquire(ctx context.Context) (time.Duration, error) {
+	return lw.update(lw.authenticate(ctx))
+}
+
+// renew refreshes the token before its ttl ends.
+func (lw *LifetimeWatcher) renew(ctx context.Context) (time.Duration, error) {
+	return lw.update(lw.refresh(ctx, lw.current))
+}
+
+// update makes an auth the current auth, and returns its ttl if it can be refreshed.
+func (lw *LifetimeWatcher) update(auth *SecretAuth, err error) (time.Duration, error) {
+	lw.current = auth
+	if err != nil {
+		return 0, err
+	}
+	lw.setAuth(auth)
+	if !lw.canRefresh(auth) {
+		return 0, nil
+	}
+	return time.Duration(auth.LeaseDuration) * time.Second, nil
+}
+
+// authenticate logs in if the client has no token, otherwise it looks up the existing token,
+// logging in if the lookup fails.
+func (lw *LifetimeWatcher) authenticate(ctx context.Context) (*SecretAuth, error) {
+	if lw.Client.GetToken() == "" {
+		return lw.Client.Login(ctx)
+	}
+	auth, err := lw.Client.LookupSelf(ctx)
+	if err != nil && lw.Client.Auth != nil {
+		lw.HandleError(err)
+		return lw.Client.Login(ctx)
+	}
+	return auth, err
+}
+
+// refresh renews the token if it is renewable, and logs in again if it isn't, if renewal fails,
+// or if the renewed ttl is shorter than the previous ttl because the token is reaching its max ttl.
+// Without an auth method the watcher can only renew the token.
+func (lw *LifetimeWatcher) refresh(ctx context.Context, previous *SecretAuth) (*SecretAuth, error) {
+	if previous != nil && previous.Renewable {
+		renewed, err := lw.Client.RenewSelf(ctx, lw.Increment)
+		if err != nil && lw.Client.Auth == nil {
+			return nil, err
+		}
+		if err != nil {
+			lw.HandleError(err)
+		} else if lw.Client.Auth == nil || renewed.LeaseDuration >= previous.LeaseDuration {
+			return renewed, nil
+		}
+	}
+	if lw.Client.Auth == nil {
+		return lw.authenticate(ctx)
+	}
+	return lw.Client.Login(ctx)
+}
+
+// canRefresh returns if the token expires, and can either be renewed or replaced by logging in again.
+func (lw *LifetimeWatcher) canRefresh(auth *SecretAuth) bool {
+	if auth.LeaseDuration <= 0 {
+		return false
+	}
+	return auth.Renewable || lw.Client.Auth != nil
+}
+
+func (lw *LifetimeWatcher) setAuth(auth *SecretAuth) {
+	lw.authMu.Lock()
+	lw.auth = auth
+	lw.authMu.Unlock()
+}
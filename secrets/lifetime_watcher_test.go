@@ -0,0 +1,164 @@
+package secrets
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+// mockTokenServer is a vault server that issues tokens from logins, and renews them with a given ttl.
+type mockTokenServer struct {
+	sync.Mutex
+	Logins   int
+	Renewals int
+	Lookups  int
+	// RenewTTLs are the ttls returned by renewals, in order; once they run out renewals are denied.
+	RenewTTLs []int
+	Tokens    []string
+}
+
+func (mts *mockTokenServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	mts.Lock()
+	defer mts.Unlock()
+	mts.Tokens = append(mts.Tokens, req.Header.Get(HeaderVaultToken))
+	switch req.URL.Path {
+	case "/v1/auth/approle/login":
+		mts.Logins++
+		fmt.Fprintf(rw, `{"auth":{"client_token":"s.login%d","lease_duration":1,"renewable":true}}`, mts.Logins)
+	case "/v1/auth/token/lookup-self":
+		mts.Lookups++
+		fmt.Fprint(rw, `{"data":{"id":"s.static","ttl":1,"renewable":true}}`)
+	case "/v1/auth/token/renew-self":
+		if len(mts.RenewTTLs) == 0 {
+			rw.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(rw, `{"errors":["permission denied"]}`)
+			return
+		}
+		mts.Renewals++
+		ttl := mts.RenewTTLs[0]
+		mts.RenewTTLs = mts.RenewTTLs[1:]
+		fmt.Fprintf(rw, `{"auth":{"client_token":"%s","lease_duration":%d,"renewable":true}}`, req.Header.Get(HeaderVaultToken), ttl)
+	default:
+		rw.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (mts *mockTokenServer) counts() (logins, renewals, lookups int) {
+	mts.Lock()
+	defer mts.Unlock()
+	return mts.Logins, mts.Renewals, mts.Lookups
+}
+
+// waitFor polls a condition until it is true or a timeout elapses.
+func waitFor(condition func() bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+func TestLifetimeWatcherRenewsStaticToken(t *testing.T) {
+	assert := assert.New(t)
+
+	mts := &mockTokenServer{RenewTTLs: []int{1, 1, 1, 1}}
+	server := httptest.NewServer(mts)
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL), OptToken("s.static"))
+	assert.Nil(err)
+
+	watcher := NewLifetimeWatcher(client, OptLifetimeRenewFraction(0.01))
+	go watcher.Start()
+	<-watcher.NotifyStarted()
+
+	assert.True(waitFor(func() bool {
+		_, renewals, _ := mts.counts()
+		return renewals >= 2
+	}))
+	assert.Nil(watcher.Stop())
+
+	logins, _, lookups := mts.counts()
+	assert.Zero(logins)
+	assert.Equal(1, lookups)
+	assert.Equal("s.static", client.GetToken())
+	assert.NotNil(watcher.Auth())
+	assert.Equal(1, watcher.Auth().LeaseDuration)
+}
+
+func TestLifetimeWatcherLogsInAgainWhenRenewalFails(t *testing.T) {
+	assert := assert.New(t)
+
+	mts := &mockTokenServer{RenewTTLs: []int{1}}
+	server := httptest.NewServer(mts)
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL), OptAuth(AppRoleAuth{RoleID: "role"}))
+	assert.Nil(err)
+
+	errors := make(chan error, 1)
+	watcher := NewLifetimeWatcher(client, OptLifetimeRenewFraction(0.01), OptLifetimeErrors(errors))
+	go watcher.Start()
+	<-watcher.NotifyStarted()
+
+	assert.True(waitFor(func() bool {
+		logins, _, _ := mts.counts()
+		return logins >= 2
+	}))
+	assert.Nil(watcher.Stop())
+
+	logins, renewals, lookups := mts.counts()
+	assert.Equal(1, renewals)
+	assert.Zero(lookups)
+	assert.Equal(fmt.Sprintf("s.login%d", logins), client.GetToken())
+	assert.NotEmpty(errors, "the failed renewal should be reported")
+
+	mts.Lock()
+	defer mts.Unlock()
+	assert.Equal("s.login1", mts.Tokens[1], "renewals should use the token from the login")
+}
+
+func TestLifetimeWatcherLogsInAgainNearMaxTTL(t *testing.T) {
+	assert := assert.New(t)
+
+	mts := &mockTokenServer{RenewTTLs: []int{0, 1, 1, 1}}
+	server := httptest.NewServer(mts)
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL), OptAuth(AppRoleAuth{RoleID: "role"}))
+	assert.Nil(err)
+
+	watcher := NewLifetimeWatcher(client, OptLifetimeRenewFraction(0.01))
+	go watcher.Start()
+	<-watcher.NotifyStarted()
+
+	assert.True(waitFor(func() bool {
+		logins, _, _ := mts.counts()
+		return logins >= 2
+	}))
+	assert.Nil(watcher.Stop())
+	assert.NotNil(watcher.Stop(), "the watcher should already be stopped")
+}
+
+func TestLifetimeWatcherDefaults(t *testing.T) {
+	assert := assert.New(t)
+
+	watcher := NewLifetimeWatcher(nil)
+	assert.Equal(DefaultRenewFraction, watcher.RenewFractionOrDefault())
+	assert.Equal(DefaultRenewRetryDelay, watcher.RetryDelayOrDefault())
+	assert.Nil(watcher.Auth())
+
+	watcher = NewLifetimeWatcher(nil, OptLifetimeRenewFraction(0.5), OptLifetimeRetryDelay(time.Second), OptLifetimeIncrement(time.Hour))
+	assert.Equal(0.5, watcher.RenewFractionOrDefault())
+	assert.Equal(time.Second, watcher.RetryDelayOrDefault())
+	assert.Equal(time.Hour, watcher.Increment)
+	assert.Equal(time.Minute, watcher.renewAfter(2*time.Minute))
+}
@@ -44,12 +44,32 @@ func OptConfig(cfg Config) Option {
 		if err := OptMount(cfg.MountOrDefault())(vc); err != nil {
 			return err
 		}
+		if err := OptTransitMount(cfg.TransitMountOrDefault())(vc); err != nil {
+			return err
+		}
+		if err := OptDatabaseMount(cfg.DatabaseMountOrDefault())(vc); err != nil {
+			return err
+		}
 		if err := OptToken(cfg.Token)(vc); err != nil {
 			return err
 		}
 		if err := OptRootCAs(cfg.RootCAs...)(vc); err != nil {
 			return err
 		}
+		if cfg.Auth.ClientCert != "" || cfg.Auth.ClientKey != "" {
+			if err := OptClientCert(cfg.Auth.ClientCert, cfg.Auth.ClientKey)(vc); err != nil {
+				return err
+			}
+		}
+		if !cfg.Auth.IsZero() {
+			auth, err := cfg.Auth.AuthMethod()
+			if err != nil {
+				return err
+			}
+			if err := OptAuth(auth)(vc); err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 }
@@ -74,6 +94,22 @@ func OptMount(mount string) Option {
 	}
 }
 
+// OptTransitMount sets the vault client transit engine mount.
+func OptTransitMount(mount string) Option {
+	return func(vc *VaultClient) error {
+		vc.Transit = &Transit{Client: vc, Mount: mount}
+		return nil
+	}
+}
+
+// OptDatabaseMount sets the vault client database engine mount.
+func OptDatabaseMount(mount string) Option {
+	return func(vc *VaultClient) error {
+		vc.Database = &Database{Client: vc, Mount: mount}
+		return nil
+	}
+}
+
 // OptToken sets the vault client token.
 func OptToken(token string) Option {
 	return func(vc *VaultClient) error {
@@ -82,6 +118,35 @@ func OptToken(token string) Option {
 	}
 }
 
+// OptAuth sets the auth method used to log in.
+// The client does not log in until `Login` is called or a lifetime watcher is started.
+func OptAuth(auth AuthMethod) Option {
+	return func(vc *VaultClient) error {
+		vc.Auth = auth
+		return nil
+	}
+}
+
+// OptClientCert sets the tls client certificate and key, by path, for client requests.
+// It is required for the cert auth method.
+func OptClientCert(certPath, keyPath string) Option {
+	return func(vc *VaultClient) error {
+		cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+		if err != nil {
+			return ex.New(err)
+		}
+		xport, err := vc.transport()
+		if err != nil {
+			return err
+		}
+		if xport.TLSClientConfig == nil {
+			xport.TLSClientConfig = &tls.Config{}
+		}
+		xport.TLSClientConfig.Certificates = append(xport.TLSClientConfig.Certificates, cert)
+		return nil
+	}
+}
+
 // OptRootCAs sets the root ca pool for client requests.
 // If unset, it will set the VaultClient Client to be an http.Client.
 // If unset, it will set the transport to be an http.Transport.
@@ -96,26 +161,9 @@ func OptRootCAs(rootCAs ...string) Option {
 			if err != nil {
 				return err
 			}
-			var client *http.Client
-			if vc.Client == nil {
-				client = &http.Client{}
-				vc.Client = client
-			} else if typed, ok := vc.Client.(*http.Client); ok && typed != nil {
-				client = typed
-			}
-			if client == nil {
-				return ex.New("invalid http client for vault client; cannot set root cas")
-			}
-
-			var xport *http.Transport
-			if client.Transport == nil {
-				xport := &http.Transport{}
-				client.Transport = xport
-			} else if typed, ok := client.Transport.(*http.Transport); ok && typed != nil {
-				xport = typed
-			}
-			if xport == nil {
-				return ex.New("invalid http transport for vault client; cannot set root cas")
+			xport, err := vc.transport()
+			if err != nil {
+				return err
 			}
 			if xport.TLSClientConfig == nil {
 				xport.TLSClientConfig = &tls.Config{}
@@ -125,3 +173,29 @@ func OptRootCAs(rootCAs ...string) Option {
 		return nil
 	}
 }
+
+// transport returns the http transport of the client, creating the http client and transport if they're unset.
+func (vc *VaultClient) transport() (*http.Transport, error) {
+	var client *http.Client
+	if vc.Client == nil {
+		client = &http.Client{}
+		vc.Client = client
+	} else if typed, ok := vc.Client.(*http.Client); ok && typed != nil {
+		client = typed
+	}
+	if client == nil {
+		return nil, ex.New("invalid http client for vault client; cannot set tls config")
+	}
+
+	var xport *http.Transport
+	if client.Transport == nil {
+		xport = &http.Transport{}
+		client.Transport = xport
+	} else if typed, ok := client.Transport.(*http.Transport); ok && typed != nil {
+		xport = typed
+	}
+	if xport == nil {
+		return nil, ex.New("invalid http transport for vault client; cannot set tls config")
+	}
+	return xport, nil
+}
@@ -0,0 +1,130 @@
+package secrets
+
+import (
+	"context"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+// RenewFunc acquires or renews something with a ttl, e.g. a token or a lease, and returns its ttl.
+// A ttl of zero or less means it doesn't expire, or can't be renewed, so it is not renewed again.
+type RenewFunc func(context.Context) (time.Duration, error)
+
+// NewRenewer returns a new renewer that calls acquire when it starts, and renew before each ttl ends.
+func NewRenewer(acquire, renew RenewFunc) *Renewer {
+	return &Renewer{
+		Latch:   async.NewLatch(),
+		Acquire: acquire,
+		Renew:   renew,
+	}
+}
+
+// Renewer is a background worker that keeps something with a ttl alive by renewing it
+// after a fraction of each ttl, and retries failed renewals after a delay.
+//
+// It is the renewal loop of the lifetime watcher, and of workers in other packages, e.g. the
+// database credential rotator.
+type Renewer struct {
+	*async.Latch
+	// Acquire is called when the renewer starts.
+	Acquire RenewFunc
+	// Renew is called after the renew fraction of each ttl, and after the retry delay when a call fails.
+	Renew RenewFunc
+	// RenewFraction is the fraction of the ttl to wait before renewing.
+	RenewFraction float64
+	// RetryDelay is the delay before retrying a failed call.
+	RetryDelay time.Duration
+	// Errors receives errors if it is set; errors are dropped if it is full.
+	Errors chan error
+	// Log receives errors if it is set.
+	Log logger.Log
+}
+
+// RenewFractionOrDefault returns the renew fraction or a default.
+func (r *Renewer) RenewFractionOrDefault() float64 {
+	if r.RenewFraction > 0 && r.RenewFraction < 1 {
+		return r.RenewFraction
+	}
+	return DefaultRenewFraction
+}
+
+// RetryDelayOrDefault returns the retry delay or a default.
+func (r *Renewer) RetryDelayOrDefault() time.Duration {
+	if r.RetryDelay > 0 {
+		return r.RetryDelay
+	}
+	return DefaultRenewRetryDelay
+}
+
+/*
+Start starts the renewer. The context passed to acquire and renew is cancelled when the renewer is stopped.
+It will return an ErrCannotStart if the renewer is already started.
+
+This call will block.
+*/
+func (r *Renewer) Start() error {
+	if !r.CanStart() {
+		return ex.New(async.ErrCannotStart)
+	}
+	r.Starting()
+	stopping := r.NotifyStopping()
+	r.Started()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopping:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ttl, err := r.Acquire(ctx)
+	for {
+		var wait <-chan time.Time
+		if err != nil {
+			r.HandleError(err)
+			wait = time.After(r.RetryDelayOrDefault())
+		} else if ttl > 0 {
+			wait = time.After(r.renewAfter(ttl))
+		}
+
+		select {
+		case <-wait:
+			ttl, err = r.Renew(ctx)
+		case <-stopping:
+			r.Stopped()
+			return nil
+		}
+	}
+}
+
+// Stop stops the renewer.
+func (r *Renewer) Stop() error {
+	if !r.CanStop() {
+		return ex.New(async.ErrCannotStop)
+	}
+	r.Stopping()
+	<-r.NotifyStopped()
+	return nil
+}
+
+// HandleError logs an error and sends it to the errors channel if it is set and ready to receive it.
+func (r *Renewer) HandleError(err error) {
+	logger.MaybeError(r.Log, err)
+	if r.Errors != nil {
+		select {
+		case r.Errors <- err:
+		default:
+		}
+	}
+}
+
+// renewAfter returns how long to wait before renewing something with a given ttl.
+func (r *Renewer) renewAfter(ttl time.Duration) time.Duration {
+	return time.Duration(float64(ttl) * r.RenewFractionOrDefault())
+}
@@ -0,0 +1,63 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestRenewer(t *testing.T) {
+	assert := assert.New(t)
+
+	var mu sync.Mutex
+	var renewals int
+	renewed := make(chan struct{}, 8)
+	renewer := NewRenewer(
+		func(_ context.Context) (time.Duration, error) {
+			return 10 * time.Millisecond, nil
+		},
+		func(_ context.Context) (time.Duration, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			renewals++
+			renewed <- struct{}{}
+			switch renewals {
+			case 1:
+				return 0, fmt.Errorf("renewal failed")
+			case 2:
+				return 10 * time.Millisecond, nil
+			default:
+				return 0, nil
+			}
+		},
+	)
+	renewer.RetryDelay = time.Millisecond
+	renewer.Errors = make(chan error, 1)
+	assert.Equal(DefaultRenewFraction, renewer.RenewFractionOrDefault())
+	assert.Equal(time.Millisecond, renewer.RetryDelayOrDefault())
+
+	started := renewer.NotifyStarted()
+	go renewer.Start()
+	<-started
+
+	// renews after the ttl, retries the failed renewal, then renews again and stops renewing without a ttl.
+	for x := 0; x < 3; x++ {
+		select {
+		case <-renewed:
+		case <-time.After(5 * time.Second):
+			assert.FailNow("renewal timed out")
+		}
+	}
+	assert.Equal("renewal failed", (<-renewer.Errors).Error())
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(3, renewals)
+	mu.Unlock()
+
+	assert.Nil(renewer.Stop())
+	assert.True(renewer.IsStopped())
+}
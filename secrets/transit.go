@@ -0,0 +1,168 @@
+package secrets
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/blend/go-sdk/ex"
+)
+
+// TransitOption is an option for transit encrypt, decrypt, rewrap and datakey requests.
+type TransitOption func(*TransitBatchItem)
+
+// OptTransitContext sets the key derivation context, which is required for derived keys.
+func OptTransitContext(context []byte) TransitOption {
+	return func(tbi *TransitBatchItem) {
+		tbi.Context = context
+	}
+}
+
+// OptTransitKeyVersion sets the key version to encrypt or rewrap with; if it is unset the latest version is used.
+func OptTransitKeyVersion(version int) TransitOption {
+	return func(tbi *TransitBatchItem) {
+		tbi.KeyVersion = version
+	}
+}
+
+// Transit defines transit secrets engine interactions, i.e. encryption as a service with keys that never leave vault.
+type Transit struct {
+	Client *VaultClient
+	// Mount is the transit engine mount path.
+	Mount string
+}
+
+// MountOrDefault returns the mount or a default.
+func (t Transit) MountOrDefault() string {
+	if t.Mount != "" {
+		return t.Mount
+	}
+	return DefaultTransitMount
+}
+
+// CreateKey creates a named encryption key.
+func (t Transit) CreateKey(ctx context.Context, key string, input CreateTransitKeyInput) error {
+	return t.do(ctx, MethodPost, t.path("keys", key), input, nil)
+}
+
+// ReadKey returns the metadata for a named encryption key; the key material itself is never returned.
+func (t Transit) ReadKey(ctx context.Context, key string) (*TransitKey, error) {
+	var output TransitKey
+	if err := t.do(ctx, MethodGet, t.path("keys", key), nil, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// RotateKey rotates a named encryption key, adding a new latest version that is used for encryption.
+// Previous versions are retained and can still decrypt, so existing ciphertext should be rewrapped.
+func (t Transit) RotateKey(ctx context.Context, key string) error {
+	return t.do(ctx, MethodPost, t.path("keys", key, "rotate"), nil, nil)
+}
+
+// Encrypt encrypts plaintext with a named key, returning the ciphertext, e.g. `vault:v1:...`.
+func (t Transit) Encrypt(ctx context.Context, key string, plaintext []byte, options ...TransitOption) (string, error) {
+	input := TransitBatchItem{Plaintext: plaintext}
+	var output TransitBatchItem
+	if err := t.do(ctx, MethodPost, t.path("encrypt", key), t.apply(input, options...), &output); err != nil {
+		return "", err
+	}
+	return output.Ciphertext, nil
+}
+
+// Decrypt decrypts ciphertext with a named key, returning the plaintext.
+func (t Transit) Decrypt(ctx context.Context, key string, ciphertext string, options ...TransitOption) ([]byte, error) {
+	input := TransitBatchItem{Ciphertext: ciphertext}
+	var output TransitBatchItem
+	if err := t.do(ctx, MethodPost, t.path("decrypt", key), t.apply(input, options...), &output); err != nil {
+		return nil, err
+	}
+	return output.Plaintext, nil
+}
+
+// Rewrap re-encrypts ciphertext with the latest version of a named key without exposing the plaintext.
+func (t Transit) Rewrap(ctx context.Context, key string, ciphertext string, options ...TransitOption) (string, error) {
+	input := TransitBatchItem{Ciphertext: ciphertext}
+	var output TransitBatchItem
+	if err := t.do(ctx, MethodPost, t.path("rewrap", key), t.apply(input, options...), &output); err != nil {
+		return "", err
+	}
+	return output.Ciphertext, nil
+}
+
+// EncryptBatch encrypts the plaintext of each item with a named key in one request.
+// The results are in the same order as the items; an item that failed has its `Error` set.
+func (t Transit) EncryptBatch(ctx context.Context, key string, items []TransitBatchItem) ([]TransitBatchItem, error) {
+	return t.batch(ctx, t.path("encrypt", key), items)
+}
+
+// DecryptBatch decrypts the ciphertext of each item with a named key in one request.
+// The results are in the same order as the items; an item that failed has its `Error` set.
+func (t Transit) DecryptBatch(ctx context.Context, key string, items []TransitBatchItem) ([]TransitBatchItem, error) {
+	return t.batch(ctx, t.path("decrypt", key), items)
+}
+
+// RewrapBatch rewraps the ciphertext of each item with the latest version of a named key in one request.
+// The results are in the same order as the items; an item that failed has its `Error` set.
+func (t Transit) RewrapBatch(ctx context.Context, key string, items []TransitBatchItem) ([]TransitBatchItem, error) {
+	return t.batch(ctx, t.path("rewrap", key), items)
+}
+
+// GenerateDataKey generates a new 256 bit data key, returning both the plaintext key and the key
+// encrypted with a named key. The plaintext key should be used to encrypt data locally and then discarded,
+// storing only the ciphertext key alongside the data, i.e. envelope encryption.
+func (t Transit) GenerateDataKey(ctx context.Context, key string, options ...TransitOption) (*TransitDataKey, error) {
+	input := t.apply(TransitBatchItem{}, options...)
+	var output TransitDataKey
+	if err := t.do(ctx, MethodPost, t.path("datakey", "plaintext", key), TransitDataKeyInput{Context: input.Context}, &output); err != nil {
+		return nil, err
+	}
+	return &output, nil
+}
+
+// --------------------------------------------------------------------------------
+// utility methods
+// --------------------------------------------------------------------------------
+
+func (t Transit) batch(ctx context.Context, path string, items []TransitBatchItem) ([]TransitBatchItem, error) {
+	var output TransitBatchOutput
+	if err := t.do(ctx, MethodPost, path, TransitBatchInput{BatchInput: items}, &output); err != nil {
+		return nil, err
+	}
+	if len(output.BatchResults) != len(items) {
+		return nil, ex.New(ErrTransitBatchMismatch, ex.OptMessagef("items: %d, results: %d", len(items), len(output.BatchResults)))
+	}
+	return output.BatchResults, nil
+}
+
+// do sends a transit request, reading the `data` field of the response into the output if it is set.
+func (t Transit) do(ctx context.Context, method, path string, input, output interface{}) error {
+	req := t.Client.createRequest(method, path).WithContext(ctx)
+	if input != nil {
+		contents, err := t.Client.jsonBody(input)
+		if err != nil {
+			return err
+		}
+		req.Body = contents
+	}
+	res, err := t.Client.send(req)
+	if output == nil {
+		return t.Client.discard(res, err)
+	}
+	if err != nil {
+		return err
+	}
+	defer res.Close()
+	return t.Client.readJSON(res, &TransitResponse{Data: output})
+}
+
+func (t Transit) apply(input TransitBatchItem, options ...TransitOption) TransitBatchItem {
+	for _, option := range options {
+		option(&input)
+	}
+	return input
+}
+
+func (t Transit) path(segments ...string) string {
+	return filepath.Join(append([]string{"/v1", strings.Trim(t.MountOrDefault(), "/")}, segments...)...)
+}
@@ -0,0 +1,134 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"math"
+
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+)
+
+// assert TransitEnvelope implements crypto.Transit.
+var (
+	_ crypto.Transit = (*TransitEnvelope)(nil)
+)
+
+// NewTransitEnvelope returns a new envelope encryptor that protects data keys with a named transit key.
+func NewTransitEnvelope(transit *Transit, key string, options ...TransitEnvelopeOption) *TransitEnvelope {
+	te := TransitEnvelope{
+		Transit: transit,
+		Key:     key,
+		Context: context.Background(),
+	}
+	for _, option := range options {
+		option(&te)
+	}
+	return &te
+}
+
+// TransitEnvelopeOption is an option for transit envelopes.
+type TransitEnvelopeOption func(*TransitEnvelope)
+
+// OptTransitEnvelopeContext sets the context used for transit requests.
+func OptTransitEnvelopeContext(ctx context.Context) TransitEnvelopeOption {
+	return func(te *TransitEnvelope) {
+		te.Context = ctx
+	}
+}
+
+// OptTransitEnvelopeTransitOptions sets the options for the transit datakey and decrypt requests, e.g. `OptTransitContext`.
+func OptTransitEnvelopeTransitOptions(options ...TransitOption) TransitEnvelopeOption {
+	return func(te *TransitEnvelope) {
+		te.TransitOptions = options
+	}
+}
+
+// TransitEnvelope is an envelope encryptor and decryptor with the same shape as `crypto.LocalTransit`.
+//
+// Each call to encrypt generates a new data key with transit, encrypts the stream locally with it, and
+// stores the data key encrypted by transit in the output; the plaintext key never leaves memory.
+// The output is the encrypted data key length as a big endian uint16, the encrypted data key, the IV,
+// the encrypted stream, and the HMAC of the stream.
+type TransitEnvelope struct {
+	Transit        *Transit
+	Key            string
+	Context        context.Context
+	TransitOptions []TransitOption
+}
+
+// Encrypt reads a source stream and encrypts it to the destination stream.
+func (te TransitEnvelope) Encrypt(dst io.Writer, src io.Reader) error {
+	dataKey, err := te.Transit.GenerateDataKey(te.Context, te.Key, te.TransitOptions...)
+	if err != nil {
+		return err
+	}
+	if len(dataKey.Ciphertext) > math.MaxUint16 {
+		return ex.New(ErrTransitInvalidEnvelope, ex.OptMessage("data key ciphertext too long"))
+	}
+
+	wr, err := crypto.NewStreamEncryptor(dataKey.Plaintext, src)
+	if err != nil {
+		return err
+	}
+
+	header := make([]byte, 2, 2+len(dataKey.Ciphertext)+len(wr.IV))
+	binary.BigEndian.PutUint16(header, uint16(len(dataKey.Ciphertext)))
+	header = append(header, dataKey.Ciphertext...)
+	header = append(header, wr.IV...)
+	if _, err = dst.Write(header); err != nil {
+		return ex.New(err)
+	}
+	if _, err = io.Copy(dst, wr); err != nil {
+		return ex.New(err)
+	}
+	// the hash is only complete once the stream has been read.
+	if _, err = dst.Write(wr.Meta().Hash); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
+
+// Decrypt reads a source stream and decrypts it to the destination stream.
+// The stream is authenticated before any of it is written to the destination, so it is buffered in memory.
+func (te TransitEnvelope) Decrypt(dst io.Writer, src io.Reader) error {
+	contents, err := ioutil.ReadAll(src)
+	if err != nil {
+		return ex.New(err)
+	}
+	if len(contents) < 2 {
+		return ex.New(ErrTransitInvalidEnvelope, ex.OptMessage("missing data key length"))
+	}
+	keyLength := int(binary.BigEndian.Uint16(contents))
+	contents = contents[2:]
+	if len(contents) < keyLength+crypto.IVSize+crypto.HashSize {
+		return ex.New(ErrTransitInvalidEnvelope, ex.OptMessage("too short"))
+	}
+	encryptedKey := string(contents[:keyLength])
+	iv := contents[keyLength : keyLength+crypto.IVSize]
+	cipherText := contents[keyLength+crypto.IVSize : len(contents)-crypto.HashSize]
+	hash := contents[len(contents)-crypto.HashSize:]
+
+	key, err := te.Transit.Decrypt(te.Context, te.Key, encryptedKey, te.TransitOptions...)
+	if err != nil {
+		return err
+	}
+	r, err := crypto.NewStreamDecryptor(key, crypto.StreamMeta{IV: iv, Hash: hash}, bytes.NewReader(cipherText))
+	if err != nil {
+		return err
+	}
+	plainText := new(bytes.Buffer)
+	if _, err = io.Copy(plainText, r); err != nil {
+		return ex.New(err)
+	}
+	if err = r.Authenticate(); err != nil {
+		return err
+	}
+	if _, err = io.Copy(dst, plainText); err != nil {
+		return ex.New(err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/crypto"
+	"github.com/blend/go-sdk/ex"
+)
+
+// mockTransitServer is a transit engine that "encrypts" by base64 encoding with the key name, so ciphertext is predictable.
+type mockTransitServer struct {
+	Requests []string
+	Bodies   []map[string]interface{}
+}
+
+func (mts *mockTransitServer) encrypt(key string, plaintext []byte) string {
+	return "vault:v1:" + base64.StdEncoding.EncodeToString(append([]byte(key+":"), plaintext...))
+}
+
+func (mts *mockTransitServer) decrypt(key string, ciphertext string) ([]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, "vault:v1:"))
+	if err != nil || !bytes.HasPrefix(decoded, []byte(key+":")) {
+		return nil, fmt.Errorf("invalid ciphertext")
+	}
+	return bytes.TrimPrefix(decoded, []byte(key+":")), nil
+}
+
+func (mts *mockTransitServer) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	mts.Requests = append(mts.Requests, req.Method+" "+req.URL.Path)
+	var input struct {
+		TransitBatchItem
+		BatchInput []TransitBatchItem `json:"batch_input"`
+	}
+	body := map[string]interface{}{}
+	if req.Body != nil {
+		var raw bytes.Buffer
+		raw.ReadFrom(req.Body)
+		json.Unmarshal(raw.Bytes(), &body)
+		json.Unmarshal(raw.Bytes(), &input)
+	}
+	mts.Bodies = append(mts.Bodies, body)
+
+	segments := strings.Split(strings.TrimPrefix(req.URL.Path, "/v1/transit/"), "/")
+	action, key := segments[0], segments[len(segments)-1]
+	var data interface{}
+	switch {
+	case action == "keys" && req.Method == MethodGet:
+		data = TransitKey{Name: key, Type: "aes256-gcm96", LatestVersion: 2, MinDecryptionVersion: 1, SupportsEncryption: true}
+	case action == "keys":
+		rw.WriteHeader(http.StatusNoContent)
+		return
+	case action == "datakey":
+		dataKey := crypto.MustCreateKey(32)
+		data = TransitDataKey{Plaintext: dataKey, Ciphertext: mts.encrypt(key, dataKey), KeyVersion: 1}
+	case len(input.BatchInput) > 0:
+		var results []TransitBatchItem
+		for _, item := range input.BatchInput {
+			results = append(results, mts.process(action, key, item))
+		}
+		data = TransitBatchOutput{BatchResults: results}
+	default:
+		result := mts.process(action, key, input.TransitBatchItem)
+		if result.Error != "" {
+			rw.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(rw, `{"errors":[%q]}`, result.Error)
+			return
+		}
+		data = result
+	}
+	json.NewEncoder(rw).Encode(map[string]interface{}{"data": data})
+}
+
+func (mts *mockTransitServer) process(action, key string, item TransitBatchItem) TransitBatchItem {
+	switch action {
+	case "encrypt":
+		return TransitBatchItem{Ciphertext: mts.encrypt(key, item.Plaintext)}
+	case "decrypt":
+		plaintext, err := mts.decrypt(key, item.Ciphertext)
+		if err != nil {
+			return TransitBatchItem{Error: err.Error()}
+		}
+		return TransitBatchItem{Plaintext: plaintext}
+	case "rewrap":
+		plaintext, err := mts.decrypt(key, item.Ciphertext)
+		if err != nil {
+			return TransitBatchItem{Error: err.Error()}
+		}
+		return TransitBatchItem{Ciphertext: strings.Replace(mts.encrypt(key, plaintext), "v1", "v2", 1)}
+	default:
+		return TransitBatchItem{Error: "unsupported"}
+	}
+}
+
+func TestTransit(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	mts := &mockTransitServer{}
+	server := httptest.NewServer(mts)
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL), OptToken("token"))
+	assert.Nil(err)
+	assert.Equal(DefaultTransitMount, client.Transit.Mount)
+
+	assert.Nil(client.Transit.CreateKey(todo, "orders", CreateTransitKeyInput{Derived: true}))
+	assert.Nil(client.Transit.RotateKey(todo, "orders"))
+	key, err := client.Transit.ReadKey(todo, "orders")
+	assert.Nil(err)
+	assert.Equal("orders", key.Name)
+	assert.Equal(2, key.LatestVersion)
+	assert.Equal([]string{"POST /v1/transit/keys/orders", "POST /v1/transit/keys/orders/rotate", "GET /v1/transit/keys/orders"}, mts.Requests)
+	assert.Equal(true, mts.Bodies[0]["derived"])
+
+	ciphertext, err := client.Transit.Encrypt(todo, "orders", []byte("plaintext"), OptTransitContext([]byte("tenant")), OptTransitKeyVersion(1))
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(ciphertext, "vault:v1:"))
+	assert.Equal(base64.StdEncoding.EncodeToString([]byte("plaintext")), mts.Bodies[3]["plaintext"], "plaintext should be base64 encoded")
+	assert.Equal(base64.StdEncoding.EncodeToString([]byte("tenant")), mts.Bodies[3]["context"])
+	assert.Equal(1.0, mts.Bodies[3]["key_version"])
+
+	plaintext, err := client.Transit.Decrypt(todo, "orders", ciphertext)
+	assert.Nil(err)
+	assert.Equal("plaintext", string(plaintext))
+
+	_, err = client.Transit.Decrypt(todo, "users", ciphertext)
+	assert.True(ex.Is(err, ErrServerError))
+
+	rewrapped, err := client.Transit.Rewrap(todo, "orders", ciphertext)
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(rewrapped, "vault:v2:"))
+}
+
+func TestTransitBatch(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	server := httptest.NewServer(&mockTransitServer{})
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL), OptTransitMount("/transit/"))
+	assert.Nil(err)
+
+	encrypted, err := client.Transit.EncryptBatch(todo, "orders", []TransitBatchItem{{Plaintext: []byte("one")}, {Plaintext: []byte("two")}})
+	assert.Nil(err)
+	assert.Len(encrypted, 2)
+
+	decrypted, err := client.Transit.DecryptBatch(todo, "orders", []TransitBatchItem{{Ciphertext: encrypted[1].Ciphertext}, {Ciphertext: "vault:v1:garbage"}, {Ciphertext: encrypted[0].Ciphertext}})
+	assert.Nil(err)
+	assert.Len(decrypted, 3)
+	assert.Equal("two", string(decrypted[0].Plaintext))
+	assert.NotEmpty(decrypted[1].Error)
+	assert.Equal("one", string(decrypted[2].Plaintext))
+
+	rewrapped, err := client.Transit.RewrapBatch(todo, "orders", encrypted)
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(rewrapped[0].Ciphertext, "vault:v2:"))
+}
+
+func TestTransitEnvelope(t *testing.T) {
+	assert := assert.New(t)
+
+	mts := &mockTransitServer{}
+	server := httptest.NewServer(mts)
+	defer server.Close()
+
+	client, err := New(OptRemote(server.URL))
+	assert.Nil(err)
+
+	// envelopes and local transits can be used interchangeably.
+	var transit crypto.Transit = NewTransitEnvelope(client.Transit, "orders", OptTransitEnvelopeTransitOptions(OptTransitContext([]byte("tenant"))))
+
+	plaintext := strings.Repeat("mary jane hawkins ", 1024)
+	ciphertext := new(bytes.Buffer)
+	assert.Nil(transit.Encrypt(ciphertext, strings.NewReader(plaintext)))
+	assert.NotContains(ciphertext.String(), "mary jane hawkins")
+	assert.Equal("POST /v1/transit/datakey/plaintext/orders", mts.Requests[0])
+	assert.Equal(base64.StdEncoding.EncodeToString([]byte("tenant")), mts.Bodies[0]["context"])
+
+	output := new(bytes.Buffer)
+	assert.Nil(transit.Decrypt(output, bytes.NewReader(ciphertext.Bytes())))
+	assert.Equal(plaintext, output.String())
+	assert.Equal("POST /v1/transit/decrypt/orders", mts.Requests[1], "only the data key should be sent to transit")
+
+	tampered := ciphertext.Bytes()
+	tampered[len(tampered)-crypto.HashSize-1] ^= 0xff
+	output.Reset()
+	assert.NotNil(transit.Decrypt(output, bytes.NewReader(tampered)))
+	assert.Empty(output.Bytes(), "unauthenticated plaintext should not be written")
+
+	err = transit.Decrypt(output, bytes.NewReader([]byte{0, 4, 'v'}))
+	assert.True(ex.Is(err, ErrTransitInvalidEnvelope))
+}
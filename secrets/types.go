@@ -98,6 +98,32 @@ type SecretListV2 struct {
 // SecretData is used for puts.
 type SecretData struct {
 	Data Values `json:"data"`
+	// Metadata is the version metadata returned with kv2 reads.
+	Metadata *SecretVersionMetadata `json:"metadata,omitempty"`
+}
+
+// SecretMetadataV2 is the structure returned for kv2 secret metadata.
+type SecretMetadataV2 struct {
+	RequestID string         `json:"request_id"`
+	Data      SecretMetadata `json:"data"`
+}
+
+// SecretMetadata is the metadata of a kv2 secret across its versions.
+type SecretMetadata struct {
+	CurrentVersion int                              `json:"current_version"`
+	OldestVersion  int                              `json:"oldest_version"`
+	MaxVersions    int                              `json:"max_versions"`
+	CreatedTime    time.Time                        `json:"created_time"`
+	UpdatedTime    time.Time                        `json:"updated_time"`
+	Versions       map[string]SecretVersionMetadata `json:"versions"`
+}
+
+// SecretVersionMetadata is the metadata of a version of a kv2 secret.
+type SecretVersionMetadata struct {
+	Version      int       `json:"version,omitempty"`
+	CreatedTime  time.Time `json:"created_time"`
+	DeletionTime string    `json:"deletion_time"`
+	Destroyed    bool      `json:"destroyed"`
 }
 
 // KeyData is used for lists.
@@ -115,6 +141,23 @@ type SecretAuth struct {
 	Renewable     bool              `json:"renewable"`
 }
 
+// TokenLookupResponse is the result of a token lookup.
+type TokenLookupResponse struct {
+	RequestID string    `json:"request_id"`
+	Data      TokenInfo `json:"data"`
+}
+
+// TokenInfo is the information about a token returned by a lookup.
+type TokenInfo struct {
+	ID         string            `json:"id"`
+	Accessor   string            `json:"accessor"`
+	Policies   []string          `json:"policies"`
+	Meta       map[string]string `json:"meta"`
+	TTL        int               `json:"ttl"`
+	Renewable  bool              `json:"renewable"`
+	ExpireTime string            `json:"expire_time"`
+}
+
 // SecretWrapInfo contains wrapping information if we have it. If what is
 // contained is an authentication token, the accessor for the token will be
 // available in WrappedAccessor.
@@ -179,3 +222,105 @@ type MountConfigInput struct {
 	ListingVisibility         string            `json:"listing_visibility,omitempty" mapstructure:"listing_visibility"`
 	PassthroughRequestHeaders []string          `json:"passthrough_request_headers,omitempty" mapstructure:"passthrough_request_headers"`
 }
+
+// TransitResponse is the response envelope of transit requests.
+type TransitResponse struct {
+	RequestID string      `json:"request_id"`
+	Data      interface{} `json:"data"`
+}
+
+// CreateTransitKeyInput is the input to create a transit key.
+type CreateTransitKeyInput struct {
+	// Type is the key type, e.g. `aes256-gcm96`, which is the vault default.
+	Type                 string `json:"type,omitempty"`
+	Derived              bool   `json:"derived,omitempty"`
+	ConvergentEncryption bool   `json:"convergent_encryption,omitempty"`
+	Exportable           bool   `json:"exportable,omitempty"`
+	AllowPlaintextBackup bool   `json:"allow_plaintext_backup,omitempty"`
+}
+
+// TransitKey is the metadata of a transit key.
+type TransitKey struct {
+	Name                 string                 `json:"name"`
+	Type                 string                 `json:"type"`
+	Keys                 map[string]interface{} `json:"keys"`
+	LatestVersion        int                    `json:"latest_version"`
+	MinDecryptionVersion int                    `json:"min_decryption_version"`
+	MinEncryptionVersion int                    `json:"min_encryption_version"`
+	DeletionAllowed      bool                   `json:"deletion_allowed"`
+	Derived              bool                   `json:"derived"`
+	Exportable           bool                   `json:"exportable"`
+	SupportsEncryption   bool                   `json:"supports_encryption"`
+	SupportsDecryption   bool                   `json:"supports_decryption"`
+	SupportsDerivation   bool                   `json:"supports_derivation"`
+	SupportsSigning      bool                   `json:"supports_signing"`
+}
+
+// TransitBatchItem is the input and output of transit encrypt, decrypt and rewrap requests, and an item of batch requests.
+// Plaintext and context are base64 encoded on the wire.
+type TransitBatchItem struct {
+	Plaintext  []byte `json:"plaintext,omitempty"`
+	Ciphertext string `json:"ciphertext,omitempty"`
+	Context    []byte `json:"context,omitempty"`
+	KeyVersion int    `json:"key_version,omitempty"`
+	// Error is set on batch results for items that failed.
+	Error string `json:"error,omitempty"`
+}
+
+// TransitBatchInput is the input of transit batch requests.
+type TransitBatchInput struct {
+	BatchInput []TransitBatchItem `json:"batch_input"`
+}
+
+// TransitBatchOutput is the output of transit batch requests.
+type TransitBatchOutput struct {
+	BatchResults []TransitBatchItem `json:"batch_results"`
+}
+
+// TransitDataKeyInput is the input of transit datakey requests.
+type TransitDataKeyInput struct {
+	Context []byte `json:"context,omitempty"`
+	Bits    int    `json:"bits,omitempty"`
+}
+
+// TransitDataKey is a data key generated by transit; the plaintext is only set for plaintext data keys.
+type TransitDataKey struct {
+	Plaintext  []byte `json:"plaintext"`
+	Ciphertext string `json:"ciphertext"`
+	KeyVersion int    `json:"key_version"`
+}
+
+// Lease is the lease on a dynamic secret, e.g. database credentials.
+type Lease struct {
+	LeaseID       string `json:"lease_id"`
+	LeaseDuration int    `json:"lease_duration"`
+	Renewable     bool   `json:"renewable"`
+}
+
+// LeaseRenewInput is the input to renew a lease.
+type LeaseRenewInput struct {
+	LeaseID   string `json:"lease_id"`
+	Increment int    `json:"increment,omitempty"`
+}
+
+// LeaseRevokeInput is the input to revoke a lease.
+type LeaseRevokeInput struct {
+	LeaseID string `json:"lease_id"`
+}
+
+// DatabaseCredentials are credentials issued by the database engine for a role, and the lease they are valid for.
+type DatabaseCredentials struct {
+	Lease
+	Username string
+	Password string
+}
+
+// DatabaseCredentialsResponse is the response of database engine credentials requests.
+type DatabaseCredentialsResponse struct {
+	RequestID string `json:"request_id"`
+	Lease
+	Data struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"data"`
+}
@@ -8,6 +8,9 @@ import (
 	"net/http"
 	"net/url"
 	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/blend/go-sdk/bufferutil"
 
@@ -19,7 +22,8 @@ import (
 
 // assert VaultClient implements Client
 var (
-	_ Client = (*VaultClient)(nil)
+	_ Client    = (*VaultClient)(nil)
+	_ Versioner = (*VaultClient)(nil)
 )
 
 // New creates a new vault client with a default set of options.
@@ -44,6 +48,8 @@ func New(options ...Option) (*VaultClient, error) {
 
 	client.KV1 = &KV1{Client: client}
 	client.KV2 = &KV2{Client: client}
+	client.Transit = &Transit{Client: client, Mount: DefaultTransitMount}
+	client.Database = &Database{Client: client, Mount: DefaultDatabaseMount}
 
 	for _, option := range options {
 		if err = option(client); err != nil {
@@ -64,15 +70,128 @@ func Must(c *VaultClient, err error) *VaultClient {
 
 // VaultClient is a client to talk to the secrets store.
 type VaultClient struct {
-	Remote     *url.URL
-	Token      string
+	Remote *url.URL
+	Token  string
+	// Auth is the auth method used to log in, and to log in again if the token can't be renewed.
+	Auth       AuthMethod
 	Mount      string
 	Log        logger.Log
 	BufferPool *bufferutil.Pool
 	KV1        *KV1
 	KV2        *KV2
+	Transit    *Transit
+	Database   *Database
 	Client     HTTPClient
 	CertPool   *CertPool
+
+	tokenMu sync.RWMutex
+}
+
+// SetToken sets the token used for requests.
+// It is safe to call while requests are in flight, e.g. from a lifetime watcher.
+func (c *VaultClient) SetToken(token string) {
+	c.tokenMu.Lock()
+	c.Token = token
+	c.tokenMu.Unlock()
+}
+
+// GetToken returns the token used for requests.
+func (c *VaultClient) GetToken() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+	return c.Token
+}
+
+// Login logs in with the auth method and sets the token used for requests.
+func (c *VaultClient) Login(ctx context.Context) (*SecretAuth, error) {
+	if c.Auth == nil {
+		return nil, ex.New(ErrAuthMethodUnset)
+	}
+	auth, err := c.Auth.Login(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	c.SetToken(auth.ClientToken)
+	logger.MaybeTrigger(ctx, c.Log, NewAuthEvent(c.Remote.Host, AuthEventLogin, auth))
+	return auth, nil
+}
+
+// RenewSelf renews the token used for requests, returning the renewed auth information.
+// If the increment is zero, vault renews the token for its default ttl.
+func (c *VaultClient) RenewSelf(ctx context.Context, increment time.Duration) (*SecretAuth, error) {
+	body := map[string]string{}
+	if increment > 0 {
+		body["increment"] = strconv.Itoa(int(increment/time.Second)) + "s"
+	}
+	contents, err := c.jsonBody(body)
+	if err != nil {
+		return nil, err
+	}
+	req := c.createRequest(MethodPost, "/v1/auth/token/renew-self").WithContext(ctx)
+	req.Body = contents
+	auth, err := c.readAuth(c.send(req))
+	if err != nil {
+		return nil, err
+	}
+	logger.MaybeTrigger(ctx, c.Log, NewAuthEvent(c.Remote.Host, AuthEventRenew, auth))
+	return auth, nil
+}
+
+// LookupSelf returns the auth information for the token used for requests.
+// The lease duration is the remaining ttl of the token, and is zero if the token does not expire.
+func (c *VaultClient) LookupSelf(ctx context.Context) (*SecretAuth, error) {
+	req := c.createRequest(MethodGet, "/v1/auth/token/lookup-self").WithContext(ctx)
+	res, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var response TokenLookupResponse
+	if err := c.readJSON(res, &response); err != nil {
+		return nil, err
+	}
+	return &SecretAuth{
+		ClientToken:   response.Data.ID,
+		Accessor:      response.Data.Accessor,
+		Policies:      response.Data.Policies,
+		Metadata:      response.Data.Meta,
+		LeaseDuration: response.Data.TTL,
+		Renewable:     response.Data.Renewable,
+	}, nil
+}
+
+// RenewLease renews the lease on a dynamic secret, returning the renewed lease.
+// If the increment is zero, vault renews the lease for its default ttl; the ttl is capped at the max ttl of the secret.
+func (c *VaultClient) RenewLease(ctx context.Context, leaseID string, increment time.Duration) (*Lease, error) {
+	contents, err := c.jsonBody(LeaseRenewInput{LeaseID: leaseID, Increment: int(increment / time.Second)})
+	if err != nil {
+		return nil, err
+	}
+	req := c.createRequest(MethodPut, "/v1/sys/leases/renew").WithContext(ctx)
+	req.Body = contents
+	res, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var lease Lease
+	if err := c.readJSON(res, &lease); err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// RevokeLease revokes the lease on a dynamic secret, e.g. so database credentials are dropped immediately.
+func (c *VaultClient) RevokeLease(ctx context.Context, leaseID string) error {
+	contents, err := c.jsonBody(LeaseRevokeInput{LeaseID: leaseID})
+	if err != nil {
+		return err
+	}
+	req := c.createRequest(MethodPut, "/v1/sys/leases/revoke").WithContext(ctx)
+	req.Body = contents
+	return c.discard(c.send(req))
 }
 
 // Put puts a value.
@@ -114,6 +233,23 @@ func (c *VaultClient) List(ctx context.Context, path string, options ...RequestO
 	return backend.List(ctx, path, options...)
 }
 
+// Version returns the current version of a secret without reading it.
+// It returns ErrVersionUnsupported if the secret is not in a kv2 backend.
+func (c *VaultClient) Version(ctx context.Context, key string) (int, error) {
+	version, err := c.getVersion(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	if version != Version2 {
+		return 0, ex.New(ErrVersionUnsupported, ex.OptMessagef("key: %s", key))
+	}
+	meta, err := c.KV2.Metadata(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	return meta.CurrentVersion, nil
+}
+
 // ReadInto reads a secret into an object.
 func (c *VaultClient) ReadInto(ctx context.Context, key string, obj interface{}, options ...RequestOption) error {
 	response, err := c.Get(ctx, key, options...)
@@ -176,6 +312,35 @@ func (c *VaultClient) getMountMeta(ctx context.Context, key string) (*MountRespo
 	return &response, nil
 }
 
+// login posts a login request to an auth path and sets the token used for requests.
+func (c *VaultClient) login(ctx context.Context, path string, input interface{}) (*SecretAuth, error) {
+	contents, err := c.jsonBody(input)
+	if err != nil {
+		return nil, err
+	}
+	req := c.createRequest(MethodPost, path).WithContext(ctx)
+	req.Header.Del(HeaderVaultToken)
+	req.Body = contents
+	return c.readAuth(c.send(req))
+}
+
+// readAuth reads the auth information from a login or renew response.
+func (c *VaultClient) readAuth(res io.ReadCloser, err error) (*SecretAuth, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer res.Close()
+
+	var response SecretV1
+	if err := c.readJSON(res, &response); err != nil {
+		return nil, err
+	}
+	if response.Auth == nil || response.Auth.ClientToken == "" {
+		return nil, ex.New(ErrAuthMissing)
+	}
+	return response.Auth, nil
+}
+
 func (c *VaultClient) jsonBody(input interface{}) (io.ReadCloser, error) {
 	buf := c.BufferPool.Get()
 	err := json.NewEncoder(buf).Encode(input)
@@ -209,7 +374,7 @@ func (c *VaultClient) createRequest(method, path string, options ...RequestOptio
 		Method: method,
 		URL:    remote,
 		Header: http.Header{
-			HeaderVaultToken: []string{c.Token},
+			HeaderVaultToken: []string{c.GetToken()},
 		},
 	}
 	c.applyOptions(req, options...)
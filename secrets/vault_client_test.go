@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/blend/go-sdk/assert"
+	"github.com/blend/go-sdk/ex"
 )
 
 func TestVaultClientBackend(t *testing.T) {
@@ -126,3 +127,28 @@ func TestVaultClientDiscard(t *testing.T) {
 		"foo": "bar",
 	})))
 }
+
+func TestVaultClientVersion(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	client, err := New()
+	assert.Nil(err)
+
+	mountMetaJSONV1 := `{"data":{"options":{"version":"1"},"path":"secret/","type":"kv"}}`
+	mountMetaJSONV2 := `{"data":{"options":{"version":"2"},"path":"secret/","type":"kv"}}`
+	metadataJSON := `{"data":{"current_version":3,"oldest_version":1,"max_versions":10,"versions":{"3":{"created_time":"2019-04-15T01:02:03Z","deletion_time":"","destroyed":false}}}}`
+
+	m := NewMockHTTPClient().
+		WithString("GET", MustURL("%s/v1/sys/internal/ui/mounts/secret/secret/data/foo", client.Remote.String()), mountMetaJSONV2).
+		WithString("GET", MustURL("%s/v1/secret/metadata/foo", client.Remote.String()), metadataJSON)
+	client.Client = m
+
+	version, err := client.Version(todo, "secret/data/foo")
+	assert.Nil(err)
+	assert.Equal(3, version)
+
+	m.WithString("GET", MustURL("%s/v1/sys/internal/ui/mounts/secret/secret/data/foo", client.Remote.String()), mountMetaJSONV1)
+	_, err = client.Version(todo, "secret/data/foo")
+	assert.True(ex.Is(err, ErrVersionUnsupported))
+}
@@ -0,0 +1,274 @@
+package secrets
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/blend/go-sdk/async"
+	"github.com/blend/go-sdk/ex"
+	"github.com/blend/go-sdk/logger"
+)
+
+/*
+NewWatcher returns a new watcher that polls secrets for changes.
+
+If the client is a Versioner, e.g. a VaultClient with a kv2 backend, only the secret version is polled and the
+secret is read when it changes; otherwise the secret is read and compared on each poll. If the client is a
+CachedClient, changed secrets are refreshed in its cache.
+
+Example:
+
+	watcher := secrets.NewWatcher(client)
+	err := watcher.Watch(ctx, "secret/data/prod/service/app/db", func(change secrets.SecretChange) {
+		pool.SetPassword(change.Values["password"])
+	})
+	...
+	go watcher.Start()
+	defer watcher.Stop()
+*/
+func NewWatcher(client Client, options ...WatcherOption) *Watcher {
+	w := Watcher{
+		Latch:    async.NewLatch(),
+		Client:   client,
+		Interval: DefaultWatchInterval,
+		watches:  make(map[string]*watch),
+	}
+	for _, option := range options {
+		option(&w)
+	}
+	return &w
+}
+
+// WatcherOption is an option for a watcher.
+type WatcherOption func(*Watcher)
+
+// OptWatcherInterval sets the interval the watcher polls secrets on.
+func OptWatcherInterval(interval time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		w.Interval = interval
+	}
+}
+
+// OptWatcherLog sets the watcher logger.
+func OptWatcherLog(log logger.Log) WatcherOption {
+	return func(w *Watcher) {
+		w.Log = log
+	}
+}
+
+// SecretChange is a change to a watched secret.
+type SecretChange struct {
+	Key string
+	// Version is the new version of the secret, if the client is a Versioner.
+	Version int
+	Values  Values
+}
+
+// SecretChangeHandler is called when a watched secret changes.
+type SecretChangeHandler func(SecretChange)
+
+// Watcher is a background worker that polls secrets and notifies handlers when they change.
+type Watcher struct {
+	*async.Latch
+	Client   Client
+	Interval time.Duration
+	Log      logger.Log
+
+	sync.Mutex
+	watches map[string]*watch
+}
+
+// IntervalOrDefault returns the poll interval or a default.
+func (w *Watcher) IntervalOrDefault() time.Duration {
+	if w.Interval > 0 {
+		return w.Interval
+	}
+	return DefaultWatchInterval
+}
+
+type watch struct {
+	Version  int
+	Values   Values
+	Handlers []SecretChangeHandler
+}
+
+// Watch reads a secret and calls a handler each time it changes after that.
+// It returns an error if the secret can't be read.
+func (w *Watcher) Watch(ctx context.Context, key string, handler SecretChangeHandler) error {
+	w.Lock()
+	existing, ok := w.watches[key]
+	if ok {
+		existing.Handlers = append(existing.Handlers, handler)
+		w.Unlock()
+		return nil
+	}
+	w.Unlock()
+
+	version, values, err := w.read(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	w.Lock()
+	defer w.Unlock()
+	if existing, ok := w.watches[key]; ok {
+		existing.Handlers = append(existing.Handlers, handler)
+		return nil
+	}
+	w.watches[key] = &watch{Version: version, Values: values, Handlers: []SecretChangeHandler{handler}}
+	return nil
+}
+
+// WatchChan reads a secret and sends the changes to it on the returned channel.
+// The channel holds the latest change; if it isn't received before the next change, the older change is dropped.
+func (w *Watcher) WatchChan(ctx context.Context, key string) (<-chan SecretChange, error) {
+	changes := make(chan SecretChange, 1)
+	var mu sync.Mutex
+	err := w.Watch(ctx, key, func(change SecretChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		select {
+		case <-changes:
+		default:
+		}
+		changes <- change
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// Unwatch stops watching a secret and removes its handlers.
+func (w *Watcher) Unwatch(key string) {
+	w.Lock()
+	defer w.Unlock()
+	delete(w.watches, key)
+}
+
+// Keys returns the watched keys.
+func (w *Watcher) Keys() []string {
+	w.Lock()
+	defer w.Unlock()
+	keys := make([]string, 0, len(w.watches))
+	for key := range w.watches {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+/*
+Start starts the watcher, which polls the watched secrets on the interval.
+It will return an ErrCannotStart if the watcher is already started.
+
+This call will block.
+*/
+func (w *Watcher) Start() error {
+	if !w.CanStart() {
+		return ex.New(async.ErrCannotStart)
+	}
+	w.Starting()
+	stopping := w.NotifyStopping()
+	w.Started()
+
+	ticker := time.NewTicker(w.IntervalOrDefault())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			w.Poll(context.Background())
+		case <-stopping:
+			w.Stopped()
+			return nil
+		}
+	}
+}
+
+// Stop stops the watcher.
+func (w *Watcher) Stop() error {
+	if !w.CanStop() {
+		return ex.New(async.ErrCannotStop)
+	}
+	w.Stopping()
+	<-w.NotifyStopped()
+	return nil
+}
+
+// Poll checks each watched secret once, and calls the handlers of the secrets that changed.
+// Errors reading secrets are logged, and the secrets are checked again on the next poll.
+func (w *Watcher) Poll(ctx context.Context) {
+	for _, key := range w.Keys() {
+		if err := w.check(ctx, key); err != nil {
+			logger.MaybeError(w.Log, err)
+		}
+	}
+}
+
+func (w *Watcher) check(ctx context.Context, key string) error {
+	w.Lock()
+	existing, ok := w.watches[key]
+	if !ok {
+		w.Unlock()
+		return nil
+	}
+	previousVersion, previousValues := existing.Version, existing.Values
+	w.Unlock()
+
+	if versioner, ok := w.Client.(Versioner); ok && previousVersion > 0 {
+		version, err := versioner.Version(ctx, key)
+		if err != nil {
+			return err
+		}
+		if version == previousVersion {
+			return nil
+		}
+	}
+
+	version, values, err := w.read(ctx, key)
+	if err != nil {
+		return err
+	}
+	if version == previousVersion && reflect.DeepEqual(values, previousValues) {
+		return nil
+	}
+
+	w.Lock()
+	existing, ok = w.watches[key]
+	if !ok {
+		w.Unlock()
+		return nil
+	}
+	existing.Version, existing.Values = version, values
+	handlers := append([]SecretChangeHandler(nil), existing.Handlers...)
+	w.Unlock()
+
+	logger.MaybeInfof(w.Log, "secret changed: %s", key)
+	for _, handler := range handlers {
+		handler(SecretChange{Key: key, Version: version, Values: copyValues(values)})
+	}
+	return nil
+}
+
+// read reads the version of a secret and its values at that version, refreshing it in the cache if the client is cached.
+// The version is zero if the client is not a Versioner or the secret is not versioned, and the latest values are read.
+func (w *Watcher) read(ctx context.Context, key string) (version int, values Values, err error) {
+	if versioner, ok := w.Client.(Versioner); ok {
+		version, err = versioner.Version(ctx, key)
+		if err != nil && !ex.Is(err, ErrVersionUnsupported) {
+			return
+		}
+		err = nil
+	}
+	var options []RequestOption
+	if version > 0 {
+		options = append(options, OptRequestVersion(version))
+	}
+	if cached, ok := w.Client.(*CachedClient); ok {
+		values, err = cached.Refresh(ctx, key, options...)
+	} else {
+		values, err = w.Client.Get(ctx, key, options...)
+	}
+	return
+}
@@ -0,0 +1,122 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blend/go-sdk/assert"
+)
+
+func TestWatcherPoll(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+	assert.Nil(remote.Put(todo, "bar", Values{"key": "one"}))
+
+	watcher := NewWatcher(remote)
+	var changes []SecretChange
+	assert.Nil(watcher.Watch(todo, "foo", func(change SecretChange) {
+		changes = append(changes, change)
+	}))
+	barChanges, err := watcher.WatchChan(todo, "bar")
+	assert.Nil(err)
+	assert.NotNil(watcher.Watch(todo, "missing", func(SecretChange) {}))
+	assert.Len(watcher.Keys(), 2)
+
+	watcher.Poll(todo)
+	assert.Empty(changes, "handlers should not be called until the secret changes")
+
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "two"}))
+	assert.Nil(remote.Put(todo, "bar", Values{"key": "two"}))
+	assert.Nil(remote.Put(todo, "bar", Values{"key": "three"}))
+	watcher.Poll(todo)
+	watcher.Poll(todo)
+	assert.Len(changes, 1)
+	assert.Equal("foo", changes[0].Key)
+	assert.Equal("two", changes[0].Values["password"])
+	assert.Equal("three", (<-barChanges).Values["key"])
+
+	watcher.Unwatch("foo")
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "three"}))
+	watcher.Poll(todo)
+	assert.Len(changes, 1)
+}
+
+func TestWatcherPollVersions(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := versionedClient{newCountingClient()}
+	remote.Versions = map[string]int{}
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+
+	cached := NewCachedClient(remote, OptCacheTTL(time.Hour))
+	watcher := NewWatcher(cached)
+	changes, err := watcher.WatchChan(todo, "foo")
+	assert.Nil(err)
+	gets := remote.gets()
+
+	watcher.Poll(todo)
+	watcher.Poll(todo)
+	assert.Equal(gets, remote.gets(), "unchanged versions should not read the secret")
+
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "two"}))
+	watcher.Poll(todo)
+	change := <-changes
+	assert.Equal(2, change.Version)
+	assert.Equal("two", change.Values["password"])
+	remote.Lock()
+	assert.Equal("2", remote.RequestedVersions[len(remote.RequestedVersions)-1], "the secret should be read at the version polled")
+	remote.Unlock()
+
+	values, err := cached.Get(todo, "foo")
+	assert.Nil(err)
+	assert.Equal("two", values["password"], "changes should be refreshed in the cache")
+}
+
+func TestWatcherIntervalOrDefault(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(DefaultWatchInterval, NewWatcher(nil).IntervalOrDefault())
+	assert.Equal(DefaultWatchInterval, NewWatcher(nil, OptWatcherInterval(0)).IntervalOrDefault())
+	assert.Equal(DefaultWatchInterval, NewWatcher(nil, OptWatcherInterval(-time.Second)).IntervalOrDefault())
+	assert.Equal(time.Second, NewWatcher(nil, OptWatcherInterval(time.Second)).IntervalOrDefault())
+
+	watcher := NewWatcher(newCountingClient(), OptWatcherInterval(0))
+	go watcher.Start()
+	<-watcher.NotifyStarted()
+	assert.Nil(watcher.Stop())
+}
+
+func TestWatcherStart(t *testing.T) {
+	assert := assert.New(t)
+	todo := context.TODO()
+
+	remote := newCountingClient()
+	assert.Nil(remote.Put(todo, "foo", Values{"password": "one"}))
+
+	watcher := NewWatcher(remote, OptWatcherInterval(time.Millisecond))
+	changes, err := watcher.WatchChan(todo, "foo")
+	assert.Nil(err)
+
+	go watcher.Start()
+	<-watcher.NotifyStarted()
+	defer watcher.Stop()
+
+	for x := 2; x < 4; x++ {
+		password := fmt.Sprintf("password%d", x)
+		remote.Lock()
+		remote.SecretValues["foo"] = Values{"password": password}
+		remote.Unlock()
+		select {
+		case change := <-changes:
+			assert.Equal(password, change.Values["password"])
+		case <-time.After(5 * time.Second):
+			assert.FailNow("timed out waiting for the change")
+		}
+	}
+}
@@ -63,6 +63,9 @@ func (crw *CompressedResponseWriter) ContentLength() int {
 func (crw *CompressedResponseWriter) Flush() {
 	crw.ensureCompressedStream()
 	crw.gzipWriter.Flush()
+	if typed, ok := crw.innerResponse.(http.Flusher); ok {
+		typed.Flush()
+	}
 }
 
 // Close closes any underlying resources.